@@ -0,0 +1,99 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// https://core.telegram.org/mtproto/auth_key
+// https://core.telegram.org/mtproto/security_guidelines
+
+var (
+	bigIntOne = big.NewInt(1)
+	// 2^{2048-64}
+	bigIntDHSafetyRange = new(big.Int).Lsh(bigIntOne, 2048-64)
+)
+
+// 生成res_pq使用的pq, p和q为两个不同的31位素数(p < q), 返回大端序数据
+func GeneratePQ() (pq, p, q []byte, err error) {
+	var bigP, bigQ *big.Int
+	for {
+		if bigP, err = rand.Prime(rand.Reader, 31); err != nil {
+			return
+		}
+		if bigQ, err = rand.Prime(rand.Reader, 31); err != nil {
+			return
+		}
+		if bigP.Cmp(bigQ) != 0 {
+			break
+		}
+	}
+
+	if bigP.Cmp(bigQ) > 0 {
+		bigP, bigQ = bigQ, bigP
+	}
+
+	pq = new(big.Int).Mul(bigP, bigQ).Bytes()
+	p = bigP.Bytes()
+	q = bigQ.Bytes()
+	return
+}
+
+// 2048位的safe prime, 即p和(p-1)/2都是素数
+func IsSafePrime(p *big.Int) bool {
+	if p.BitLen() != 2048 || !p.ProbablyPrime(20) {
+		return false
+	}
+	return new(big.Int).Rsh(p, 1).ProbablyPrime(20)
+}
+
+// 选择满足条件的生成元g, g生成(p-1)/2阶的循环子群
+func FindDHGenerator(p *big.Int) int32 {
+	mod := func(m int64) int64 {
+		return new(big.Int).Mod(p, big.NewInt(m)).Int64()
+	}
+
+	switch {
+	case mod(8) == 7:
+		return 2
+	case mod(3) == 2:
+		return 3
+	case mod(5) == 1 || mod(5) == 4:
+		return 5
+	case mod(24) == 19 || mod(24) == 23:
+		return 6
+	case mod(7) == 3 || mod(7) == 5 || mod(7) == 6:
+		return 7
+	default:
+		// p为safe prime时4总是满足条件
+		return 4
+	}
+}
+
+// 检查g_a和g_b: 1 < g_x < p - 1, 并且2^{2048-64} <= g_x <= p - 2^{2048-64}
+func CheckDHParams(gX, p *big.Int) bool {
+	if gX.Cmp(bigIntOne) <= 0 || gX.Cmp(new(big.Int).Sub(p, bigIntOne)) >= 0 {
+		return false
+	}
+	if gX.Cmp(bigIntDHSafetyRange) < 0 || gX.Cmp(new(big.Int).Sub(p, bigIntDHSafetyRange)) > 0 {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"math/big"
+	"testing"
+)
+
+// 客户端内置的good prime
+const testGoodPrime = "c71caeb9c6b1c9048e6c522f70f13f73980d40238e3e21c14934d037563d930f48198a0a" +
+	"a7c14058229493d22530f4dbfa336f6e0ac925139543aed44cce7c3720fd51f69458705a" +
+	"c68cd4fe6b6b13abdc9746512969328454f18faf8c595f642477fe96bb2a941d5bcd1d4a" +
+	"c8cc49880708fa9b378e3c4f3a9060bee67cf9a4a4a695811051907e162753b56b0f6b41" +
+	"0dba74d8a84b2a14b3144e0ef1284754fd17ed950d5965b4b9dd46582db1178d169c6bc4" +
+	"65b0d6ff9ca3928fef5b9ae4e418fc15e83ebea0f87fa9ff5eed70050ded2849f47bf959" +
+	"d956850ce929851f0d8115f635b105ee2e4e15d04b2454bf6f4fadf034b10403119cd8e3" +
+	"b92fcc5b"
+
+func TestGeneratePQ(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		pq, p, q, err := GeneratePQ()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		bigP := new(big.Int).SetBytes(p)
+		bigQ := new(big.Int).SetBytes(q)
+		if bigP.BitLen() != 31 || bigQ.BitLen() != 31 || bigP.Cmp(bigQ) >= 0 {
+			t.Fatalf("invalid p, q: %s, %s", bigP, bigQ)
+		}
+		if !bigP.ProbablyPrime(20) || !bigQ.ProbablyPrime(20) {
+			t.Fatalf("p, q must be prime: %s, %s", bigP, bigQ)
+		}
+		if new(big.Int).Mul(bigP, bigQ).Cmp(new(big.Int).SetBytes(pq)) != 0 {
+			t.Fatalf("invalid pq: %x", pq)
+		}
+	}
+}
+
+func TestDHParams(t *testing.T) {
+	p, _ := new(big.Int).SetString(testGoodPrime, 16)
+	if !IsSafePrime(p) {
+		t.Fatal("good prime must be safe prime")
+	}
+	if IsSafePrime(new(big.Int).Add(p, big.NewInt(2))) {
+		t.Fatal("p+2 must not be safe prime")
+	}
+
+	if g := FindDHGenerator(p); g != 3 {
+		t.Fatalf("invalid g: %d", g)
+	}
+
+	if CheckDHParams(big.NewInt(2), p) {
+		t.Fatal("small g_a must be rejected")
+	}
+	if CheckDHParams(new(big.Int).Sub(p, big.NewInt(1)), p) {
+		t.Fatal("p-1 must be rejected")
+	}
+	gA := new(big.Int).Exp(big.NewInt(3), new(big.Int).SetBytes(GenerateNonce(256)), p)
+	if !CheckDHParams(gA, p) {
+		t.Fatalf("g_a must be accepted: %x", gA)
+	}
+}
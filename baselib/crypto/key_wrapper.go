@@ -0,0 +1,148 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// 版本0保留为未加密数据
+const KeyVersionPlaintext = 0
+
+type MasterKeyConfig struct {
+	Version int32  // 大于0
+	File    string // base64编码的32字节key文件
+	Env     string // File为空时从环境变量读取base64编码的key
+}
+
+type KeyWrapperConfig struct {
+	Version    int32 // 当前用于加密的master key版本, 0为不加密
+	MasterKeys []MasterKeyConfig
+}
+
+// 使用master key对数据做AES-256-GCM加密(envelope encryption),
+// 每个master key有一个版本号, 旧版本的key保留用于解密, 轮换后重新加密
+type KeyWrapper struct {
+	version int32
+	aeads   map[int32]cipher.AEAD
+}
+
+func NewKeyWrapper(c *KeyWrapperConfig) (*KeyWrapper, error) {
+	w := &KeyWrapper{
+		version: KeyVersionPlaintext,
+		aeads:   make(map[int32]cipher.AEAD),
+	}
+	if c == nil {
+		return w, nil
+	}
+
+	for _, k := range c.MasterKeys {
+		if k.Version <= KeyVersionPlaintext {
+			return nil, fmt.Errorf("invalid master key version: %d", k.Version)
+		}
+		if _, ok := w.aeads[k.Version]; ok {
+			return nil, fmt.Errorf("duplicate master key version: %d", k.Version)
+		}
+
+		key, err := loadMasterKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("load master key %d error: %v", k.Version, err)
+		}
+		aead, err := newAESGCM(key)
+		if err != nil {
+			return nil, fmt.Errorf("load master key %d error: %v", k.Version, err)
+		}
+		w.aeads[k.Version] = aead
+	}
+
+	if c.Version != KeyVersionPlaintext {
+		if _, ok := w.aeads[c.Version]; !ok {
+			return nil, fmt.Errorf("not found master key version: %d", c.Version)
+		}
+	}
+	w.version = c.Version
+	return w, nil
+}
+
+func loadMasterKey(c MasterKeyConfig) ([]byte, error) {
+	var data string
+	if c.File != "" {
+		b, err := ioutil.ReadFile(c.File)
+		if err != nil {
+			return nil, err
+		}
+		data = string(b)
+	} else if c.Env != "" {
+		data = os.Getenv(c.Env)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid master key len: %d", len(key))
+	}
+	return key, nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// 当前加密使用的版本
+func (w *KeyWrapper) Version() int32 {
+	return w.version
+}
+
+// 使用当前版本的master key加密, 输出nonce + ciphertext + tag
+// additionalData绑定数据所属的记录(如auth_key_id), 防止密文被替换到其它记录
+func (w *KeyWrapper) Wrap(plaintext, additionalData []byte) (int32, []byte, error) {
+	if w.version == KeyVersionPlaintext {
+		return KeyVersionPlaintext, plaintext, nil
+	}
+
+	aead := w.aeads[w.version]
+	nonce := GenerateNonce(aead.NonceSize())
+	return w.version, aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func (w *KeyWrapper) Unwrap(version int32, ciphertext, additionalData []byte) ([]byte, error) {
+	if version == KeyVersionPlaintext {
+		return ciphertext, nil
+	}
+
+	aead, ok := w.aeads[version]
+	if !ok {
+		return nil, fmt.Errorf("not found master key version: %d", version)
+	}
+	if len(ciphertext) < aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("invalid ciphertext len: %d", len(ciphertext))
+	}
+	return aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], additionalData)
+}
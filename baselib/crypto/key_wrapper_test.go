@@ -0,0 +1,85 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"testing"
+)
+
+func TestKeyWrapper(t *testing.T) {
+	os.Setenv("TEST_MASTER_KEY_1", base64.StdEncoding.EncodeToString(GenerateNonce(32)))
+	os.Setenv("TEST_MASTER_KEY_2", base64.StdEncoding.EncodeToString(GenerateNonce(32)))
+	defer os.Unsetenv("TEST_MASTER_KEY_1")
+	defer os.Unsetenv("TEST_MASTER_KEY_2")
+
+	masterKeys := []MasterKeyConfig{{Version: 1, Env: "TEST_MASTER_KEY_1"}, {Version: 2, Env: "TEST_MASTER_KEY_2"}}
+	w1, err := NewKeyWrapper(&KeyWrapperConfig{Version: 1, MasterKeys: masterKeys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w2, err := NewKeyWrapper(&KeyWrapperConfig{Version: 2, MasterKeys: masterKeys})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authKey := GenerateNonce(256)
+	aad := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	v, data, err := w1.Wrap(authKey, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 1 || bytes.Contains(data, authKey) {
+		t.Fatalf("invalid wrap: version %d", v)
+	}
+
+	// 轮换后旧版本仍可解密
+	for _, w := range []*KeyWrapper{w1, w2} {
+		b, err := w.Unwrap(v, data, aad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, authKey) {
+			t.Fatal("unwrap mismatch")
+		}
+	}
+
+	if _, err = w1.Unwrap(v, data, []byte{8, 7, 6, 5, 4, 3, 2, 1}); err == nil {
+		t.Fatal("other additional data must be rejected")
+	}
+	data[len(data)-1] ^= 0x01
+	if _, err = w1.Unwrap(v, data, aad); err == nil {
+		t.Fatal("modified ciphertext must be rejected")
+	}
+	if _, err = w1.Unwrap(3, data, aad); err == nil {
+		t.Fatal("unknown version must be rejected")
+	}
+
+	// 未配置master key时不加密
+	w0, _ := NewKeyWrapper(nil)
+	if v, data, _ = w0.Wrap(authKey, aad); v != KeyVersionPlaintext || !bytes.Equal(data, authKey) {
+		t.Fatal("plaintext wrap mismatch")
+	}
+
+	if _, err = NewKeyWrapper(&KeyWrapperConfig{Version: 3, MasterKeys: masterKeys}); err == nil {
+		t.Fatal("missing current version must be rejected")
+	}
+}
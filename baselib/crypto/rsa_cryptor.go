@@ -20,7 +20,9 @@ package crypto
 import (
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/pem"
+	"fmt"
 	"math/big"
 )
 
@@ -127,26 +129,85 @@ FwIDAQAB
 
 // bca2c43964f3b7d1e7dfff4a769fd174770487399df315de2d2a47208cda5d32c90f0f01849cb58d1fe2a9e1bc25ee72aed55a6ea312900ea5b48a60ca51fffff1688ccb17d411eee043d8397420074a8e8ba92bd3c8976481fdfe238f40e583b0bf8bb7c8031b4c41cbeb0f7bfd991ddcca3235fa3bd078b0eb318c5ae4e6a0e8583ae2a09a2b009ede1407cfa4e05fdb0ef7a215ee752ac913495b43ca4258da4c63c701f62f2bf96062b5cbe8b8b0c0be6b674d7eda921a03ce62a0a49058962018e2a03bdefeeee5421ea44f10815d2308e8712423ee6cff1d83efcf94b2d52b2c54e4276242d663d84332e2cf7194d2b35fc5decc4d0c1c46ba6d0a6717
 type RSACryptor struct {
-	key *rsa.PrivateKey
+	key         *rsa.PrivateKey
+	fingerprint uint64
 }
 
 // TODO(@benqi): 这里写死了pkcs1PemPrivateKey
 func NewRSACryptor() *RSACryptor {
 	// testPrivateKey
-	block, _ := pem.Decode([]byte(pkcs1PemPrivateKey))
+	c, err := NewRSACryptorFromPEM(pkcs1PemPrivateKey)
+	if err != nil {
+		panic(err.Error())
+	}
+	return c
+}
+
+// 支持pkcs1和pkcs8格式的私钥
+func NewRSACryptorFromPEM(pemData []byte) (*RSACryptor, error) {
+	block, _ := pem.Decode(pemData)
 	if block == nil {
-		panic("Invalid pemsKeyData: " + string(pkcs1PemPrivateKey))
-		return nil
+		return nil, fmt.Errorf("invalid pemsKeyData: %s", string(pemData))
 	}
 
-	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
-	if err != nil {
-		panic("Failed to parse private key: " + err.Error())
+	var key *rsa.PrivateKey
+	switch block.Type {
+	case "PRIVATE KEY":
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %v", err)
+		}
+		var ok bool
+		if key, ok = k.(*rsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("not rsa private key")
+		}
+	default:
+		k, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %v", err)
+		}
+		key = k
+	}
+
+	if key.N.BitLen() != 2048 {
+		return nil, fmt.Errorf("invalid rsa key bits: %d", key.N.BitLen())
 	}
 
 	return &RSACryptor{
-		key: key,
+		key:         key,
+		fingerprint: calcRSAFingerprint(key.N, key.E),
+	}, nil
+}
+
+// 客户端通过server_public_key_fingerprints选择公钥
+func (m *RSACryptor) Fingerprint() uint64 {
+	return m.fingerprint
+}
+
+// fingerprint为SHA1(rsa_public_key n:string e:string)的低64位
+// https://core.telegram.org/mtproto/auth_key
+func calcRSAFingerprint(n *big.Int, e int) uint64 {
+	buf := appendTLString(nil, n.Bytes())
+	buf = appendTLString(buf, big.NewInt(int64(e)).Bytes())
+	digest := Sha1Digest(buf)
+	return binary.LittleEndian.Uint64(digest[12:])
+}
+
+// TL string序列化
+func appendTLString(buf []byte, b []byte) []byte {
+	n := len(b)
+	if n < 254 {
+		buf = append(buf, byte(n))
+		n++
+	} else {
+		buf = append(buf, 254, byte(n), byte(n>>8), byte(n>>16))
+		n += 4
+	}
+	buf = append(buf, b...)
+	for ; n%4 != 0; n++ {
+		buf = append(buf, 0)
 	}
+	return buf
 }
 
 func (m *RSACryptor) Encrypt(b []byte) []byte {
@@ -18,12 +18,8 @@
 package crypto
 
 import (
-	"crypto/rsa"
-	"crypto/x509"
 	"encoding/hex"
-	"encoding/pem"
 	"fmt"
-	"math/big"
 	"testing"
 )
 
@@ -41,6 +37,25 @@ func TestNewRSACryptor(t *testing.T) {
 	fmt.Println("len = ", len(decData), ", data: ", string(decData))
 }
 
+func TestRSAFingerprint(t *testing.T) {
+	// 客户端内置的公钥fingerprint
+	if fp := NewRSACryptor().Fingerprint(); fp != 12240908862933197005 {
+		t.Fatalf("invalid fingerprint: %d", fp)
+	}
+
+	c, err := NewRSACryptorFromPEM(pkcs8PemPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Fingerprint() != 12240908862933197005 {
+		t.Fatalf("invalid pkcs8 fingerprint: %d", c.Fingerprint())
+	}
+
+	if _, err = NewRSACryptorFromPEM(pkcs1PemPublicKey); err == nil {
+		t.Fatal("public key must be rejected")
+	}
+}
+
 //import (
 //"fmt"
 //"github.com/nebulaim/telegramd/mtproto"
@@ -48,7 +48,7 @@ func NewKetamaSelector(ketamaKey string) Selector {
 }
 
 func (s *KetamaSelector) wrapAddr(addr string, idx int) string {
-	return fmt.Sprintf("%s-%d", addr, idx)
+	return ketamaNodeName(addr, idx)
 }
 
 func (s *KetamaSelector) upWrapAddr(addr string) string {
@@ -69,13 +69,12 @@ func (s *KetamaSelector) Add(addr grpc.Address) error {
 }
 
 func (s *KetamaSelector) Delete(addr grpc.Address) error {
+	// baseSelector.Delete会删除addrMap, 先取出weight
+	a, ok := s.addrMap[addr.Addr]
 	err := s.baseSelector.Delete(addr)
-	if err == nil {
-		a, ok := s.addrMap[addr.Addr]
-		if ok {
-			for i := 0; i < a.weight; i++ {
-				s.hash.Remove(s.wrapAddr(addr.Addr, i))
-			}
+	if err == nil && ok {
+		for i := 0; i < a.weight; i++ {
+			s.hash.Remove(s.wrapAddr(addr.Addr, i))
 		}
 	}
 	return err
@@ -110,3 +109,29 @@ func (s *KetamaSelector) Get(ctx context.Context) (addr grpc.Address, err error)
 
 	return addr, NoAvailableAddressErr
 }
+
+func ketamaNodeName(addr string, idx int) string {
+	return fmt.Sprintf("%s-%d", addr, idx)
+}
+
+// 和KetamaSelector规则相同的hash环, 服务端用来判断key是否归本节点处理
+// 注册到etcd的节点都没有设置weight
+type KetamaRing struct {
+	hash *Ketama
+}
+
+func NewKetamaRing(addrs ...string) *KetamaRing {
+	r := &KetamaRing{hash: NewKetama(10, nil)}
+	for _, addr := range addrs {
+		r.hash.Add(ketamaNodeName(addr, 0))
+	}
+	return r
+}
+
+func (r *KetamaRing) Get(key string) (string, bool) {
+	node, ok := r.hash.Get(key)
+	if !ok {
+		return "", false
+	}
+	return node[:strings.LastIndex(node, "-")], true
+}
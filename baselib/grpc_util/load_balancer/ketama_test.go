@@ -20,6 +20,9 @@ package load_balancer
 import (
 	"fmt"
 	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 )
 
 func TestKetama(t *testing.T) {
@@ -34,3 +37,33 @@ func TestKetama(t *testing.T) {
 	fmt.Println(k.Get("234"))
 	fmt.Println(k.Get("345"))
 }
+
+func TestKetamaRingMatchSelector(t *testing.T) {
+	addrs := []string{"127.0.0.1:10000", "127.0.0.1:10001", "127.0.0.1:10002"}
+
+	s := NewKetamaSelector(DefaultKetamaKey)
+	for _, addr := range addrs {
+		s.Add(grpc.Address{Addr: addr})
+		s.Up(grpc.Address{Addr: addr})
+	}
+	r := NewKetamaRing(addrs...)
+
+	for _, key := range []string{"1", "2", "123", "234", "345", "100000"} {
+		addr, _ := s.Get(context.WithValue(context.Background(), DefaultKetamaKey, key))
+		addr2, _ := r.Get(key)
+		if addr.Addr != addr2 {
+			t.Fatalf("key %s: selector %s, ring %s", key, addr.Addr, addr2)
+		}
+	}
+
+	// 删除第一个节点
+	s.Delete(grpc.Address{Addr: addrs[0]})
+	r = NewKetamaRing(addrs[1:]...)
+	for _, key := range []string{"1", "2", "123", "234", "345", "100000"} {
+		addr, err := s.Get(context.WithValue(context.Background(), DefaultKetamaKey, key))
+		addr2, _ := r.Get(key)
+		if err != nil || addr.Addr != addr2 {
+			t.Fatalf("key %s: selector %s(%v), ring %s", key, addr.Addr, err, addr2)
+		}
+	}
+}
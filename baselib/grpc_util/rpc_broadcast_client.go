@@ -0,0 +1,149 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc_util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
+	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery/etcd3"
+	"google.golang.org/grpc"
+)
+
+// 调用服务的所有节点(比如session服务器的管理接口), 不经过负载均衡
+// 每次调用前从etcd读取当前注册的节点, 连接按地址缓存
+type RPCBroadcastClient struct {
+	mu     sync.Mutex
+	lookup func(ctx context.Context) ([]string, error)
+	conns  map[string]*grpc.ClientConn
+}
+
+func NewRPCBroadcastClient(discovery *service_discovery.ServiceDiscoveryClientConfig) (*RPCBroadcastClient, error) {
+	client, err := clientv3.New(clientv3.Config{Endpoints: discovery.EtcdAddrs})
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s/%s", "/nebulaim", discovery.ServiceName)
+	return newRPCBroadcastClient(func(ctx context.Context) ([]string, error) {
+		resp, err := client.Get(ctx, key, clientv3.WithPrefix())
+		if err != nil {
+			return nil, err
+		}
+
+		addrs := make([]string, 0, len(resp.Kvs))
+		for _, kv := range resp.Kvs {
+			node := etcd3.NodeData{}
+			if err := json.Unmarshal(kv.Value, &node); err != nil {
+				glog.Errorf("broadcast - parse node data error: {key: %s, err: %v}", kv.Key, err)
+				continue
+			}
+			addrs = append(addrs, node.Addr)
+		}
+		return addrs, nil
+	}), nil
+}
+
+func newRPCBroadcastClient(lookup func(ctx context.Context) ([]string, error)) *RPCBroadcastClient {
+	return &RPCBroadcastClient{
+		lookup: lookup,
+		conns:  make(map[string]*grpc.ClientConn),
+	}
+}
+
+// 并发调用所有节点, 返回调用成功的节点数, 有节点失败时同时返回其中一个错误
+func (c *RPCBroadcastClient) Broadcast(ctx context.Context, f func(ctx context.Context, conn *grpc.ClientConn) error) (int, error) {
+	conns, err := c.getConns(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		ok      int
+		lastErr error
+	)
+	for addr, conn := range conns {
+		wg.Add(1)
+		go func(addr string, conn *grpc.ClientConn) {
+			defer wg.Done()
+			err := f(ctx, conn)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				glog.Errorf("broadcast - call error: {addr: %s, err: %v}", addr, err)
+				lastErr = err
+			} else {
+				ok++
+			}
+		}(addr, conn)
+	}
+	wg.Wait()
+
+	return ok, lastErr
+}
+
+func (c *RPCBroadcastClient) getConns(ctx context.Context) (map[string]*grpc.ClientConn, error) {
+	addrs, err := c.lookup(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	alive := make(map[string]*grpc.ClientConn, len(addrs))
+	for _, addr := range addrs {
+		conn, ok := c.conns[addr]
+		if !ok {
+			conn, err = grpc.Dial(addr, grpc.WithInsecure())
+			if err != nil {
+				glog.Errorf("broadcast - dial error: {addr: %s, err: %v}", addr, err)
+				continue
+			}
+			c.conns[addr] = conn
+		}
+		alive[addr] = conn
+	}
+
+	// 已下线的节点
+	for addr, conn := range c.conns {
+		if _, ok := alive[addr]; !ok {
+			conn.Close()
+			delete(c.conns, addr)
+		}
+	}
+	return alive, nil
+}
+
+func (c *RPCBroadcastClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for addr, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, addr)
+	}
+}
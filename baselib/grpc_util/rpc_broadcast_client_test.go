@@ -0,0 +1,81 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package grpc_util
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func TestRPCBroadcastClient(t *testing.T) {
+	addrs := []string{"127.0.0.1:20001", "127.0.0.1:20002"}
+	c := newRPCBroadcastClient(func(ctx context.Context) ([]string, error) {
+		return addrs, nil
+	})
+	defer c.Close()
+
+	var (
+		mu    sync.Mutex
+		conns = make(map[*grpc.ClientConn]bool)
+	)
+	ok, err := c.Broadcast(context.Background(), func(ctx context.Context, conn *grpc.ClientConn) error {
+		mu.Lock()
+		defer mu.Unlock()
+		conns[conn] = true
+		return nil
+	})
+	if ok != 2 || err != nil || len(conns) != 2 {
+		t.Fatalf("invalid broadcast result: {ok: %d, err: %v, conns: %d}", ok, err, len(conns))
+	}
+
+	// 连接复用, 下线的节点关闭
+	addrs = addrs[:1]
+	failed := fmt.Errorf("call failed")
+	ok, err = c.Broadcast(context.Background(), func(ctx context.Context, conn *grpc.ClientConn) error {
+		if !conns[conn] {
+			t.Error("conn not reused")
+		}
+		return failed
+	})
+	if ok != 0 || err != failed {
+		t.Fatalf("invalid broadcast result: {ok: %d, err: %v}", ok, err)
+	}
+	if len(c.conns) != 1 || c.conns[addrs[0]] == nil {
+		t.Fatalf("invalid conns: %v", c.conns)
+	}
+}
+
+func TestRPCBroadcastClientLookupError(t *testing.T) {
+	failed := fmt.Errorf("etcd unavailable")
+	c := newRPCBroadcastClient(func(ctx context.Context) ([]string, error) {
+		return nil, failed
+	})
+
+	called := false
+	ok, err := c.Broadcast(context.Background(), func(ctx context.Context, conn *grpc.ClientConn) error {
+		called = true
+		return nil
+	})
+	if ok != 0 || err != failed || called {
+		t.Fatalf("invalid broadcast result: {ok: %d, err: %v, called: %v}", ok, err, called)
+	}
+}
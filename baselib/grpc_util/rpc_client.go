@@ -102,6 +102,11 @@ func (c *RPCClient) GetClientConn() *grpc.ClientConn {
 
 // 通用grpc转发器
 func (c *RPCClient) Invoke(rpcMetaData *RpcMetadata, object mtproto.TLObject) (mtproto.TLObject, error) {
+	return c.InvokeContext(context.Background(), rpcMetaData, object)
+}
+
+// ctx被取消时服务端handler的ctx也会被取消, 返回ctx.Err()
+func (c *RPCClient) InvokeContext(ctx context.Context, rpcMetaData *RpcMetadata, object mtproto.TLObject) (mtproto.TLObject, error) {
 	t := mtproto.FindRPCContextTuple(object)
 	if t == nil {
 		err := fmt.Errorf("Invoke error: %v not regist!\n", object)
@@ -118,9 +123,12 @@ func (c *RPCClient) Invoke(rpcMetaData *RpcMetadata, object mtproto.TLObject) (m
 
 	// ctx := context.Background()
 	// glog.Infof("Invoke - NewReplyFunc: {%v}\n", r)
-	ctx, _ := RpcMetadataToOutgoing(context.Background(), rpcMetaData)
+	ctx, err := RpcMetadataToOutgoing(ctx, rpcMetaData)
+	if err != nil {
+		return nil, mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_INTERNAL), "INTERNAL_SERVER_ERROR")
+	}
 	glog.Infof("Invoke - NewReplyFunc: {%v}\n", r)
-	err := c.conn.Invoke(ctx, t.Method, object, r, grpc.Header(&header), grpc.Trailer(&trailer))
+	err = c.conn.Invoke(ctx, t.Method, object, r, grpc.Header(&header), grpc.Trailer(&trailer))
 
 	glog.Infof("header: {%v}, trailer: {%v}", header, trailer)
 
@@ -130,6 +138,9 @@ func (c *RPCClient) Invoke(rpcMetaData *RpcMetadata, object mtproto.TLObject) (m
 
 	if err != nil {
 		glog.Errorf("RPC method: %s,  >> %v.Invoke(_) = _, %v: \n", t.Method, c.conn, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		// TODO(@benqi): 哪些情况需要断开客户端连接
 		if s, ok := status.FromError(err); ok {
 			switch s.Code() {
@@ -55,6 +55,15 @@ func NewRpcServer(addr string, discovery *service_discovery.ServiceDiscoveryServ
 type RegisterRPCServerFunc func(s *grpc.Server)
 
 func (s *RPCServer) Serve(regFunc RegisterRPCServerFunc) {
+	s.serve(regFunc, true)
+}
+
+// 不处理退出信号, 用于进程里还有其他服务, 由app.DoMainAppInstance收到信号后调用Destroy退出
+func (s *RPCServer) Serve2(regFunc RegisterRPCServerFunc) {
+	s.serve(regFunc, false)
+}
+
+func (s *RPCServer) serve(regFunc RegisterRPCServerFunc, handleSignal bool) {
 	// defer s.GracefulStop()
 	listener, err := net.Listen("tcp", s.addr)
 
@@ -71,19 +80,21 @@ func (s *RPCServer) Serve(regFunc RegisterRPCServerFunc) {
 	defer s.s.GracefulStop()
 	go s.registry.Register()
 
-	ch := make(chan os.Signal, 1)
-	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGKILL, syscall.SIGHUP, syscall.SIGQUIT)
-	go func() {
-		s2 := <-ch
-		glog.Infof("exit...")
-		s.registry.Deregister()
-		if i, ok := s2.(syscall.Signal); ok {
-			os.Exit(int(i))
-		} else {
-			os.Exit(0)
-		}
+	if handleSignal {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT, syscall.SIGKILL, syscall.SIGHUP, syscall.SIGQUIT)
+		go func() {
+			s2 := <-ch
+			glog.Infof("exit...")
+			s.registry.Deregister()
+			if i, ok := s2.(syscall.Signal); ok {
+				os.Exit(int(i))
+			} else {
+				os.Exit(0)
+			}
 
-	}()
+		}()
+	}
 
 	if err := s.s.Serve(listener); err != nil {
 		glog.Fatalf("failed to serve: %s", err)
@@ -91,5 +102,6 @@ func (s *RPCServer) Serve(regFunc RegisterRPCServerFunc) {
 }
 
 func (s *RPCServer) Stop() {
+	s.registry.Deregister()
 	s.s.GracefulStop()
 }
@@ -19,6 +19,7 @@ package etcd3
 
 import (
 	"encoding/json"
+	"errors"
 	etcd3 "github.com/coreos/etcd/clientv3"
 	"github.com/coreos/etcd/mvcc/mvccpb"
 	"golang.org/x/net/context"
@@ -31,6 +32,7 @@ type EtcdWatcher struct {
 	key     string
 	client  *etcd3.Client
 	updates []*naming.Update
+	rch     etcd3.WatchChan
 	ctx     context.Context
 	cancel  context.CancelFunc
 }
@@ -54,10 +56,12 @@ func newEtcdWatcher(key string, cli *etcd3.Client) naming.Watcher {
 func (w *EtcdWatcher) Next() ([]*naming.Update, error) {
 	updates := make([]*naming.Update, 0)
 
-	if len(w.updates) == 0 {
+	if w.rch == nil {
 		// query addresses from etcd
 		resp, err := w.client.Get(w.ctx, w.key, etcd3.WithPrefix())
 		if err == nil {
+			// 从Get之后的revision开始watch, 不漏掉中间的变化
+			w.rch = w.client.Watch(w.ctx, w.key, etcd3.WithPrefix(), etcd3.WithPrevKV(), etcd3.WithRev(resp.Header.Revision+1))
 			addrs := extractAddrs(resp)
 			if len(addrs) > 0 {
 				for _, v := range addrs {
@@ -72,8 +76,11 @@ func (w *EtcdWatcher) Next() ([]*naming.Update, error) {
 	}
 
 	// generate etcd Watcher
-	rch := w.client.Watch(w.ctx, w.key, etcd3.WithPrefix())
-	for wresp := range rch {
+	// DELETE事件没有value, 需要WithPrevKV才能拿到被删除节点的地址
+	if w.rch == nil {
+		w.rch = w.client.Watch(w.ctx, w.key, etcd3.WithPrefix(), etcd3.WithPrevKV())
+	}
+	for wresp := range w.rch {
 		for _, ev := range wresp.Events {
 			switch ev.Type {
 			case mvccpb.PUT:
@@ -85,8 +92,11 @@ func (w *EtcdWatcher) Next() ([]*naming.Update, error) {
 				}
 				updates = append(updates, &naming.Update{Op: naming.Add, Addr: nodeData.Addr, Metadata: &nodeData.Metadata})
 			case mvccpb.DELETE:
+				if ev.PrevKv == nil {
+					continue
+				}
 				nodeData := NodeData{}
-				err := json.Unmarshal([]byte(ev.Kv.Value), &nodeData)
+				err := json.Unmarshal([]byte(ev.PrevKv.Value), &nodeData)
 				if err != nil {
 					grpclog.Println("Parse node data error:", err)
 					continue
@@ -94,8 +104,11 @@ func (w *EtcdWatcher) Next() ([]*naming.Update, error) {
 				updates = append(updates, &naming.Update{Op: naming.Delete, Addr: nodeData.Addr, Metadata: &nodeData.Metadata})
 			}
 		}
+		if len(updates) > 0 {
+			return updates, nil
+		}
 	}
-	return updates, nil
+	return nil, errors.New("etcd watcher closed")
 }
 
 func extractAddrs(resp *etcd3.GetResponse) []NodeData {
@@ -0,0 +1,272 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net2
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/golang/glog"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HAProxy PROXY protocol
+// https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt
+const (
+	proxyProtocolV1Prefix    = "PROXY "
+	proxyProtocolV1MaxLen    = 107
+	proxyProtocolV2HeaderLen = 16
+
+	// 读取PROXY头的超时时间
+	proxyProtocolHeaderTimeout = 5 * time.Second
+)
+
+var proxyProtocolV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+type ProxyProtocolConfig struct {
+	Enable bool
+	// 可信的负载均衡地址(CIDR)，只解析来自这些地址的PROXY头
+	TrustedSources []string
+}
+
+// ProxyProtocolListener在连接首次读取(或获取RemoteAddr)时解析PROXY头，
+// 不会阻塞Accept，解析后RemoteAddr()返回真实的客户端地址。
+// 可信来源的连接没有PROXY头时按普通连接处理。
+type ProxyProtocolListener struct {
+	net.Listener
+	trusted []*net.IPNet
+}
+
+func NewProxyProtocolListener(lsn net.Listener, conf ProxyProtocolConfig) (*ProxyProtocolListener, error) {
+	if len(conf.TrustedSources) == 0 {
+		return nil, fmt.Errorf("proxy protocol: trusted sources is empty")
+	}
+
+	l := &ProxyProtocolListener{Listener: lsn}
+	for _, s := range conf.TrustedSources {
+		if !strings.Contains(s, "/") {
+			if strings.Contains(s, ":") {
+				s += "/128"
+			} else {
+				s += "/32"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("proxy protocol: invalid trusted source %s: %v", s, err)
+		}
+		l.trusted = append(l.trusted, ipNet)
+	}
+	return l, nil
+}
+
+func (l *ProxyProtocolListener) isTrusted(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ipNet := range l.trusted {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *ProxyProtocolListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.isTrusted(conn.RemoteAddr()) {
+		return conn, nil
+	}
+	return newProxyProtocolConn(conn), nil
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+	r          *bufio.Reader
+	once       sync.Once
+	remoteAddr net.Addr
+	localAddr  net.Addr
+	err        error
+}
+
+func newProxyProtocolConn(conn net.Conn) *proxyProtocolConn {
+	return &proxyProtocolConn{
+		Conn: conn,
+		r:    bufio.NewReader(conn),
+	}
+}
+
+func (c *proxyProtocolConn) init() {
+	c.once.Do(func() {
+		c.Conn.SetReadDeadline(time.Now().Add(proxyProtocolHeaderTimeout))
+		c.remoteAddr, c.localAddr, c.err = readProxyProtocolHeader(c.r)
+		c.Conn.SetReadDeadline(time.Time{})
+
+		if c.err != nil {
+			glog.Errorf("read proxy protocol header from %s error: %v", c.Conn.RemoteAddr(), c.err)
+		}
+	})
+}
+
+func (c *proxyProtocolConn) Read(p []byte) (int, error) {
+	c.init()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(p)
+}
+
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.init()
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyProtocolConn) LocalAddr() net.Addr {
+	c.init()
+	if c.localAddr != nil {
+		return c.localAddr
+	}
+	return c.Conn.LocalAddr()
+}
+
+// 返回PROXY头中的源地址和目的地址，没有PROXY头、LOCAL命令或UNKNOWN协议时返回nil
+func readProxyProtocolHeader(r *bufio.Reader) (src, dst net.Addr, err error) {
+	b, err := r.Peek(len(proxyProtocolV1Prefix))
+	if err != nil {
+		return nil, nil, err
+	}
+	if string(b) == proxyProtocolV1Prefix {
+		return readProxyProtocolV1(r)
+	}
+
+	// 前缀匹配v2签名后才继续Peek，避免短包时阻塞
+	if !bytes.Equal(b, proxyProtocolV2Signature[:len(b)]) {
+		return nil, nil, nil
+	}
+	if b, err = r.Peek(proxyProtocolV2HeaderLen); err != nil {
+		return nil, nil, err
+	}
+	if !bytes.Equal(b[:12], proxyProtocolV2Signature) {
+		return nil, nil, nil
+	}
+	return readProxyProtocolV2(r)
+}
+
+// PROXY TCP4 255.255.255.255 255.255.255.255 65535 65535\r\n
+func readProxyProtocolV1(r *bufio.Reader) (src, dst net.Addr, err error) {
+	var line []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, c)
+		if c == '\n' {
+			break
+		}
+		if len(line) >= proxyProtocolV1MaxLen {
+			return nil, nil, fmt.Errorf("proxy protocol v1: header too long")
+		}
+	}
+
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, fmt.Errorf("proxy protocol v1: invalid header: %q", line)
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, nil, fmt.Errorf("proxy protocol v1: invalid header: %q", line)
+	}
+
+	srcIP, dstIP := net.ParseIP(fields[2]), net.ParseIP(fields[3])
+	if srcIP == nil || dstIP == nil || (fields[1] == "TCP4") != (srcIP.To4() != nil) {
+		return nil, nil, fmt.Errorf("proxy protocol v1: invalid address: %q", line)
+	}
+	srcPort, err1 := strconv.ParseUint(fields[4], 10, 16)
+	dstPort, err2 := strconv.ParseUint(fields[5], 10, 16)
+	if err1 != nil || err2 != nil {
+		return nil, nil, fmt.Errorf("proxy protocol v1: invalid port: %q", line)
+	}
+
+	return &net.TCPAddr{IP: srcIP, Port: int(srcPort)}, &net.TCPAddr{IP: dstIP, Port: int(dstPort)}, nil
+}
+
+// 12字节签名 + ver_cmd(1) + fam(1) + len(2) + 地址(和TLV)
+func readProxyProtocolV2(r *bufio.Reader) (src, dst net.Addr, err error) {
+	header := make([]byte, proxyProtocolV2HeaderLen)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return nil, nil, err
+	}
+
+	verCmd, fam := header[12], header[13]
+	size := int(binary.BigEndian.Uint16(header[14:]))
+	if verCmd>>4 != 2 {
+		return nil, nil, fmt.Errorf("proxy protocol v2: invalid version: %d", verCmd>>4)
+	}
+
+	payload := make([]byte, size)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return nil, nil, err
+	}
+
+	switch verCmd & 0x0f {
+	case 0x00:
+		// LOCAL: 负载均衡自身的连接(如健康检查)，使用真实连接地址
+		return nil, nil, nil
+	case 0x01:
+		// PROXY
+	default:
+		return nil, nil, fmt.Errorf("proxy protocol v2: invalid command: %d", verCmd&0x0f)
+	}
+
+	switch fam {
+	case 0x11: // TCP over IPv4
+		if size < 12 {
+			return nil, nil, fmt.Errorf("proxy protocol v2: invalid ipv4 address len: %d", size)
+		}
+		src = &net.TCPAddr{IP: net.IP(payload[0:4]), Port: int(binary.BigEndian.Uint16(payload[8:]))}
+		dst = &net.TCPAddr{IP: net.IP(payload[4:8]), Port: int(binary.BigEndian.Uint16(payload[10:]))}
+	case 0x21: // TCP over IPv6
+		if size < 36 {
+			return nil, nil, fmt.Errorf("proxy protocol v2: invalid ipv6 address len: %d", size)
+		}
+		src = &net.TCPAddr{IP: net.IP(payload[0:16]), Port: int(binary.BigEndian.Uint16(payload[32:]))}
+		dst = &net.TCPAddr{IP: net.IP(payload[16:32]), Port: int(binary.BigEndian.Uint16(payload[34:]))}
+	default:
+		// UNSPEC、UDP、unix socket，忽略地址
+		return nil, nil, nil
+	}
+	return src, dst, nil
+}
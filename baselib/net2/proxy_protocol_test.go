@@ -0,0 +1,207 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+)
+
+func newTestProxyProtocolListener(t *testing.T, trusted ...string) (*ProxyProtocolListener, string) {
+	lsn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := NewProxyProtocolListener(lsn, ProxyProtocolConfig{Enable: true, TrustedSources: trusted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l, lsn.Addr().String()
+}
+
+// 发送data后accept，返回服务端连接
+func acceptTestProxyProtocolConn(t *testing.T, l net.Listener, addr string, data []byte) (net.Conn, net.Conn) {
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Write(data)
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, conn
+}
+
+func makeTestProxyProtocolV2(cmd, fam byte, addrs []byte) []byte {
+	b := append([]byte{}, proxyProtocolV2Signature...)
+	b = append(b, 0x20|cmd, fam, 0, 0)
+	binary.BigEndian.PutUint16(b[14:], uint16(len(addrs)))
+	return append(b, addrs...)
+}
+
+func checkProxyProtocolConn(t *testing.T, conn net.Conn, remoteAddr string, payload []byte) {
+	b := make([]byte, len(payload))
+	if _, err := io.ReadFull(NewBufferedConn(conn), b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, payload) {
+		t.Fatalf("invalid data: %v (need %v)", b, payload)
+	}
+	if conn.RemoteAddr().String() != remoteAddr {
+		t.Fatalf("invalid remote addr: %s (need %s)", conn.RemoteAddr(), remoteAddr)
+	}
+}
+
+func TestProxyProtocolV1(t *testing.T) {
+	l, addr := newTestProxyProtocolListener(t, "127.0.0.0/8")
+	defer l.Close()
+
+	payload := []byte{0xef, 0x01, 0x02, 0x03, 0x04}
+
+	tests := []struct {
+		header     string
+		remoteAddr string
+	}{
+		{"PROXY TCP4 1.2.3.4 5.6.7.8 1234 443\r\n", "1.2.3.4:1234"},
+		{"PROXY TCP6 2001:db8::1 2001:db8::2 1234 443\r\n", "[2001:db8::1]:1234"},
+	}
+	for _, tt := range tests {
+		c, conn := acceptTestProxyProtocolConn(t, l, addr, append([]byte(tt.header), payload...))
+		checkProxyProtocolConn(t, conn, tt.remoteAddr, payload)
+		c.Close()
+		conn.Close()
+	}
+
+	// UNKNOWN使用真实连接地址
+	c, conn := acceptTestProxyProtocolConn(t, l, addr, append([]byte("PROXY UNKNOWN\r\n"), payload...))
+	checkProxyProtocolConn(t, conn, c.LocalAddr().String(), payload)
+	c.Close()
+	conn.Close()
+}
+
+func TestProxyProtocolV2(t *testing.T) {
+	l, addr := newTestProxyProtocolListener(t, "127.0.0.1")
+	defer l.Close()
+
+	payload := []byte{0xef, 0x01, 0x02, 0x03, 0x04}
+
+	ipv4 := []byte{1, 2, 3, 4, 5, 6, 7, 8, 0x04, 0xd2, 0x01, 0xbb}
+	ipv6 := append(append(net.ParseIP("2001:db8::1").To16(), net.ParseIP("2001:db8::2").To16()...), 0x04, 0xd2, 0x01, 0xbb)
+	// 带TLV
+	ipv4TLV := append(append([]byte{}, ipv4...), 0x04, 0x00, 0x02, 0xaa, 0xbb)
+
+	tests := []struct {
+		name       string
+		header     []byte
+		remoteAddr string
+	}{
+		{"tcp4", makeTestProxyProtocolV2(0x01, 0x11, ipv4), "1.2.3.4:1234"},
+		{"tcp6", makeTestProxyProtocolV2(0x01, 0x21, ipv6), "[2001:db8::1]:1234"},
+		{"tcp4 with tlv", makeTestProxyProtocolV2(0x01, 0x11, ipv4TLV), "1.2.3.4:1234"},
+		{"local", makeTestProxyProtocolV2(0x00, 0x00, nil), ""},
+	}
+	for _, tt := range tests {
+		c, conn := acceptTestProxyProtocolConn(t, l, addr, append(tt.header, payload...))
+		remoteAddr := tt.remoteAddr
+		if remoteAddr == "" {
+			remoteAddr = c.LocalAddr().String()
+		}
+		checkProxyProtocolConn(t, conn, remoteAddr, payload)
+		c.Close()
+		conn.Close()
+	}
+}
+
+// 可信来源没有PROXY头时按普通连接处理
+func TestProxyProtocolWithoutHeader(t *testing.T) {
+	l, addr := newTestProxyProtocolListener(t, "127.0.0.1")
+	defer l.Close()
+
+	payload := []byte{0xef, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}
+	c, conn := acceptTestProxyProtocolConn(t, l, addr, payload)
+	defer c.Close()
+	defer conn.Close()
+
+	checkProxyProtocolConn(t, conn, c.LocalAddr().String(), payload)
+}
+
+// 非可信来源不解析PROXY头
+func TestProxyProtocolUntrusted(t *testing.T) {
+	l, addr := newTestProxyProtocolListener(t, "10.0.0.0/8")
+	defer l.Close()
+
+	data := []byte("PROXY TCP4 1.2.3.4 5.6.7.8 1234 443\r\n")
+	c, conn := acceptTestProxyProtocolConn(t, l, addr, data)
+	defer c.Close()
+	defer conn.Close()
+
+	checkProxyProtocolConn(t, conn, c.LocalAddr().String(), data)
+}
+
+func TestProxyProtocolInvalidHeader(t *testing.T) {
+	l, addr := newTestProxyProtocolListener(t, "127.0.0.1")
+	defer l.Close()
+
+	tooLong := "PROXY TCP4 " + string(bytes.Repeat([]byte{'1'}, proxyProtocolV1MaxLen)) + "\r\n"
+	badVersion := makeTestProxyProtocolV2(0x01, 0x11, make([]byte, 12))
+	badVersion[12] = 0x11
+
+	tests := []struct {
+		name   string
+		header []byte
+	}{
+		{"v1 bad proto", []byte("PROXY UDP4 1.2.3.4 5.6.7.8 1234 443\r\n")},
+		{"v1 bad addr", []byte("PROXY TCP4 1.2.3 5.6.7.8 1234 443\r\n")},
+		{"v1 addr family mismatch", []byte("PROXY TCP4 2001:db8::1 5.6.7.8 1234 443\r\n")},
+		{"v1 bad port", []byte("PROXY TCP4 1.2.3.4 5.6.7.8 123456 443\r\n")},
+		{"v1 missing crlf", []byte("PROXY TCP4 1.2.3.4 5.6.7.8 1234 443\n")},
+		{"v1 too long", []byte(tooLong)},
+		{"v2 bad version", badVersion},
+		{"v2 bad command", makeTestProxyProtocolV2(0x02, 0x11, make([]byte, 12))},
+		{"v2 short ipv4", makeTestProxyProtocolV2(0x01, 0x11, make([]byte, 8))},
+		{"v2 short ipv6", makeTestProxyProtocolV2(0x01, 0x21, make([]byte, 12))},
+	}
+	for _, tt := range tests {
+		c, conn := acceptTestProxyProtocolConn(t, l, addr, append(tt.header, 0xef, 0x01, 0x02, 0x03))
+		if _, err := conn.Read(make([]byte, 4)); err == nil {
+			t.Errorf("%s: must be rejected", tt.name)
+		}
+		c.Close()
+		conn.Close()
+	}
+}
+
+func TestProxyProtocolInvalidConfig(t *testing.T) {
+	lsn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+
+	if _, err = NewProxyProtocolListener(lsn, ProxyProtocolConfig{Enable: true}); err == nil {
+		t.Error("empty trusted sources must be rejected")
+	}
+	if _, err = NewProxyProtocolListener(lsn, ProxyProtocolConfig{Enable: true, TrustedSources: []string{"10.0.0.0/33"}}); err == nil {
+		t.Error("invalid trusted source must be rejected")
+	}
+}
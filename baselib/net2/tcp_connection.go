@@ -137,7 +137,14 @@ func (c *TcpConnection) sendLoop() {
 	for {
 		select {
 		case msg, ok := <-c.sendChan:
-			if !ok || c.codec.Send(msg) != nil {
+			if !ok {
+				return
+			}
+			if m, ok := msg.(*closeAfterSend); ok {
+				c.codec.Send(m.msg)
+				return
+			}
+			if c.codec.Send(msg) != nil {
 				return
 			}
 		case <-c.closeChan:
@@ -178,3 +185,19 @@ func (c *TcpConnection) Send(msg interface{}) error {
 		return ConnectionBlockedError
 	}
 }
+
+// SendAndClose 先把msg写出去再关闭连接
+// 直接Send后Close, 还在sendChan里排队的msg会被丢掉(如传输层错误码)
+func (c *TcpConnection) SendAndClose(msg interface{}) error {
+	if c.sendChan == nil {
+		err := c.Send(msg)
+		c.Close()
+		return err
+	}
+	return c.Send(&closeAfterSend{msg: msg})
+}
+
+// sendLoop收到closeAfterSend, 发送完msg后关闭连接
+type closeAfterSend struct {
+	msg interface{}
+}
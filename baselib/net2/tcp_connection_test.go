@@ -153,3 +153,27 @@ func TestConnectionError(t *testing.T) {
 		t.Errorf(`expected ConnectionClosedError or ConnectionBlockedError but get --> %s`, err.Error())
 	}
 }
+
+func TestSendAndClose(t *testing.T) {
+	sendChan := make(chan interface{}, 4)
+	conn := NewTcpConnection("", nil, 4, &TestConnCodec{sender: sendChan}, nil)
+
+	conn.Send("msg0")
+	if err := conn.SendAndClose("msg1"); err != nil {
+		t.Fatal(err)
+	}
+
+	var result []interface{}
+	for m := range sendChan {
+		result = append(result, m)
+	}
+	if len(result) != 2 || result[0] != "msg0" || result[1] != "msg1" {
+		t.Fatalf("expect [msg0 msg1], got %v", result)
+	}
+	if !conn.IsClosed() {
+		t.Fatal("expect closed")
+	}
+	if err := conn.Send("msg2"); err != ConnectionClosedError {
+		t.Fatalf("expect ConnectionClosedError, got %v", err)
+	}
+}
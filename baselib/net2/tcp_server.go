@@ -41,6 +41,7 @@ type TcpServer struct {
 	listener          net.Listener
 	serverName        string
 	protoName         string
+	protocol          Protocol
 	sendChanSize      int
 	callback          TcpConnectionCallback
 	running           bool
@@ -52,6 +53,7 @@ type TcpServerArgs struct {
 	Listener                net.Listener
 	ServerName              string
 	ProtoName               string
+	Protocol                Protocol // 不为nil时使用Protocol创建codec, 忽略ProtoName
 	SendChanSize            int
 	ConnectionCallback      TcpConnectionCallback
 	MaxConcurrentConnection int
@@ -66,6 +68,7 @@ func NewTcpServer(args TcpServerArgs) *TcpServer {
 		listener:          args.Listener,
 		serverName:        args.ServerName,
 		protoName:         args.ProtoName,
+		protocol:          args.Protocol,
 		sendChanSize:      args.SendChanSize,
 		callback:          args.ConnectionCallback,
 		running:           false,
@@ -87,7 +90,7 @@ func (s *TcpServer) Serve() {
 			return
 		}
 
-		codec, err := NewCodecByName(s.protoName, conn)
+		codec, err := s.newCodec(conn)
 		if err != nil {
 			glog.Error(err)
 			conn.Close()
@@ -117,7 +120,7 @@ func (s *TcpServer) Serve2() {
 		}
 
 		conn2 := NewBufferedConn(conn)
-		codec, err := NewCodecByName(s.protoName, conn2)
+		codec, err := s.newCodec(conn2)
 		if err != nil {
 			glog.Error(err)
 			conn.Close()
@@ -131,6 +134,13 @@ func (s *TcpServer) Serve2() {
 	s.running = false
 }
 
+func (s *TcpServer) newCodec(conn net.Conn) (Codec, error) {
+	if s.protocol != nil {
+		return s.protocol.NewCodec(conn)
+	}
+	return NewCodecByName(s.protoName, conn)
+}
+
 func Accept(listener net.Listener) (net.Conn, error) {
 	var tempDelay time.Duration
 	for {
@@ -0,0 +1,163 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net2
+
+import (
+	"fmt"
+	"github.com/golang/glog"
+	"golang.org/x/net/websocket"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+)
+
+const (
+	defaultWebSocketPath        = "/apiws"
+	defaultWebSocketSubProtocol = "binary"
+)
+
+type WebSocketConfig struct {
+	Path        string
+	SubProtocol string
+}
+
+// WebSocketListener接收websocket连接，并以net.Conn的形式交给TcpServer，
+// 每个binary frame的数据按字节流处理，上层codec无需区分tcp和websocket
+type WebSocketListener struct {
+	listener    net.Listener
+	server      *http.Server
+	subProtocol string
+	acceptChan  chan net.Conn
+	closeChan   chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewWebSocketListener(lsn net.Listener, conf WebSocketConfig) *WebSocketListener {
+	if conf.Path == "" {
+		conf.Path = defaultWebSocketPath
+	}
+	if conf.SubProtocol == "" {
+		conf.SubProtocol = defaultWebSocketSubProtocol
+	}
+
+	l := &WebSocketListener{
+		listener:    lsn,
+		subProtocol: conf.SubProtocol,
+		acceptChan:  make(chan net.Conn),
+		closeChan:   make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(conf.Path, websocket.Server{
+		Handshake: l.handshake,
+		Handler:   l.serveWebSocket,
+	})
+	l.server = &http.Server{Handler: mux}
+
+	go func() {
+		err := l.server.Serve(lsn)
+		if err != nil && err != http.ErrServerClosed {
+			glog.Errorf("websocket listener serve error: %v", err)
+		}
+		l.Close()
+	}()
+
+	return l
+}
+
+// 不检查Origin，只接受约定的子协议
+func (l *WebSocketListener) handshake(config *websocket.Config, req *http.Request) error {
+	for _, p := range config.Protocol {
+		if p == l.subProtocol {
+			config.Protocol = []string{p}
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid websocket subprotocol: %v", config.Protocol)
+}
+
+func (l *WebSocketListener) serveWebSocket(ws *websocket.Conn) {
+	ws.PayloadType = websocket.BinaryFrame
+	conn := newWebSocketConn(ws)
+
+	select {
+	case l.acceptChan <- conn:
+	case <-l.closeChan:
+		return
+	}
+
+	// websocket.Server在Handler返回后关闭连接，由TcpConnection负责关闭
+	<-conn.closeChan
+}
+
+func (l *WebSocketListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.acceptChan:
+		return conn, nil
+	case <-l.closeChan:
+		return nil, io.EOF
+	}
+}
+
+func (l *WebSocketListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closeChan)
+		err = l.server.Close()
+	})
+	return err
+}
+
+func (l *WebSocketListener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////
+type webSocketConn struct {
+	*websocket.Conn
+	remoteAddr net.Addr
+	closeChan  chan struct{}
+	closeOnce  sync.Once
+}
+
+func newWebSocketConn(ws *websocket.Conn) *webSocketConn {
+	// 服务端websocket.Conn的RemoteAddr()返回的是Origin，使用http请求的对端地址
+	var remoteAddr net.Addr = ws.RemoteAddr()
+	if addr, err := net.ResolveTCPAddr("tcp", ws.Request().RemoteAddr); err == nil {
+		remoteAddr = addr
+	}
+
+	return &webSocketConn{
+		Conn:       ws,
+		remoteAddr: remoteAddr,
+		closeChan:  make(chan struct{}),
+	}
+}
+
+func (c *webSocketConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+func (c *webSocketConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() {
+		close(c.closeChan)
+	})
+	return err
+}
@@ -0,0 +1,108 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net2
+
+import (
+	"bytes"
+	"golang.org/x/net/websocket"
+	"io"
+	"net"
+	"testing"
+)
+
+func newTestWebSocketListener(t *testing.T) (*WebSocketListener, string) {
+	lsn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewWebSocketListener(lsn, WebSocketConfig{}), lsn.Addr().String()
+}
+
+func dialTestWebSocket(addr, protocol string) (*websocket.Conn, error) {
+	config, err := websocket.NewConfig("ws://"+addr+defaultWebSocketPath, "http://localhost/")
+	if err != nil {
+		return nil, err
+	}
+	config.Protocol = []string{protocol}
+	ws, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	ws.PayloadType = websocket.BinaryFrame
+	return ws, nil
+}
+
+func TestWebSocketListener(t *testing.T) {
+	l, addr := newTestWebSocketListener(t)
+	defer l.Close()
+
+	ws, err := dialTestWebSocket(addr, defaultWebSocketSubProtocol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if host, _, _ := net.SplitHostPort(conn.RemoteAddr().String()); host != "127.0.0.1" {
+		t.Fatalf("invalid remote addr: %s", conn.RemoteAddr())
+	}
+
+	// 两个frame的数据按字节流读取
+	ws.Write([]byte{0xef, 0x01, 0x02})
+	ws.Write([]byte{0x03, 0x04})
+
+	b := make([]byte, 5)
+	if _, err = io.ReadFull(NewBufferedConn(conn), b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, []byte{0xef, 0x01, 0x02, 0x03, 0x04}) {
+		t.Fatalf("invalid data: %v", b)
+	}
+
+	conn.Write([]byte{0x05, 0x06})
+	if _, err = io.ReadFull(ws, b[:2]); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b[:2], []byte{0x05, 0x06}) {
+		t.Fatalf("invalid data: %v", b[:2])
+	}
+}
+
+func TestWebSocketListenerInvalidSubProtocol(t *testing.T) {
+	l, addr := newTestWebSocketListener(t)
+	defer l.Close()
+
+	if ws, err := dialTestWebSocket(addr, "text"); err == nil {
+		ws.Close()
+		t.Fatal("invalid subprotocol must be rejected")
+	}
+}
+
+func TestWebSocketListenerClose(t *testing.T) {
+	l, _ := newTestWebSocketListener(t)
+	l.Close()
+
+	if _, err := l.Accept(); err != io.EOF {
+		t.Fatalf("accept after close: %v", err)
+	}
+}
@@ -17,49 +17,43 @@
 
 package nsq_client
 
-/*
 import (
 	"errors"
+	"sync"
 	"time"
-	"github.com/nsqio/go-nsq"
+
 	"github.com/golang/glog"
+	"github.com/nsqio/go-nsq"
 )
 
-
-// 使用者可以使用快速的配置进行事件的消费
-// 配置
-// 生产者
-// [producer]
-// nsqd地址
-// nsqd=127.0.0.1:4151
-// 消费者
+// 消费者配置
 // [consumer]
 // nsqd连接地址
-// nsqd=127.0.0.1:4151
+// nsqdAddrs = ["127.0.0.1:4150"]
 // nsqlookupd连接地址
-// nsqlookupd=127.0.0.1:4161,127.0.0.2:4161,127.0.0.3:4161
-// max_flight=100
-// concurrent=20
-// channel=chan1
-// max_retry=5
-
+// nsqlookupdAddrs = ["127.0.0.1:4161", "127.0.0.2:4161", "127.0.0.3:4161"]
+// maxInFlight = 100
+// concurrent = 20
+// channel = "chan1"
+// maxAttempts = 5
+// requeueDelay = "1s"
+// maxRequeueDelay = "1m"
 type ConsumerConfig struct {
-	NsqdAddr string
-	NsqlookupdAddr string
-	MaxFlight int
-	Concurrent int
-	Channel int
-	MaxRetry int
+	NsqdAddrs       []string
+	NsqlookupdAddrs []string
+	MaxInFlight     int
+	Concurrent      int
+	Channel         string
+	RetryConfig
 }
 
-// consumer 消费者结构体
-type consumer struct {
-	isInit      bool
-	// Debug       bool
+// nsqConsumer 消费者结构体
+type nsqConsumer struct {
+	mu          sync.Mutex
 	channelName string
 	concurrent  int
 	maxInFlight int
-	maxAttempt  uint16
+	retry       RetryConfig
 	//addr 连接地址
 	nsqdAddr       []string
 	nsqLookupdAddr []string
@@ -73,50 +67,108 @@ type topicInfo struct {
 	maxInFlight   int
 	concurrentNum int
 	config        *nsq.Config
-	handler       nsq.HandlerFunc
+	handler       HandlerFunc
 	failHandler   FailMessageFunc
 	consumer      *nsq.Consumer
+	closeChan     chan struct{}
 }
 
-// 失败消息处理函数类型
-type FailMessageFunc func(message FailMessage) (err error)
+func NewNsqConsumer(config *ConsumerConfig) (Consumer, error) {
+	if config.Channel == "" {
+		return nil, errors.New("config channel not found")
+	}
+	if len(config.NsqdAddrs) == 0 && len(config.NsqlookupdAddrs) == 0 {
+		return nil, errors.New("nsqd addr or nsqlookupd address required")
+	}
 
-func (f FailMessageFunc) HandleFailMessage(message FailMessage) (err error) {
-	err = f(message)
-	return
+	c := &nsqConsumer{
+		channelName:    config.Channel,
+		concurrent:     config.Concurrent,
+		maxInFlight:    config.MaxInFlight,
+		retry:          config.RetryConfig,
+		nsqdAddr:       config.NsqdAddrs,
+		nsqLookupdAddr: config.NsqlookupdAddrs,
+		topics:         make(map[string]*topicInfo),
+	}
+	if c.maxInFlight < 1 {
+		c.maxInFlight = 1
+	}
+	if c.concurrent < 1 {
+		c.concurrent = 1
+	}
+	if c.maxInFlight < c.concurrent {
+		return nil, errors.New("max_in_flight should exceed than concurrent")
+	}
+	return c, nil
 }
 
-// 失败消息处理接口,继承了该接口的接口都会调用该接口
-type FailMessageHandler interface {
-	HandleFailMessage(message FailMessage) (err error)
+func (c *nsqConsumer) getTopic(topic string) *topicInfo {
+	t, ok := c.topics[topic]
+	if !ok {
+		t = &topicInfo{
+			topic:         topic,
+			concurrentNum: c.concurrent,
+			maxInFlight:   c.maxInFlight,
+			config:        nsq.NewConfig(),
+			closeChan:     make(chan struct{}),
+		}
+		t.config.MaxAttempts = c.retry.maxAttempts()
+		t.config.MaxInFlight = c.maxInFlight
+		t.config.DefaultRequeueDelay = c.retry.requeueDelay()
+		t.config.MaxRequeueDelay = c.retry.maxRequeueDelay()
+		c.topics[topic] = t
+	}
+	return t
 }
 
-type FailMessage struct {
-	Body      []byte
-	Attempt   uint16
-	Timestamp int64
-	MessageID string
-	FailMsg   string
+// AddHandler 添加handler, 需要在Run之前调用
+func (c *nsqConsumer) AddHandler(topic string, handler HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.getTopic(topic).handler = handler
+}
+
+// AddFailHandler 最后一次投递仍然失败时调用, 之后消息会被确认
+func (c *nsqConsumer) AddFailHandler(topic string, handler FailMessageFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.getTopic(topic).failHandler = handler
+}
+
+// 自定义 handler
+func (t *topicInfo) handleMessage(nm *nsq.Message) (err error) {
+	m := &Message{
+		ID:        string(nm.ID[:]),
+		Body:      nm.Body,
+		Attempts:  nm.Attempts,
+		Timestamp: nm.Timestamp,
+	}
+	err = t.handler(m)
+	if err != nil && nm.Attempts >= t.config.MaxAttempts {
+		glog.Errorf("nsq consumer - message failed: {topic: %s, id: %s, attempts: %d, err: %v}", t.topic, m.ID, nm.Attempts, err)
+		if t.failHandler != nil {
+			t.failHandler(FailMessage{
+				MessageID: m.ID,
+				Body:      nm.Body,
+				Attempt:   nm.Attempts,
+				Timestamp: nm.Timestamp,
+				FailMsg:   err.Error(),
+			})
+		}
+		err = nil
+	}
+	return
 }
 
 // Connect 连接
 func (t *topicInfo) Connect(channelName string, nsqdAddr []string, nsqlookupdAddr []string) {
-	if len(nsqdAddr) == 0 && len(nsqlookupdAddr) == 0 {
-		glog.Warningln("nsqd和nsqlookupd地址皆为空，跳过连接,topic:", t.topic)
-		return
-	}
 	var (
 		retryNum     = 0
 		sleepSeconds = 0
 		err          error
 	)
-	t.consumer, err = nsq.NewConsumer(t.topic, channelName, t.config)
-	if err != nil {
-		glog.Errorf("新建nsq consumer失败，err:%s,topic:%s,channel:%s", err.Error(), t.topic, channelName)
-		return
-	}
-	t.consumer.ChangeMaxInFlight(t.maxInFlight)
-	t.consumer.AddConcurrentHandlers(nsq.Handler(t.handler), t.concurrentNum)
 	// 不断进行重连，直到连接成功
 	for {
 		if len(nsqlookupdAddr) > 0 {
@@ -139,191 +191,54 @@ func (t *topicInfo) Connect(channelName string, nsqdAddr []string, nsqlookupdAdd
 			if retryNum%6 == 0 {
 				sleepSeconds = 30
 			}
-			time.Sleep(time.Duration(sleepSeconds) * time.Second)
-			continue
+			select {
+			case <-time.After(time.Duration(sleepSeconds) * time.Second):
+				continue
+			case <-t.closeChan:
+				return
+			}
 		}
 
-		glog.Infof("连接nsqlookupd(addr:%v)/nsqd(%v)成功", nsqlookupdAddr, nsqdAddr)
+		glog.Infof("连接nsqlookupd(addr:%v)/nsqd(%v)成功,topic:%s", nsqlookupdAddr, nsqdAddr, t.topic)
 		break
 	}
-
-	err = nil
-	return
-}
-
-// newConsumer 新建消费者
-func newConsumer() consumer {
-	return consumer{
-		nsqdAddr:       make([]string, 0),
-		nsqLookupdAddr: make([]string, 0),
-		topics:         make(map[string]*topicInfo),
-	}
 }
 
-// AddHandler 添加handler
-func (c *consumer) AddHandler(topic string, handler nsq.HandlerFunc) {
-	var (
-		t  = &topicInfo{}
-		ok bool
-	)
-	if t, ok = c.topics[topic]; !ok {
-		t = &topicInfo{}
-		t.concurrentNum = c.concurrent
-		t.maxInFlight = c.maxInFlight
-		t.config = nsq.NewConfig()
-		t.config.MaxAttempts = c.maxAttempt
-	}
-
-	t.topic = topic
-	// 自定义 handler
-	t.handler = func(nm *nsq.Message) (err error) {
-		err = handler(nm)
-		if err != nil && Consumer.topics[topic].config.MaxAttempts > 0 && Consumer.topics[topic].config.MaxAttempts == nm.Attempts && Consumer.topics[topic].failHandler != nil {
-			messageID := make([]byte, 0)
-			for _, v := range nm.ID {
-				messageID = append(messageID, v)
-			}
-			Consumer.topics[topic].failHandler(FailMessage{
-				MessageID: string(messageID),
-				Body:      nm.Body,
-				Timestamp: nm.Timestamp,
-				FailMsg:   err.Error(),
-			})
-			err = nil
+// Run 运行
+func (c *nsqConsumer) Run() (err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, t := range c.topics {
+		if t.handler == nil {
+			err = errors.New("handler not found, topic: " + t.topic)
+			return
 		}
-		return
-	}
-	c.topics[topic] = t
-}
-
-func (c *consumer) AddFailHandler(topic string, handler FailMessageFunc) {
-	var (
-		t  = &topicInfo{}
-		ok bool
-	)
-	if t, ok = c.topics[topic]; !ok {
-		t = &topicInfo{}
-		t.concurrentNum = c.concurrent
-		t.maxInFlight = c.maxInFlight
-		t.config = nsq.NewConfig()
-		t.config.MaxAttempts = c.maxAttempt
-	}
-
-	t.topic = topic
-	t.failHandler = handler
-	c.topics[topic] = t
-}
-
-// SetAddr 设置consumer地址
-func (c *consumer) SetNsqlookupdAddr(node, addr string) {
-	exist := false
-	for _, v := range c.nsqLookupdAddr {
-		if v == addr {
-			exist = true
-			break
+		t.consumer, err = nsq.NewConsumer(t.topic, c.channelName, t.config)
+		if err != nil {
+			glog.Errorf("新建nsq consumer失败，err:%s,topic:%s,channel:%s", err.Error(), t.topic, c.channelName)
+			return
 		}
+		t.consumer.ChangeMaxInFlight(t.maxInFlight)
+		// 连接前添加handler, 否则Stop时StopChan不会关闭
+		t.consumer.AddConcurrentHandlers(nsq.HandlerFunc(t.handleMessage), t.concurrentNum)
+		go t.Connect(c.channelName, c.nsqdAddr, c.nsqLookupdAddr)
 	}
-	if !exist {
-		c.nsqLookupdAddr = append(c.nsqLookupdAddr, addr)
-	}
+	return
 }
 
-// SetMultiNsqlookupdAddr 设置多个consumer地址
-func (c *consumer) SetMultiNsqlookupdAddr(node string, addrArr []string) {
-	for _, v := range addrArr {
-		c.SetNsqlookupdAddr(node, v)
-	}
-}
+// Stop 停止, 正在处理的消息处理完后返回
+func (c *nsqConsumer) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-// SetNsqdAddr
-func (c *consumer) SetNsqdAddr(node, addr string) {
-	exist := false
-	for _, v := range c.nsqdAddr {
-		if v == addr {
-			exist = true
-			break
+	for _, t := range c.topics {
+		if t.consumer == nil {
+			continue
 		}
-	}
-	if !exist {
-		c.nsqdAddr = append(c.nsqdAddr, addr)
-	}
-}
-
-// SetMultiNsqdAddr
-func (c *consumer) SetMultiNsqdAddr(node string, addrArr []string) {
-	for _, v := range addrArr {
-		c.SetNsqdAddr(node, v)
+		close(t.closeChan)
+		t.consumer.Stop()
+		<-t.consumer.StopChan
+		t.consumer = nil
 	}
 }
-
-// StopAll 停止
-func (c *consumer) Stop() {
-	for k := range c.topics {
-		c.topics[k].consumer.Stop()
-	}
-}
-
-// Run 运行
-func (c *consumer) Run() (err error) {
-	if !c.isInit {
-		err = errors.New("consumer not init")
-		return
-	}
-	if len(c.nsqdAddr) == 0 && len(c.nsqLookupdAddr) == 0 {
-		err = errors.New("nsqd addr or nsqlookupd address required")
-		return
-	}
-	for _, topicInfo := range c.topics {
-		topicInfo.config.MaxAttempts = c.maxAttempt
-		topicInfo.config.MaxInFlight = c.maxInFlight
-		go topicInfo.Connect(c.channelName, c.nsqdAddr, c.nsqLookupdAddr)
-	}
-
-	return
-}
-
-// Init 初始化
-func (c *consumer) Initialize(config *ConsumerConfig) (err error) {
-	//if nsqdAddr, ok := configSection["nsqd"]; ok {
-	//	Consumer.nsqdAddr = strings.Split(nsqdAddr, ",")
-	//}
-	//if nsqlookupdArr, ok := configSection["nsqlookupd"]; ok {
-	//	Consumer.nsqLookupdAddr = strings.Split(nsqlookupdArr, ",")
-	//}
-	//if maxInFlight, _ := strconv.Atoi(configSection["maxInFlight"]); maxInFlight > 0 {
-	//	Consumer.maxInFlight = maxInFlight
-	//}
-	//if concurrent, _ := strconv.Atoi(configSection["concurrent"]); concurrent > 0 {
-	//	Consumer.concurrent = concurrent
-	//}
-	//if channelName, _ := configSection["channelName"]; channelName != "" {
-	//	Consumer.channelName = channelName
-	//}
-	//if Consumer.channelName == "" {
-	//	err = errors.New("config channelName not found")
-	//	return
-	//}
-	//if maxAttempt, _ := strconv.Atoi(configSection["max_attempt"]); maxAttempt > 0 {
-	//	Consumer.maxAttempt = uint16(maxAttempt)
-	//}
-	//
-	//if Consumer.maxInFlight < 1 {
-	//	Consumer.maxInFlight = 1
-	//}
-	//if Consumer.concurrent < 1 {
-	//	Consumer.concurrent = 1
-	//}
-	//
-	//if Consumer.maxInFlight < Consumer.concurrent {
-	//	err = errors.New("max_in_flight should exceed than concurrent")
-	//	return
-	//}
-
-	Consumer.isInit = true
-	return
-}
-
-var (
-	Consumer = newConsumer()
-)
-*/
@@ -0,0 +1,197 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nsq_client
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+const kDefaultMemoryQueueSize = 1024
+
+// 进程内队列, 同时实现Producer和Consumer, 用于测试或者没有部署nsqd的单机环境
+// 每个topic一个goroutine按顺序投递, 失败时按RetryConfig延迟重试(阻塞后面的消息), 进程退出时未投递的消息丢失
+type MemoryQueue struct {
+	mu        sync.Mutex
+	size      int
+	retry     RetryConfig
+	nextId    int64
+	running   bool
+	stopped   bool
+	topics    map[string]*memoryTopic
+	closeChan chan struct{}
+	wg        sync.WaitGroup
+}
+
+type memoryTopic struct {
+	topic       string
+	queue       chan *Message
+	handler     HandlerFunc
+	failHandler FailMessageFunc
+	started     bool
+}
+
+// size为每个topic最多缓存的消息数, 超过时Publish返回错误
+func NewMemoryQueue(size int, retry RetryConfig) *MemoryQueue {
+	if size <= 0 {
+		size = kDefaultMemoryQueueSize
+	}
+	return &MemoryQueue{
+		size:      size,
+		retry:     retry,
+		topics:    make(map[string]*memoryTopic),
+		closeChan: make(chan struct{}),
+	}
+}
+
+func (q *MemoryQueue) getTopic(topic string) *memoryTopic {
+	t, ok := q.topics[topic]
+	if !ok {
+		t = &memoryTopic{topic: topic, queue: make(chan *Message, q.size)}
+		q.topics[topic] = t
+	}
+	return t
+}
+
+func (q *MemoryQueue) Publish(topic string, body []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.nextId++
+	m := &Message{
+		ID:        strconv.FormatInt(q.nextId, 10),
+		Body:      body,
+		Timestamp: time.Now().UnixNano(),
+	}
+
+	select {
+	case q.getTopic(topic).queue <- m:
+		return nil
+	default:
+		return fmt.Errorf("memory queue - topic %s is full", topic)
+	}
+}
+
+func (q *MemoryQueue) AddHandler(topic string, handler HandlerFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := q.getTopic(topic)
+	t.handler = handler
+	if q.running {
+		q.start(t)
+	}
+}
+
+func (q *MemoryQueue) AddFailHandler(topic string, handler FailMessageFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.getTopic(topic).failHandler = handler
+}
+
+func (q *MemoryQueue) Run() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return fmt.Errorf("memory queue - stopped")
+	}
+	q.running = true
+	for _, t := range q.topics {
+		q.start(t)
+	}
+	return nil
+}
+
+// 调用时需要持有q.mu
+func (q *MemoryQueue) start(t *memoryTopic) {
+	if t.started || t.handler == nil {
+		return
+	}
+	t.started = true
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		for {
+			select {
+			case m := <-t.queue:
+				if !q.deliver(t, m) {
+					return
+				}
+			case <-q.closeChan:
+				return
+			}
+		}
+	}()
+}
+
+// 返回false表示队列已停止
+func (q *MemoryQueue) deliver(t *memoryTopic, m *Message) bool {
+	q.mu.Lock()
+	handler, failHandler := t.handler, t.failHandler
+	q.mu.Unlock()
+
+	for {
+		m.Attempts++
+		err := handler(m)
+		if err == nil {
+			return true
+		}
+
+		if m.Attempts >= q.retry.maxAttempts() {
+			glog.Errorf("memory queue - message failed: {topic: %s, id: %s, attempts: %d, err: %v}", t.topic, m.ID, m.Attempts, err)
+			if failHandler != nil {
+				failHandler(FailMessage{
+					MessageID: m.ID,
+					Body:      m.Body,
+					Attempt:   m.Attempts,
+					Timestamp: m.Timestamp,
+					FailMsg:   err.Error(),
+				})
+			}
+			return true
+		}
+
+		select {
+		case <-time.After(q.retry.backoff(m.Attempts)):
+		case <-q.closeChan:
+			return false
+		}
+	}
+}
+
+// 等待正在处理的消息处理完
+func (q *MemoryQueue) Stop() {
+	q.mu.Lock()
+	if !q.running {
+		q.mu.Unlock()
+		return
+	}
+	q.running = false
+	q.stopped = true
+	close(q.closeChan)
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
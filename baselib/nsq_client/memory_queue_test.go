@@ -0,0 +1,103 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nsq_client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nebulaim/telegramd/baselib/base"
+)
+
+func TestMemoryQueueRetry(t *testing.T) {
+	q := NewMemoryQueue(16, RetryConfig{
+		MaxAttempts:     3,
+		RequeueDelay:    base.Duration(time.Millisecond),
+		MaxRequeueDelay: base.Duration(2 * time.Millisecond),
+	})
+	defer q.Stop()
+
+	var (
+		received = make(chan string, 16)
+		failed   = make(chan FailMessage, 16)
+	)
+	q.AddHandler("test", func(m *Message) error {
+		body := string(m.Body)
+		switch {
+		case body == "retry" && m.Attempts < 2:
+			return errors.New("retry")
+		case body == "fail":
+			return errors.New("fail")
+		}
+		received <- body
+		return nil
+	})
+	q.AddFailHandler("test", func(m FailMessage) error {
+		failed <- m
+		return nil
+	})
+
+	// Run之前发布的消息也要投递
+	for _, body := range []string{"a", "retry", "fail", "b"} {
+		if err := q.Publish("test", []byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Run()
+
+	for _, expected := range []string{"a", "retry", "b"} {
+		select {
+		case body := <-received:
+			if body != expected {
+				t.Fatalf("expected %s, got %s", expected, body)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for %s", expected)
+		}
+	}
+
+	select {
+	case m := <-failed:
+		if string(m.Body) != "fail" || m.Attempt != 3 {
+			t.Fatalf("bad fail message: %v", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("fail handler not called")
+	}
+}
+
+func TestMemoryQueueFull(t *testing.T) {
+	q := NewMemoryQueue(1, RetryConfig{})
+	if err := q.Publish("test", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Publish("test", []byte("b")); err == nil {
+		t.Fatal("expected queue full error")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	c := RetryConfig{RequeueDelay: base.Duration(time.Second), MaxRequeueDelay: base.Duration(3 * time.Second)}
+	if d := c.backoff(2); d != 2*time.Second {
+		t.Fatalf("backoff(2) = %v", d)
+	}
+	if d := c.backoff(5); d != 3*time.Second {
+		t.Fatalf("backoff(5) = %v", d)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nsq_client
+
+import (
+	"time"
+
+	"github.com/nebulaim/telegramd/baselib/base"
+)
+
+// 生产者和消费者接口, nsq和进程内队列(MemoryQueue)都实现了这两个接口,
+// 测试时可以用MemoryQueue代替nsqd
+type Producer interface {
+	Publish(topic string, body []byte) error
+	Stop()
+}
+
+type Consumer interface {
+	AddHandler(topic string, handler HandlerFunc)
+	AddFailHandler(topic string, handler FailMessageFunc)
+	Run() error
+	Stop()
+}
+
+type Message struct {
+	ID        string
+	Body      []byte
+	Attempts  uint16 // 第几次投递, 从1开始
+	Timestamp int64
+}
+
+// 返回error时消息延迟后重新投递, 超过最大次数后交给FailMessageFunc
+type HandlerFunc func(message *Message) error
+
+// 失败消息处理函数类型
+type FailMessageFunc func(message FailMessage) (err error)
+
+func (f FailMessageFunc) HandleFailMessage(message FailMessage) (err error) {
+	err = f(message)
+	return
+}
+
+// 失败消息处理接口,继承了该接口的接口都会调用该接口
+type FailMessageHandler interface {
+	HandleFailMessage(message FailMessage) (err error)
+}
+
+type FailMessage struct {
+	Body      []byte
+	Attempt   uint16
+	Timestamp int64
+	MessageID string
+	FailMsg   string
+}
+
+const (
+	kDefaultMaxAttempts     = 5
+	kDefaultRequeueDelay    = time.Second
+	kDefaultMaxRequeueDelay = time.Minute
+)
+
+// 重试策略: 第n次失败后延迟n*RequeueDelay重新投递, 最多MaxRequeueDelay
+type RetryConfig struct {
+	MaxAttempts     uint16
+	RequeueDelay    base.Duration
+	MaxRequeueDelay base.Duration
+}
+
+func (c RetryConfig) maxAttempts() uint16 {
+	if c.MaxAttempts == 0 {
+		return kDefaultMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+func (c RetryConfig) requeueDelay() time.Duration {
+	if c.RequeueDelay <= 0 {
+		return kDefaultRequeueDelay
+	}
+	return time.Duration(c.RequeueDelay)
+}
+
+func (c RetryConfig) maxRequeueDelay() time.Duration {
+	if c.MaxRequeueDelay <= 0 {
+		return kDefaultMaxRequeueDelay
+	}
+	return time.Duration(c.MaxRequeueDelay)
+}
+
+// 和go-nsq的Requeue(-1)算法一致
+func (c RetryConfig) backoff(attempts uint16) time.Duration {
+	delay := c.requeueDelay() * time.Duration(attempts)
+	if max := c.maxRequeueDelay(); delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,225 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rate_limit
+
+import (
+	"expvar"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/gomodule/redigo/redis"
+	"github.com/nebulaim/telegramd/baselib/base"
+	"github.com/nebulaim/telegramd/baselib/redis_client"
+	"github.com/nebulaim/telegramd/baselib/sync2"
+	"time"
+)
+
+// 限流维度
+const (
+	FloodKeyUserId    = "user_id"
+	FloodKeyAuthKeyId = "auth_key_id"
+	FloodKeyIP        = "ip"
+	FloodKeyPhone     = "phone"
+
+	// 匹配所有方法
+	FloodMethodAll = "*"
+)
+
+// Period内每个user_id/auth_key_id/ip/phone最多调用Limit次method
+type FloodRule struct {
+	Method string // TL方法名, 如auth.sendCode, *匹配所有方法
+	Key    string
+	Limit  int
+	Period base.Duration
+}
+
+func (r *FloodRule) String() string {
+	return fmt.Sprintf("{method: %s, key: %s, limit: %d, period: %v}", r.Method, r.Key, r.Limit, time.Duration(r.Period))
+}
+
+// 计数存在redis里, 所有节点共享, 没有规则时不限制
+type FloodLimiterConfig struct {
+	Redis string // redis_client的名字
+	Rules []FloodRule
+}
+
+type FloodKeys struct {
+	UserId    int32
+	AuthKeyId int64
+	IP        string
+	Phone     string
+}
+
+func (k *FloodKeys) value(key string) string {
+	switch key {
+	case FloodKeyUserId:
+		if k.UserId != 0 {
+			return fmt.Sprint(k.UserId)
+		}
+	case FloodKeyAuthKeyId:
+		if k.AuthKeyId != 0 {
+			return fmt.Sprint(k.AuthKeyId)
+		}
+	case FloodKeyIP:
+		return k.IP
+	case FloodKeyPhone:
+		return k.Phone
+	}
+	return ""
+}
+
+// 计数器, 第一次计数时设置period秒后过期, 返回当前计数和剩余秒数
+type FloodCounter interface {
+	Incr(key string, period int) (count int, ttl int, err error)
+}
+
+type FloodLimiterStats struct {
+	Allowed sync2.AtomicInt64
+	Limited sync2.AtomicInt64 // 返回FLOOD_WAIT的次数
+	Errors  sync2.AtomicInt64 // 计数失败的次数, 失败时放行
+}
+
+type FloodLimiter struct {
+	name    string
+	rules   map[string][]*FloodRule
+	counter FloodCounter
+	stats   FloodLimiterStats
+}
+
+func NewFloodLimiter(name string, conf FloodLimiterConfig) (*FloodLimiter, error) {
+	var counter FloodCounter
+	if len(conf.Rules) > 0 {
+		pool := redis_client.GetRedisClient(conf.Redis)
+		if pool == nil {
+			return nil, fmt.Errorf("not found redis: %s", conf.Redis)
+		}
+		counter = &redisFloodCounter{pool: pool}
+	}
+	return newFloodLimiter(name, conf, counter)
+}
+
+func newFloodLimiter(name string, conf FloodLimiterConfig, counter FloodCounter) (*FloodLimiter, error) {
+	l := &FloodLimiter{
+		name:    name,
+		rules:   make(map[string][]*FloodRule),
+		counter: counter,
+	}
+
+	for i := range conf.Rules {
+		r := &conf.Rules[i]
+		switch r.Key {
+		case FloodKeyUserId, FloodKeyAuthKeyId, FloodKeyIP, FloodKeyPhone:
+		default:
+			return nil, fmt.Errorf("invalid flood rule key: %v", r)
+		}
+		if r.Method == "" || r.Limit <= 0 || time.Duration(r.Period) < time.Second {
+			return nil, fmt.Errorf("invalid flood rule: %v", r)
+		}
+		l.rules[r.Method] = append(l.rules[r.Method], r)
+	}
+
+	if name != "" && expvar.Get("rate_limit."+name) == nil {
+		expvar.Publish("rate_limit."+name, expvar.Func(func() interface{} {
+			return l.Snapshot()
+		}))
+	}
+	return l, nil
+}
+
+func (l *FloodLimiter) Enabled() bool {
+	return len(l.rules) > 0
+}
+
+// 检查并计数, 超过限制返回需要等待的秒数, 0为放行
+// 同时命中多条规则时返回最长的等待时间
+func (l *FloodLimiter) Check(method string, keys *FloodKeys) int {
+	if !l.Enabled() {
+		return 0
+	}
+
+	wait := 0
+	for _, rules := range [][]*FloodRule{l.rules[method], l.rules[FloodMethodAll]} {
+		for _, r := range rules {
+			v := keys.value(r.Key)
+			if v == "" {
+				continue
+			}
+
+			period := int(time.Duration(r.Period) / time.Second)
+			// 同一个方法和key可以配置多个周期(如5/1m和50/1d), 每条规则单独计数
+			count, ttl, err := l.counter.Incr(fmt.Sprintf("flood:%s:%s:%d:%s", r.Method, r.Key, period, v), period)
+			if err != nil {
+				glog.Errorf("rate_limit.%s - incr error: %v", l.name, err)
+				l.stats.Errors.Add(1)
+				continue
+			}
+			if count <= r.Limit {
+				continue
+			}
+
+			if ttl <= 0 {
+				ttl = period
+			}
+			if ttl > wait {
+				wait = ttl
+			}
+			glog.Warningf("rate_limit.%s - flood: {method: %s, %s: %s, count: %d, rule: %v}", l.name, method, r.Key, v, count, r)
+		}
+	}
+
+	if wait > 0 {
+		l.stats.Limited.Add(1)
+	} else {
+		l.stats.Allowed.Add(1)
+	}
+	return wait
+}
+
+func (l *FloodLimiter) Snapshot() map[string]int64 {
+	return map[string]int64{
+		"allowed": l.stats.Allowed.Get(),
+		"limited": l.stats.Limited.Get(),
+		"errors":  l.stats.Errors.Get(),
+	}
+}
+
+// INCR和EXPIRE在一个脚本里执行, 避免key没有过期时间
+var floodIncrScript = redis.NewScript(1, `
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[1])
+end
+return {count, redis.call('TTL', KEYS[1])}
+`)
+
+type redisFloodCounter struct {
+	pool *redis_client.RedisPool
+}
+
+func (c *redisFloodCounter) Incr(key string, period int) (int, int, error) {
+	conn := c.pool.Get()
+	defer conn.Close()
+
+	values, err := redis.Ints(floodIncrScript.Do(conn, key, period))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(values) != 2 {
+		return 0, 0, fmt.Errorf("invalid reply: %v", values)
+	}
+	return values[0], values[1], nil
+}
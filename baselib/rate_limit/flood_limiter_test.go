@@ -0,0 +1,150 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rate_limit
+
+import (
+	"github.com/nebulaim/telegramd/baselib/base"
+	"testing"
+	"time"
+)
+
+type testFloodCounter struct {
+	clock   *testClock
+	counts  map[string]int
+	expires map[string]time.Time
+}
+
+func newTestFloodCounter() *testFloodCounter {
+	return &testFloodCounter{
+		clock:   &testClock{t: time.Unix(1500000000, 0)},
+		counts:  make(map[string]int),
+		expires: make(map[string]time.Time),
+	}
+}
+
+func (c *testFloodCounter) Incr(key string, period int) (int, int, error) {
+	now := c.clock.now()
+	if !now.Before(c.expires[key]) {
+		c.counts[key] = 0
+		c.expires[key] = now.Add(time.Duration(period) * time.Second)
+	}
+	c.counts[key]++
+	return c.counts[key], int(c.expires[key].Sub(now) / time.Second), nil
+}
+
+func TestFloodLimiter(t *testing.T) {
+	counter := newTestFloodCounter()
+	l, err := newFloodLimiter("", FloodLimiterConfig{Rules: []FloodRule{
+		{Method: "auth.sendCode", Key: FloodKeyPhone, Limit: 2, Period: base.Duration(time.Hour)},
+		{Method: FloodMethodAll, Key: FloodKeyUserId, Limit: 5, Period: base.Duration(time.Minute)},
+	}}, counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := &FloodKeys{AuthKeyId: 1, IP: "1.2.3.4", Phone: "8613588888888"}
+	for i := 0; i < 2; i++ {
+		if wait := l.Check("auth.sendCode", keys); wait != 0 {
+			t.Fatalf("check %d: %d", i, wait)
+		}
+	}
+	if wait := l.Check("auth.sendCode", keys); wait != 3600 {
+		t.Fatalf("invalid wait: %d", wait)
+	}
+	// 其它手机号不受影响
+	if wait := l.Check("auth.sendCode", &FloodKeys{Phone: "8613599999999"}); wait != 0 {
+		t.Fatalf("invalid wait: %d", wait)
+	}
+
+	counter.clock.advance(30 * time.Minute)
+	if wait := l.Check("auth.sendCode", keys); wait != 1800 {
+		t.Fatalf("invalid wait: %d", wait)
+	}
+	counter.clock.advance(30 * time.Minute)
+	if wait := l.Check("auth.sendCode", keys); wait != 0 {
+		t.Fatalf("invalid wait: %d", wait)
+	}
+
+	// 未登录时没有user_id, 不受*规则限制
+	for i := 0; i < 10; i++ {
+		if wait := l.Check("help.getConfig", keys); wait != 0 {
+			t.Fatalf("invalid wait: %d", wait)
+		}
+	}
+
+	// 所有方法共享*规则的计数
+	keys.UserId = 100
+	for i := 0; i < 5; i++ {
+		l.Check("messages.getDialogs", keys)
+	}
+	if wait := l.Check("help.getConfig", keys); wait != 60 {
+		t.Fatalf("invalid wait: %d", wait)
+	}
+
+	s := l.Snapshot()
+	if s["limited"] != 3 || s["errors"] != 0 {
+		t.Fatalf("invalid stats: %v", s)
+	}
+}
+
+func TestFloodLimiterMultiPeriod(t *testing.T) {
+	counter := newTestFloodCounter()
+	l, err := newFloodLimiter("", FloodLimiterConfig{Rules: []FloodRule{
+		{Method: "auth.sendCode", Key: FloodKeyPhone, Limit: 2, Period: base.Duration(time.Minute)},
+		{Method: "auth.sendCode", Key: FloodKeyPhone, Limit: 3, Period: base.Duration(time.Hour)},
+	}}, counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := &FloodKeys{Phone: "8613588888888"}
+	for i := 0; i < 2; i++ {
+		if wait := l.Check("auth.sendCode", keys); wait != 0 {
+			t.Fatalf("check %d: %d", i, wait)
+		}
+	}
+	if wait := l.Check("auth.sendCode", keys); wait != 60 {
+		t.Fatalf("invalid wait: %d", wait)
+	}
+
+	// 每分钟的计数过期后, 每小时的计数还在
+	counter.clock.advance(time.Minute)
+	if wait := l.Check("auth.sendCode", keys); wait != 3540 {
+		t.Fatalf("invalid wait: %d", wait)
+	}
+}
+
+func TestFloodLimiterInvalidConfig(t *testing.T) {
+	rules := []FloodRule{
+		{Method: "auth.sendCode", Key: "email", Limit: 1, Period: base.Duration(time.Minute)},
+		{Method: "", Key: FloodKeyIP, Limit: 1, Period: base.Duration(time.Minute)},
+		{Method: "auth.sendCode", Key: FloodKeyIP, Limit: 0, Period: base.Duration(time.Minute)},
+		{Method: "auth.sendCode", Key: FloodKeyIP, Limit: 1, Period: base.Duration(time.Millisecond)},
+	}
+	for _, r := range rules {
+		if _, err := newFloodLimiter("", FloodLimiterConfig{Rules: []FloodRule{r}}, newTestFloodCounter()); err == nil {
+			t.Errorf("invalid rule must be rejected: %v", &r)
+		}
+	}
+
+	// 没有规则时不需要redis
+	l, err := NewFloodLimiter("", FloodLimiterConfig{})
+	if err != nil || l.Enabled() || l.Check("auth.sendCode", &FloodKeys{Phone: "1"}) != 0 {
+		t.Fatal("empty config must be disabled")
+	}
+}
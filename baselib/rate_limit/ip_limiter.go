@@ -0,0 +1,294 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rate_limit
+
+import (
+	"expvar"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/base"
+	"github.com/nebulaim/telegramd/baselib/sync2"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultIPv4Prefix = 24
+	defaultIPv6Prefix = 64
+
+	// 回收空闲记录的间隔
+	cleanupInterval = time.Minute
+)
+
+// 按来源IP和所在子网限流，Rate和SubnetRate都为0时不限制
+type IPLimiterConfig struct {
+	Rate        float64 // 单个IP每秒允许的次数
+	Burst       int
+	SubnetRate  float64 // 单个子网每秒允许的次数
+	SubnetBurst int
+	IPv4Prefix  int // 子网掩码长度, 默认24
+	IPv6Prefix  int // 默认64
+	// 连续被限流BanThreshold次后封禁BanDuration, 0为不封禁
+	BanThreshold int
+	BanDuration  base.Duration
+	// 不限流的地址(CIDR), 如内网健康检查
+	Whitelist []string
+}
+
+func (c *IPLimiterConfig) String() string {
+	return fmt.Sprintf("{rate: %v, burst: %d, subnet_rate: %v, subnet_burst: %d, ban_threshold: %d, ban_duration: %v}",
+		c.Rate,
+		c.Burst,
+		c.SubnetRate,
+		c.SubnetBurst,
+		c.BanThreshold,
+		time.Duration(c.BanDuration))
+}
+
+type IPLimiterStats struct {
+	Allowed       sync2.AtomicInt64 // 放行次数
+	Limited       sync2.AtomicInt64 // 单个IP超过限制被拒绝的次数
+	SubnetLimited sync2.AtomicInt64 // 所在子网超过限制被拒绝的次数
+	Rejected      sync2.AtomicInt64 // 封禁期间被拒绝的次数
+	Bans          sync2.AtomicInt64 // 累计封禁次数
+}
+
+type limitEntry struct {
+	bucket      *TokenBucket
+	violations  int
+	bannedUntil time.Time
+}
+
+type IPLimiter struct {
+	name      string
+	conf      IPLimiterConfig
+	ipv4Mask  net.IPMask
+	ipv6Mask  net.IPMask
+	whitelist []*net.IPNet
+
+	mu          sync.Mutex
+	ips         map[string]*limitEntry
+	subnets     map[string]*limitEntry
+	lastCleanup time.Time
+	stats       IPLimiterStats
+
+	now func() time.Time
+}
+
+func NewIPLimiter(name string, conf IPLimiterConfig) (*IPLimiter, error) {
+	if conf.Rate < 0 || conf.SubnetRate < 0 {
+		return nil, fmt.Errorf("invalid rate: %v", &conf)
+	}
+	if conf.Rate > 0 && conf.Burst < 1 {
+		conf.Burst = 1
+	}
+	if conf.SubnetRate > 0 && conf.SubnetBurst < 1 {
+		conf.SubnetBurst = 1
+	}
+	if conf.IPv4Prefix == 0 {
+		conf.IPv4Prefix = defaultIPv4Prefix
+	}
+	if conf.IPv6Prefix == 0 {
+		conf.IPv6Prefix = defaultIPv6Prefix
+	}
+	if conf.IPv4Prefix < 0 || conf.IPv4Prefix > 32 || conf.IPv6Prefix < 0 || conf.IPv6Prefix > 128 {
+		return nil, fmt.Errorf("invalid subnet prefix: ipv4 /%d, ipv6 /%d", conf.IPv4Prefix, conf.IPv6Prefix)
+	}
+
+	l := &IPLimiter{
+		name:     name,
+		conf:     conf,
+		ipv4Mask: net.CIDRMask(conf.IPv4Prefix, 32),
+		ipv6Mask: net.CIDRMask(conf.IPv6Prefix, 128),
+		ips:      make(map[string]*limitEntry),
+		subnets:  make(map[string]*limitEntry),
+		now:      time.Now,
+	}
+
+	for _, s := range conf.Whitelist {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid whitelist %s: %v", s, err)
+		}
+		l.whitelist = append(l.whitelist, ipNet)
+	}
+
+	// 通过expvar导出统计数据, 名字为rate_limit.{name}
+	if name != "" && expvar.Get("rate_limit."+name) == nil {
+		expvar.Publish("rate_limit."+name, expvar.Func(func() interface{} {
+			return l.Snapshot()
+		}))
+	}
+	return l, nil
+}
+
+func (l *IPLimiter) Enabled() bool {
+	return l.conf.Rate > 0 || l.conf.SubnetRate > 0
+}
+
+// addr可以是host:port或者ip，无法解析的地址不限流
+func (l *IPLimiter) AllowAddr(addr string) bool {
+	if !l.Enabled() {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		glog.Warningf("rate_limit.%s - invalid addr: %s", l.name, addr)
+		return true
+	}
+	return l.Allow(ip)
+}
+
+func (l *IPLimiter) Allow(ip net.IP) bool {
+	if !l.Enabled() {
+		return true
+	}
+	for _, ipNet := range l.whitelist {
+		if ipNet.Contains(ip) {
+			l.stats.Allowed.Add(1)
+			return true
+		}
+	}
+
+	var subnet net.IP
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, subnet = ip4, ip4.Mask(l.ipv4Mask)
+	} else {
+		subnet = ip.Mask(l.ipv6Mask)
+	}
+
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cleanup(now)
+
+	e := l.ips[string(ip)]
+	if e == nil {
+		e = &limitEntry{}
+		if l.conf.Rate > 0 {
+			e.bucket = NewTokenBucket(l.conf.Rate, l.conf.Burst, now)
+		}
+		l.ips[string(ip)] = e
+	}
+
+	if now.Before(e.bannedUntil) {
+		l.stats.Rejected.Add(1)
+		return false
+	}
+
+	allowed := e.bucket == nil || e.bucket.Take(now)
+	if allowed && l.conf.SubnetRate > 0 {
+		se := l.subnets[string(subnet)]
+		if se == nil {
+			se = &limitEntry{bucket: NewTokenBucket(l.conf.SubnetRate, l.conf.SubnetBurst, now)}
+			l.subnets[string(subnet)] = se
+		}
+		if !se.bucket.Take(now) {
+			// 子网被同网段的其它IP用完, 不扣这个IP的令牌, 也不算它的违规次数
+			if e.bucket != nil {
+				e.bucket.Return()
+			}
+			l.stats.SubnetLimited.Add(1)
+			return false
+		}
+	}
+
+	if allowed {
+		e.violations = 0
+		l.stats.Allowed.Add(1)
+		return true
+	}
+
+	l.stats.Limited.Add(1)
+	e.violations++
+	if l.conf.BanThreshold > 0 && e.violations >= l.conf.BanThreshold {
+		e.violations = 0
+		e.bannedUntil = now.Add(time.Duration(l.conf.BanDuration))
+		l.stats.Bans.Add(1)
+		glog.Warningf("rate_limit.%s - ban %s for %v", l.name, ip, time.Duration(l.conf.BanDuration))
+	}
+	return false
+}
+
+// 回收令牌已回满且未封禁的记录
+func (l *IPLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < cleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+
+	for k, e := range l.ips {
+		if !now.Before(e.bannedUntil) && (e.bucket == nil || e.bucket.Full(now)) {
+			delete(l.ips, k)
+		}
+	}
+	for k, e := range l.subnets {
+		if e.bucket.Full(now) {
+			delete(l.subnets, k)
+		}
+	}
+}
+
+// 当前封禁的IP列表
+func (l *IPLimiter) BannedList() []string {
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var banned []string
+	for k, e := range l.ips {
+		if now.Before(e.bannedUntil) {
+			banned = append(banned, net.IP(k).String())
+		}
+	}
+	return banned
+}
+
+func (l *IPLimiter) Snapshot() map[string]int64 {
+	now := l.now()
+
+	l.mu.Lock()
+	ips, subnets := len(l.ips), len(l.subnets)
+	banned := 0
+	for _, e := range l.ips {
+		if now.Before(e.bannedUntil) {
+			banned++
+		}
+	}
+	l.mu.Unlock()
+
+	return map[string]int64{
+		"allowed":        l.stats.Allowed.Get(),
+		"limited":        l.stats.Limited.Get(),
+		"subnet_limited": l.stats.SubnetLimited.Get(),
+		"rejected":       l.stats.Rejected.Get(),
+		"bans":           l.stats.Bans.Get(),
+		"banned":         int64(banned),
+		"ips":            int64(ips),
+		"subnets":        int64(subnets),
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rate_limit
+
+import (
+	"github.com/nebulaim/telegramd/baselib/base"
+	"net"
+	"testing"
+	"time"
+)
+
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) now() time.Time          { return c.t }
+func (c *testClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestIPLimiter(t *testing.T, conf IPLimiterConfig) (*IPLimiter, *testClock) {
+	l, err := NewIPLimiter("", conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &testClock{t: time.Unix(1500000000, 0)}
+	l.now = clock.now
+	return l, clock
+}
+
+func checkAllow(t *testing.T, l *IPLimiter, addr string, want bool) {
+	if got := l.AllowAddr(addr); got != want {
+		t.Fatalf("allow %s: %v (need %v)", addr, got, want)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	b := NewTokenBucket(2, 3, now)
+
+	for i := 0; i < 3; i++ {
+		if !b.Take(now) {
+			t.Fatalf("take %d failed", i)
+		}
+	}
+	if b.Take(now) {
+		t.Fatal("empty bucket must be rejected")
+	}
+
+	// 2/s, 500ms生成一个令牌
+	now = now.Add(500 * time.Millisecond)
+	if !b.Take(now) || b.Take(now) {
+		t.Fatal("invalid refill")
+	}
+
+	now = now.Add(time.Hour)
+	if !b.Full(now) {
+		t.Fatal("bucket must be full")
+	}
+}
+
+func TestIPLimiterDisabled(t *testing.T) {
+	l, _ := newTestIPLimiter(t, IPLimiterConfig{})
+	for i := 0; i < 100; i++ {
+		checkAllow(t, l, "1.2.3.4:1234", true)
+	}
+}
+
+func TestIPLimiterPerIP(t *testing.T) {
+	l, clock := newTestIPLimiter(t, IPLimiterConfig{Rate: 1, Burst: 2})
+
+	checkAllow(t, l, "1.2.3.4:1234", true)
+	checkAllow(t, l, "1.2.3.4:1235", true)
+	checkAllow(t, l, "1.2.3.4:1236", false)
+	// 其它IP不受影响
+	checkAllow(t, l, "1.2.3.5:1234", true)
+	checkAllow(t, l, "[2001:db8::1]:1234", true)
+
+	clock.advance(time.Second)
+	checkAllow(t, l, "1.2.3.4:1234", true)
+	checkAllow(t, l, "1.2.3.4:1234", false)
+
+	s := l.Snapshot()
+	if s["allowed"] != 5 || s["limited"] != 2 {
+		t.Fatalf("invalid stats: %v", s)
+	}
+}
+
+func TestIPLimiterPerSubnet(t *testing.T) {
+	l, _ := newTestIPLimiter(t, IPLimiterConfig{Rate: 10, Burst: 10, SubnetRate: 1, SubnetBurst: 3})
+
+	checkAllow(t, l, "1.2.3.1", true)
+	checkAllow(t, l, "1.2.3.2", true)
+	checkAllow(t, l, "1.2.3.3", true)
+	checkAllow(t, l, "1.2.3.4", false)
+	checkAllow(t, l, "1.2.4.1", true)
+
+	// ipv6按/64
+	checkAllow(t, l, "2001:db8::1", true)
+	checkAllow(t, l, "2001:db8::2", true)
+	checkAllow(t, l, "2001:db8::3", true)
+	checkAllow(t, l, "2001:db8::4", false)
+	checkAllow(t, l, "2001:db8:0:1::1", true)
+}
+
+func TestIPLimiterSubnetNotBanIP(t *testing.T) {
+	l, clock := newTestIPLimiter(t, IPLimiterConfig{
+		Rate:         1,
+		Burst:        2,
+		SubnetRate:   1,
+		SubnetBurst:  2,
+		BanThreshold: 2,
+		BanDuration:  base.Duration(time.Minute),
+	})
+
+	// 同子网的其它IP用完了子网的令牌
+	checkAllow(t, l, "1.2.3.1", true)
+	checkAllow(t, l, "1.2.3.1", true)
+	for i := 0; i < 3; i++ {
+		checkAllow(t, l, "1.2.3.2", false)
+	}
+
+	// 1.2.3.2没有被封禁, 自己的令牌也没有被扣掉
+	clock.advance(2 * time.Second)
+	checkAllow(t, l, "1.2.3.2", true)
+	checkAllow(t, l, "1.2.3.2", true)
+	if s := l.Snapshot(); s["subnet_limited"] != 3 || s["limited"] != 0 || s["bans"] != 0 {
+		t.Fatalf("invalid stats: %v", s)
+	}
+}
+
+func TestIPLimiterBan(t *testing.T) {
+	l, clock := newTestIPLimiter(t, IPLimiterConfig{
+		Rate:         1,
+		Burst:        1,
+		BanThreshold: 3,
+		BanDuration:  base.Duration(time.Minute),
+	})
+
+	checkAllow(t, l, "1.2.3.4", true)
+	for i := 0; i < 3; i++ {
+		checkAllow(t, l, "1.2.3.4", false)
+	}
+	if banned := l.BannedList(); len(banned) != 1 || banned[0] != "1.2.3.4" {
+		t.Fatalf("invalid banned list: %v", banned)
+	}
+
+	// 封禁期间令牌回满也拒绝
+	clock.advance(30 * time.Second)
+	checkAllow(t, l, "1.2.3.4", false)
+
+	clock.advance(31 * time.Second)
+	checkAllow(t, l, "1.2.3.4", true)
+
+	s := l.Snapshot()
+	if s["bans"] != 1 || s["rejected"] != 1 || s["banned"] != 0 {
+		t.Fatalf("invalid stats: %v", s)
+	}
+}
+
+func TestIPLimiterWhitelist(t *testing.T) {
+	l, _ := newTestIPLimiter(t, IPLimiterConfig{Rate: 1, Burst: 1, Whitelist: []string{"10.0.0.0/8"}})
+
+	for i := 0; i < 10; i++ {
+		checkAllow(t, l, "10.1.2.3:1234", true)
+	}
+	checkAllow(t, l, "11.1.2.3:1234", true)
+	checkAllow(t, l, "11.1.2.3:1234", false)
+}
+
+func TestIPLimiterCleanup(t *testing.T) {
+	l, clock := newTestIPLimiter(t, IPLimiterConfig{Rate: 1, Burst: 1, SubnetRate: 1, SubnetBurst: 1})
+
+	l.Allow(net.ParseIP("1.2.3.4"))
+	l.Allow(net.ParseIP("2001:db8::1"))
+	if s := l.Snapshot(); s["ips"] != 2 || s["subnets"] != 2 {
+		t.Fatalf("invalid stats: %v", s)
+	}
+
+	clock.advance(cleanupInterval)
+	l.Allow(net.ParseIP("5.6.7.8"))
+	if s := l.Snapshot(); s["ips"] != 1 || s["subnets"] != 1 {
+		t.Fatalf("idle entries must be removed: %v", s)
+	}
+}
+
+func TestIPLimiterInvalidConfig(t *testing.T) {
+	confs := []IPLimiterConfig{
+		{Rate: -1},
+		{Rate: 1, IPv4Prefix: 33},
+		{Rate: 1, IPv6Prefix: 129},
+		{Rate: 1, Whitelist: []string{"10.0.0.1"}},
+	}
+	for _, conf := range confs {
+		if _, err := NewIPLimiter("", conf); err == nil {
+			t.Errorf("invalid config must be rejected: %v", &conf)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rate_limit
+
+import (
+	"time"
+)
+
+// 令牌桶，非线程安全，由调用方加锁
+type TokenBucket struct {
+	rate   float64 // 每秒生成的令牌数
+	burst  float64 // 桶容量
+	tokens float64
+	last   time.Time
+}
+
+func NewTokenBucket(rate float64, burst int, now time.Time) *TokenBucket {
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// 取一个令牌，桶空时返回false
+func (b *TokenBucket) Take(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// 归还Take取走的令牌
+func (b *TokenBucket) Return() {
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// 是否已经回满，回满的桶可以回收
+func (b *TokenBucket) Full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.burst
+}
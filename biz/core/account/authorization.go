@@ -69,3 +69,18 @@ func (m *AccountModel) GetAuthKeyIdByHash(userId int32, hash int64) int64 {
 func (m *AccountModel) DeleteAuthorization(authKeyId int64) {
 	m.dao.AuthUsersDAO.Delete(time.Now().Unix(), authKeyId)
 }
+
+// 删除除selfAuthKeyId以外的所有授权, 返回被删除的auth_key_id
+func (m *AccountModel) ResetAuthorizations(selfAuthKeyId int64, userId int32) []int64 {
+	doList := m.dao.AuthUsersDAO.SelectListByUserId(userId)
+	authKeyIds := make([]int64, 0, len(doList))
+	now := time.Now().Unix()
+	for _, do := range doList {
+		if do.AuthId == selfAuthKeyId {
+			continue
+		}
+		m.dao.AuthUsersDAO.Delete(now, do.AuthId)
+		authKeyIds = append(authKeyIds, do.AuthId)
+	}
+	return authKeyIds
+}
@@ -149,7 +149,7 @@ func (m *AccountModel) CheckRecoverCode(userId int32, code string) error {
 		return err
 	}
 
-	// TODO(@benqi): FLOOD_WAIT, check attempts??
+	// 尝试次数由session按floodLimit配置的规则限制(auth.recoverPassword)
 
 	if do.Code != code {
 		err := mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_CODE_INVALID)
@@ -368,7 +368,7 @@ func (p *passwordData) GetPasswordSetting(currentPasswordHash []byte) (*mtproto.
 }
 
 func (p *passwordData) RequestPasswordRecovery() (*mtproto.Auth_PasswordRecovery, error) {
-	// TODO(@benqi): FLOOD_WAIT
+	// FLOOD_WAIT由session按floodLimit配置的规则检查(auth.requestPasswordRecovery)
 	passwordRecovery := &mtproto.TLAuthPasswordRecovery{Data2: &mtproto.Auth_PasswordRecovery_Data{
 		EmailPattern: p.email,
 	}}
@@ -242,13 +242,7 @@ func (code *phoneCodeData) DoSendCode(phoneRegistered, allowFlashCall, currentNu
 		CreatedTime:      time.Now().Unix(),
 	}
 	code.tableId = code.dao.AuthPhoneTransactionsDAO.Insert(do)
-	//// TODO(@benqi):
-	//lastCreatedAt := time.Unix(time.Now().Unix()-15*60, 0).Format("2006-01-02 15:04:05")
-	//do := code.dao.AuthPhoneTransactionsDAO.SelectByPhoneAndApiIdAndHash(code.phoneNumber, apiId, apiHash, lastCreatedAt)
-	//if do == nil {
-	//} else {
-	//	// TODO(@benqi): FLOOD_WAIT_X, too many attempts, please try later.
-	//}
+	// 发送次数由session按floodLimit配置的规则限制, 超过返回FLOOD_WAIT_X
 
 	return nil
 }
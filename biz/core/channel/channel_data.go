@@ -1008,6 +1008,20 @@ func (m *channelLogicData) SetTopMessage(topMessage int32) (err error) {
 	return
 }
 
+func (m *channelLogicData) GetTopMessage() int32 {
+	return m.TopMessage
+}
+
+// 用户在频道里的已读状态, 不在频道里时返回0
+func (m *channelLogicData) GetReadHistoryState(userId int32) (readInboxMaxId, readOutboxMaxId int32) {
+	participant := m.checkOrLoadChannelParticipant(userId)
+	if participant != nil {
+		readInboxMaxId = participant.ReadInboxMaxId
+		readOutboxMaxId = participant.ReadOutboxMaxId
+	}
+	return
+}
+
 func (m *channelLogicData) ReadOutboxHistory(userId, maxId int32) bool {
 	affected := m.dao.ChannelParticipantsDAO.UpdateReadInboxMaxId(maxId, m.Id, userId)
 	return affected > 0
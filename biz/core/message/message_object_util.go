@@ -232,6 +232,14 @@ func PickAllIDListByMessages(messageList []*mtproto.Message) (userIdList, chatId
 			case mtproto.TLConstructor_CRC32_message:
 				m2 := m.To_Message()
 				userIdList = AppendID(userIdList, m2.GetFromId())
+				if fwdFrom := m2.GetFwdFrom().GetData2(); fwdFrom != nil {
+					if fwdFrom.GetFromId() != 0 {
+						userIdList = AppendID(userIdList, fwdFrom.GetFromId())
+					}
+					if fwdFrom.GetChannelId() != 0 {
+						channelIdList = AppendID(channelIdList, fwdFrom.GetChannelId())
+					}
+				}
 
 				p := m2.GetToId()
 				switch p.GetConstructor() {
@@ -244,7 +252,7 @@ func PickAllIDListByMessages(messageList []*mtproto.Message) (userIdList, chatId
 						continue
 					}
 				case mtproto.TLConstructor_CRC32_peerChannel:
-					channelIdList = AppendID(chatIdList, p.GetData2().GetChannelId())
+					channelIdList = AppendID(channelIdList, p.GetData2().GetChannelId())
 				}
 			case mtproto.TLConstructor_CRC32_messageService:
 				m2 := m.To_MessageService()
@@ -261,7 +269,7 @@ func PickAllIDListByMessages(messageList []*mtproto.Message) (userIdList, chatId
 					}
 					chatIdList = AppendID(chatIdList, p.GetData2().GetChatId())
 				case mtproto.TLConstructor_CRC32_peerChannel:
-					channelIdList = AppendID(chatIdList, p.GetData2().GetChannelId())
+					channelIdList = AppendID(channelIdList, p.GetData2().GetChannelId())
 				}
 			case mtproto.TLConstructor_CRC32_messageEmpty:
 			}
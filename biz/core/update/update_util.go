@@ -0,0 +1,100 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package updates
+
+import (
+	"github.com/nebulaim/telegramd/biz/core/message"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+)
+
+type idList []int32
+
+func (l *idList) add(id int32) {
+	if id == 0 {
+		return
+	}
+	for _, i := range *l {
+		if i == id {
+			return
+		}
+	}
+	*l = append(*l, id)
+}
+
+// 收集messages和updates里引用到的user、chat和channel, 用于填充users和chats
+func PickAllIDListByMessagesAndUpdates(messageList []*mtproto.Message, updateList []*mtproto.Update) (userIdList, chatIdList, channelIdList []int32) {
+	var (
+		users, chats, channels idList
+		messages               = append([]*mtproto.Message{}, messageList...)
+	)
+
+	addPeer := func(peer *mtproto.Peer) {
+		switch peer.GetConstructor() {
+		case mtproto.TLConstructor_CRC32_peerUser:
+			users.add(peer.GetData2().GetUserId())
+		case mtproto.TLConstructor_CRC32_peerChat:
+			chats.add(peer.GetData2().GetChatId())
+		case mtproto.TLConstructor_CRC32_peerChannel:
+			channels.add(peer.GetData2().GetChannelId())
+		}
+	}
+
+	for _, update := range updateList {
+		data := update.GetData2()
+		if data == nil {
+			continue
+		}
+
+		if data.GetMessage_1() != nil {
+			messages = append(messages, data.GetMessage_1())
+		}
+		addPeer(data.GetPeer_39())
+		addPeer(data.GetPeer_28().GetData2().GetPeer())
+		addPeer(data.GetPeer_61().GetData2().GetPeer())
+		for _, p := range data.GetOrder_62() {
+			addPeer(p.GetData2().GetPeer())
+		}
+
+		// updateUserName、updateChatParticipantAdd等
+		users.add(data.GetUserId())
+		users.add(data.GetInviterId())
+		chats.add(data.GetChatId())
+		channels.add(data.GetChannelId())
+
+		if participants := data.GetParticipants().GetData2(); participants != nil {
+			chats.add(participants.GetChatId())
+			for _, p := range participants.GetParticipants() {
+				users.add(p.GetData2().GetUserId())
+				users.add(p.GetData2().GetInviterId())
+			}
+		}
+	}
+
+	userIdList2, chatIdList2, channelIdList2 := message.PickAllIDListByMessages(messages)
+	for _, id := range userIdList2 {
+		users.add(id)
+	}
+	for _, id := range chatIdList2 {
+		chats.add(id)
+	}
+	for _, id := range channelIdList2 {
+		channels.add(id)
+	}
+
+	return []int32(users), []int32(chats), []int32(channels)
+}
@@ -24,13 +24,19 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type AuthKeyMetadata struct {
-	Nonce                []byte   `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	ServerNonce          []byte   `protobuf:"bytes,2,opt,name=server_nonce,json=serverNonce,proto3" json:"server_nonce,omitempty"`
-	NewNonce             []byte   `protobuf:"bytes,3,opt,name=new_nonce,json=newNonce,proto3" json:"new_nonce,omitempty"`
-	A                    []byte   `protobuf:"bytes,4,opt,name=a,proto3" json:"a,omitempty"`
-	P                    []byte   `protobuf:"bytes,5,opt,name=p,proto3" json:"p,omitempty"`
-	AuthKeyId            int64    `protobuf:"varint,6,opt,name=auth_key_id,json=authKeyId,proto3" json:"auth_key_id,omitempty"`
-	AuthKey              []byte   `protobuf:"bytes,7,opt,name=auth_key,json=authKey,proto3" json:"auth_key,omitempty"`
+	Nonce       []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	ServerNonce []byte `protobuf:"bytes,2,opt,name=server_nonce,json=serverNonce,proto3" json:"server_nonce,omitempty"`
+	NewNonce    []byte `protobuf:"bytes,3,opt,name=new_nonce,json=newNonce,proto3" json:"new_nonce,omitempty"`
+	A           []byte `protobuf:"bytes,4,opt,name=a,proto3" json:"a,omitempty"`
+	P           []byte `protobuf:"bytes,5,opt,name=p,proto3" json:"p,omitempty"`
+	AuthKeyId   int64  `protobuf:"varint,6,opt,name=auth_key_id,json=authKeyId,proto3" json:"auth_key_id,omitempty"`
+	AuthKey     []byte `protobuf:"bytes,7,opt,name=auth_key,json=authKey,proto3" json:"auth_key,omitempty"`
+	// p_q_inner_data_temp的expires_in, 0为永久key
+	ExpiresIn int32 `protobuf:"varint,8,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	// res_pq返回的pq及其分解, 每次握手随机生成
+	Pq                   []byte   `protobuf:"bytes,9,opt,name=pq,proto3" json:"pq,omitempty"`
+	PqP                  []byte   `protobuf:"bytes,10,opt,name=pq_p,json=pqP,proto3" json:"pq_p,omitempty"`
+	PqQ                  []byte   `protobuf:"bytes,11,opt,name=pq_q,json=pqQ,proto3" json:"pq_q,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -40,7 +46,7 @@ func (m *AuthKeyMetadata) Reset()         { *m = AuthKeyMetadata{} }
 func (m *AuthKeyMetadata) String() string { return proto.CompactTextString(m) }
 func (*AuthKeyMetadata) ProtoMessage()    {}
 func (*AuthKeyMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_auth_key_service_1f78725018aa9ed6, []int{0}
+	return fileDescriptor_auth_key_service_71284462b89dbf6c, []int{0}
 }
 func (m *AuthKeyMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthKeyMetadata.Unmarshal(m, b)
@@ -109,6 +115,34 @@ func (m *AuthKeyMetadata) GetAuthKey() []byte {
 	return nil
 }
 
+func (m *AuthKeyMetadata) GetExpiresIn() int32 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+func (m *AuthKeyMetadata) GetPq() []byte {
+	if m != nil {
+		return m.Pq
+	}
+	return nil
+}
+
+func (m *AuthKeyMetadata) GetPqP() []byte {
+	if m != nil {
+		return m.PqP
+	}
+	return nil
+}
+
+func (m *AuthKeyMetadata) GetPqQ() []byte {
+	if m != nil {
+		return m.PqQ
+	}
+	return nil
+}
+
 type AuthKeyRequest struct {
 	AuthKeyId            int64    `protobuf:"varint,1,opt,name=auth_key_id,json=authKeyId,proto3" json:"auth_key_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -120,7 +154,7 @@ func (m *AuthKeyRequest) Reset()         { *m = AuthKeyRequest{} }
 func (m *AuthKeyRequest) String() string { return proto.CompactTextString(m) }
 func (*AuthKeyRequest) ProtoMessage()    {}
 func (*AuthKeyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_auth_key_service_1f78725018aa9ed6, []int{1}
+	return fileDescriptor_auth_key_service_71284462b89dbf6c, []int{1}
 }
 func (m *AuthKeyRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthKeyRequest.Unmarshal(m, b)
@@ -160,7 +194,7 @@ func (m *AuthKeyData) Reset()         { *m = AuthKeyData{} }
 func (m *AuthKeyData) String() string { return proto.CompactTextString(m) }
 func (*AuthKeyData) ProtoMessage()    {}
 func (*AuthKeyData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_auth_key_service_1f78725018aa9ed6, []int{2}
+	return fileDescriptor_auth_key_service_71284462b89dbf6c, []int{2}
 }
 func (m *AuthKeyData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthKeyData.Unmarshal(m, b)
@@ -213,7 +247,7 @@ func (m *AuthKeyIdRequest) Reset()         { *m = AuthKeyIdRequest{} }
 func (m *AuthKeyIdRequest) String() string { return proto.CompactTextString(m) }
 func (*AuthKeyIdRequest) ProtoMessage()    {}
 func (*AuthKeyIdRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_auth_key_service_1f78725018aa9ed6, []int{3}
+	return fileDescriptor_auth_key_service_71284462b89dbf6c, []int{3}
 }
 func (m *AuthKeyIdRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthKeyIdRequest.Unmarshal(m, b)
@@ -253,7 +287,7 @@ func (m *UserIdResponse) Reset()         { *m = UserIdResponse{} }
 func (m *UserIdResponse) String() string { return proto.CompactTextString(m) }
 func (*UserIdResponse) ProtoMessage()    {}
 func (*UserIdResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_auth_key_service_1f78725018aa9ed6, []int{4}
+	return fileDescriptor_auth_key_service_71284462b89dbf6c, []int{4}
 }
 func (m *UserIdResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserIdResponse.Unmarshal(m, b)
@@ -410,32 +444,35 @@ var _ZRPCAuthKey_serviceDesc = grpc.ServiceDesc{
 }
 
 func init() {
-	proto.RegisterFile("auth_key_service.proto", fileDescriptor_auth_key_service_1f78725018aa9ed6)
-}
-
-var fileDescriptor_auth_key_service_1f78725018aa9ed6 = []byte{
-	// 358 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x4f, 0x4f, 0xea, 0x40,
-	0x14, 0xc5, 0x33, 0xf4, 0x41, 0xe1, 0xb6, 0xe1, 0xbd, 0xd7, 0x47, 0xa0, 0xf0, 0x12, 0x83, 0xac,
-	0x58, 0x98, 0xc6, 0xe0, 0xda, 0x05, 0xe8, 0x42, 0x63, 0x34, 0xd8, 0xc4, 0x0d, 0x1b, 0x1c, 0xca,
-	0x4d, 0x20, 0x42, 0x5b, 0xe7, 0x8f, 0x04, 0xbf, 0x84, 0xdf, 0xc8, 0xcf, 0x66, 0xe6, 0x0f, 0x18,
-	0xba, 0x30, 0xc6, 0x15, 0xdc, 0xf9, 0x9d, 0x73, 0x7a, 0xee, 0x64, 0xa0, 0x49, 0xa5, 0x58, 0x4c,
-	0x9f, 0x70, 0x3b, 0xe5, 0xc8, 0x5e, 0x96, 0x09, 0x46, 0x39, 0xcb, 0x44, 0x16, 0xb8, 0x6b, 0xa1,
-	0xff, 0xf4, 0xde, 0x09, 0xfc, 0x1e, 0x4a, 0xb1, 0xb8, 0xc1, 0xed, 0x2d, 0x0a, 0x3a, 0xa7, 0x82,
-	0x06, 0x0d, 0x28, 0xa7, 0x59, 0x9a, 0x60, 0x48, 0xba, 0xa4, 0xef, 0xc7, 0x66, 0x08, 0x8e, 0xc1,
-	0x57, 0x19, 0xc8, 0xa6, 0x06, 0x96, 0x34, 0xf4, 0xcc, 0xd9, 0x9d, 0x96, 0xfc, 0x87, 0x5a, 0x8a,
-	0x1b, 0xcb, 0x1d, 0xcd, 0xab, 0x29, 0x6e, 0x0c, 0xf4, 0x81, 0xd0, 0xf0, 0x97, 0x3e, 0x24, 0x54,
-	0x4d, 0x79, 0x58, 0x36, 0x53, 0x1e, 0x1c, 0x81, 0xb7, 0x2f, 0xba, 0x9c, 0x87, 0x95, 0x2e, 0xe9,
-	0x3b, 0x71, 0x8d, 0x9a, 0x5e, 0xd7, 0xf3, 0xa0, 0x0d, 0xd5, 0x1d, 0x0f, 0x5d, 0x6d, 0x72, 0x2d,
-	0xec, 0x9d, 0x42, 0xdd, 0xf6, 0x8f, 0xf1, 0x59, 0x22, 0x17, 0xc5, 0x30, 0x52, 0x08, 0xeb, 0x3d,
-	0x82, 0x67, 0x1d, 0x97, 0x6a, 0xdb, 0x26, 0x54, 0x18, 0x72, 0xb9, 0x12, 0x5a, 0x59, 0x8e, 0xed,
-	0x54, 0x8c, 0x29, 0x7d, 0xd5, 0xc9, 0x39, 0xec, 0x34, 0x80, 0x3f, 0xc3, 0x9d, 0xee, 0xbb, 0xad,
-	0x28, 0xd4, 0x1f, 0x38, 0x32, 0x65, 0xe0, 0x79, 0x96, 0x72, 0xfc, 0x71, 0xb1, 0x16, 0xb8, 0x92,
-	0x23, 0x53, 0xcc, 0x31, 0x46, 0xa9, 0x83, 0x07, 0x6f, 0x04, 0xbc, 0x49, 0x3c, 0xbe, 0xb0, 0xdd,
-	0x82, 0x73, 0xf0, 0xef, 0x25, 0xb2, 0xed, 0x6e, 0x6e, 0x45, 0xf6, 0x55, 0x44, 0x87, 0x37, 0xda,
-	0x69, 0x14, 0x81, 0xbe, 0xb8, 0x21, 0x78, 0xda, 0x6e, 0x6a, 0x07, 0xed, 0xa2, 0x68, 0xbf, 0x7b,
-	0xe7, 0x33, 0xf8, 0x70, 0xc5, 0xd1, 0x09, 0xfc, 0x4b, 0xb2, 0x75, 0x94, 0xe2, 0x4c, 0xae, 0xe8,
-	0x72, 0x1d, 0xbd, 0x6a, 0xd5, 0xe8, 0xef, 0x64, 0xac, 0x7e, 0xe3, 0x3c, 0x51, 0x6f, 0x52, 0x7d,
-	0xec, 0xaa, 0x34, 0x26, 0xb3, 0x8a, 0xa6, 0x67, 0x1f, 0x01, 0x00, 0x00, 0xff, 0xff, 0x36, 0x67,
-	0xa5, 0xfb, 0xd5, 0x02, 0x00, 0x00,
+	proto.RegisterFile("auth_key_service.proto", fileDescriptor_auth_key_service_71284462b89dbf6c)
+}
+
+var fileDescriptor_auth_key_service_71284462b89dbf6c = []byte{
+	// 406 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xcb, 0x6f, 0xd3, 0x40,
+	0x10, 0xc6, 0xb5, 0x76, 0xf3, 0x1a, 0x47, 0x81, 0x2e, 0x55, 0xbb, 0x2d, 0x02, 0x85, 0x9c, 0x72,
+	0x40, 0x16, 0x2a, 0x67, 0x0e, 0x29, 0x1c, 0xa8, 0x10, 0x28, 0xb5, 0xc4, 0xa5, 0x17, 0xb3, 0x75,
+	0x46, 0xaa, 0x45, 0xb3, 0x5e, 0xef, 0x83, 0x12, 0xfe, 0x09, 0x0e, 0xfc, 0xc3, 0x68, 0x1f, 0x2e,
+	0x8a, 0x0f, 0x08, 0x71, 0x4a, 0xe6, 0xfb, 0xcd, 0x37, 0xfe, 0x66, 0xb4, 0x70, 0xcc, 0xad, 0xb9,
+	0x2d, 0xbf, 0xe2, 0xae, 0xd4, 0xa8, 0xbe, 0xd5, 0x15, 0xe6, 0x52, 0x35, 0xa6, 0xa1, 0xa3, 0xad,
+	0xf1, 0x7f, 0x16, 0xbf, 0x12, 0x78, 0xb4, 0xb2, 0xe6, 0xf6, 0x03, 0xee, 0x3e, 0xa2, 0xe1, 0x1b,
+	0x6e, 0x38, 0x3d, 0x82, 0x81, 0x68, 0x44, 0x85, 0x8c, 0xcc, 0xc9, 0x72, 0x5a, 0x84, 0x82, 0xbe,
+	0x80, 0xa9, 0x9b, 0x81, 0xaa, 0x0c, 0x30, 0xf1, 0x30, 0x0b, 0xda, 0x27, 0xdf, 0xf2, 0x14, 0x26,
+	0x02, 0xef, 0x23, 0x4f, 0x3d, 0x1f, 0x0b, 0xbc, 0x0f, 0x70, 0x0a, 0x84, 0xb3, 0x03, 0x2f, 0x12,
+	0xee, 0x2a, 0xc9, 0x06, 0xa1, 0x92, 0xf4, 0x39, 0x64, 0x0f, 0x41, 0xeb, 0x0d, 0x1b, 0xce, 0xc9,
+	0x32, 0x2d, 0x26, 0x3c, 0xe4, 0xba, 0xdc, 0xd0, 0x53, 0x18, 0x77, 0x9c, 0x8d, 0xbc, 0x69, 0x14,
+	0x21, 0x7d, 0x06, 0x80, 0xdf, 0x65, 0xad, 0x50, 0x97, 0xb5, 0x60, 0xe3, 0x39, 0x59, 0x0e, 0x8a,
+	0x49, 0x54, 0x2e, 0x05, 0x9d, 0x41, 0x22, 0x5b, 0x36, 0xf1, 0x9e, 0x44, 0xb6, 0xf4, 0x10, 0x0e,
+	0x64, 0x5b, 0x4a, 0x06, 0x5e, 0x49, 0x65, 0xbb, 0x8e, 0x52, 0xcb, 0xb2, 0x4e, 0xba, 0x5a, 0xbc,
+	0x82, 0x59, 0x3c, 0x4a, 0x81, 0xad, 0x45, 0x6d, 0xfa, 0x09, 0x49, 0x2f, 0xe1, 0xe2, 0x0b, 0x64,
+	0xd1, 0xf1, 0xce, 0x9d, 0xf0, 0x18, 0x86, 0x0a, 0xb5, 0xbd, 0x33, 0xbe, 0x73, 0x50, 0xc4, 0xaa,
+	0x3f, 0x26, 0xf9, 0xdb, 0xa2, 0xe9, 0xde, 0xa2, 0x8b, 0x73, 0x78, 0xbc, 0xea, 0xfa, 0xfe, 0x35,
+	0x15, 0x87, 0xd9, 0x67, 0x8d, 0xca, 0x19, 0xb4, 0x6c, 0x84, 0xc6, 0xff, 0x0e, 0x76, 0x02, 0x23,
+	0xab, 0x51, 0x39, 0x96, 0x06, 0xa3, 0xf5, 0x83, 0xcf, 0x7f, 0x12, 0xc8, 0xae, 0x8b, 0xf5, 0xdb,
+	0x98, 0x8d, 0xbe, 0x81, 0xe9, 0x95, 0x45, 0xb5, 0xeb, 0xea, 0x93, 0x3c, 0x3e, 0xb5, 0x7c, 0xff,
+	0xa2, 0x67, 0x47, 0x7d, 0xe0, 0x0f, 0xb7, 0x82, 0xcc, 0xdb, 0x43, 0x6c, 0x7a, 0xda, 0x6f, 0x7a,
+	0xd8, 0xfd, 0xec, 0xcf, 0xe0, 0xfd, 0x15, 0x2f, 0x5e, 0xc2, 0x93, 0xaa, 0xd9, 0xe6, 0x02, 0x6f,
+	0xec, 0x1d, 0xaf, 0xb7, 0xf9, 0x0f, 0xdf, 0x75, 0x71, 0x78, 0xbd, 0x76, 0xbf, 0x85, 0xac, 0xdc,
+	0x43, 0x77, 0x1f, 0x7b, 0x9f, 0xac, 0xc9, 0xcd, 0xd0, 0xd3, 0xd7, 0xbf, 0x07, 0x00, 0xd0, 0x6b,
+	0xb4, 0x2c, 0x2a, 0x03, 0x00, 0x00,
 }
@@ -0,0 +1,155 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/nebulaim/telegramd/baselib/crypto"
+)
+
+// https://core.telegram.org/method/auth.bindTempAuthKey
+const (
+	TLConstructor_CRC32_bind_auth_key_inner TLConstructor = 0x75a3f765
+
+	// random:int128 msg_id:long seqno:int msg_len:int
+	bindAuthKeyMessageHeaderLen = 32
+	bindAuthKeyInnerLen         = 40
+)
+
+///////////////////////////////////////////////////////////////////////////////
+// bind_auth_key_inner#75a3f765 nonce:long temp_auth_key_id:long perm_auth_key_id:long temp_session_id:long expires_at:int = BindAuthKeyInner;
+type TLBindAuthKeyInner struct {
+	Nonce         int64
+	TempAuthKeyId int64
+	PermAuthKeyId int64
+	TempSessionId int64
+	ExpiresAt     int32
+}
+
+func (m *TLBindAuthKeyInner) String() string {
+	return fmt.Sprintf("{bind_auth_key_inner#75a3f765 - nonce: %d, temp_auth_key_id: %d, perm_auth_key_id: %d, temp_session_id: %d, expires_at: %d}",
+		m.Nonce,
+		m.TempAuthKeyId,
+		m.PermAuthKeyId,
+		m.TempSessionId,
+		m.ExpiresAt)
+}
+
+func (m *TLBindAuthKeyInner) Encode() []byte {
+	x := NewEncodeBuf(bindAuthKeyInnerLen)
+	x.Int(int32(TLConstructor_CRC32_bind_auth_key_inner))
+	x.Long(m.Nonce)
+	x.Long(m.TempAuthKeyId)
+	x.Long(m.PermAuthKeyId)
+	x.Long(m.TempSessionId)
+	x.Int(m.ExpiresAt)
+	return x.buf
+}
+
+func (m *TLBindAuthKeyInner) EncodeToLayer(layer int) []byte {
+	return m.Encode()
+}
+
+func (m *TLBindAuthKeyInner) Decode(dbuf *DecodeBuf) error {
+	m.Nonce = dbuf.Long()
+	m.TempAuthKeyId = dbuf.Long()
+	m.PermAuthKeyId = dbuf.Long()
+	m.TempSessionId = dbuf.Long()
+	m.ExpiresAt = dbuf.Int()
+	return dbuf.err
+}
+
+// 使用永久key按MTProto 1.0加密binding message, 客户端和测试使用
+func EncryptBindAuthKeyInner(permAuthKey []byte, msgId int64, inner *TLBindAuthKeyInner) []byte {
+	innerData := inner.Encode()
+
+	x := NewEncodeBuf(bindAuthKeyMessageHeaderLen + len(innerData) + 16)
+	x.Bytes(crypto.GenerateNonce(16))
+	x.Long(msgId)
+	x.Int(0)
+	x.Int(int32(len(innerData)))
+	x.Bytes(innerData)
+	// 补齐16字节
+	if n := len(x.buf) % 16; n != 0 {
+		x.Bytes(crypto.GenerateNonce(16 - n))
+	}
+
+	msgKey := crypto.Sha1Digest(x.buf[:bindAuthKeyMessageHeaderLen+len(innerData)])[4:20]
+	aesKey, aesIV := generateMessageKeyV1(msgKey, permAuthKey, 0)
+	encryptedData, _ := crypto.NewAES256IGECryptor(aesKey, aesIV).Encrypt(x.buf)
+
+	x2 := NewEncodeBuf(24 + len(encryptedData))
+	x2.Long(authKeyIdFromAuthKey(permAuthKey))
+	x2.Bytes(msgKey)
+	x2.Bytes(encryptedData)
+	return x2.buf
+}
+
+// 解密auth.bindTempAuthKey的encrypted_message并校验auth_key_id和msg_key,
+// 返回binding message的msg_id和bind_auth_key_inner, 其余字段由调用方校验
+func DecryptBindAuthKeyInner(permAuthKey []byte, encryptedMessage []byte) (int64, *TLBindAuthKeyInner, error) {
+	if len(permAuthKey) != 256 {
+		return 0, nil, fmt.Errorf("invalid auth_key len: %d", len(permAuthKey))
+	}
+	if len(encryptedMessage) < 24+bindAuthKeyMessageHeaderLen+bindAuthKeyInnerLen || (len(encryptedMessage)-24)%16 != 0 {
+		return 0, nil, fmt.Errorf("invalid encrypted_message len: %d", len(encryptedMessage))
+	}
+
+	authKeyId := int64(binary.LittleEndian.Uint64(encryptedMessage))
+	if authKeyId != authKeyIdFromAuthKey(permAuthKey) {
+		return 0, nil, fmt.Errorf("invalid encrypted_message auth_key_id: %d", authKeyId)
+	}
+
+	msgKey := encryptedMessage[8:24]
+	aesKey, aesIV := generateMessageKeyV1(msgKey, permAuthKey, 0)
+	x, err := crypto.NewAES256IGECryptor(aesKey, aesIV).Decrypt(encryptedMessage[24:])
+	if err != nil {
+		return 0, nil, err
+	}
+
+	messageLen := int(int32(binary.LittleEndian.Uint32(x[28:])))
+	if messageLen != bindAuthKeyInnerLen {
+		return 0, nil, fmt.Errorf("invalid encrypted_message msg_len: %d", messageLen)
+	}
+	if !bytes.Equal(crypto.Sha1Digest(x[:bindAuthKeyMessageHeaderLen+messageLen])[4:20], msgKey) {
+		return 0, nil, fmt.Errorf("invalid encrypted_message msg_key")
+	}
+
+	dbuf := NewDecodeBuf(x[16:])
+	msgId := dbuf.Long()
+	if seqNo := dbuf.Int(); seqNo != 0 {
+		return 0, nil, fmt.Errorf("invalid encrypted_message seqno: %d", seqNo)
+	}
+	dbuf.Int()
+	if c := dbuf.Int(); c != int32(TLConstructor_CRC32_bind_auth_key_inner) {
+		return 0, nil, fmt.Errorf("invalid encrypted_message constructor: %d", c)
+	}
+
+	inner := &TLBindAuthKeyInner{}
+	if err = inner.Decode(dbuf); err != nil {
+		return 0, nil, err
+	}
+	return msgId, inner, nil
+}
+
+// auth_key_id为sha1(auth_key)的低64位
+func authKeyIdFromAuthKey(authKey []byte) int64 {
+	return int64(binary.LittleEndian.Uint64(crypto.Sha1Digest(authKey)[12:]))
+}
@@ -0,0 +1,64 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"testing"
+)
+
+func TestBindAuthKeyInner(t *testing.T) {
+	permAuthKey := crypto.GenerateNonce(256)
+	inner := &TLBindAuthKeyInner{
+		Nonce:         0x1122334455667788,
+		TempAuthKeyId: 1001,
+		PermAuthKeyId: authKeyIdFromAuthKey(permAuthKey),
+		TempSessionId: 2002,
+		ExpiresAt:     1500000000,
+	}
+
+	encrypted := EncryptBindAuthKeyInner(permAuthKey, 6500000000000000004, inner)
+	msgId, inner2, err := DecryptBindAuthKeyInner(permAuthKey, encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msgId != 6500000000000000004 {
+		t.Fatalf("invalid msg_id: %d", msgId)
+	}
+	if *inner2 != *inner {
+		t.Fatalf("invalid bind_auth_key_inner: %v (need %v)", inner2, inner)
+	}
+
+	// 其它key无法解密
+	if _, _, err = DecryptBindAuthKeyInner(crypto.GenerateNonce(256), encrypted); err == nil {
+		t.Fatal("invalid auth_key must be rejected")
+	}
+
+	// 篡改数据
+	for _, i := range []int{0, 8, 24, len(encrypted) - 1} {
+		b := append([]byte{}, encrypted...)
+		b[i] ^= 0x01
+		if _, _, err = DecryptBindAuthKeyInner(permAuthKey, b); err == nil {
+			t.Errorf("modified byte %d must be rejected", i)
+		}
+	}
+
+	if _, _, err = DecryptBindAuthKeyInner(permAuthKey, encrypted[:len(encrypted)-16]); err == nil {
+		t.Error("short message must be rejected")
+	}
+}
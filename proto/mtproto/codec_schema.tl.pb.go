@@ -67245,14 +67245,14 @@ func (m *AuthKeyInfo) Decode(dbuf *DecodeBuf) error {
 	return dbuf.err
 }
 
-// authKeyInfo flags:# auth_key_id:long auth_key:bytes future_salt:flags.0?FutureSalt= AuthKeyInfo;
+// authKeyInfo flags:# auth_key_id:long auth_key:bytes future_salt:flags.0?FutureSalt auth_key_type:flags.1?int perm_auth_key_id:flags.1?long expires_at:flags.1?int = AuthKeyInfo;
 func (m *AuthKeyInfo) To_AuthKeyInfo() *TLAuthKeyInfo {
 	return &TLAuthKeyInfo{
 		Data2: m.Data2,
 	}
 }
 
-// authKeyInfo flags:# auth_key_id:long auth_key:bytes future_salt:flags.0?FutureSalt= AuthKeyInfo;
+// authKeyInfo flags:# auth_key_id:long auth_key:bytes future_salt:flags.0?FutureSalt auth_key_type:flags.1?int perm_auth_key_id:flags.1?long expires_at:flags.1?int = AuthKeyInfo;
 func (m *TLAuthKeyInfo) To_AuthKeyInfo() *AuthKeyInfo {
 	return &AuthKeyInfo{
 		Constructor: TLConstructor_CRC32_authKeyInfo,
@@ -67269,6 +67269,15 @@ func (m *TLAuthKeyInfo) GetAuthKey() []byte  { return m.Data2.AuthKey }
 func (m *TLAuthKeyInfo) SetFutureSalt(v *FutureSalt) { m.Data2.FutureSalt = v }
 func (m *TLAuthKeyInfo) GetFutureSalt() *FutureSalt  { return m.Data2.FutureSalt }
 
+func (m *TLAuthKeyInfo) SetAuthKeyType(v int32) { m.Data2.AuthKeyType = v }
+func (m *TLAuthKeyInfo) GetAuthKeyType() int32  { return m.Data2.AuthKeyType }
+
+func (m *TLAuthKeyInfo) SetPermAuthKeyId(v int64) { m.Data2.PermAuthKeyId = v }
+func (m *TLAuthKeyInfo) GetPermAuthKeyId() int64  { return m.Data2.PermAuthKeyId }
+
+func (m *TLAuthKeyInfo) SetExpiresAt(v int32) { m.Data2.ExpiresAt = v }
+func (m *TLAuthKeyInfo) GetExpiresAt() int32  { return m.Data2.ExpiresAt }
+
 func NewTLAuthKeyInfo() *TLAuthKeyInfo {
 	return &TLAuthKeyInfo{Data2: &AuthKeyInfo_Data{}}
 }
@@ -67282,6 +67291,9 @@ func (m *TLAuthKeyInfo) Encode() []byte {
 	if m.GetFutureSalt() != nil {
 		flags |= 1 << 0
 	}
+	if m.GetAuthKeyType() != 0 || m.GetPermAuthKeyId() != 0 || m.GetExpiresAt() != 0 {
+		flags |= 1 << 1
+	}
 	x.UInt(flags)
 
 	x.Long(m.GetAuthKeyId())
@@ -67289,6 +67301,11 @@ func (m *TLAuthKeyInfo) Encode() []byte {
 	if m.GetFutureSalt() != nil {
 		x.Bytes(m.GetFutureSalt().Encode())
 	}
+	if (flags & (1 << 1)) != 0 {
+		x.Int(m.GetAuthKeyType())
+		x.Long(m.GetPermAuthKeyId())
+		x.Int(m.GetExpiresAt())
+	}
 
 	return x.buf
 }
@@ -67302,6 +67319,9 @@ func (m *TLAuthKeyInfo) EncodeToLayer(layer int) []byte {
 	if m.GetFutureSalt() != nil {
 		flags |= 1 << 0
 	}
+	if m.GetAuthKeyType() != 0 || m.GetPermAuthKeyId() != 0 || m.GetExpiresAt() != 0 {
+		flags |= 1 << 1
+	}
 	x.UInt(flags)
 
 	x.Long(m.GetAuthKeyId())
@@ -67309,6 +67329,11 @@ func (m *TLAuthKeyInfo) EncodeToLayer(layer int) []byte {
 	if m.GetFutureSalt() != nil {
 		x.Bytes(m.GetFutureSalt().EncodeToLayer(layer))
 	}
+	if (flags & (1 << 1)) != 0 {
+		x.Int(m.GetAuthKeyType())
+		x.Long(m.GetPermAuthKeyId())
+		x.Int(m.GetExpiresAt())
+	}
 
 	return x.buf
 }
@@ -67323,6 +67348,11 @@ func (m *TLAuthKeyInfo) Decode(dbuf *DecodeBuf) error {
 		m4.Decode(dbuf)
 		m.SetFutureSalt(m4)
 	}
+	if (flags & (1 << 1)) != 0 {
+		m.SetAuthKeyType(dbuf.Int())
+		m.SetPermAuthKeyId(dbuf.Long())
+		m.SetExpiresAt(dbuf.Int())
+	}
 
 	return dbuf.err
 }
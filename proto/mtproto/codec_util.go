@@ -19,33 +19,30 @@ package mtproto
 
 import (
 	"github.com/nebulaim/telegramd/baselib/crypto"
+	"sync/atomic"
 	"time"
 )
 
+// 最后生成的服务端msg_id
+var lastMessageId int64
+
+// 服务端msg_id约等于unixtime*2^32, 模4余1(rpc_response), 并且严格递增,
+// 客户端根据收到的msg_id校正时间偏差
 func GenerateMessageId() int64 {
 	const nano = 1000 * 1000 * 1000
 	unixnano := time.Now().UnixNano()
 
-	messageId := ((unixnano / nano) << 32) | ((unixnano % nano) & -4)
+	messageId := ((unixnano / nano) << 32) | ((unixnano % nano) & -4) | 1
 	for {
-		//rpc_response
-		if (messageId % 4) != 1 {
-			messageId += 1
-		} else {
-			break
+		last := atomic.LoadInt64(&lastMessageId)
+		id := messageId
+		if id <= last {
+			id = last + 4
+		}
+		if atomic.CompareAndSwapInt64(&lastMessageId, last, id) {
+			return id
 		}
-
-		/****************************
-		 * // rpc_request
-		 * if (messageId % 4) != 3 {
-		 * 	messageId += 1
-		 * } else {
-		 * 	break
-		 * }
-		 */
 	}
-
-	return messageId
 }
 
 /*
@@ -100,40 +97,47 @@ func generateMessageKey(msgKey, authKey []byte, incoming bool) (aesKey, aesIV []
 		aesIV = append(aesIV, sha256_b[24:24+8]...)
 
 	default:
-		aesKey = make([]byte, 0, 32)
-		aesIV = make([]byte, 0, 32)
-		t_a := make([]byte, 0, 48)
-		t_b := make([]byte, 0, 48)
-		t_c := make([]byte, 0, 48)
-		t_d := make([]byte, 0, 48)
-
-		t_a = append(t_a, msgKey...)
-		t_a = append(t_a, authKey[x:x+32]...)
-
-		t_b = append(t_b, authKey[32+x:32+x+16]...)
-		t_b = append(t_b, msgKey...)
-		t_b = append(t_b, authKey[48+x:48+x+16]...)
-
-		t_c = append(t_c, authKey[64+x:64+x+32]...)
-		t_c = append(t_c, msgKey...)
-
-		t_d = append(t_d, msgKey...)
-		t_d = append(t_d, authKey[96+x:96+x+32]...)
-
-		sha1_a := crypto.Sha1Digest(t_a)
-		sha1_b := crypto.Sha1Digest(t_b)
-		sha1_c := crypto.Sha1Digest(t_c)
-		sha1_d := crypto.Sha1Digest(t_d)
+		aesKey, aesIV = generateMessageKeyV1(msgKey, authKey, x)
+	}
 
-		aesKey = append(aesKey, sha1_a[0:8]...)
-		aesKey = append(aesKey, sha1_b[8:8+12]...)
-		aesKey = append(aesKey, sha1_c[4:4+12]...)
+	return
+}
 
-		aesIV = append(aesIV, sha1_a[8:8+12]...)
-		aesIV = append(aesIV, sha1_b[0:8]...)
-		aesIV = append(aesIV, sha1_c[16:16+4]...)
-		aesIV = append(aesIV, sha1_d[0:8]...)
-	}
+// MTProto 1.0的aes_key和aes_iv, auth.bindTempAuthKey的encrypted_message仍然使用1.0加密
+func generateMessageKeyV1(msgKey, authKey []byte, x int) (aesKey, aesIV []byte) {
+	aesKey = make([]byte, 0, 32)
+	aesIV = make([]byte, 0, 32)
+	t_a := make([]byte, 0, 48)
+	t_b := make([]byte, 0, 48)
+	t_c := make([]byte, 0, 48)
+	t_d := make([]byte, 0, 48)
+
+	t_a = append(t_a, msgKey...)
+	t_a = append(t_a, authKey[x:x+32]...)
+
+	t_b = append(t_b, authKey[32+x:32+x+16]...)
+	t_b = append(t_b, msgKey...)
+	t_b = append(t_b, authKey[48+x:48+x+16]...)
+
+	t_c = append(t_c, authKey[64+x:64+x+32]...)
+	t_c = append(t_c, msgKey...)
+
+	t_d = append(t_d, msgKey...)
+	t_d = append(t_d, authKey[96+x:96+x+32]...)
+
+	sha1_a := crypto.Sha1Digest(t_a)
+	sha1_b := crypto.Sha1Digest(t_b)
+	sha1_c := crypto.Sha1Digest(t_c)
+	sha1_d := crypto.Sha1Digest(t_d)
+
+	aesKey = append(aesKey, sha1_a[0:8]...)
+	aesKey = append(aesKey, sha1_b[8:8+12]...)
+	aesKey = append(aesKey, sha1_c[4:4+12]...)
+
+	aesIV = append(aesIV, sha1_a[8:8+12]...)
+	aesIV = append(aesIV, sha1_b[0:8]...)
+	aesIV = append(aesIV, sha1_c[16:16+4]...)
+	aesIV = append(aesIV, sha1_d[0:8]...)
 
 	return
 }
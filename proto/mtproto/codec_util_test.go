@@ -0,0 +1,63 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGenerateMessageId(t *testing.T) {
+	const n = 10000
+	var (
+		wg  sync.WaitGroup
+		ids [4][]int64
+	)
+
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				ids[i] = append(ids[i], GenerateMessageId())
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	now := time.Now().Unix()
+	seen := make(map[int64]bool, len(ids)*n)
+	for i := range ids {
+		for j, id := range ids[i] {
+			if id%4 != 1 {
+				t.Fatalf("invalid msg_id: %d", id)
+			}
+			if j > 0 && id <= ids[i][j-1] {
+				t.Fatalf("msg_id must be increasing: %d <= %d", id, ids[i][j-1])
+			}
+			if seen[id] {
+				t.Fatalf("duplicate msg_id: %d", id)
+			}
+			seen[id] = true
+			if d := now - id>>32; d < 0 || d > 1 {
+				t.Fatalf("invalid msg_id time: %d (now %d)", id>>32, now)
+			}
+		}
+	}
+}
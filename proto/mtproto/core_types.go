@@ -21,6 +21,12 @@ const (
 	MTPROTO_VERSION = 2
 )
 
+// authKeyInfo.auth_key_type
+const (
+	AUTH_KEY_TYPE_PERM = 0 // 永久key
+	AUTH_KEY_TYPE_TEMP = 1 // 临时key(PFS)
+)
+
 type TLObject interface {
 	Encode() []byte
 	EncodeToLayer(layer int) []byte
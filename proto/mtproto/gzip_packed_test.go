@@ -0,0 +1,94 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGzipPacked(t *testing.T) {
+	// 可压缩的数据
+	data := bytes.Repeat(NewTLPing().Encode(), 100)
+	gzipPacked := NewTLGzipPackedByData(data)
+	if gzipPacked == nil {
+		t.Fatal("data must be compressed")
+	}
+	b := gzipPacked.Encode()
+	if len(b) >= len(data) {
+		t.Fatalf("invalid gzip_packed len: %d, data len: %d", len(b), len(data))
+	}
+
+	obj := NewDecodeBuf(b).Object()
+	gzipPacked2, ok := obj.(*TLGzipPacked)
+	if !ok || !bytes.Equal(gzipPacked2.PackedData, data) {
+		t.Fatalf("invalid object: %v", obj)
+	}
+
+	// 压缩后不会变小
+	if NewTLGzipPackedByData(NewTLPing().Encode()) != nil {
+		t.Fatal("small data must not be compressed")
+	}
+
+	// 错误的gzip数据
+	x := NewEncodeBuf(512)
+	x.Int(int32(TLConstructor_CRC32_gzip_packed))
+	x.StringBytes([]byte("invalid gzip data"))
+	dbuf := NewDecodeBuf(x.GetBuf())
+	dbuf.Object()
+	if dbuf.GetError() == nil {
+		t.Fatal("invalid gzip data must be rejected")
+	}
+
+	// 解压后超过上限
+	gzipData, _ := gzipCompress(make([]byte, kMaxGzipPackedDataLen+1))
+	x = NewEncodeBuf(len(gzipData) + 8)
+	x.Int(int32(TLConstructor_CRC32_gzip_packed))
+	x.StringBytes(gzipData)
+	dbuf = NewDecodeBuf(x.GetBuf())
+	dbuf.Object()
+	if dbuf.GetError() == nil {
+		t.Fatal("too large gzip_packed must be rejected")
+	}
+}
+
+func TestGzipPackedInContainer(t *testing.T) {
+	ping := &TLPing{PingId: 1}
+	msgContainer := &TLMsgContainer{
+		Messages: []TLMessage2{{
+			MsgId:  6500000000000000004,
+			Seqno:  1,
+			Object: &TLGzipPacked{PackedData: ping.Encode()},
+		}},
+	}
+	msgContainer.Messages[0].Bytes = int32(len(msgContainer.Messages[0].Object.Encode()))
+
+	obj := NewDecodeBuf(msgContainer.Encode()).Object()
+	msgContainer2, ok := obj.(*TLMsgContainer)
+	if !ok || len(msgContainer2.Messages) != 1 {
+		t.Fatalf("invalid object: %v", obj)
+	}
+	gzipPacked, ok := msgContainer2.Messages[0].Object.(*TLGzipPacked)
+	if !ok {
+		t.Fatalf("invalid object: %v", msgContainer2.Messages[0].Object)
+	}
+	ping2, ok := NewDecodeBuf(gzipPacked.PackedData).Object().(*TLPing)
+	if !ok || ping2.PingId != ping.PingId {
+		t.Fatalf("invalid ping: %v", ping2)
+	}
+}
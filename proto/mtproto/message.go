@@ -149,12 +149,13 @@ type EncryptedMessage2 struct {
 	authKeyId int64
 	NeedAck   bool
 
-	msgKey    []byte
-	Salt      int64
-	SessionId int64
-	MessageId int64
-	SeqNo     int32
-	Object    TLObject
+	msgKey        []byte
+	quickAckToken int32
+	Salt          int64
+	SessionId     int64
+	MessageId     int64
+	SeqNo         int32
+	Object        TLObject
 }
 
 func NewEncryptedMessage2(authKeyId int64) *EncryptedMessage2 {
@@ -172,6 +173,11 @@ func (m *EncryptedMessage2) MessageType() int {
 	return ENCRYPTED_MESSAGE
 }
 
+// 解密成功后有效，用于回复传输层QuickAck
+func (m *EncryptedMessage2) QuickAckToken() int32 {
+	return m.quickAckToken
+}
+
 func (m *EncryptedMessage2) Encode(authKeyId int64, authKey []byte) ([]byte, error) {
 	buf, err := m.encode(authKeyId, authKey)
 	return buf, err
@@ -349,6 +355,14 @@ func (m *EncryptedMessage2) decrypt(msgKey, authKey, data []byte) ([]byte, error
 		return nil, err
 	}
 
+	// quick ack token: msg_key_large的前32位，最高位置1
+	switch MTPROTO_VERSION {
+	case 2:
+		m.quickAckToken = int32(binary.LittleEndian.Uint32(messageKey) | 0x80000000)
+	default:
+		m.quickAckToken = int32(binary.LittleEndian.Uint32(messageKey[4:]) | 0x80000000)
+	}
+
 	return x, nil
 }
 
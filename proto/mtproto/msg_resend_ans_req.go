@@ -0,0 +1,65 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"fmt"
+)
+
+// https://core.telegram.org/mtproto/service_messages_about_messages#explicit-request-to-re-send-answers
+const (
+	TLConstructor_CRC32_msg_resend_ans_req TLConstructor = -2045723925 // 0x8610baeb
+)
+
+func init() {
+	registers2[int32(TLConstructor_CRC32_msg_resend_ans_req)] = TLObjectHelper{newTLObjectFunc: func() TLObject { return NewTLMsgResendAnsReq() }, layer: 0, classIdList: []int32{}}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// msg_resend_ans_req#8610baeb msg_ids:Vector<long> = MsgResendReq;
+type TLMsgResendAnsReq struct {
+	MsgIds []int64
+}
+
+func NewTLMsgResendAnsReq() *TLMsgResendAnsReq {
+	return &TLMsgResendAnsReq{}
+}
+
+func (m *TLMsgResendAnsReq) String() string {
+	return fmt.Sprintf("{msg_resend_ans_req#8610baeb - msg_ids: %v}", m.MsgIds)
+}
+
+func (m *TLMsgResendAnsReq) GetMsgIds() []int64 {
+	return m.MsgIds
+}
+
+func (m *TLMsgResendAnsReq) Encode() []byte {
+	x := NewEncodeBuf(512)
+	x.Int(int32(TLConstructor_CRC32_msg_resend_ans_req))
+	x.VectorLong(m.MsgIds)
+	return x.buf
+}
+
+func (m *TLMsgResendAnsReq) EncodeToLayer(layer int) []byte {
+	return m.Encode()
+}
+
+func (m *TLMsgResendAnsReq) Decode(dbuf *DecodeBuf) error {
+	m.MsgIds = dbuf.VectorLong()
+	return dbuf.err
+}
@@ -0,0 +1,36 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMsgResendAnsReq(t *testing.T) {
+	req := &TLMsgResendAnsReq{MsgIds: []int64{6500000000000000004, 6500000000000000008}}
+
+	obj := NewDecodeBuf(req.Encode()).Object()
+	req2, ok := obj.(*TLMsgResendAnsReq)
+	if !ok {
+		t.Fatalf("invalid object: %v", obj)
+	}
+	if !reflect.DeepEqual(req2.MsgIds, req.MsgIds) {
+		t.Fatalf("invalid msg_ids: %v (need %v)", req2.MsgIds, req.MsgIds)
+	}
+}
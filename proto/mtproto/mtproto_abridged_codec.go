@@ -22,7 +22,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/golang/glog"
-	"github.com/nebulaim/telegramd/baselib/net2"
 	"io"
 )
 
@@ -35,11 +34,12 @@ import (
 // by the data themselves (sequence number and CRC32 not added).
 // In this case, server responses look the same (the server does not send 0xefas the first byte).
 //
+// The codec is also used as the default inner protocol of the obfuscated transport (MTProtoAppCodec).
 type MTProtoAbridgedCodec struct {
-	conn *net2.BufferedConn
+	conn io.ReadWriteCloser
 }
 
-func NewMTProtoAbridgedCodec(conn *net2.BufferedConn) *MTProtoAbridgedCodec {
+func NewMTProtoAbridgedCodec(conn io.ReadWriteCloser) *MTProtoAbridgedCodec {
 	return &MTProtoAbridgedCodec{
 		conn: conn,
 	}
@@ -72,7 +72,6 @@ func (c *MTProtoAbridgedCodec) Receive() (interface{}, error) {
 
 	// glog.Info("first_byte: ", hex.EncodeToString(b[:1]))
 	needAck := bool(b[0]>>7 == 1)
-	_ = needAck
 
 	b[0] = b[0] & 0x7f
 	// glog.Info("first_byte2: ", hex.EncodeToString(b[:1]))
@@ -108,7 +107,6 @@ func (c *MTProtoAbridgedCodec) Receive() (interface{}, error) {
 		glog.Info("ReadFull2: ", hex.EncodeToString(buf[:256]))
 	}
 
-	// TODO(@benqi): process report ack and quickack
 	// 截断QuickAck消息，客户端有问题
 	if size == 4 {
 		glog.Errorf("Server response error: ", int32(binary.LittleEndian.Uint32(buf)))
@@ -118,20 +116,30 @@ func (c *MTProtoAbridgedCodec) Receive() (interface{}, error) {
 
 	authKeyId := int64(binary.LittleEndian.Uint64(buf))
 	message := NewMTPRawMessage(authKeyId, 0, TRANSPORT_TCP)
+	message.needQuickAck = needAck
 	message.Decode(buf)
 	return message, nil
 }
 
 func (c *MTProtoAbridgedCodec) Send(msg interface{}) error {
-	message, ok := msg.(*MTPRawMessage)
-	if !ok {
-		err := fmt.Errorf("msg type error, only MTPRawMessage, msg: {%v}", msg)
+	var b []byte
+
+	switch message := msg.(type) {
+	case *MTPRawMessage:
+		b = message.Encode()
+	case *MTPTransportError:
+		b = message.Encode()
+	case *MTPQuickAckMessage:
+		// abridged协议的QuickAck按大端序发送，首字节最高位为1，客户端据此和长度区分
+		b = make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(message.Token))
+		return c.write(b)
+	default:
+		err := fmt.Errorf("msg type error, only MTPRawMessage, MTPTransportError or MTPQuickAckMessage, msg: {%v}", msg)
 		glog.Error(err)
 		return err
 	}
 
-	b := message.Encode()
-
 	sb := make([]byte, 4)
 	// minus padding
 	size := len(b) / 4
@@ -142,7 +150,10 @@ func (c *MTProtoAbridgedCodec) Send(msg interface{}) error {
 		binary.LittleEndian.PutUint32(sb, uint32(size<<8|127))
 	}
 
-	b = append(sb, b...)
+	return c.write(append(sb, b...))
+}
+
+func (c *MTProtoAbridgedCodec) write(b []byte) error {
 	_, err := c.conn.Write(b)
 
 	if err != nil {
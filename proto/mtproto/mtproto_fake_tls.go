@@ -0,0 +1,275 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"io"
+)
+
+// MTProxy fake-TLS transport (ee-prefixed secret)
+//
+// The client sends a TLS 1.3 ClientHello whose 32-byte random is
+// HMAC-SHA256(secret, ClientHello with zeroed random), the last 4 bytes xor-ed with the unix timestamp.
+// The server answers with ServerHello + ChangeCipherSpec + ApplicationData records,
+// the server random is HMAC-SHA256(secret, client_random + response with zeroed random).
+// All following data, starting with the 64-byte obfuscated init, is carried in ApplicationData records.
+const (
+	tlsRecordHandshake        = 0x16
+	tlsRecordChangeCipherSpec = 0x14
+	tlsRecordApplicationData  = 0x17
+
+	tlsHandshakeClientHello = 0x01
+	tlsHandshakeServerHello = 0x02
+
+	tlsRecordHeaderLen  = 5
+	tlsMaxRecordPayload = 16384
+
+	// ClientHello: record header(5) + handshake type(1) + length(3) + version(2) + random(32)
+	fakeTLSRandomOffset = 11
+	fakeTLSRandomLen    = 32
+	fakeTLSSessionIdLen = 32
+
+	// 客户端时间戳允许的最大误差(秒)
+	fakeTLSMaxTimeSkew = 300
+)
+
+type fakeTLSClientHello struct {
+	Random     []byte
+	SessionId  []byte
+	ServerName string
+	Timestamp  int64
+}
+
+// 校验ClientHello, hello为完整的TLS record
+func parseFakeTLSClientHello(secret []byte, hello []byte, now int64) (*fakeTLSClientHello, error) {
+	if len(hello) < fakeTLSRandomOffset+fakeTLSRandomLen+1+fakeTLSSessionIdLen {
+		return nil, fmt.Errorf("fake tls: client hello too short: %d", len(hello))
+	}
+	if hello[0] != tlsRecordHandshake || hello[1] != 0x03 || hello[2] != 0x01 {
+		return nil, fmt.Errorf("fake tls: invalid record header")
+	}
+	if int(binary.BigEndian.Uint16(hello[3:5]))+tlsRecordHeaderLen != len(hello) {
+		return nil, fmt.Errorf("fake tls: invalid record length")
+	}
+	if hello[5] != tlsHandshakeClientHello {
+		return nil, fmt.Errorf("fake tls: not a client hello: %d", hello[5])
+	}
+
+	random := make([]byte, fakeTLSRandomLen)
+	copy(random, hello[fakeTLSRandomOffset:])
+
+	zeroed := make([]byte, len(hello))
+	copy(zeroed, hello)
+	for i := 0; i < fakeTLSRandomLen; i++ {
+		zeroed[fakeTLSRandomOffset+i] = 0
+	}
+	mac := hmacSha256(secret, zeroed)
+
+	if !hmac.Equal(mac[:fakeTLSRandomLen-4], random[:fakeTLSRandomLen-4]) {
+		return nil, fmt.Errorf("fake tls: invalid client random")
+	}
+
+	var ts [4]byte
+	for i := 0; i < 4; i++ {
+		ts[i] = mac[fakeTLSRandomLen-4+i] ^ random[fakeTLSRandomLen-4+i]
+	}
+	timestamp := int64(binary.LittleEndian.Uint32(ts[:]))
+	if timestamp < now-fakeTLSMaxTimeSkew || timestamp > now+fakeTLSMaxTimeSkew {
+		return nil, fmt.Errorf("fake tls: invalid timestamp: %d (now %d)", timestamp, now)
+	}
+
+	offset := fakeTLSRandomOffset + fakeTLSRandomLen
+	if int(hello[offset]) != fakeTLSSessionIdLen {
+		return nil, fmt.Errorf("fake tls: invalid session_id len: %d", hello[offset])
+	}
+	offset++
+
+	h := &fakeTLSClientHello{
+		Random:    random,
+		SessionId: hello[offset : offset+fakeTLSSessionIdLen],
+		Timestamp: timestamp,
+	}
+	h.ServerName = parseTLSServerName(hello[offset+fakeTLSSessionIdLen:])
+	return h, nil
+}
+
+// 从ClientHello的cipher_suites开始解析extensions，查找server_name
+func parseTLSServerName(b []byte) string {
+	// cipher_suites
+	if len(b) < 2 {
+		return ""
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n+1 {
+		return ""
+	}
+	b = b[2+n:]
+
+	// compression_methods
+	n = int(b[0])
+	if len(b) < 1+n+2 {
+		return ""
+	}
+	b = b[1+n:]
+
+	// extensions
+	n = int(binary.BigEndian.Uint16(b))
+	b = b[2:]
+	if len(b) < n {
+		return ""
+	}
+	b = b[:n]
+
+	for len(b) >= 4 {
+		extType := binary.BigEndian.Uint16(b)
+		extLen := int(binary.BigEndian.Uint16(b[2:]))
+		b = b[4:]
+		if len(b) < extLen {
+			return ""
+		}
+		if extType == 0x0000 {
+			// server_name_list len(2) + name_type(1) + host_name len(2) + host_name
+			ext := b[:extLen]
+			if len(ext) < 5 || ext[2] != 0 {
+				return ""
+			}
+			nameLen := int(binary.BigEndian.Uint16(ext[3:]))
+			if len(ext) < 5+nameLen {
+				return ""
+			}
+			return string(ext[5 : 5+nameLen])
+		}
+		b = b[extLen:]
+	}
+	return ""
+}
+
+// 生成ServerHello + ChangeCipherSpec + ApplicationData
+func makeFakeTLSServerHello(secret []byte, clientHello *fakeTLSClientHello) []byte {
+	x := make([]byte, 0, 256)
+
+	// ServerHello
+	x = append(x, tlsRecordHandshake, 0x03, 0x03, 0x00, 0x7a)
+	x = append(x, tlsHandshakeServerHello, 0x00, 0x00, 0x76, 0x03, 0x03)
+	x = append(x, make([]byte, fakeTLSRandomLen)...)
+	x = append(x, fakeTLSSessionIdLen)
+	x = append(x, clientHello.SessionId...)
+	// TLS_AES_128_GCM_SHA256, no compression
+	x = append(x, 0x13, 0x01, 0x00)
+	// extensions: key_share(x25519) and supported_versions(TLS 1.3)
+	x = append(x, 0x00, 0x2e)
+	x = append(x, 0x00, 0x33, 0x00, 0x24, 0x00, 0x1d, 0x00, 0x20)
+	x = append(x, crypto.GenerateNonce(32)...)
+	x = append(x, 0x00, 0x2b, 0x00, 0x02, 0x03, 0x04)
+
+	// ChangeCipherSpec
+	x = append(x, tlsRecordChangeCipherSpec, 0x03, 0x03, 0x00, 0x01, 0x01)
+
+	// ApplicationData with random payload
+	n := 1024 + int(binary.LittleEndian.Uint16(crypto.GenerateNonce(2)))%3072
+	x = append(x, tlsRecordApplicationData, 0x03, 0x03, byte(n>>8), byte(n))
+	x = append(x, crypto.GenerateNonce(n)...)
+
+	mac := hmacSha256(secret, append(append([]byte{}, clientHello.Random...), x...))
+	copy(x[fakeTLSRandomOffset:], mac)
+	return x
+}
+
+func hmacSha256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////
+// FakeTLSConn把数据封装在TLS ApplicationData record中
+type FakeTLSConn struct {
+	conn io.ReadWriteCloser
+	left int // 当前record未读取的长度
+}
+
+func NewFakeTLSConn(conn io.ReadWriteCloser) *FakeTLSConn {
+	return &FakeTLSConn{
+		conn: conn,
+	}
+}
+
+func (c *FakeTLSConn) Read(p []byte) (int, error) {
+	for c.left == 0 {
+		var header [tlsRecordHeaderLen]byte
+		if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+			return 0, err
+		}
+		if header[1] != 0x03 || header[2] != 0x03 {
+			return 0, fmt.Errorf("fake tls: invalid record version: %x%x", header[1], header[2])
+		}
+
+		size := int(binary.BigEndian.Uint16(header[3:]))
+		switch header[0] {
+		case tlsRecordChangeCipherSpec:
+			// 客户端会先发送ChangeCipherSpec，忽略
+			var ccs [1]byte
+			if size != 1 {
+				return 0, fmt.Errorf("fake tls: invalid change_cipher_spec len: %d", size)
+			}
+			if _, err := io.ReadFull(c.conn, ccs[:]); err != nil {
+				return 0, err
+			}
+		case tlsRecordApplicationData:
+			if size > tlsMaxRecordPayload+256 {
+				return 0, fmt.Errorf("fake tls: record too large: %d", size)
+			}
+			c.left = size
+		default:
+			return 0, fmt.Errorf("fake tls: invalid record type: %d", header[0])
+		}
+	}
+
+	if len(p) > c.left {
+		p = p[:c.left]
+	}
+	n, err := c.conn.Read(p)
+	c.left -= n
+	return n, err
+}
+
+func (c *FakeTLSConn) Write(p []byte) (int, error) {
+	b := make([]byte, 0, len(p)+(len(p)/tlsMaxRecordPayload+1)*tlsRecordHeaderLen)
+	for i := 0; i < len(p); i += tlsMaxRecordPayload {
+		chunk := p[i:]
+		if len(chunk) > tlsMaxRecordPayload {
+			chunk = chunk[:tlsMaxRecordPayload]
+		}
+		b = append(b, tlsRecordApplicationData, 0x03, 0x03, byte(len(chunk)>>8), byte(len(chunk)))
+		b = append(b, chunk...)
+	}
+
+	if _, err := c.conn.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *FakeTLSConn) Close() error {
+	return c.conn.Close()
+}
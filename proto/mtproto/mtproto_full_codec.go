@@ -21,10 +21,16 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/golang/glog"
-	"github.com/nebulaim/telegramd/baselib/net2"
+	"hash/crc32"
 	"io"
 )
 
+const (
+	// length(4) + seq_num(4) + crc32(4)
+	fullPacketMinLen = 12
+	fullPacketMaxLen = 16 * 1024 * 1024
+)
+
 // https://core.telegram.org/mtproto#tcp-transport
 //
 // If a payload (packet) needs to be transmitted from server to client or from client to server,
@@ -35,94 +41,112 @@ import (
 // (the first packet sent is numbered 0, the next one 1, etc.),
 // and 4 CRC32 bytes at the end (length, sequence number, and payload together).
 //
+// 收到长度、seq_num或crc32不合法的包时返回错误，由net2关闭连接
 type MTProtoFullCodec struct {
-	conn *net2.BufferedConn
+	conn    io.ReadWriteCloser
+	recvSeq uint32 // 下一个收到的包的seq_num
+	sendSeq uint32 // 下一个发送的包的seq_num
 }
 
-func NewMTProtoFullCodec(conn *net2.BufferedConn) *MTProtoFullCodec {
+func NewMTProtoFullCodec(conn io.ReadWriteCloser) *MTProtoFullCodec {
 	return &MTProtoFullCodec{
 		conn: conn,
 	}
 }
 
 func (c *MTProtoFullCodec) Receive() (interface{}, error) {
-	var size int
-	var n int
-	var err error
-
 	b := make([]byte, 4)
-	n, err = io.ReadFull(c.conn, b)
+	_, err := io.ReadFull(c.conn, b)
 	if err != nil {
 		return nil, err
 	}
 
-	size = int(binary.LittleEndian.Uint32(b) << 2)
+	// 最高位为QuickAck标志
+	needAck := bool(b[3]>>7 == 1)
+	size := int(binary.LittleEndian.Uint32(b) & 0x7fffffff)
 	// Check bufLen
-	if size < 12 || size%4 != 0 {
+	if size < fullPacketMinLen || size > fullPacketMaxLen || size%4 != 0 {
 		err = fmt.Errorf("invalid len: %d", size)
+		glog.Error(err)
 		return nil, err
 	}
 
-	//buf := make([]byte, size - 4)
-	//n, err = io.ReadFull(c.conn, buf)
-	//if err != nil {
-	//	return nil, err
-	//}
-
-	left := size
 	buf := make([]byte, size-4)
-	for left > 0 {
-		n, err = io.ReadFull(c.conn, buf[size-left:])
-		if err != nil {
-			glog.Error("ReadFull2 error: ", err)
-			return nil, err
-		}
-		left -= n
+	_, err = io.ReadFull(c.conn, buf)
+	if err != nil {
+		glog.Error("ReadFull2 error: ", err)
+		return nil, err
+	}
+
+	crc := crc32.NewIEEE()
+	crc.Write(b)
+	crc.Write(buf[:len(buf)-4])
+	if crc.Sum32() != binary.LittleEndian.Uint32(buf[len(buf)-4:]) {
+		err = fmt.Errorf("invalid crc32: %d (need %d)", binary.LittleEndian.Uint32(buf[len(buf)-4:]), crc.Sum32())
+		glog.Error(err)
+		return nil, err
 	}
 
 	seqNum := binary.LittleEndian.Uint32(buf[:4])
-	// TODO(@benqi): check seqNum, save last seq_num
-	_ = seqNum
+	if seqNum != c.recvSeq {
+		err = fmt.Errorf("invalid seq_num: %d (need %d)", seqNum, c.recvSeq)
+		glog.Error(err)
+		return nil, err
+	}
+	c.recvSeq++
 
-	crc32 := binary.LittleEndian.Uint32(buf[len(buf)-4:])
-	// TODO(@benqi): check crc32
-	_ = crc32
+	payload := buf[4 : len(buf)-4]
 
-	authKeyId := int64(binary.LittleEndian.Uint64(buf[4:]))
+	// 截断QuickAck消息，客户端有问题
+	if len(payload) == 4 {
+		glog.Errorf("Server response error: %d", int32(binary.LittleEndian.Uint32(payload)))
+		return nil, nil
+	}
+	if len(payload) < 8 {
+		err = fmt.Errorf("invalid payload len: %d", len(payload))
+		glog.Error(err)
+		return nil, err
+	}
+
+	authKeyId := int64(binary.LittleEndian.Uint64(payload))
 	message := NewMTPRawMessage(authKeyId, 0, TRANSPORT_TCP)
-	message.Decode(buf)
+	message.needQuickAck = needAck
+	message.Decode(payload)
 	return message, nil
 }
 
 func (c *MTProtoFullCodec) Send(msg interface{}) error {
-	message, ok := msg.(*MTPRawMessage)
-	if !ok {
-		err := fmt.Errorf("msg type error, only MTPRawMessage, msg: {%v}", msg)
+	var b []byte
+
+	switch message := msg.(type) {
+	case *MTPRawMessage:
+		b = message.Encode()
+	case *MTPTransportError:
+		b = message.Encode()
+	case *MTPQuickAckMessage:
+		// QuickAck不带长度、seq_num和crc32，按小端序发送，最高位为1
+		return c.write(message.Encode())
+	default:
+		err := fmt.Errorf("msg type error, only MTPRawMessage, MTPTransportError or MTPQuickAckMessage, msg: {%v}", msg)
 		glog.Error(err)
 		return err
 	}
 
-	b := message.Encode()
-
-	sb := make([]byte, 8)
-	// minus padding
-	size := len(b) / 4
+	size := fullPacketMinLen + len(b)
+	sb := make([]byte, 8, size)
+	binary.LittleEndian.PutUint32(sb, uint32(size))
+	binary.LittleEndian.PutUint32(sb[4:], c.sendSeq)
+	sb = append(sb, b...)
 
-	//if size < 127 {
-	//	sb = []byte{byte(size)}
-	//} else {
+	crc32Buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(crc32Buf, crc32.ChecksumIEEE(sb))
+	sb = append(sb, crc32Buf...)
 
-	binary.LittleEndian.PutUint32(sb, uint32(size))
-	// TODO(@benqi): gen seq_num
-	var seqNum uint32 = 0
-	binary.LittleEndian.PutUint32(sb[4:], seqNum)
-	//}
-	b = append(sb, b...)
-	var crc32Buf []byte = make([]byte, 4)
-	var crc32 uint32 = 0
-	binary.LittleEndian.PutUint32(crc32Buf, crc32)
-	b = append(sb, crc32Buf...)
+	c.sendSeq++
+	return c.write(sb)
+}
 
+func (c *MTProtoFullCodec) write(b []byte) error {
 	_, err := c.conn.Write(b)
 	if err != nil {
 		glog.Errorf("Send msg error: %s", err)
@@ -0,0 +1,210 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/nebulaim/telegramd/baselib/net2"
+)
+
+// 按full协议打包
+func makeTestFullPacket(seqNum uint32, payload []byte) []byte {
+	b := make([]byte, 8, 12+len(payload))
+	binary.LittleEndian.PutUint32(b, uint32(12+len(payload)))
+	binary.LittleEndian.PutUint32(b[4:], seqNum)
+	b = append(b, payload...)
+	crc := make([]byte, 4)
+	binary.LittleEndian.PutUint32(crc, crc32.ChecksumIEEE(b))
+	return append(b, crc...)
+}
+
+// bytes.Reader作为连接，读完返回io.EOF
+type testReadConn struct {
+	*bytes.Reader
+}
+
+func (c testReadConn) Write(p []byte) (int, error) { return len(p), nil }
+func (c testReadConn) Close() error                { return nil }
+
+func newTestFullCodec(data []byte) *MTProtoFullCodec {
+	return NewMTProtoFullCodec(testReadConn{bytes.NewReader(data)})
+}
+
+func TestFullCodecRoundTrip(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewMTProtoFullCodec(net2.NewBufferedConn(c1))
+	client := NewMTProtoFullCodec(c2)
+	defer server.Close()
+	defer client.Close()
+
+	messages := testRawMessages()[2:]
+	go func() {
+		for _, m := range messages {
+			client.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, server, m)
+	}
+
+	go func() {
+		for _, m := range messages {
+			server.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, client, m)
+	}
+}
+
+func TestFullCodecSend(t *testing.T) {
+	var buf bytes.Buffer
+	codec := NewMTProtoFullCodec(struct {
+		io.ReadWriter
+		io.Closer
+	}{&buf, nil})
+
+	m := testRawMessages()[2]
+	codec.Send(m)
+	codec.Send(NewMTPQuickAckMessage(testQuickAckToken))
+	codec.Send(NewMTPTransportError(TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND))
+	codec.Send(m)
+
+	// QuickAck不占用seq_num
+	var want []byte
+	want = append(want, makeTestFullPacket(0, m.Payload)...)
+	want = append(want, 0x04, 0x03, 0x02, 0x81)
+	want = append(want, makeTestFullPacket(1, transportErrorBytes(-404))...)
+	want = append(want, makeTestFullPacket(2, m.Payload)...)
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("invalid data: %v (need %v)", buf.Bytes(), want)
+	}
+}
+
+func TestFullCodecQuickAck(t *testing.T) {
+	m := testRawMessages()[2]
+	p := makeTestFullPacket(0, m.Payload)
+	p[3] |= 0x80
+	// crc32包含QuickAck标志
+	binary.LittleEndian.PutUint32(p[len(p)-4:], crc32.ChecksumIEEE(p[:len(p)-4]))
+
+	checkReceivedQuickAck(t, newTestFullCodec(p), m)
+}
+
+func TestFullCodecInvalidFrames(t *testing.T) {
+	payload := testRawMessages()[2].Payload
+
+	badCrc := makeTestFullPacket(0, payload)
+	badCrc[len(badCrc)-1] ^= 0xff
+
+	badLen := makeTestFullPacket(0, payload)
+	binary.LittleEndian.PutUint32(badLen, 8)
+
+	unaligned := makeTestFullPacket(0, payload)
+	binary.LittleEndian.PutUint32(unaligned, uint32(len(unaligned)+1))
+
+	tooLarge := makeTestFullPacket(0, payload)
+	binary.LittleEndian.PutUint32(tooLarge, fullPacketMaxLen+4)
+
+	corrupted := makeTestFullPacket(0, payload)
+	corrupted[20] ^= 0x01
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"bad crc32", badCrc},
+		{"bad seq_num", makeTestFullPacket(1, payload)},
+		{"len too small", badLen},
+		{"len not aligned", unaligned},
+		{"len too large", tooLarge},
+		{"corrupted payload", corrupted},
+		{"truncated", makeTestFullPacket(0, payload)[:30]},
+		{"empty payload", makeTestFullPacket(0, nil)},
+	}
+
+	for _, tt := range tests {
+		if r, err := newTestFullCodec(tt.data).Receive(); err == nil {
+			t.Errorf("%s: must be rejected, receive %v", tt.name, r)
+		}
+	}
+}
+
+func TestFullCodecReplayedFrame(t *testing.T) {
+	payload := testRawMessages()[2].Payload
+	p := makeTestFullPacket(0, payload)
+
+	codec := newTestFullCodec(append(append([]byte{}, p...), p...))
+	if _, err := codec.Receive(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := codec.Receive(); err == nil {
+		t.Fatal("replayed frame must be rejected")
+	}
+}
+
+// 随机修改合法的数据包，codec不能panic，且不能接受crc32不匹配的包
+func TestFullCodecFuzz(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	messages := testRawMessages()[2:]
+
+	for i := 0; i < 2000; i++ {
+		var data []byte
+		for seq, m := range messages {
+			data = append(data, makeTestFullPacket(uint32(seq), m.Payload)...)
+		}
+		orig := append([]byte{}, data...)
+
+		switch r.Intn(3) {
+		case 0:
+			// 随机翻转若干字节
+			for n := r.Intn(4) + 1; n > 0; n-- {
+				data[r.Intn(len(data))] ^= byte(r.Intn(255) + 1)
+			}
+		case 1:
+			// 截断
+			data = data[:r.Intn(len(data))]
+		case 2:
+			// 随机数据
+			data = make([]byte, r.Intn(256))
+			r.Read(data)
+		}
+
+		codec := newTestFullCodec(data)
+		for n := 0; ; n++ {
+			msg, err := codec.Receive()
+			if err != nil {
+				break
+			}
+			if n >= len(messages) {
+				t.Fatalf("receive too many messages: %v", msg)
+			}
+			got, ok := msg.(*MTPRawMessage)
+			if !ok || !bytes.Equal(got.Payload, messages[n].Payload) {
+				t.Fatalf("corrupted frame accepted: %v (orig %v, data %v)", msg, orig, data)
+			}
+		}
+	}
+}
@@ -78,18 +78,26 @@ func (c *MTProtoHttpProxyCodec) Receive() (interface{}, error) {
 
 func (c *MTProtoHttpProxyCodec) Send(msg interface{}) error {
 	// SendToHttpReply(msg, w)
-	message, ok := msg.(*MTPRawMessage)
-	if !ok {
-		err := fmt.Errorf("msg type error, only MTPRawMessage, msg: {%v}", msg)
+	switch message := msg.(type) {
+	case *MTPRawMessage:
+		return c.writeResponse(http.StatusOK, message.Encode())
+	case *MTPTransportError:
+		// http传输使用http状态码(404/429/444)返回错误
+		return c.writeResponse(int(-message.Code), message.Encode())
+	case *MTPQuickAckMessage:
+		// http传输不支持QuickAck
+		return nil
+	default:
+		err := fmt.Errorf("msg type error, only MTPRawMessage, MTPTransportError or MTPQuickAckMessage, msg: {%v}", msg)
 		glog.Error(err)
 		// conn.Close()
 		return err
 	}
+}
 
-	b := message.Encode()
-
+func (c *MTProtoHttpProxyCodec) writeResponse(statusCode int, b []byte) error {
 	rsp := http.Response{
-		StatusCode: 200,
+		StatusCode: statusCode,
 		ProtoMajor: 1,
 		ProtoMinor: 1,
 		Request:    &http.Request{Method: "POST"},
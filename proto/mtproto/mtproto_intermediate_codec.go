@@ -21,7 +21,6 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/golang/glog"
-	"github.com/nebulaim/telegramd/baselib/net2"
 	"io"
 )
 
@@ -35,10 +34,10 @@ import (
 // thus decreasing total packet size by 8 bytes.
 //
 type MTProtoIntermediateCodec struct {
-	conn *net2.BufferedConn
+	conn io.ReadWriteCloser
 }
 
-func NewMTProtoIntermediateCodec(conn *net2.BufferedConn) *MTProtoIntermediateCodec {
+func NewMTProtoIntermediateCodec(conn io.ReadWriteCloser) *MTProtoIntermediateCodec {
 	return &MTProtoIntermediateCodec{
 		conn: conn,
 	}
@@ -55,11 +54,13 @@ func (c *MTProtoIntermediateCodec) Receive() (interface{}, error) {
 		return nil, err
 	}
 
-	size = int(binary.LittleEndian.Uint32(b) << 2)
-
-	// glog.Info("first_byte: ", hex.EncodeToString(b[:1]))
-	// needAck := bool(b[0] >> 7 == 1)
-	// _ = needAck
+	// 长度为字节数，最高位为QuickAck标志
+	needAck := bool(b[3]>>7 == 1)
+	size = int(binary.LittleEndian.Uint32(b) & 0x7fffffff)
+	if size < 4 || size%4 != 0 {
+		err = fmt.Errorf("invalid len: %d", size)
+		return nil, err
+	}
 
 	//b[0] = b[0] & 0x7f
 	//// glog.Info("first_byte2: ", hex.EncodeToString(b[:1]))
@@ -95,7 +96,6 @@ func (c *MTProtoIntermediateCodec) Receive() (interface{}, error) {
 	//	glog.Info("ReadFull2: ", hex.EncodeToString(buf[:256]))
 	//}
 
-	// TODO(@benqi): process report ack and quickack
 	// 截断QuickAck消息，客户端有问题
 	if size == 4 {
 		glog.Errorf("Server response error: ", int32(binary.LittleEndian.Uint32(buf)))
@@ -105,31 +105,35 @@ func (c *MTProtoIntermediateCodec) Receive() (interface{}, error) {
 
 	authKeyId := int64(binary.LittleEndian.Uint64(buf))
 	message := NewMTPRawMessage(authKeyId, 0, TRANSPORT_TCP)
+	message.needQuickAck = needAck
 	message.Decode(buf)
 	return message, nil
 }
 
 func (c *MTProtoIntermediateCodec) Send(msg interface{}) error {
-	message, ok := msg.(*MTPRawMessage)
-	if !ok {
-		err := fmt.Errorf("msg type error, only MTPRawMessage, msg: {%v}", msg)
+	var b []byte
+
+	switch message := msg.(type) {
+	case *MTPRawMessage:
+		b = message.Encode()
+	case *MTPTransportError:
+		b = message.Encode()
+	case *MTPQuickAckMessage:
+		// QuickAck不带长度，按小端序发送，最高位为1
+		return c.write(message.Encode())
+	default:
+		err := fmt.Errorf("msg type error, only MTPRawMessage, MTPTransportError or MTPQuickAckMessage, msg: {%v}", msg)
 		glog.Error(err)
 		return err
 	}
 
-	b := message.Encode()
-
-	sb := make([]byte, 4)
-	// minus padding
-	size := len(b) / 4
+	sb := make([]byte, 4, 4+len(b))
+	binary.LittleEndian.PutUint32(sb, uint32(len(b)))
 
-	//if size < 127 {
-	//	sb = []byte{byte(size)}
-	//} else {
-	binary.LittleEndian.PutUint32(sb, uint32(size))
-	//}
+	return c.write(append(sb, b...))
+}
 
-	b = append(sb, b...)
+func (c *MTProtoIntermediateCodec) write(b []byte) error {
 	_, err := c.conn.Write(b)
 
 	if err != nil {
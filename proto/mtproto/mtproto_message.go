@@ -18,6 +18,7 @@
 package mtproto
 
 import (
+	"encoding/binary"
 	"fmt"
 )
 
@@ -43,9 +44,10 @@ func NewMTPRawMessage(authKeyId int64, quickAckId int32, connType int) *MTPRawMe
 ////////////////////////////////////////////////////////////////////////////
 // 代理使用
 type MTPRawMessage struct {
-	connType   int
-	authKeyId  int64 // 由原始数据解压获得
-	quickAckId int32 // EncryptedMessage，则可能存在
+	connType     int
+	authKeyId    int64 // 由原始数据解压获得
+	quickAckId   int32 // EncryptedMessage，则可能存在
+	needQuickAck bool  // 客户端在传输层设置了QuickAck标志
 
 	// 原始数据
 	Payload []byte
@@ -71,6 +73,10 @@ func (m *MTPRawMessage) QuickAckId() int32 {
 	return m.quickAckId
 }
 
+func (m *MTPRawMessage) NeedQuickAck() bool {
+	return m.needQuickAck
+}
+
 ////////////////////////////////////////////////////////////////////////////
 func (m *MTPRawMessage) Encode() []byte {
 	return m.Payload
@@ -81,6 +87,75 @@ func (m *MTPRawMessage) Decode(b []byte) error {
 	return nil
 }
 
+////////////////////////////////////////////////////////////////////////////
+// https://core.telegram.org/mtproto/mtproto-transports#quick-ack
+//
+// Token为msg_key_large的前32位(最高位置1)，需要auth_key计算，由session服务器生成
+type MTPQuickAckMessage struct {
+	Token int32
+}
+
+func NewMTPQuickAckMessage(token int32) *MTPQuickAckMessage {
+	return &MTPQuickAckMessage{
+		Token: token,
+	}
+}
+
+func (m *MTPQuickAckMessage) String() string {
+	return fmt.Sprintf("{quick_ack_token: %d}", m.Token)
+}
+
+func (m *MTPQuickAckMessage) Encode() []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(m.Token))
+	return b
+}
+
+func (m *MTPQuickAckMessage) Decode(b []byte) error {
+	if len(b) != 4 {
+		return fmt.Errorf("invalid MTPQuickAckMessage len: %d", len(b))
+	}
+	m.Token = int32(binary.LittleEndian.Uint32(b))
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////
+// https://core.telegram.org/mtproto/mtproto-transports#transport-errors
+const (
+	TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND = -404 // auth_key不存在
+	TRANSPORT_ERROR_FLOOD              = -429 // 连接过多或请求过快
+	TRANSPORT_ERROR_INVALID_DC         = -444 // 无效的dc
+)
+
+// 传输层错误，按普通数据包发送，payload为4字节的错误码
+type MTPTransportError struct {
+	Code int32
+}
+
+func NewMTPTransportError(code int32) *MTPTransportError {
+	return &MTPTransportError{
+		Code: code,
+	}
+}
+
+func (m *MTPTransportError) String() string {
+	return fmt.Sprintf("{transport_error: %d}", m.Code)
+}
+
+func (m *MTPTransportError) Encode() []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(m.Code))
+	return b
+}
+
+func (m *MTPTransportError) Decode(b []byte) error {
+	if len(b) != 4 {
+		return fmt.Errorf("invalid MTPTransportError len: %d", len(b))
+	}
+	m.Code = int32(binary.LittleEndian.Uint32(b))
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////
 func NewUnencryptedRawMessage() *UnencryptedRawMessage {
 	return &UnencryptedRawMessage{
@@ -0,0 +1,151 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"io"
+)
+
+const (
+	// 随机填充长度为0~15
+	paddedIntermediateMaxPadding = 16
+)
+
+// https://core.telegram.org/mtproto/mtproto-transports#padded-intermediate
+//
+// Padded version of the intermediate protocol, to use with obfuscation enabled to bypass ISP blocks.
+// if the client sends 0xdddddddd as the first int (four bytes),
+// then packet length is encoded always by four bytes as in the intermediate version,
+// followed by the payload and 0-15 bytes of random padding.
+// The padding is included in the packet length.
+//
+// The codec is used both as a raw tcp transport and as
+// the inner protocol of the obfuscated transport (MTProtoAppCodec).
+type MTProtoPaddedIntermediateCodec struct {
+	conn io.ReadWriteCloser
+}
+
+func NewMTProtoPaddedIntermediateCodec(conn io.ReadWriteCloser) *MTProtoPaddedIntermediateCodec {
+	return &MTProtoPaddedIntermediateCodec{
+		conn: conn,
+	}
+}
+
+func (c *MTProtoPaddedIntermediateCodec) Receive() (interface{}, error) {
+	b := make([]byte, 4)
+	_, err := io.ReadFull(c.conn, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// 最高位为QuickAck标志
+	needAck := bool(b[3]>>7 == 1)
+	size := int(binary.LittleEndian.Uint32(b) & 0x7fffffff)
+	if size < 8 {
+		err = fmt.Errorf("invalid len: %d", size)
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+	_, err = io.ReadFull(c.conn, buf)
+	if err != nil {
+		glog.Error("ReadFull2 error: ", err)
+		return nil, err
+	}
+
+	authKeyId := int64(binary.LittleEndian.Uint64(buf))
+	buf, err = trimPaddedPayload(authKeyId, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	message := NewMTPRawMessage(authKeyId, 0, TRANSPORT_TCP)
+	message.needQuickAck = needAck
+	message.Decode(buf)
+	return message, nil
+}
+
+func (c *MTProtoPaddedIntermediateCodec) Send(msg interface{}) error {
+	var (
+		b       []byte
+		padding []byte
+	)
+
+	switch message := msg.(type) {
+	case *MTPRawMessage:
+		b = message.Encode()
+		padding = crypto.GenerateNonce(int(crypto.GenerateNonce(1)[0]) % paddedIntermediateMaxPadding)
+	case *MTPTransportError:
+		// 客户端按4字节长度识别错误码，不能填充
+		b = message.Encode()
+	case *MTPQuickAckMessage:
+		// QuickAck不带长度，按小端序发送，最高位为1
+		return c.write(message.Encode())
+	default:
+		err := fmt.Errorf("msg type error, only MTPRawMessage, MTPTransportError or MTPQuickAckMessage, msg: {%v}", msg)
+		glog.Error(err)
+		return err
+	}
+
+	sb := make([]byte, 4, 4+len(b)+len(padding))
+	binary.LittleEndian.PutUint32(sb, uint32(len(b)+len(padding)))
+
+	sb = append(sb, b...)
+	sb = append(sb, padding...)
+	return c.write(sb)
+}
+
+func (c *MTProtoPaddedIntermediateCodec) write(b []byte) error {
+	_, err := c.conn.Write(b)
+
+	if err != nil {
+		glog.Errorf("Send msg error: %s", err)
+	}
+
+	return err
+}
+
+func (c *MTProtoPaddedIntermediateCodec) Close() error {
+	return c.conn.Close()
+}
+
+// 去掉随机填充数据
+//
+// unencrypted message: auth_key_id(8) + message_id(8) + message_data_length(4) + message_data
+// encrypted message: auth_key_id(8) + msg_key(16) + encrypted_data (divisible by 16)
+func trimPaddedPayload(authKeyId int64, buf []byte) ([]byte, error) {
+	if authKeyId == 0 {
+		if len(buf) < 20 {
+			return nil, fmt.Errorf("invalid unencrypted message len: %d", len(buf))
+		}
+		messageLen := int(int32(binary.LittleEndian.Uint32(buf[16:])))
+		if messageLen < 0 || 20+messageLen > len(buf) || len(buf)-20-messageLen >= paddedIntermediateMaxPadding {
+			return nil, fmt.Errorf("invalid unencrypted message len: %d (buf len %d)", messageLen, len(buf))
+		}
+		return buf[:20+messageLen], nil
+	}
+
+	if len(buf) < 24+16 {
+		return nil, fmt.Errorf("invalid encrypted message len: %d", len(buf))
+	}
+	return buf[:24+(len(buf)-24)/16*16], nil
+}
@@ -0,0 +1,257 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"github.com/nebulaim/telegramd/baselib/net2"
+)
+
+func makeTestUnencryptedPayload(data []byte) []byte {
+	x := NewEncodeBuf(20 + len(data))
+	x.Long(0)
+	x.Long(GenerateMessageId())
+	x.Int(int32(len(data)))
+	x.Bytes(data)
+	return x.buf
+}
+
+func makeTestEncryptedPayload(authKeyId int64, dataLen int) []byte {
+	x := NewEncodeBuf(24 + dataLen)
+	x.Long(authKeyId)
+	x.Bytes(crypto.GenerateNonce(16 + dataLen))
+	return x.buf
+}
+
+func testRawMessages() []*MTPRawMessage {
+	payloads := [][]byte{
+		makeTestUnencryptedPayload(crypto.GenerateNonce(40)),
+		makeTestUnencryptedPayload(crypto.GenerateNonce(1)),
+		makeTestEncryptedPayload(0x1234567890, 32),
+		makeTestEncryptedPayload(-1, 4096),
+	}
+
+	messages := make([]*MTPRawMessage, 0, len(payloads))
+	for _, p := range payloads {
+		m := NewMTPRawMessage(int64(binary.LittleEndian.Uint64(p)), 0, TRANSPORT_TCP)
+		m.Decode(p)
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+func checkReceived(t *testing.T, codec net2.Codec, want *MTPRawMessage) {
+	r, err := codec.Receive()
+	if err != nil {
+		t.Fatalf("receive error: %v", err)
+	}
+	got, ok := r.(*MTPRawMessage)
+	if !ok {
+		t.Fatalf("receive invalid message: %v", r)
+	}
+	if got.AuthKeyId() != want.AuthKeyId() {
+		t.Fatalf("auth_key_id mismatch: %d != %d", got.AuthKeyId(), want.AuthKeyId())
+	}
+	if !bytes.Equal(got.Payload, want.Payload) {
+		t.Fatalf("payload mismatch: len %d != %d", len(got.Payload), len(want.Payload))
+	}
+}
+
+func TestPaddedIntermediateCodecRoundTrip(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewMTProtoPaddedIntermediateCodec(net2.NewBufferedConn(c1))
+	client := NewMTProtoPaddedIntermediateCodec(c2)
+	defer server.Close()
+	defer client.Close()
+
+	messages := testRawMessages()
+	go func() {
+		for _, m := range messages {
+			client.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, server, m)
+	}
+
+	go func() {
+		for _, m := range messages {
+			server.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, client, m)
+	}
+}
+
+func TestPaddedIntermediateCodecInvalidLength(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewMTProtoPaddedIntermediateCodec(net2.NewBufferedConn(c1))
+	defer server.Close()
+	defer c2.Close()
+
+	go func() {
+		// unencrypted message with message_data_length larger than the packet
+		p := makeTestUnencryptedPayload(crypto.GenerateNonce(8))
+		binary.LittleEndian.PutUint32(p[16:], 1024)
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(len(p)))
+		c2.Write(append(b, p...))
+	}()
+
+	if _, err := server.Receive(); err == nil {
+		t.Fatal("invalid message_data_length must be rejected")
+	}
+}
+
+func TestProxyCodecPaddedIntermediate(t *testing.T) {
+	c1, c2 := net.Pipe()
+	codec, _ := NewMTProtoProxy().NewCodec(net2.NewBufferedConn(c1))
+	defer codec.Close()
+	defer c2.Close()
+
+	messages := testRawMessages()
+	go func() {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, MTPROTO_PADDED_INTERMEDIATE_FLAG)
+		c2.Write(b)
+		client := NewMTProtoPaddedIntermediateCodec(c2)
+		for _, m := range messages {
+			client.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, codec, m)
+	}
+	if _, ok := codec.(*MTProtoProxyCodec).codec.(*MTProtoPaddedIntermediateCodec); !ok {
+		t.Fatalf("invalid codec: %T", codec.(*MTProtoProxyCodec).codec)
+	}
+}
+
+// 模拟客户端的混淆协议加密流
+type testObfuscatedClientStream struct {
+	net.Conn
+	encrypt *crypto.AesCTR128Encrypt
+	decrypt *crypto.AesCTR128Encrypt
+}
+
+// 生成混淆协议的64字节头，56~63字节为加密后的数据
+func newTestObfuscatedClientStream(t *testing.T, conn net.Conn, protocolTag uint32) (*testObfuscatedClientStream, []byte) {
+	var init []byte
+	for {
+		init = crypto.GenerateNonce(64)
+		val := binary.LittleEndian.Uint32(init)
+		if init[0] != MTPROTO_ABRIDGED_FLAG &&
+			val != HTTP_HEAD_FLAG &&
+			val != HTTP_POST_FLAG &&
+			val != HTTP_GET_FLAG &&
+			val != HTTP_OPTION_FLAG &&
+			val != MTPROTO_INTERMEDIATE_FLAG &&
+			val != MTPROTO_PADDED_INTERMEDIATE_FLAG &&
+			binary.LittleEndian.Uint32(init[4:]) != VAL2_FLAG {
+			break
+		}
+	}
+	binary.LittleEndian.PutUint32(init[56:], protocolTag)
+
+	var reversed [48]byte
+	for i := 0; i < 48; i++ {
+		reversed[i] = init[55-i]
+	}
+
+	e, err := crypto.NewAesCTR128Encrypt(init[8:40], init[40:56])
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := crypto.NewAesCTR128Encrypt(reversed[:32], reversed[32:48])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted := make([]byte, 64)
+	copy(encrypted, init)
+	e.Encrypt(encrypted)
+	copy(init[56:], encrypted[56:])
+
+	return &testObfuscatedClientStream{Conn: conn, encrypt: e, decrypt: d}, init
+}
+
+func (s *testObfuscatedClientStream) Read(p []byte) (int, error) {
+	n, err := s.Conn.Read(p)
+	s.decrypt.Encrypt(p[:n])
+	return n, err
+}
+
+func (s *testObfuscatedClientStream) Write(p []byte) (int, error) {
+	b := make([]byte, len(p))
+	copy(b, p)
+	s.encrypt.Encrypt(b)
+	return s.Conn.Write(b)
+}
+
+func TestProxyCodecObfuscatedPaddedIntermediate(t *testing.T) {
+	c1, c2 := net.Pipe()
+	codec, _ := NewMTProtoProxy().NewCodec(net2.NewBufferedConn(c1))
+	defer codec.Close()
+	defer c2.Close()
+
+	stream, init := newTestObfuscatedClientStream(t, c2, MTPROTO_APP_PADDED_INTERMEDIATE_TAG)
+	client := NewMTProtoPaddedIntermediateCodec(stream)
+
+	messages := testRawMessages()
+	go func() {
+		c2.Write(init)
+		for _, m := range messages {
+			client.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, codec, m)
+	}
+	if _, ok := codec.(*MTProtoProxyCodec).codec.(*MTProtoAppCodec); !ok {
+		t.Fatalf("invalid codec: %T", codec.(*MTProtoProxyCodec).codec)
+	}
+
+	go func() {
+		for _, m := range messages {
+			codec.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, client, m)
+	}
+}
+
+func TestProxyCodecObfuscatedInvalidTag(t *testing.T) {
+	c1, c2 := net.Pipe()
+	codec, _ := NewMTProtoProxy().NewCodec(net2.NewBufferedConn(c1))
+	defer codec.Close()
+	defer c2.Close()
+
+	_, init := newTestObfuscatedClientStream(t, c2, 0x01020304)
+	go c2.Write(init)
+
+	if _, err := codec.Receive(); err == nil {
+		t.Fatal("invalid protocol tag must be rejected")
+	}
+}
@@ -18,6 +18,7 @@
 package mtproto
 
 import (
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"github.com/golang/glog"
@@ -25,9 +26,9 @@ import (
 	"github.com/nebulaim/telegramd/baselib/net2"
 	"io"
 	// "net/http"
-	"net"
-	// "time"
 	"fmt"
+	"net"
+	"time"
 )
 
 // TODO(@benqi): Quick ack (https://core.telegram.org/mtproto#tcp-transport)
@@ -73,8 +74,13 @@ const (
 
 const (
 	// Tcp Transport
-	MTPROTO_ABRIDGED_FLAG     = 0xef
-	MTPROTO_INTERMEDIATE_FLAG = 0xeeeeeeee
+	MTPROTO_ABRIDGED_FLAG            = 0xef
+	MTPROTO_INTERMEDIATE_FLAG        = 0xeeeeeeee
+	MTPROTO_PADDED_INTERMEDIATE_FLAG = 0xdddddddd
+
+	// 混淆协议64字节头中56~59字节的内部协议标识
+	MTPROTO_APP_ABRIDGED_TAG            = 0xefefefef
+	MTPROTO_APP_PADDED_INTERMEDIATE_TAG = 0xdddddddd
 
 	// Http Transport
 	HTTP_HEAD_FLAG   = 0x44414548
@@ -93,12 +99,41 @@ func init() {
 // 服务端MTPProto代理
 // 服务端需要兼容各种协议
 type MTProtoProxy struct {
+	// 不为nil时只接受使用secret的混淆协议
+	secret *MTProxySecret
+	// 握手失败时转发的地址
+	decoy  string
+	replay *replayCache
+	// 允许的传输协议(TRANSPORT_TCP, TRANSPORT_HTTP), 为空则都允许
+	transports map[int]bool
 }
 
 func NewMTProtoProxy() *MTProtoProxy {
 	return &MTProtoProxy{}
 }
 
+// MTProxy兼容模式
+func NewMTProtoSecretProxy(secret *MTProxySecret, decoy string) *MTProtoProxy {
+	return &MTProtoProxy{
+		secret: secret,
+		decoy:  decoy,
+		replay: newReplayCache(replayCacheWindow),
+	}
+}
+
+func (m *MTProtoProxy) acceptTransports(transports ...int) {
+	if m.transports == nil {
+		m.transports = make(map[int]bool)
+	}
+	for _, t := range transports {
+		m.transports[t] = true
+	}
+}
+
+func (m *MTProtoProxy) isAcceptedTransport(transport int) bool {
+	return len(m.transports) == 0 || m.transports[transport]
+}
+
 func (m *MTProtoProxy) NewCodec(rw io.ReadWriter) (net2.Codec, error) {
 	codec := &MTProtoProxyCodec{
 		codecType: TRANSPORT_TCP,
@@ -130,15 +165,22 @@ type MTProtoProxyCodec struct {
 		val != 0x20544547 &&
 		val != 0x4954504f &&
 		val != 0xeeeeeeee &&
+		val != 0xdddddddd &&
 		val2 != 0x00000000) {
 		bytes[56] = bytes[57] = bytes[58] = bytes[59] = 0xef;
 		break;
 	}
+
+  bytes[56~59] is 0xdddddddd if the padded intermediate protocol is used in the obfuscated stream.
 */
 func (c *MTProtoProxyCodec) peekCodec() error {
 	//if c.State == STATE_DATA {
 	//	return nil
 	//}
+	if c.proto.secret != nil {
+		return c.peekSecretCodec()
+	}
+
 	conn, _ := c.conn.(*net2.BufferedConn)
 	// var b_0_1 = make([]byte, 1)
 	b_0_1, err := conn.Peek(1)
@@ -190,15 +232,23 @@ func (c *MTProtoProxyCodec) peekCodec() error {
 		return nil
 	}
 
+	// a padded intermediate version
+	if val == MTPROTO_PADDED_INTERMEDIATE_FLAG {
+		glog.Info("mtproto padded intermediate version.")
+		c.codec = NewMTProtoPaddedIntermediateCodec(conn)
+		conn.Discard(4)
+		return nil
+	}
+
 	// recv 4~64 bytes
 	// var b_4_60 = make([]byte, 60)
-	b_4_60, err := conn.Peek(64)
+	b_0_64, err := conn.Peek(64)
 	// io.ReadFull(c.conn, b_4_60)
 	if err != nil {
 		glog.Errorf("MTProtoProxyCodec - read b_4_60 error: %v", err)
 		return err
 	}
-	b_4_60 = b_4_60[4:64]
+	b_4_60 := b_0_64[4:64]
 	val2 := (uint32(b_4_60[3]) << 24) | (uint32(b_4_60[2]) << 16) | (uint32(b_4_60[1]) << 8) | (uint32(b_4_60[0]))
 	if val2 == VAL2_FLAG {
 		glog.Info("mtproto full version.")
@@ -206,46 +256,167 @@ func (c *MTProtoProxyCodec) peekCodec() error {
 		return nil
 	}
 
-	var tmp [64]byte
-	// 生成decrypt_key
-	for i := 0; i < 48; i++ {
-		tmp[i] = b_4_60[51-i]
+	codec, err := newMTProtoObfuscatedCodec(conn, b_0_64, nil)
+	if err != nil {
+		return err
 	}
+	c.codec = codec
+	conn.Discard(64)
+
+	return nil
+}
+
+// 配置了secret，只接受使用secret的混淆协议(或fake-TLS)
+func (c *MTProtoProxyCodec) peekSecretCodec() error {
+	conn, _ := c.conn.(*net2.BufferedConn)
+	secret := c.proto.secret
 
-	e, err := crypto.NewAesCTR128Encrypt(tmp[:32], tmp[32:48])
+	if secret.FakeTLS {
+		return c.peekFakeTLSCodec(conn)
+	}
+
+	init, err := conn.Peek(64)
 	if err != nil {
-		// glog.Error("NewAesCTR128Encrypt error: %s", err)
+		glog.Errorf("MTProtoProxyCodec - read obfuscated init error: %v", err)
 		return err
 	}
 
-	d, err := crypto.NewAesCTR128Encrypt(b_4_60[4:36], b_4_60[36:52])
+	codec, err := newMTProtoObfuscatedCodec(conn, init, secret.Secret)
+	if err == nil && c.proto.replay.checkAndPut(init[8:56]) {
+		err = fmt.Errorf("replayed obfuscated init from %s", conn.RemoteAddr())
+	}
 	if err != nil {
-		glog.Errorf("NewAesCTR128Encrypt error: %s", err)
+		return c.rejectHandshake(conn, err)
+	}
+
+	c.codec = codec
+	conn.Discard(64)
+	return nil
+}
+
+func (c *MTProtoProxyCodec) peekFakeTLSCodec(conn *net2.BufferedConn) error {
+	secret := c.proto.secret
+
+	header, err := conn.Peek(tlsRecordHeaderLen)
+	if err != nil {
+		glog.Errorf("MTProtoProxyCodec - read tls record header error: %v", err)
 		return err
 	}
+	if header[0] != tlsRecordHandshake {
+		return c.rejectHandshake(conn, fmt.Errorf("not a tls handshake: %s", hex.EncodeToString(header)))
+	}
 
-	d.Encrypt(b_0_1)
-	d.Encrypt(b_1_3)
-	d.Encrypt(b_4_60)
+	size := tlsRecordHeaderLen + int(binary.BigEndian.Uint16(header[3:]))
+	if size > conn.BufioReader().Size() {
+		return c.rejectHandshake(conn, fmt.Errorf("client hello too large: %d", size))
+	}
+	b, err := conn.Peek(size)
+	if err != nil {
+		glog.Errorf("MTProtoProxyCodec - read client hello error: %v", err)
+		return err
+	}
 
-	if b_4_60[52] != 0xef && b_4_60[53] != 0xef && b_4_60[54] != 0xef && b_4_60[55] != 0xef {
-		glog.Errorf("MTProtoProxyCodec - first 56~59 byte != 0xef")
-		return errors.New("mtproto buf[56:60]'s byte != 0xef!!")
+	hello, err := parseFakeTLSClientHello(secret.Secret, b, time.Now().Unix())
+	if err == nil && secret.Domain != "" && hello.ServerName != secret.Domain {
+		err = fmt.Errorf("invalid server name: %s", hello.ServerName)
+	}
+	if err == nil && c.proto.replay.checkAndPut(hello.Random) {
+		err = fmt.Errorf("replayed client hello from %s", conn.RemoteAddr())
+	}
+	if err != nil {
+		return c.rejectHandshake(conn, err)
 	}
 
-	glog.Info("first_bytes_64: ", hex.EncodeToString(b_0_1), hex.EncodeToString(b_1_3), hex.EncodeToString(b_4_60))
-	c.codec = NewMTProtoAppCodec(conn, d, e)
-	conn.Discard(64)
+	conn.Discard(size)
+	if _, err = conn.Write(makeFakeTLSServerHello(secret.Secret, hello)); err != nil {
+		return err
+	}
 
+	// ServerHello已经发出，之后的错误直接关闭连接
+	stream := NewFakeTLSConn(conn)
+	init := make([]byte, 64)
+	if _, err = io.ReadFull(stream, init); err != nil {
+		glog.Errorf("MTProtoProxyCodec - read obfuscated init error: %v", err)
+		return err
+	}
+
+	codec, err := newMTProtoObfuscatedCodec(stream, init, secret.Secret)
+	if err != nil {
+		return err
+	}
+	c.codec = codec
+	return nil
+}
+
+// 握手失败，有decoy则转发，否则关闭
+func (c *MTProtoProxyCodec) rejectHandshake(conn *net2.BufferedConn, err error) error {
+	glog.Errorf("MTProtoProxyCodec - invalid handshake from %s: %v", conn.RemoteAddr(), err)
+	if c.proto.decoy == "" {
+		return err
+	}
+	c.codec = newMTProtoDecoyCodec(conn, c.proto.decoy)
 	return nil
 }
 
+// init为客户端发送的64字节头，secret不为空时
+// key = SHA256(key + secret)
+func newMTProtoObfuscatedCodec(conn io.ReadWriteCloser, init []byte, secret []byte) (*MTProtoAppCodec, error) {
+	var b [64]byte
+	copy(b[:], init)
+
+	var tmp [48]byte
+	// 生成encrypt_key
+	for i := 0; i < 48; i++ {
+		tmp[i] = b[55-i]
+	}
+
+	encryptKey := tmp[:32]
+	decryptKey := b[8:40]
+	if len(secret) > 0 {
+		encryptKey = crypto.Sha256Digest(append(append([]byte{}, encryptKey...), secret...))
+		decryptKey = crypto.Sha256Digest(append(append([]byte{}, decryptKey...), secret...))
+	}
+
+	e, err := crypto.NewAesCTR128Encrypt(encryptKey, tmp[32:48])
+	if err != nil {
+		// glog.Error("NewAesCTR128Encrypt error: %s", err)
+		return nil, err
+	}
+
+	d, err := crypto.NewAesCTR128Encrypt(decryptKey, b[40:56])
+	if err != nil {
+		glog.Errorf("NewAesCTR128Encrypt error: %s", err)
+		return nil, err
+	}
+
+	d.Encrypt(b[:])
+
+	glog.Info("first_bytes_64: ", hex.EncodeToString(b[:]))
+
+	protocolTag := binary.LittleEndian.Uint32(b[56:60])
+	switch protocolTag {
+	case MTPROTO_APP_ABRIDGED_TAG:
+		return NewMTProtoAppCodec(conn, d, e), nil
+	case MTPROTO_APP_PADDED_INTERMEDIATE_TAG:
+		glog.Info("mtproto obfuscated padded intermediate version.")
+		return NewMTProtoAppPaddedIntermediateCodec(conn, d, e), nil
+	default:
+		glog.Errorf("MTProtoProxyCodec - invalid first 56~59 bytes: %s", hex.EncodeToString(b[56:60]))
+		return nil, errors.New("mtproto buf[56:60] is not a valid protocol tag!!")
+	}
+}
+
 func (c *MTProtoProxyCodec) Receive() (interface{}, error) {
 	if c.codec == nil {
 		err := c.peekCodec()
 		if err != nil {
 			return nil, err
 		}
+		if !c.proto.isAcceptedTransport(c.codecType) {
+			err = fmt.Errorf("transport not accepted: %d", c.codecType)
+			glog.Error(err)
+			return nil, err
+		}
 	}
 	return c.codec.Receive()
 }
@@ -261,6 +432,6 @@ func (c *MTProtoProxyCodec) Close() error {
 	if c.codec != nil {
 		return c.codec.Close()
 	} else {
-		return nil
+		return c.conn.Close()
 	}
 }
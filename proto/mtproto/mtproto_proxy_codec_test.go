@@ -0,0 +1,52 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"net"
+	"testing"
+
+	"github.com/nebulaim/telegramd/baselib/net2"
+)
+
+func TestProxyCodecTransports(t *testing.T) {
+	proxy := NewMTProtoProxy()
+	proxy.acceptTransports(TRANSPORT_TCP)
+
+	c1, c2 := net.Pipe()
+	codec, _ := proxy.NewCodec(net2.NewBufferedConn(c1))
+	defer codec.Close()
+	defer c2.Close()
+
+	go c2.Write([]byte("POST /api HTTP/1.1\r\nContent-Length: 0\r\n\r\n"))
+	if _, err := codec.Receive(); err == nil {
+		t.Fatal("http transport must be rejected")
+	}
+
+	c3, c4 := net.Pipe()
+	codec2, _ := proxy.NewCodec(net2.NewBufferedConn(c3))
+	defer codec2.Close()
+	defer c4.Close()
+
+	m := testRawMessages()[2]
+	go func() {
+		c4.Write([]byte{MTPROTO_ABRIDGED_FLAG})
+		NewMTProtoAbridgedCodec(c4).Send(m)
+	}()
+	checkReceived(t, codec2, m)
+}
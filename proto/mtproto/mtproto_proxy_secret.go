@@ -0,0 +1,156 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/net2"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	mtproxySecretLen = 16
+
+	// 重放检测的保存时间
+	replayCacheWindow = 2 * fakeTLSMaxTimeSkew * time.Second
+)
+
+// MTProxy兼容的secret
+//
+//	<32 hex>                  obfuscated transport
+//	dd<32 hex>                obfuscated transport (client uses padded intermediate)
+//	ee<32 hex><domain in hex> fake-TLS transport
+type MTProxySecret struct {
+	Secret  []byte
+	FakeTLS bool
+	Domain  string
+}
+
+func ParseMTProxySecret(s string) (*MTProxySecret, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mtproxy secret: %v", err)
+	}
+
+	secret := &MTProxySecret{}
+	switch {
+	case len(b) == mtproxySecretLen:
+	case len(b) == mtproxySecretLen+1 && b[0] == 0xdd:
+		b = b[1:]
+	case len(b) > mtproxySecretLen+1 && b[0] == 0xee:
+		secret.FakeTLS = true
+		secret.Domain = string(b[1+mtproxySecretLen:])
+		b = b[1 : 1+mtproxySecretLen]
+	default:
+		return nil, fmt.Errorf("invalid mtproxy secret: %s", s)
+	}
+	secret.Secret = b
+	return secret, nil
+}
+
+func (s *MTProxySecret) String() string {
+	return fmt.Sprintf("{fake_tls: %v, domain: %s}", s.FakeTLS, s.Domain)
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////
+// 握手重放检测
+type replayCache struct {
+	sync.Mutex
+	window  time.Duration
+	entries map[string]time.Time
+	lastGC  time.Time
+}
+
+func newReplayCache(window time.Duration) *replayCache {
+	return &replayCache{
+		window:  window,
+		entries: make(map[string]time.Time),
+		lastGC:  time.Now(),
+	}
+}
+
+// 已存在返回true
+func (c *replayCache) checkAndPut(key []byte) bool {
+	now := time.Now()
+
+	c.Lock()
+	defer c.Unlock()
+
+	if now.Sub(c.lastGC) > c.window {
+		for k, t := range c.entries {
+			if now.Sub(t) > c.window {
+				delete(c.entries, k)
+			}
+		}
+		c.lastGC = now
+	}
+
+	k := string(key)
+	if t, ok := c.entries[k]; ok && now.Sub(t) <= c.window {
+		return true
+	}
+	c.entries[k] = now
+	return false
+}
+
+//////////////////////////////////////////////////////////////////////////////////////////////////
+// 握手失败的连接原样转发给decoy，使端口看起来是一个普通服务
+type mtprotoDecoyCodec struct {
+	conn *net2.BufferedConn
+	addr string
+}
+
+func newMTProtoDecoyCodec(conn *net2.BufferedConn, addr string) *mtprotoDecoyCodec {
+	return &mtprotoDecoyCodec{
+		conn: conn,
+		addr: addr,
+	}
+}
+
+// 转发结束后返回io.EOF
+func (c *mtprotoDecoyCodec) Receive() (interface{}, error) {
+	decoy, err := net.DialTimeout("tcp", c.addr, 5*time.Second)
+	if err != nil {
+		glog.Errorf("dial decoy %s error: %v", c.addr, err)
+		return nil, err
+	}
+	defer decoy.Close()
+
+	glog.Infof("forward %s to decoy %s", c.conn.RemoteAddr(), c.addr)
+	go func() {
+		io.Copy(c.conn, decoy)
+		c.conn.Close()
+	}()
+
+	// 已经peek的数据仍在BufferedConn中
+	io.Copy(decoy, c.conn)
+	return nil, io.EOF
+}
+
+func (c *mtprotoDecoyCodec) Send(msg interface{}) error {
+	return fmt.Errorf("decoy connection can't send msg")
+}
+
+func (c *mtprotoDecoyCodec) Close() error {
+	return c.conn.Close()
+}
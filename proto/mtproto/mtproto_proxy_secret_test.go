@@ -0,0 +1,363 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"github.com/nebulaim/telegramd/baselib/net2"
+)
+
+// 以下握手数据由独立实现(python hmac + openssl aes-256-ctr)生成
+const (
+	testProxySecret = "0123456789abcdef0123456789abcdef"
+
+	// ClientHello, sni: www.example.com, timestamp: 1539820800
+	testRecordedClientHello = "16030100a00100009c0303babcc0f86c99de9a1da6171d875d16f07a0d879fc56ca0d0c9d49573f4508484" +
+		"20202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f000a130113021303c02bc02f0100004900" +
+		"000014001200000f7777772e6578616d706c652e636f6d002b0003020304003300260024001d0020404142434445464748494a" +
+		"4b4c4d4e4f505152535455565758595a5b5c5d5e5f"
+	testRecordedClientHelloTime = 1539820800
+
+	// 64字节obfuscated init(padded intermediate) + 第一个加密的数据包
+	testRecordedObfuscated = "7a1111110405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a" +
+		"2b2c2d2e2f30313233343536379f5afca128529aa9ced6e08b4e6d709d5a2cb2db872ab5b888c36951487efa22b80995b4d466" +
+		"0db5ccbed7"
+	testRecordedObfuscatedPayload = "000000000000000004000000a0f4c75b080000000102030405060708"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParseMTProxySecret(t *testing.T) {
+	secret, err := ParseMTProxySecret(testProxySecret)
+	if err != nil || secret.FakeTLS || !bytes.Equal(secret.Secret, mustDecodeHex(testProxySecret)) {
+		t.Fatalf("parse secret error: %v, %v", err, secret)
+	}
+
+	secret, err = ParseMTProxySecret("dd" + testProxySecret)
+	if err != nil || secret.FakeTLS || !bytes.Equal(secret.Secret, mustDecodeHex(testProxySecret)) {
+		t.Fatalf("parse dd secret error: %v, %v", err, secret)
+	}
+
+	secret, err = ParseMTProxySecret("ee" + testProxySecret + hex.EncodeToString([]byte("www.example.com")))
+	if err != nil || !secret.FakeTLS || secret.Domain != "www.example.com" ||
+		!bytes.Equal(secret.Secret, mustDecodeHex(testProxySecret)) {
+		t.Fatalf("parse ee secret error: %v, %v", err, secret)
+	}
+
+	for _, s := range []string{"", "0123", "ff" + testProxySecret, "ee" + testProxySecret, "zz"} {
+		if _, err = ParseMTProxySecret(s); err == nil {
+			t.Fatalf("invalid secret %s must be rejected", s)
+		}
+	}
+}
+
+func TestFakeTLSRecordedClientHello(t *testing.T) {
+	secret := mustDecodeHex(testProxySecret)
+	hello := mustDecodeHex(testRecordedClientHello)
+
+	h, err := parseFakeTLSClientHello(secret, hello, testRecordedClientHelloTime+10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.ServerName != "www.example.com" || h.Timestamp != testRecordedClientHelloTime {
+		t.Fatalf("invalid client hello: %v", h)
+	}
+	if !bytes.Equal(h.SessionId, hello[44:76]) {
+		t.Fatalf("invalid session_id: %s", hex.EncodeToString(h.SessionId))
+	}
+
+	if _, err = parseFakeTLSClientHello(secret, hello, testRecordedClientHelloTime+fakeTLSMaxTimeSkew+1); err == nil {
+		t.Fatal("expired client hello must be rejected")
+	}
+	if _, err = parseFakeTLSClientHello(crypto.GenerateNonce(16), hello, testRecordedClientHelloTime); err == nil {
+		t.Fatal("client hello with another secret must be rejected")
+	}
+
+	hello[len(hello)-1] ^= 0xff
+	if _, err = parseFakeTLSClientHello(secret, hello, testRecordedClientHelloTime); err == nil {
+		t.Fatal("modified client hello must be rejected")
+	}
+}
+
+func TestFakeTLSServerHello(t *testing.T) {
+	secret := mustDecodeHex(testProxySecret)
+	h, err := parseFakeTLSClientHello(secret, mustDecodeHex(testRecordedClientHello), testRecordedClientHelloTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := makeFakeTLSServerHello(secret, h)
+	if b[0] != tlsRecordHandshake || b[5] != tlsHandshakeServerHello {
+		t.Fatalf("invalid server hello: %s", hex.EncodeToString(b[:6]))
+	}
+	if !bytes.Equal(b[44:76], h.SessionId) {
+		t.Fatal("session_id must be echoed")
+	}
+
+	// 客户端校验server random
+	random := make([]byte, fakeTLSRandomLen)
+	copy(random, b[fakeTLSRandomOffset:])
+	zeroed := append([]byte{}, b...)
+	copy(zeroed[fakeTLSRandomOffset:], make([]byte, fakeTLSRandomLen))
+	if !bytes.Equal(random, hmacSha256(secret, append(append([]byte{}, h.Random...), zeroed...))) {
+		t.Fatal("invalid server random")
+	}
+}
+
+func TestFakeTLSConn(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewFakeTLSConn(c1)
+	defer server.Close()
+	defer c2.Close()
+
+	data := crypto.GenerateNonce(tlsMaxRecordPayload + 100)
+	go func() {
+		// 客户端先发送ChangeCipherSpec
+		c2.Write([]byte{tlsRecordChangeCipherSpec, 0x03, 0x03, 0x00, 0x01, 0x01})
+		NewFakeTLSConn(c2).Write(data)
+	}()
+
+	b := make([]byte, len(data))
+	if _, err := io.ReadFull(server, b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, data) {
+		t.Fatal("fake tls data mismatch")
+	}
+}
+
+func newTestSecretProxyCodec(t *testing.T, secret, decoy string) (net2.Codec, net.Conn) {
+	s, err := ParseMTProxySecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c1, c2 := net.Pipe()
+	codec, _ := NewMTProtoSecretProxy(s, decoy).NewCodec(net2.NewBufferedConn(c1))
+	return codec, c2
+}
+
+func TestSecretProxyRecordedObfuscated(t *testing.T) {
+	codec, c2 := newTestSecretProxyCodec(t, "dd"+testProxySecret, "")
+	defer codec.Close()
+	defer c2.Close()
+
+	go c2.Write(mustDecodeHex(testRecordedObfuscated))
+
+	want := NewMTPRawMessage(0, 0, TRANSPORT_TCP)
+	want.Decode(mustDecodeHex(testRecordedObfuscatedPayload))
+	checkReceived(t, codec, want)
+}
+
+func TestSecretProxyRejectsReplay(t *testing.T) {
+	s, _ := ParseMTProxySecret(testProxySecret)
+	proxy := NewMTProtoSecretProxy(s, "")
+
+	for i := 0; i < 2; i++ {
+		c1, c2 := net.Pipe()
+		codec, _ := proxy.NewCodec(net2.NewBufferedConn(c1))
+		go c2.Write(mustDecodeHex(testRecordedObfuscated))
+
+		_, err := codec.Receive()
+		if i == 0 && err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && err == nil {
+			t.Fatal("replayed handshake must be rejected")
+		}
+		codec.Close()
+		c2.Close()
+	}
+}
+
+func TestSecretProxyRejectsPlainTransport(t *testing.T) {
+	codec, c2 := newTestSecretProxyCodec(t, testProxySecret, "")
+	defer codec.Close()
+	defer c2.Close()
+
+	// 未使用secret的混淆协议
+	_, init := newTestObfuscatedClientStream(t, c2, MTPROTO_APP_PADDED_INTERMEDIATE_TAG)
+	go c2.Write(init)
+
+	if _, err := codec.Receive(); err == nil {
+		t.Fatal("obfuscated init without secret must be rejected")
+	}
+}
+
+func TestSecretProxyDecoy(t *testing.T) {
+	lsn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lsn.Close()
+
+	codec, c2 := newTestSecretProxyCodec(t, testProxySecret, lsn.Addr().String())
+	defer codec.Close()
+
+	probe := []byte("GET / HTTP/1.1\r\nHost: www.example.com\r\n\r\n")
+	probe = append(probe, make([]byte, 64)...)
+	go c2.Write(probe)
+	go codec.Receive()
+
+	conn, err := lsn.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	b := make([]byte, len(probe))
+	if _, err = io.ReadFull(conn, b); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, probe) {
+		t.Fatal("decoy must receive the original bytes")
+	}
+
+	conn.Write([]byte("HTTP/1.1 200 OK\r\n"))
+	b = make([]byte, 17)
+	if _, err = io.ReadFull(c2, b); err != nil || string(b) != "HTTP/1.1 200 OK\r\n" {
+		t.Fatalf("client must receive the decoy response: %v", err)
+	}
+	c2.Close()
+}
+
+// 用当前时间生成ClientHello
+func makeTestClientHello(secret []byte) []byte {
+	hello := mustDecodeHex(testRecordedClientHello)
+	copy(hello[fakeTLSRandomOffset:], make([]byte, fakeTLSRandomLen))
+	mac := hmacSha256(secret, hello)
+
+	var ts [4]byte
+	binary.LittleEndian.PutUint32(ts[:], uint32(time.Now().Unix()))
+	for i := 0; i < 4; i++ {
+		mac[28+i] ^= ts[i]
+	}
+	copy(hello[fakeTLSRandomOffset:], mac)
+	return hello
+}
+
+func TestSecretProxyFakeTLS(t *testing.T) {
+	codec, c2 := newTestSecretProxyCodec(t, "ee"+testProxySecret+hex.EncodeToString([]byte("www.example.com")), "")
+	defer codec.Close()
+	defer c2.Close()
+
+	secret := mustDecodeHex(testProxySecret)
+	hello := makeTestClientHello(secret)
+	clientTLS := NewFakeTLSConn(c2)
+
+	messages := testRawMessages()
+	go func() {
+		c2.Write(hello)
+
+		// ServerHello + ChangeCipherSpec + ApplicationData
+		b := make([]byte, 127+6+5)
+		io.ReadFull(c2, b)
+		random := append([]byte{}, b[fakeTLSRandomOffset:fakeTLSRandomOffset+fakeTLSRandomLen]...)
+		noise := make([]byte, binary.BigEndian.Uint16(b[len(b)-2:]))
+		io.ReadFull(c2, noise)
+
+		copy(b[fakeTLSRandomOffset:], make([]byte, fakeTLSRandomLen))
+		mac := hmacSha256(secret, append(append(append([]byte{}, hello[fakeTLSRandomOffset:fakeTLSRandomOffset+fakeTLSRandomLen]...), b...), noise...))
+		if !bytes.Equal(mac, random) {
+			c2.Close()
+			return
+		}
+
+		c2.Write([]byte{tlsRecordChangeCipherSpec, 0x03, 0x03, 0x00, 0x01, 0x01})
+		s := mustDecodeHex(testProxySecret)
+		stream, init := newTestSecretObfuscatedClientStream(t, clientTLS, s, MTPROTO_APP_PADDED_INTERMEDIATE_TAG)
+		clientTLS.Write(init)
+		client := NewMTProtoPaddedIntermediateCodec(stream)
+		for _, m := range messages {
+			client.Send(m)
+		}
+	}()
+
+	for _, m := range messages {
+		checkReceived(t, codec, m)
+	}
+}
+
+func TestSecretProxyFakeTLSRejectsExpired(t *testing.T) {
+	codec, c2 := newTestSecretProxyCodec(t, "ee"+testProxySecret+hex.EncodeToString([]byte("www.example.com")), "")
+	defer codec.Close()
+	defer c2.Close()
+
+	// 过期的ClientHello
+	go c2.Write(mustDecodeHex(testRecordedClientHello))
+	if _, err := codec.Receive(); err == nil {
+		t.Fatal("expired client hello must be rejected")
+	}
+}
+
+type testSecretObfuscatedClientStream struct {
+	io.ReadWriteCloser
+	encrypt *crypto.AesCTR128Encrypt
+	decrypt *crypto.AesCTR128Encrypt
+}
+
+func newTestSecretObfuscatedClientStream(t *testing.T, conn io.ReadWriteCloser, secret []byte, protocolTag uint32) (*testSecretObfuscatedClientStream, []byte) {
+	init := crypto.GenerateNonce(64)
+	init[0] = 0x7a
+	binary.LittleEndian.PutUint32(init[56:], protocolTag)
+
+	var reversed [48]byte
+	for i := 0; i < 48; i++ {
+		reversed[i] = init[55-i]
+	}
+
+	e, err := crypto.NewAesCTR128Encrypt(crypto.Sha256Digest(append(append([]byte{}, init[8:40]...), secret...)), init[40:56])
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := crypto.NewAesCTR128Encrypt(crypto.Sha256Digest(append(append([]byte{}, reversed[:32]...), secret...)), reversed[32:48])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted := append([]byte{}, init...)
+	e.Encrypt(encrypted)
+	copy(init[56:], encrypted[56:])
+
+	return &testSecretObfuscatedClientStream{ReadWriteCloser: conn, encrypt: e, decrypt: d}, init
+}
+
+func (s *testSecretObfuscatedClientStream) Read(p []byte) (int, error) {
+	n, err := s.ReadWriteCloser.Read(p)
+	s.decrypt.Encrypt(p[:n])
+	return n, err
+}
+
+func (s *testSecretObfuscatedClientStream) Write(p []byte) (int, error) {
+	b := append([]byte{}, p...)
+	s.encrypt.Encrypt(b)
+	return s.ReadWriteCloser.Write(b)
+}
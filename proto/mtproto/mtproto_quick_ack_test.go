@@ -0,0 +1,194 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mtproto
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"github.com/nebulaim/telegramd/baselib/net2"
+)
+
+const testQuickAckToken = int32(-0x7efdfcfc) // 0x81020304
+
+func checkWritten(t *testing.T, r io.Reader, want []byte) {
+	b := make([]byte, len(want))
+	if _, err := io.ReadFull(r, b); err != nil {
+		t.Fatalf("read error: %v", err)
+	}
+	if !bytes.Equal(b, want) {
+		t.Fatalf("invalid data: %v (need %v)", b, want)
+	}
+}
+
+func checkReceivedQuickAck(t *testing.T, codec net2.Codec, want *MTPRawMessage) {
+	r, err := codec.Receive()
+	if err != nil {
+		t.Fatalf("receive error: %v", err)
+	}
+	got, ok := r.(*MTPRawMessage)
+	if !ok {
+		t.Fatalf("receive invalid message: %v", r)
+	}
+	if !got.NeedQuickAck() {
+		t.Fatal("quick ack flag lost")
+	}
+	if !bytes.Equal(got.Payload, want.Payload) {
+		t.Fatalf("payload mismatch: len %d != %d", len(got.Payload), len(want.Payload))
+	}
+}
+
+func transportErrorBytes(code int32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, uint32(code))
+	return b
+}
+
+func TestAbridgedCodecQuickAck(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewMTProtoAbridgedCodec(net2.NewBufferedConn(c1))
+	defer server.Close()
+	defer c2.Close()
+
+	m := testRawMessages()[2]
+	go func() {
+		c2.Write(append([]byte{byte(len(m.Payload)/4) | 0x80}, m.Payload...))
+	}()
+	checkReceivedQuickAck(t, server, m)
+
+	go server.Send(NewMTPQuickAckMessage(testQuickAckToken))
+	checkWritten(t, c2, []byte{0x81, 0x02, 0x03, 0x04})
+
+	go server.Send(NewMTPTransportError(TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND))
+	checkWritten(t, c2, append([]byte{0x01}, transportErrorBytes(-404)...))
+}
+
+func TestIntermediateCodecQuickAck(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewMTProtoIntermediateCodec(net2.NewBufferedConn(c1))
+	defer server.Close()
+	defer c2.Close()
+
+	m := testRawMessages()[2]
+	go func() {
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, uint32(len(m.Payload))|0x80000000)
+		c2.Write(append(b, m.Payload...))
+	}()
+	checkReceivedQuickAck(t, server, m)
+
+	go server.Send(NewMTPQuickAckMessage(testQuickAckToken))
+	checkWritten(t, c2, []byte{0x04, 0x03, 0x02, 0x81})
+
+	go server.Send(NewMTPTransportError(TRANSPORT_ERROR_FLOOD))
+	checkWritten(t, c2, append([]byte{0x04, 0x00, 0x00, 0x00}, transportErrorBytes(-429)...))
+}
+
+func TestIntermediateCodecRoundTrip(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewMTProtoIntermediateCodec(net2.NewBufferedConn(c1))
+	client := NewMTProtoIntermediateCodec(c2)
+	defer server.Close()
+	defer client.Close()
+
+	messages := testRawMessages()[2:]
+	go func() {
+		for _, m := range messages {
+			client.Send(m)
+		}
+	}()
+	for _, m := range messages {
+		checkReceived(t, server, m)
+	}
+}
+
+func TestPaddedIntermediateCodecTransportError(t *testing.T) {
+	c1, c2 := net.Pipe()
+	server := NewMTProtoPaddedIntermediateCodec(net2.NewBufferedConn(c1))
+	defer server.Close()
+	defer c2.Close()
+
+	// 错误码不能带填充
+	go server.Send(NewMTPTransportError(TRANSPORT_ERROR_INVALID_DC))
+	checkWritten(t, c2, append([]byte{0x04, 0x00, 0x00, 0x00}, transportErrorBytes(-444)...))
+
+	go server.Send(NewMTPQuickAckMessage(testQuickAckToken))
+	checkWritten(t, c2, []byte{0x04, 0x03, 0x02, 0x81})
+}
+
+func TestProxyCodecObfuscatedQuickAck(t *testing.T) {
+	c1, c2 := net.Pipe()
+	codec, _ := NewMTProtoProxy().NewCodec(net2.NewBufferedConn(c1))
+	defer codec.Close()
+	defer c2.Close()
+
+	stream, init := newTestObfuscatedClientStream(t, c2, MTPROTO_APP_ABRIDGED_TAG)
+
+	m := testRawMessages()[2]
+	go func() {
+		c2.Write(init)
+		stream.Write(append([]byte{byte(len(m.Payload)/4) | 0x80}, m.Payload...))
+	}()
+	checkReceivedQuickAck(t, codec, m)
+
+	go codec.Send(NewMTPQuickAckMessage(testQuickAckToken))
+	checkWritten(t, stream, []byte{0x81, 0x02, 0x03, 0x04})
+
+	go codec.Send(NewMTPTransportError(TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND))
+	checkWritten(t, stream, append([]byte{0x01}, transportErrorBytes(-404)...))
+}
+
+// 模拟客户端加密消息，校验quick ack token
+func TestEncryptedMessageQuickAckToken(t *testing.T) {
+	authKey := crypto.GenerateNonce(256)
+
+	obj := &TLPing{PingId: 1}
+	objData := obj.Encode()
+
+	x := NewEncodeBuf(512)
+	x.Long(1)
+	x.Long(2)
+	x.Long(GenerateMessageId() | 3)
+	x.Int(1)
+	x.Int(int32(len(objData)))
+	x.Bytes(objData)
+	x.Bytes(crypto.GenerateNonce(16 - (32+len(objData))%16 + 16))
+	plain := x.buf
+
+	msgKeyLarge := crypto.Sha256Digest(append(append([]byte{}, authKey[88:88+32]...), plain...))
+	msgKey := msgKeyLarge[8 : 8+16]
+	aesKey, aesIV := generateMessageKey(msgKey, authKey, false)
+	encrypted, err := crypto.NewAES256IGECryptor(aesKey, aesIV).Encrypt(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := NewEncryptedMessage2(1)
+	if err = message.Decode(1, authKey, append(append([]byte{}, msgKey...), encrypted...)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := int32(binary.LittleEndian.Uint32(msgKeyLarge) | 0x80000000)
+	if message.QuickAckToken() != want {
+		t.Fatalf("invalid quick ack token: %x (need %x)", uint32(message.QuickAckToken()), uint32(want))
+	}
+}
@@ -18,12 +18,14 @@
 package mtproto
 
 import (
+	"expvar"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/etcd_util"
 	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
 	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery/etcd3"
 	"github.com/nebulaim/telegramd/baselib/net2"
+	"github.com/nebulaim/telegramd/baselib/sync2"
 	"net"
 )
 
@@ -33,15 +35,65 @@ type MTProtoServerCallback interface {
 	OnServerConnectionClosed(c *net2.TcpConnection)
 }
 
+// 监听类型
+const (
+	LISTENER_TCP       = "tcp"       // 默认
+	LISTENER_WEBSOCKET = "websocket" // MTProto over WebSocket, 浏览器客户端使用
+)
+
+// 允许的传输协议
+const (
+	TRANSPORT_NAME_TCP  = "tcp"
+	TRANSPORT_NAME_HTTP = "http"
+)
+
 type MTProtoServerConfig struct {
 	Server    net2.ServerConfig
-	Discovery service_discovery.ServiceDiscoveryServerConfig
+	Listener  string
+	WebSocket net2.WebSocketConfig
+	// 允许的传输协议(tcp, http), 为空则都允许
+	Transports []string
+	// MTProxy secret, 配置后只接受使用secret的混淆协议, ee开头为fake-TLS
+	Secret string
+	// 握手失败的连接转发到Decoy, 为空则直接关闭
+	Decoy string
+	// 最大连接数, 0为不限制
+	MaxConnections int
+	// 部署在haproxy等负载均衡后面时解析PROXY协议头, 获取客户端真实地址
+	ProxyProtocol net2.ProxyProtocolConfig
+	Discovery     service_discovery.ServiceDiscoveryServerConfig
+}
+
+// 每个监听的统计数据
+type MTProtoServerStats struct {
+	Connections         sync2.AtomicInt64 // 当前连接数
+	TotalConnections    sync2.AtomicInt64 // 累计连接数
+	RejectedConnections sync2.AtomicInt64 // 超过MaxConnections被拒绝的连接数
+	ReceivedMessages    sync2.AtomicInt64
+	ReceivedBytes       sync2.AtomicInt64
+}
+
+func (s *MTProtoServerStats) Snapshot() map[string]int64 {
+	return map[string]int64{
+		"connections":          s.Connections.Get(),
+		"total_connections":    s.TotalConnections.Get(),
+		"rejected_connections": s.RejectedConnections.Get(),
+		"received_messages":    s.ReceivedMessages.Get(),
+		"received_bytes":       s.ReceivedBytes.Get(),
+	}
+}
+
+func (s *MTProtoServerStats) String() string {
+	return fmt.Sprintf("%v", s.Snapshot())
 }
 
 type MTProtoServer struct {
-	server   *net2.TcpServer
-	registry *etcd3.EtcdReigistry
-	callback MTProtoServerCallback
+	name           string
+	server         *net2.TcpServer
+	registry       *etcd3.EtcdReigistry
+	callback       MTProtoServerCallback
+	maxConnections int64
+	stats          MTProtoServerStats
 }
 
 func NewMTProtoServer(conf *MTProtoServerConfig, cb MTProtoServerCallback) *MTProtoServer {
@@ -50,13 +102,63 @@ func NewMTProtoServer(conf *MTProtoServerConfig, cb MTProtoServerCallback) *MTPr
 		glog.Fatal("listen error: %v", err)
 	}
 
+	// 先解析PROXY头, websocket的http请求也能拿到真实地址
+	if conf.ProxyProtocol.Enable {
+		lsn, err = net2.NewProxyProtocolListener(lsn, conf.ProxyProtocol)
+		if err != nil {
+			glog.Fatal(err)
+		}
+	}
+
+	switch conf.Listener {
+	case "", LISTENER_TCP:
+	case LISTENER_WEBSOCKET:
+		lsn = net2.NewWebSocketListener(lsn, conf.WebSocket)
+	default:
+		glog.Fatalf("invalid listener type: %s", conf.Listener)
+	}
+
+	var proxy *MTProtoProxy
+	if conf.Secret != "" {
+		secret, err := ParseMTProxySecret(conf.Secret)
+		if err != nil {
+			glog.Fatal(err)
+		}
+		proxy = NewMTProtoSecretProxy(secret, conf.Decoy)
+	}
+
+	if len(conf.Transports) > 0 {
+		if proxy == nil {
+			proxy = NewMTProtoProxy()
+		}
+		for _, t := range conf.Transports {
+			switch t {
+			case TRANSPORT_NAME_TCP:
+				proxy.acceptTransports(TRANSPORT_TCP)
+			case TRANSPORT_NAME_HTTP:
+				proxy.acceptTransports(TRANSPORT_HTTP)
+			default:
+				glog.Fatalf("invalid transport: %s", t)
+			}
+		}
+	}
+
+	// 未配置secret和transports时使用注册的默认协议
+	var protocol net2.Protocol
+	if proxy != nil {
+		protocol = proxy
+	}
+
 	server := &MTProtoServer{
-		callback: cb,
+		name:           conf.Server.Name,
+		callback:       cb,
+		maxConnections: int64(conf.MaxConnections),
 	}
 	server.server = net2.NewTcpServer(net2.TcpServerArgs{
 		Listener:           lsn,
 		ServerName:         conf.Server.Name,
 		ProtoName:          conf.Server.ProtoName,
+		Protocol:           protocol,
 		SendChanSize:       1024,
 		ConnectionCallback: server,
 	}) // todo (yumcoder): set max connection
@@ -66,10 +168,25 @@ func NewMTProtoServer(conf *MTProtoServerConfig, cb MTProtoServerCallback) *MTPr
 		glog.Fatal(err)
 	}
 
+	// 通过expvar导出统计数据, 名字为mtproto_server.{name}
+	if expvar.Get("mtproto_server."+server.name) == nil {
+		expvar.Publish("mtproto_server."+server.name, expvar.Func(func() interface{} {
+			return server.stats.Snapshot()
+		}))
+	}
+
 	return server
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////////////
+func (s *MTProtoServer) Name() string {
+	return s.name
+}
+
+func (s *MTProtoServer) Stats() *MTProtoServerStats {
+	return &s.stats
+}
+
 func (s *MTProtoServer) GetConnection(connID uint64) *net2.TcpConnection {
 	return s.server.GetConnection(connID)
 }
@@ -93,6 +210,14 @@ func (s *MTProtoServer) Pause() {
 func (s *MTProtoServer) OnNewConnection(conn *net2.TcpConnection) {
 	glog.Infof("onNewConnection %v", conn.RemoteAddr())
 
+	s.stats.TotalConnections.Add(1)
+	if n := s.stats.Connections.Add(1); s.maxConnections > 0 && n > s.maxConnections {
+		glog.Warningf("onNewConnection - too many connections: {server: %s, connections: %d, peer: %s}", s.name, n, conn)
+		s.stats.RejectedConnections.Add(1)
+		conn.Close()
+		return
+	}
+
 	if s.callback != nil {
 		s.callback.OnServerNewConnection(conn)
 	}
@@ -108,6 +233,9 @@ func (s *MTProtoServer) OnConnectionDataArrived(conn *net2.TcpConnection, msg in
 		return err
 	}
 
+	s.stats.ReceivedMessages.Add(1)
+	s.stats.ReceivedBytes.Add(int64(len(message.Payload)))
+
 	if s.callback != nil {
 		s.callback.OnServerMessageDataArrived(conn, message)
 	}
@@ -118,6 +246,8 @@ func (s *MTProtoServer) OnConnectionDataArrived(conn *net2.TcpConnection, msg in
 func (s *MTProtoServer) OnConnectionClosed(conn *net2.TcpConnection) {
 	glog.Infof("onConnectionClosed - %v", conn.RemoteAddr())
 
+	s.stats.Connections.Add(-1)
+
 	if s.callback != nil {
 		s.callback.OnServerConnectionClosed(conn)
 	}
@@ -23,6 +23,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"github.com/golang/glog"
+	"io"
+	"io/ioutil"
 )
 
 //const (
@@ -173,8 +175,28 @@ func (m *TLMsgCopy) Decode(dbuf *DecodeBuf) error {
 
 ///////////////////////////////////////////////////////////////////////////////
 //gzip_packed#3072cfa1 packed_data:string = Object; // parsed manually
+// PackedData为解压后的数据, 编码时再压缩
+// 解压后不能超过一个包的最大长度, 防止gzip炸弹
+const kMaxGzipPackedDataLen = fullPacketMaxLen
+
 type TLGzipPacked struct {
 	PackedData []byte
+	gzipData   []byte // 已压缩的数据, 避免重复压缩
+}
+
+// 压缩data, 压缩后没有变小返回nil
+func NewTLGzipPackedByData(data []byte) *TLGzipPacked {
+	gzipData, err := gzipCompress(data)
+	if err != nil {
+		glog.Error("gzip compress error: ", err)
+		return nil
+	}
+
+	// gzip_packed的constructor和string的长度前缀
+	if len(gzipData)+8 >= len(data) {
+		return nil
+	}
+	return &TLGzipPacked{PackedData: data, gzipData: gzipData}
 }
 
 func (m *TLGzipPacked) String() string {
@@ -182,9 +204,17 @@ func (m *TLGzipPacked) String() string {
 }
 
 func (m *TLGzipPacked) Encode() []byte {
-	x := NewEncodeBuf(512)
+	if m.gzipData == nil {
+		gzipData, err := gzipCompress(m.PackedData)
+		if err != nil {
+			glog.Error("gzip compress error: ", err)
+		}
+		m.gzipData = gzipData
+	}
+
+	x := NewEncodeBuf(len(m.gzipData) + 8)
 	x.Int(int32(TLConstructor_CRC32_gzip_packed))
-	x.Bytes(m.PackedData)
+	x.StringBytes(m.gzipData)
 	return x.buf
 }
 
@@ -193,22 +223,42 @@ func (m *TLGzipPacked) EncodeToLayer(int) []byte {
 }
 
 func (m *TLGzipPacked) Decode(dbuf *DecodeBuf) error {
-	m.PackedData = make([]byte, 0, 4096)
+	gzipData := dbuf.StringBytes()
+	if dbuf.err != nil {
+		return dbuf.err
+	}
 
-	var buf bytes.Buffer
-	_, _ = buf.Write(dbuf.StringBytes())
-	gz, _ := gzip.NewReader(&buf)
-
-	b := make([]byte, 4096)
-	for true {
-		n, _ := gz.Read(b)
-		if n <= 0 {
-			break
-		}
-		m.PackedData = append(m.PackedData, b[0:n]...)
+	gz, err := gzip.NewReader(bytes.NewReader(gzipData))
+	if err != nil {
+		dbuf.err = fmt.Errorf("decode gzip_packed error: %v", err)
+		return dbuf.err
 	}
+	defer gz.Close()
 
-	return dbuf.err
+	m.PackedData, err = ioutil.ReadAll(io.LimitReader(gz, kMaxGzipPackedDataLen+1))
+	if err != nil {
+		dbuf.err = fmt.Errorf("decode gzip_packed error: %v", err)
+		return dbuf.err
+	}
+	if len(m.PackedData) > kMaxGzipPackedDataLen {
+		m.PackedData = nil
+		dbuf.err = fmt.Errorf("decode gzip_packed error: packed_data too large (> %d)", kMaxGzipPackedDataLen)
+		return dbuf.err
+	}
+	m.gzipData = gzipData
+	return nil
+}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -128,6 +128,10 @@ const (
 	TLRpcErrorCodes_FILE_REFERENCE_X             TLRpcErrorCodes = 400500
 	TLRpcErrorCodes_FILE_TOKEN_INVALID           TLRpcErrorCodes = 400501
 	TLRpcErrorCodes_REQUEST_TOKEN_INVALID        TLRpcErrorCodes = 400502
+	// auth.bindTempAuthKey
+	TLRpcErrorCodes_ENCRYPTED_MESSAGE_INVALID   TLRpcErrorCodes = 400510
+	TLRpcErrorCodes_TEMP_AUTH_KEY_EMPTY         TLRpcErrorCodes = 400511
+	TLRpcErrorCodes_TEMP_AUTH_KEY_ALREADY_BOUND TLRpcErrorCodes = 400512
 	//
 	TLRpcErrorCodes_PHONE_CODE_INVALID      TLRpcErrorCodes = 400025
 	TLRpcErrorCodes_PHONE_NUMBER_BANNED     TLRpcErrorCodes = 400030
@@ -376,6 +380,9 @@ var TLRpcErrorCodes_name = map[int32]string{
 	400500: "FILE_REFERENCE_X",
 	400501: "FILE_TOKEN_INVALID",
 	400502: "REQUEST_TOKEN_INVALID",
+	400510: "ENCRYPTED_MESSAGE_INVALID",
+	400511: "TEMP_AUTH_KEY_EMPTY",
+	400512: "TEMP_AUTH_KEY_ALREADY_BOUND",
 	400025: "PHONE_CODE_INVALID",
 	400030: "PHONE_NUMBER_BANNED",
 	400040: "SESSION_PASSWORD_NEEDED",
@@ -478,6 +485,9 @@ var TLRpcErrorCodes_value = map[string]int32{
 	"FILE_REFERENCE_X":               400500,
 	"FILE_TOKEN_INVALID":             400501,
 	"REQUEST_TOKEN_INVALID":          400502,
+	"ENCRYPTED_MESSAGE_INVALID":      400510,
+	"TEMP_AUTH_KEY_EMPTY":            400511,
+	"TEMP_AUTH_KEY_ALREADY_BOUND":    400512,
 	"PHONE_CODE_INVALID":             400025,
 	"PHONE_NUMBER_BANNED":            400030,
 	"SESSION_PASSWORD_NEEDED":        400040,
@@ -551,7 +561,7 @@ func (x TLRpcErrorCodes) String() string {
 	return proto.EnumName(TLRpcErrorCodes_name, int32(x))
 }
 func (TLRpcErrorCodes) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_error_codes_2d6ce7ab8b6602b9, []int{0}
+	return fileDescriptor_rpc_error_codes_74e2ddff4a2343e2, []int{0}
 }
 
 func init() {
@@ -559,92 +569,94 @@ func init() {
 }
 
 func init() {
-	proto.RegisterFile("rpc_error_codes.proto", fileDescriptor_rpc_error_codes_2d6ce7ab8b6602b9)
+	proto.RegisterFile("rpc_error_codes.proto", fileDescriptor_rpc_error_codes_74e2ddff4a2343e2)
 }
 
-var fileDescriptor_rpc_error_codes_2d6ce7ab8b6602b9 = []byte{
-	// 1319 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_rpc_error_codes_74e2ddff4a2343e2 = []byte{
+	// 1360 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x56, 0xcb, 0x8f, 0x15, 0xc5,
-	0x1e, 0xbe, 0x61, 0x2e, 0x70, 0x29, 0x1e, 0x53, 0x14, 0x0c, 0x34, 0x17, 0xc4, 0x68, 0x58, 0x18,
-	0x17, 0xb3, 0xd0, 0xf8, 0x07, 0xd4, 0xe9, 0xfa, 0x9d, 0x39, 0x95, 0xd3, 0x5d, 0xd5, 0x54, 0x55,
-	0xcf, 0xc3, 0x4d, 0x45, 0xc6, 0x89, 0x21, 0x11, 0x86, 0x8c, 0xb8, 0xf7, 0xfd, 0x7e, 0x80, 0x28,
-	0x8a, 0x26, 0x86, 0x85, 0x0b, 0x17, 0x3e, 0x12, 0xb5, 0xcf, 0x80, 0x39, 0x3a, 0xe8, 0x42, 0x79,
-	0x8c, 0x22, 0x08, 0x89, 0xd1, 0xa5, 0x31, 0xf1, 0x1d, 0x17, 0xc0, 0xda, 0x54, 0x55, 0x77, 0x9f,
-	0x3e, 0xa3, 0xbb, 0xee, 0xef, 0xab, 0xfa, 0x3d, 0xbe, 0xfa, 0xea, 0xd7, 0x8d, 0xc6, 0x16, 0x0e,
-	0xcd, 0xda, 0xb9, 0x85, 0x85, 0xf9, 0x05, 0x3b, 0x3b, 0x7f, 0xef, 0xdc, 0x03, 0xe3, 0x87, 0x16,
-	0xe6, 0x0f, 0xcf, 0x93, 0xb5, 0x07, 0x0e, 0xfb, 0x87, 0xdb, 0x7f, 0xda, 0x82, 0x46, 0x4d, 0xa2,
-	0x0e, 0xcd, 0x82, 0x5b, 0x13, 0xbb, 0x25, 0x64, 0x33, 0xda, 0x08, 0x4a, 0x49, 0x65, 0x63, 0xc9,
-	0xc0, 0xca, 0x2e, 0xfe, 0x0f, 0xd9, 0x8a, 0x36, 0xb5, 0x79, 0x02, 0x36, 0xe5, 0x13, 0x8a, 0x1a,
-	0xb0, 0xd3, 0xf8, 0xb5, 0x25, 0x42, 0xc6, 0xd0, 0x68, 0xd6, 0x91, 0xa2, 0x09, 0x9f, 0x58, 0x22,
-	0x64, 0x3b, 0xda, 0x2c, 0xc0, 0x4c, 0x49, 0xd5, 0x6d, 0x10, 0xaf, 0x2f, 0x11, 0x17, 0x25, 0xd7,
-	0xa0, 0x1a, 0xe8, 0x1b, 0x1e, 0x1d, 0x0d, 0xe9, 0x34, 0x80, 0x95, 0xa6, 0x03, 0x0a, 0xbf, 0xbf,
-	0xca, 0x05, 0x69, 0x73, 0xa5, 0x8d, 0xa0, 0x29, 0x58, 0x2e, 0x26, 0x69, 0xc2, 0x19, 0x7e, 0xa8,
-	0x88, 0xc8, 0x36, 0x84, 0x13, 0xba, 0x02, 0x7f, 0xb8, 0x88, 0xc8, 0xff, 0xd1, 0xd6, 0x50, 0x8c,
-	0xc8, 0xd3, 0x16, 0xa8, 0x9a, 0x7b, 0xa4, 0x88, 0xc8, 0x4e, 0x34, 0x16, 0x38, 0xdf, 0x51, 0x87,
-	0xea, 0x8e, 0x85, 0x34, 0x33, 0x33, 0xf8, 0xd1, 0x10, 0xb0, 0x41, 0x06, 0xfc, 0xb1, 0x22, 0x22,
-	0x11, 0x22, 0x4d, 0x7c, 0x3a, 0xe3, 0x0a, 0x18, 0x7e, 0xbc, 0x88, 0x5c, 0x1f, 0x34, 0xe3, 0x96,
-	0xb3, 0x3a, 0xc9, 0x13, 0xcd, 0x24, 0x65, 0x01, 0x32, 0x8e, 0xf3, 0x8c, 0x03, 0xc3, 0x4f, 0x16,
-	0x11, 0xb9, 0x09, 0x6d, 0x1f, 0x22, 0x73, 0x51, 0xd3, 0x4f, 0x15, 0x11, 0xd9, 0x82, 0x36, 0x3a,
-	0x65, 0xb4, 0x35, 0x52, 0xda, 0x36, 0x4c, 0xe1, 0xa7, 0x43, 0x9a, 0x01, 0x98, 0xe6, 0x71, 0x07,
-	0x3f, 0x53, 0x44, 0x64, 0x37, 0x8a, 0xcc, 0x4c, 0xe6, 0xaa, 0x12, 0xda, 0xa8, 0x3c, 0x36, 0x72,
-	0xd0, 0xeb, 0xb3, 0x45, 0x14, 0x84, 0x4b, 0xc0, 0x66, 0x54, 0x99, 0x9a, 0x78, 0xae, 0x88, 0xc8,
-	0x0e, 0xb4, 0x65, 0x40, 0x4c, 0xdb, 0x94, 0x6b, 0xcd, 0xc5, 0x04, 0x7e, 0x3e, 0x68, 0x97, 0xb2,
-	0xbb, 0x6c, 0xdc, 0x81, 0xb8, 0xab, 0xf3, 0xb4, 0xde, 0xf6, 0x42, 0xc8, 0x97, 0x75, 0xa4, 0x91,
-	0x15, 0x68, 0x19, 0x4f, 0x41, 0x68, 0x2e, 0x85, 0xc6, 0x2f, 0x06, 0x99, 0xda, 0x1c, 0x12, 0x66,
-	0x87, 0x4e, 0xe4, 0x48, 0x10, 0xb6, 0xc1, 0x04, 0x61, 0x8f, 0x16, 0x91, 0xb3, 0x4d, 0xaa, 0x27,
-	0xec, 0x14, 0xe5, 0xc6, 0xb6, 0x29, 0x4f, 0x80, 0xe1, 0x97, 0x8a, 0x88, 0xdc, 0x8a, 0x76, 0xb9,
-	0xd2, 0x78, 0xcc, 0x33, 0x2a, 0x8c, 0x9d, 0x04, 0xe5, 0x92, 0x58, 0x99, 0x1b, 0x46, 0x0d, 0x30,
-	0x7c, 0x2c, 0x6c, 0xf5, 0x0e, 0x52, 0xa0, 0x8d, 0xe2, 0xb1, 0x83, 0x5f, 0x0e, 0x3d, 0xfb, 0x1c,
-	0x42, 0x1a, 0x9b, 0x4a, 0xc6, 0xdb, 0x4e, 0xd7, 0x57, 0x82, 0xec, 0x7e, 0xbd, 0x27, 0x72, 0x93,
-	0xd3, 0xc4, 0xe9, 0x66, 0x68, 0x6c, 0xf0, 0xf1, 0x50, 0x7b, 0x4b, 0x1a, 0x3b, 0xa1, 0x64, 0x9e,
-	0x69, 0xdb, 0x4a, 0x64, 0xdc, 0x05, 0x86, 0x5f, 0xad, 0x6a, 0x4f, 0xc0, 0x2a, 0x68, 0x83, 0x02,
-	0x11, 0x3b, 0xb3, 0x5e, 0x3b, 0x55, 0x76, 0x9b, 0x80, 0x35, 0xb2, 0x0b, 0xa2, 0xee, 0xf6, 0xfa,
-	0x29, 0x7f, 0xfc, 0x0a, 0xf6, 0xe6, 0xa0, 0xcd, 0x0a, 0xf2, 0xc6, 0xa9, 0x95, 0x5e, 0xaa, 0x98,
-	0x13, 0xe1, 0x54, 0x86, 0x8c, 0xd1, 0xa2, 0x42, 0x00, 0xc3, 0x6f, 0x86, 0xe2, 0x35, 0x68, 0x2f,
-	0x42, 0x46, 0xb5, 0x9e, 0x92, 0x8a, 0x59, 0x01, 0xc0, 0x80, 0xe1, 0xb7, 0x8b, 0x88, 0x10, 0xb4,
-	0x61, 0x28, 0xda, 0x07, 0xa5, 0x07, 0xab, 0xa5, 0xde, 0xe6, 0x15, 0xf9, 0x61, 0xe8, 0x49, 0xc0,
-	0xd4, 0x20, 0x56, 0x8b, 0x32, 0xfc, 0xd1, 0x00, 0xd7, 0x34, 0x19, 0x18, 0xa6, 0x08, 0xa6, 0x84,
-	0x94, 0xf2, 0xa4, 0x06, 0x7b, 0x41, 0xea, 0x00, 0xe6, 0x22, 0x96, 0xa2, 0xcd, 0x55, 0x0a, 0x0c,
-	0x2f, 0x86, 0x28, 0x4e, 0xea, 0x21, 0x17, 0xf4, 0xc3, 0x86, 0x1a, 0xaf, 0x3d, 0xff, 0x49, 0xa8,
-	0xb5, 0x22, 0xb4, 0xcd, 0x05, 0x9d, 0xa4, 0x3c, 0xa1, 0xad, 0x04, 0xf0, 0xa7, 0xc3, 0xe4, 0xf0,
-	0xa9, 0x2e, 0xfd, 0x0b, 0x59, 0x87, 0x3d, 0x13, 0x2c, 0x12, 0x77, 0xa8, 0x69, 0xde, 0xce, 0x2f,
-	0x42, 0x19, 0x1e, 0x1e, 0x0a, 0xf6, 0x65, 0x11, 0x91, 0x5d, 0x68, 0x5b, 0xd3, 0x76, 0x8e, 0x87,
-	0x69, 0xae, 0x8d, 0xc6, 0x67, 0xc3, 0x19, 0x08, 0x69, 0x81, 0x71, 0x63, 0xfd, 0xf6, 0x0c, 0x94,
-	0xbf, 0x38, 0x52, 0xe0, 0x73, 0x81, 0xf6, 0xb0, 0xe1, 0x26, 0x59, 0x51, 0xe8, 0xf9, 0xa0, 0xa0,
-	0x90, 0x76, 0xb0, 0x02, 0x5f, 0x68, 0xec, 0xa1, 0x2d, 0x99, 0xaf, 0xa8, 0x67, 0x39, 0x18, 0x22,
-	0xd0, 0x2c, 0xe5, 0xc2, 0x3a, 0x4b, 0xf9, 0xb9, 0xf3, 0x55, 0xe9, 0x95, 0x46, 0xa9, 0xbe, 0x4c,
-	0x60, 0xf8, 0xeb, 0xba, 0x6b, 0x21, 0x20, 0xb1, 0x99, 0xe2, 0x93, 0xd4, 0x00, 0xfe, 0xa6, 0xce,
-	0x15, 0xe0, 0xbc, 0x95, 0xf0, 0x38, 0x78, 0xdd, 0x0a, 0x8a, 0x2f, 0x85, 0xde, 0xfd, 0xf5, 0x08,
-	0xa6, 0xb3, 0x5c, 0xd8, 0x72, 0x35, 0xbe, 0x5c, 0x44, 0x64, 0x0f, 0xda, 0x5d, 0xbe, 0xea, 0xb2,
-	0x9a, 0x32, 0x46, 0x3d, 0x8f, 0x8e, 0x9d, 0x5e, 0x55, 0x0a, 0x1b, 0x56, 0xd5, 0xc4, 0x95, 0x70,
-	0x9f, 0x85, 0x9f, 0x1a, 0xdc, 0x80, 0xad, 0xab, 0x18, 0xe8, 0x77, 0x35, 0x74, 0x54, 0x2e, 0x08,
-	0x43, 0xb9, 0x1c, 0xb2, 0xdf, 0xff, 0x93, 0xaa, 0xce, 0xf2, 0x87, 0x40, 0xd1, 0x38, 0x06, 0xad,
-	0x87, 0xa9, 0xb3, 0xbd, 0x72, 0x57, 0x96, 0x9b, 0x3a, 0x61, 0x18, 0x3b, 0xe7, 0x7a, 0x7e, 0xc8,
-	0xd5, 0xb3, 0xa0, 0x21, 0x23, 0x3e, 0xdf, 0xf3, 0xf2, 0x65, 0xe0, 0x3e, 0x1a, 0x03, 0xd3, 0x5c,
-	0xe8, 0xf9, 0x6b, 0x5b, 0xc5, 0x69, 0x30, 0xcb, 0x81, 0x49, 0x41, 0x6b, 0x3a, 0x01, 0x4d, 0xe6,
-	0x72, 0x2f, 0x22, 0x37, 0xa3, 0x1d, 0x15, 0xe3, 0x6d, 0x63, 0x78, 0x3a, 0xf8, 0x7a, 0x7c, 0x1b,
-	0xea, 0xa8, 0x16, 0x0c, 0x1d, 0xfe, 0x95, 0x9e, 0x37, 0x4c, 0xbd, 0xd9, 0x17, 0x7e, 0xb5, 0x57,
-	0x7f, 0x07, 0x6c, 0x02, 0xed, 0xe0, 0x42, 0xfc, 0xce, 0x62, 0x44, 0x36, 0xa3, 0xf5, 0x1e, 0xed,
-	0x72, 0x3f, 0xb4, 0xde, 0x5d, 0xf4, 0xa3, 0xda, 0x43, 0x34, 0x51, 0x40, 0xd9, 0xcc, 0x50, 0x97,
-	0xef, 0x2d, 0x46, 0x04, 0xa3, 0xf5, 0x2d, 0xca, 0x6c, 0x39, 0xa6, 0xf0, 0x91, 0x11, 0x77, 0x93,
-	0x68, 0x6e, 0x3a, 0xb6, 0x0b, 0x33, 0x36, 0x17, 0x0a, 0x26, 0x9c, 0xa3, 0x5c, 0xa1, 0x3f, 0xf7,
-	0xfd, 0x8d, 0xae, 0xc9, 0xaa, 0xc3, 0x5f, 0xfa, 0xf5, 0x4d, 0xb7, 0x0c, 0x68, 0x6c, 0xbc, 0xd7,
-	0x18, 0xfe, 0xb5, 0xef, 0x45, 0xac, 0xe6, 0x95, 0x82, 0x49, 0xe9, 0xaa, 0xfa, 0x6d, 0x18, 0xae,
-	0x64, 0xf8, 0xbd, 0xef, 0x65, 0xf0, 0xdb, 0xc1, 0x96, 0x13, 0xbd, 0x34, 0xfa, 0x1f, 0xfd, 0x70,
-	0xc0, 0x55, 0x66, 0xe7, 0x98, 0x52, 0x8c, 0x3f, 0xfb, 0xae, 0xed, 0x0d, 0xb9, 0x70, 0xa4, 0x54,
-	0xfc, 0x6e, 0x60, 0xf8, 0xe8, 0x48, 0x3d, 0xe4, 0xbd, 0xf1, 0xe3, 0x99, 0xe6, 0xc7, 0xe1, 0xf4,
-	0x72, 0x44, 0x6e, 0x41, 0x3b, 0x63, 0x9a, 0xb8, 0x7b, 0x21, 0x8d, 0x8c, 0x65, 0x62, 0x13, 0x3a,
-	0xd3, 0xf8, 0x3f, 0xf8, 0x78, 0x39, 0x22, 0x9b, 0xd0, 0xba, 0xb6, 0x54, 0x2d, 0xce, 0x18, 0x08,
-	0x7c, 0x6c, 0xc4, 0x95, 0x1c, 0x7e, 0x49, 0x12, 0x19, 0xd3, 0xc4, 0xe7, 0xf9, 0xeb, 0x47, 0xbf,
-	0x6c, 0x00, 0x1c, 0x1f, 0x71, 0x13, 0xb8, 0x9d, 0x48, 0xc9, 0xc2, 0xa7, 0x6c, 0x1a, 0x9f, 0xbc,
-	0xb4, 0x83, 0x20, 0xb4, 0xda, 0x63, 0xf8, 0xad, 0x11, 0xb2, 0x11, 0xfd, 0x8f, 0x0b, 0xe3, 0x06,
-	0x55, 0x82, 0xaf, 0x79, 0xb1, 0xab, 0x57, 0xab, 0x41, 0x4d, 0x82, 0xb2, 0x3e, 0x0b, 0x3e, 0xf9,
-	0xf9, 0x6e, 0xb7, 0x2f, 0xfc, 0xfb, 0x5c, 0x1f, 0x21, 0xeb, 0xd1, 0x1a, 0xff, 0x7c, 0x07, 0xbe,
-	0x31, 0xe2, 0x08, 0xd6, 0x02, 0xa5, 0xf0, 0x77, 0xff, 0x25, 0xa3, 0x68, 0x9d, 0x7f, 0xb6, 0x7a,
-	0x6f, 0x82, 0xcf, 0x5c, 0xdc, 0x43, 0x30, 0x42, 0x01, 0x88, 0xa5, 0x10, 0xf8, 0xb3, 0x8b, 0x7b,
-	0xc8, 0x18, 0xc2, 0x42, 0x1a, 0x05, 0x26, 0x57, 0xc2, 0xc6, 0x09, 0x07, 0x61, 0x70, 0x7f, 0x75,
-	0xeb, 0x36, 0xb4, 0x73, 0x76, 0xfe, 0xc0, 0xf8, 0xc1, 0xb9, 0x7d, 0x0f, 0xde, 0x7f, 0xcf, 0xfe,
-	0x03, 0xe3, 0x73, 0x07, 0xef, 0xdb, 0x7f, 0x70, 0x6e, 0xbc, 0xfc, 0x0d, 0x6c, 0xad, 0x4d, 0x4d,
-	0xe6, 0x1e, 0x3a, 0xab, 0xf6, 0xad, 0xf1, 0xc8, 0x9d, 0x7f, 0x07, 0x00, 0x00, 0xff, 0xff, 0xad,
-	0xb7, 0x66, 0x0e, 0x3a, 0x0a, 0x00, 0x00,
+	0x1a, 0xbf, 0x61, 0x2e, 0x70, 0x29, 0x1e, 0x53, 0x14, 0x0c, 0x34, 0x17, 0x2e, 0x37, 0x1a, 0x16,
+	0xc6, 0xc5, 0x2c, 0x34, 0xfe, 0x01, 0x75, 0xba, 0xbe, 0x33, 0xa7, 0x32, 0xdd, 0x55, 0x4d, 0x55,
+	0xf5, 0x3c, 0xdc, 0x54, 0x64, 0x9c, 0x18, 0x12, 0x61, 0xc8, 0x88, 0x7b, 0x7c, 0xbf, 0x15, 0x44,
+	0x51, 0x34, 0x31, 0x2c, 0x5c, 0xb8, 0xf0, 0x91, 0xa8, 0x7d, 0x66, 0x30, 0x47, 0x07, 0x8d, 0x51,
+	0x1e, 0xa3, 0x08, 0x42, 0x62, 0x74, 0xe9, 0xc6, 0x77, 0x5c, 0x00, 0x4b, 0x35, 0x55, 0xd5, 0xdd,
+	0xa7, 0xcf, 0xe8, 0xee, 0x9c, 0xdf, 0xaf, 0xaa, 0xbe, 0xef, 0xfb, 0xd5, 0xef, 0xfb, 0xaa, 0xd1,
+	0xc8, 0xfc, 0xa1, 0x19, 0x3b, 0x3b, 0x3f, 0x3f, 0x37, 0x6f, 0x67, 0xe6, 0xee, 0x9e, 0xbd, 0x6f,
+	0xf4, 0xd0, 0xfc, 0xdc, 0xe1, 0x39, 0xb2, 0xf6, 0xc0, 0x61, 0xff, 0xe3, 0xd6, 0x4f, 0xb7, 0xa2,
+	0x61, 0x93, 0xa8, 0x43, 0x33, 0xe0, 0xd6, 0xc4, 0x6e, 0x09, 0xd9, 0x8c, 0x36, 0x82, 0x52, 0x52,
+	0xd9, 0x58, 0x32, 0xb0, 0x72, 0x1c, 0xff, 0x8b, 0x6c, 0x45, 0x9b, 0xda, 0x3c, 0x01, 0x9b, 0xf2,
+	0x31, 0x45, 0x0d, 0xd8, 0x29, 0xfc, 0xf2, 0x12, 0x21, 0x23, 0x68, 0x38, 0xeb, 0x48, 0xd1, 0x84,
+	0x4f, 0x2e, 0x11, 0xb2, 0x1d, 0x6d, 0x16, 0x60, 0x26, 0xa5, 0x1a, 0x6f, 0x10, 0xaf, 0x2c, 0x11,
+	0x77, 0x4a, 0xae, 0x41, 0x35, 0xd0, 0x57, 0x3d, 0x3a, 0x1c, 0xc2, 0x69, 0x00, 0x2b, 0x4d, 0x07,
+	0x14, 0x7e, 0x67, 0x95, 0x3b, 0xa4, 0xcd, 0x95, 0x36, 0x82, 0xa6, 0x60, 0xb9, 0x98, 0xa0, 0x09,
+	0x67, 0xf8, 0x48, 0x11, 0x91, 0x6d, 0x08, 0x27, 0x74, 0x05, 0xfe, 0x40, 0x11, 0x91, 0xff, 0xa2,
+	0xad, 0x21, 0x19, 0x91, 0xa7, 0x2d, 0x50, 0x35, 0xf7, 0x60, 0x11, 0x91, 0x9d, 0x68, 0x24, 0x70,
+	0xbe, 0xa2, 0x0e, 0xd5, 0x1d, 0x0b, 0x69, 0x66, 0xa6, 0xf1, 0x43, 0xe1, 0xc0, 0x06, 0x19, 0xf0,
+	0x87, 0x8b, 0x88, 0x44, 0x88, 0x34, 0xf1, 0xa9, 0x8c, 0x2b, 0x60, 0xf8, 0x91, 0x22, 0x72, 0x75,
+	0xd0, 0x8c, 0x5b, 0xce, 0xea, 0x20, 0x8f, 0x36, 0x83, 0x94, 0x09, 0xc8, 0x38, 0xce, 0x33, 0x0e,
+	0x0c, 0x3f, 0x56, 0x44, 0xe4, 0x7f, 0x68, 0xfb, 0x00, 0x99, 0x8b, 0x9a, 0x7e, 0xbc, 0x88, 0xc8,
+	0x16, 0xb4, 0xd1, 0x29, 0xa3, 0xad, 0x91, 0xd2, 0xb6, 0x61, 0x12, 0x3f, 0x11, 0xc2, 0xf4, 0xc1,
+	0x34, 0x8f, 0x3b, 0xf8, 0xc9, 0x22, 0x22, 0xbb, 0x51, 0x64, 0xa6, 0x33, 0x97, 0x95, 0xd0, 0x46,
+	0xe5, 0xb1, 0x91, 0xfd, 0x5a, 0x9f, 0x2a, 0xa2, 0x20, 0x5c, 0x02, 0x36, 0xa3, 0xca, 0xd4, 0xc4,
+	0xd3, 0x45, 0x44, 0x76, 0xa0, 0x2d, 0x7d, 0x62, 0xca, 0xa6, 0x5c, 0x6b, 0x2e, 0xc6, 0xf0, 0x33,
+	0x41, 0xbb, 0x94, 0xdd, 0x61, 0xe3, 0x0e, 0xc4, 0xe3, 0x3a, 0x4f, 0xeb, 0x6d, 0xcf, 0x86, 0x78,
+	0x59, 0x47, 0x1a, 0x59, 0x81, 0x96, 0xf1, 0x14, 0x84, 0xe6, 0x52, 0x68, 0xfc, 0x5c, 0x90, 0xa9,
+	0xcd, 0x21, 0x61, 0x76, 0xe0, 0x46, 0x8e, 0x06, 0x61, 0x1b, 0x4c, 0x10, 0xf6, 0x58, 0x11, 0x39,
+	0xdb, 0xa4, 0x7a, 0xcc, 0x4e, 0x52, 0x6e, 0x6c, 0x9b, 0xf2, 0x04, 0x18, 0x7e, 0xbe, 0x88, 0xc8,
+	0xcd, 0x68, 0x97, 0x4b, 0x8d, 0xc7, 0x3c, 0xa3, 0xc2, 0xd8, 0x09, 0x50, 0x2e, 0x88, 0x95, 0xb9,
+	0x61, 0xd4, 0x00, 0xc3, 0xc7, 0xc3, 0x56, 0xef, 0x20, 0x05, 0xda, 0x28, 0x1e, 0x3b, 0xf8, 0x85,
+	0x50, 0xb3, 0x8f, 0x21, 0xa4, 0xb1, 0xa9, 0x64, 0xbc, 0xed, 0x74, 0x7d, 0x31, 0xc8, 0xee, 0xd7,
+	0x7b, 0x22, 0x37, 0x39, 0x4d, 0x9c, 0x6e, 0x86, 0xc6, 0x06, 0x9f, 0x08, 0xb9, 0xb7, 0xa4, 0xb1,
+	0x63, 0x4a, 0xe6, 0x99, 0xb6, 0xad, 0x44, 0xc6, 0xe3, 0xc0, 0xf0, 0x4b, 0x55, 0xee, 0x09, 0x58,
+	0x05, 0x6d, 0x50, 0x20, 0x62, 0x67, 0xd6, 0x6b, 0x8b, 0x65, 0xb5, 0x09, 0x58, 0x23, 0xc7, 0x41,
+	0xd4, 0xd5, 0x5e, 0x5f, 0xf4, 0xd7, 0xaf, 0x60, 0x6f, 0x0e, 0xda, 0xac, 0x20, 0x6f, 0x2c, 0x46,
+	0xe4, 0xff, 0x68, 0x07, 0x88, 0x58, 0x4d, 0x67, 0x06, 0x98, 0x4d, 0x41, 0x6b, 0x3a, 0xd6, 0xd7,
+	0xea, 0x8f, 0x45, 0x7f, 0x39, 0x06, 0xd2, 0xcc, 0xd2, 0xdc, 0x74, 0xec, 0x38, 0x4c, 0x97, 0x72,
+	0xfd, 0xb9, 0x18, 0x91, 0x9b, 0xd0, 0xce, 0x41, 0x8a, 0x26, 0x0a, 0x28, 0x9b, 0xb6, 0x2d, 0x99,
+	0x0b, 0x86, 0x8f, 0x9c, 0x5e, 0x69, 0xd5, 0xea, 0xdc, 0x93, 0xe1, 0xd2, 0x07, 0x7c, 0xd7, 0xa2,
+	0x42, 0x00, 0xc3, 0xaf, 0x05, 0x6d, 0x34, 0x68, 0xaf, 0x71, 0x46, 0xb5, 0x9e, 0x94, 0x8a, 0x59,
+	0x01, 0xc0, 0x80, 0xe1, 0x37, 0x8a, 0x88, 0x10, 0xb4, 0x61, 0xe0, 0xb4, 0x77, 0x4b, 0x8b, 0x57,
+	0x4b, 0x7d, 0x17, 0x55, 0xe4, 0x7b, 0x41, 0x32, 0x01, 0x93, 0xfd, 0xb3, 0x5a, 0x94, 0xe1, 0xf7,
+	0xfb, 0xb8, 0xa6, 0x49, 0xdf, 0x8f, 0x45, 0xf0, 0x3c, 0xa4, 0x94, 0x27, 0x35, 0xd8, 0x0d, 0x37,
+	0x19, 0xc0, 0x5c, 0xc4, 0x52, 0xb4, 0xb9, 0x4a, 0x81, 0xe1, 0x85, 0x70, 0x8a, 0xbb, 0xc9, 0x01,
+	0x93, 0xf5, 0xc2, 0x86, 0x1a, 0xaf, 0x5b, 0xea, 0xc3, 0x90, 0x6b, 0x45, 0x68, 0x9b, 0x0b, 0x3a,
+	0x41, 0x79, 0x42, 0x5b, 0x09, 0xe0, 0x8f, 0x06, 0xc9, 0x41, 0xd3, 0x2c, 0xfd, 0x03, 0x59, 0x1f,
+	0x7b, 0x26, 0x38, 0x30, 0xee, 0x50, 0xd3, 0x6c, 0xfe, 0xcf, 0x42, 0x1a, 0x1e, 0x1e, 0x38, 0xec,
+	0xf3, 0x22, 0x22, 0xbb, 0xd0, 0xb6, 0xa6, 0xab, 0x1d, 0x0f, 0x53, 0x5c, 0x1b, 0x8d, 0xcf, 0x86,
+	0x3b, 0x10, 0xd2, 0x02, 0xe3, 0xc6, 0xfa, 0xed, 0x19, 0x28, 0xdf, 0x97, 0x52, 0xe0, 0x73, 0x81,
+	0xf6, 0xb0, 0xe1, 0x26, 0x59, 0x91, 0xe8, 0xf9, 0xa0, 0xa0, 0x90, 0xb6, 0xbf, 0x02, 0x5f, 0x68,
+	0xec, 0xa1, 0x2d, 0x99, 0xaf, 0xc8, 0x67, 0x39, 0x18, 0x22, 0xd0, 0x2c, 0xe5, 0xc2, 0x3a, 0xc7,
+	0xfa, 0xb1, 0xf6, 0x45, 0xe9, 0x95, 0x46, 0xaa, 0x3e, 0x4d, 0x60, 0xf8, 0xcb, 0xba, 0x6a, 0x21,
+	0x20, 0xb1, 0x99, 0xe2, 0x13, 0xd4, 0x00, 0xfe, 0xaa, 0x8e, 0x15, 0xe0, 0xbc, 0x95, 0xf0, 0x38,
+	0xb4, 0x92, 0x15, 0x14, 0x5f, 0x0a, 0xb5, 0xe7, 0xba, 0x36, 0x9d, 0xe5, 0xc2, 0x96, 0xab, 0xf1,
+	0xe5, 0x22, 0x22, 0x7b, 0xd0, 0xee, 0xf2, 0xaf, 0x2e, 0xb3, 0x29, 0xcf, 0xa8, 0xc7, 0xdd, 0xf1,
+	0xd3, 0xab, 0x4a, 0x61, 0xc3, 0xaa, 0x9a, 0xb8, 0x12, 0xc6, 0x85, 0xf0, 0x43, 0x89, 0x1b, 0xb0,
+	0x75, 0x16, 0x7d, 0xfd, 0xae, 0x86, 0x8a, 0xca, 0x05, 0x61, 0xe6, 0x97, 0x33, 0xfc, 0xdb, 0xbf,
+	0x53, 0xd5, 0x5d, 0x7e, 0x17, 0x28, 0x1a, 0xc7, 0xa0, 0xf5, 0x20, 0x75, 0xb6, 0x5b, 0xee, 0xca,
+	0x72, 0x53, 0x07, 0x0c, 0x6d, 0x7a, 0xae, 0xeb, 0x67, 0x68, 0x3d, 0x6a, 0x1a, 0x32, 0xe2, 0xf3,
+	0x5d, 0x2f, 0x5f, 0x06, 0xee, 0x4d, 0xea, 0x9b, 0xe6, 0x42, 0xd7, 0xb7, 0x6d, 0x75, 0x4e, 0x83,
+	0x59, 0x0e, 0x4c, 0x3d, 0x25, 0xfa, 0xcc, 0xe5, 0xae, 0x9f, 0x24, 0x15, 0xe3, 0x6d, 0x63, 0x78,
+	0xda, 0x7f, 0x9c, 0xbe, 0x0e, 0x79, 0x54, 0x0b, 0x06, 0x2e, 0xff, 0x4a, 0xd7, 0x1b, 0xa6, 0xde,
+	0xec, 0x13, 0xbf, 0xda, 0xad, 0x9f, 0x19, 0x9b, 0x40, 0x3b, 0xb8, 0x10, 0xbf, 0xb9, 0x10, 0x91,
+	0xcd, 0x68, 0xbd, 0x47, 0xc7, 0xb9, 0x9f, 0x89, 0x6f, 0x2d, 0xf8, 0x97, 0xc0, 0x43, 0xd5, 0xfc,
+	0x69, 0x56, 0xf9, 0xf6, 0x42, 0x44, 0x30, 0x5a, 0xdf, 0xa2, 0xcc, 0x96, 0x53, 0x10, 0x1f, 0x1d,
+	0x72, 0x9d, 0x54, 0x4f, 0xad, 0x5c, 0x28, 0x18, 0x73, 0x8e, 0x72, 0x89, 0xfe, 0xd0, 0xf3, 0x1d,
+	0x5d, 0x93, 0x55, 0x85, 0x3f, 0xf6, 0xea, 0x4e, 0xb7, 0x0c, 0x68, 0x6c, 0xbc, 0xd7, 0x18, 0xfe,
+	0xa9, 0xe7, 0x45, 0xac, 0xe6, 0x95, 0x82, 0x09, 0xe9, 0xb2, 0xfa, 0x79, 0x10, 0xae, 0x64, 0xf8,
+	0xa5, 0xe7, 0x65, 0xf0, 0xdb, 0xc1, 0x96, 0x0f, 0x46, 0x69, 0xf4, 0x5f, 0x7b, 0xe1, 0x82, 0xab,
+	0xc8, 0xce, 0x31, 0xa5, 0x18, 0xbf, 0xf5, 0x5c, 0xd9, 0x1b, 0x72, 0xe1, 0x48, 0xa9, 0xf8, 0x9d,
+	0xc0, 0xf0, 0xb1, 0xa1, 0xfa, 0x0d, 0xf1, 0xc6, 0x8f, 0xa7, 0x9b, 0x6f, 0xcf, 0xe9, 0x65, 0x3f,
+	0x9e, 0x63, 0x9a, 0xb8, 0xbe, 0x90, 0x46, 0xc6, 0x32, 0xb1, 0x09, 0x9d, 0x6e, 0x7c, 0x7e, 0x7c,
+	0xb0, 0x1c, 0x91, 0x4d, 0x68, 0x5d, 0x5b, 0xaa, 0x16, 0x67, 0x0c, 0x04, 0x3e, 0x3e, 0x44, 0x46,
+	0xaa, 0x2f, 0x9e, 0x44, 0xc6, 0x34, 0xf1, 0x71, 0x7e, 0xff, 0xde, 0x2f, 0xeb, 0x03, 0x27, 0x86,
+	0xdc, 0x04, 0x6e, 0x27, 0x52, 0xb2, 0xf0, 0x52, 0x4e, 0xe1, 0x53, 0x97, 0x76, 0x10, 0x84, 0x56,
+	0x7b, 0x0c, 0xbf, 0x3e, 0x44, 0x36, 0xa2, 0xff, 0x70, 0x61, 0xdc, 0xa0, 0x4a, 0xf0, 0x35, 0x2f,
+	0x76, 0xf5, 0xd7, 0x6a, 0x50, 0x13, 0xa0, 0xac, 0x8f, 0x82, 0x4f, 0x7d, 0xb2, 0xdb, 0xed, 0x0b,
+	0x9f, 0x56, 0xd7, 0x87, 0xc8, 0x7a, 0xb4, 0xc6, 0xff, 0xbe, 0x0d, 0xdf, 0x18, 0x72, 0x04, 0x6b,
+	0x81, 0x52, 0xf8, 0x9b, 0x7f, 0x93, 0x61, 0xb4, 0xce, 0xff, 0xb6, 0x7a, 0x6f, 0x82, 0xcf, 0x5c,
+	0xdc, 0x43, 0x30, 0x42, 0x01, 0x88, 0xa5, 0x10, 0xf8, 0xe3, 0x8b, 0x7b, 0xc8, 0x08, 0xc2, 0x42,
+	0x1a, 0x05, 0x26, 0x57, 0xc2, 0xc6, 0x09, 0x07, 0x61, 0x70, 0x6f, 0x75, 0xeb, 0x16, 0xb4, 0x73,
+	0x66, 0xee, 0xc0, 0xe8, 0xc1, 0xd9, 0x7d, 0xf7, 0xdf, 0x7b, 0xd7, 0xfe, 0x03, 0xa3, 0xb3, 0x07,
+	0xef, 0xd9, 0x7f, 0x70, 0x76, 0xb4, 0xfc, 0xca, 0x6c, 0xad, 0x4d, 0x4d, 0xe6, 0x7e, 0x74, 0x56,
+	0xed, 0x5b, 0xe3, 0x91, 0xdb, 0xff, 0x1a, 0x00, 0xf0, 0x9c, 0x4c, 0x68, 0x99, 0x0a, 0x00, 0x00,
 }
@@ -44,7 +44,7 @@ func (m *AttachData_Data) Reset()         { *m = AttachData_Data{} }
 func (m *AttachData_Data) String() string { return proto.CompactTextString(m) }
 func (*AttachData_Data) ProtoMessage()    {}
 func (*AttachData_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{0}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{0}
 }
 func (m *AttachData_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AttachData_Data.Unmarshal(m, b)
@@ -125,7 +125,7 @@ func (m *AttachData) Reset()         { *m = AttachData{} }
 func (m *AttachData) String() string { return proto.CompactTextString(m) }
 func (*AttachData) ProtoMessage()    {}
 func (*AttachData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{1}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{1}
 }
 func (m *AttachData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AttachData.Unmarshal(m, b)
@@ -171,7 +171,7 @@ func (m *TLAttachData) Reset()         { *m = TLAttachData{} }
 func (m *TLAttachData) String() string { return proto.CompactTextString(m) }
 func (*TLAttachData) ProtoMessage()    {}
 func (*TLAttachData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{2}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{2}
 }
 func (m *TLAttachData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLAttachData.Unmarshal(m, b)
@@ -213,7 +213,7 @@ func (m *ConnectToServer_Data) Reset()         { *m = ConnectToServer_Data{} }
 func (m *ConnectToServer_Data) String() string { return proto.CompactTextString(m) }
 func (*ConnectToServer_Data) ProtoMessage()    {}
 func (*ConnectToServer_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{3}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{3}
 }
 func (m *ConnectToServer_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConnectToServer_Data.Unmarshal(m, b)
@@ -252,7 +252,7 @@ func (m *ConnectToServer) Reset()         { *m = ConnectToServer{} }
 func (m *ConnectToServer) String() string { return proto.CompactTextString(m) }
 func (*ConnectToServer) ProtoMessage()    {}
 func (*ConnectToServer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{4}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{4}
 }
 func (m *ConnectToServer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConnectToServer.Unmarshal(m, b)
@@ -298,7 +298,7 @@ func (m *TLSyncConnectToSessionServer) Reset()         { *m = TLSyncConnectToSes
 func (m *TLSyncConnectToSessionServer) String() string { return proto.CompactTextString(m) }
 func (*TLSyncConnectToSessionServer) ProtoMessage()    {}
 func (*TLSyncConnectToSessionServer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{5}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{5}
 }
 func (m *TLSyncConnectToSessionServer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncConnectToSessionServer.Unmarshal(m, b)
@@ -341,7 +341,7 @@ func (m *ServerConnected_Data) Reset()         { *m = ServerConnected_Data{} }
 func (m *ServerConnected_Data) String() string { return proto.CompactTextString(m) }
 func (*ServerConnected_Data) ProtoMessage()    {}
 func (*ServerConnected_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{6}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{6}
 }
 func (m *ServerConnected_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ServerConnected_Data.Unmarshal(m, b)
@@ -387,7 +387,7 @@ func (m *ServerConnected) Reset()         { *m = ServerConnected{} }
 func (m *ServerConnected) String() string { return proto.CompactTextString(m) }
 func (*ServerConnected) ProtoMessage()    {}
 func (*ServerConnected) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{7}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{7}
 }
 func (m *ServerConnected) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ServerConnected.Unmarshal(m, b)
@@ -433,7 +433,7 @@ func (m *TLSyncSessionServerConnected) Reset()         { *m = TLSyncSessionServe
 func (m *TLSyncSessionServerConnected) String() string { return proto.CompactTextString(m) }
 func (*TLSyncSessionServerConnected) ProtoMessage()    {}
 func (*TLSyncSessionServerConnected) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{8}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{8}
 }
 func (m *TLSyncSessionServerConnected) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncSessionServerConnected.Unmarshal(m, b)
@@ -479,7 +479,7 @@ func (m *PushData_Data) Reset()         { *m = PushData_Data{} }
 func (m *PushData_Data) String() string { return proto.CompactTextString(m) }
 func (*PushData_Data) ProtoMessage()    {}
 func (*PushData_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{9}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{9}
 }
 func (m *PushData_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PushData_Data.Unmarshal(m, b)
@@ -539,7 +539,7 @@ func (m *PushData) Reset()         { *m = PushData{} }
 func (m *PushData) String() string { return proto.CompactTextString(m) }
 func (*PushData) ProtoMessage()    {}
 func (*PushData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{10}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{10}
 }
 func (m *PushData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PushData.Unmarshal(m, b)
@@ -585,7 +585,7 @@ func (m *TLSyncPushUpdatesData) Reset()         { *m = TLSyncPushUpdatesData{} }
 func (m *TLSyncPushUpdatesData) String() string { return proto.CompactTextString(m) }
 func (*TLSyncPushUpdatesData) ProtoMessage()    {}
 func (*TLSyncPushUpdatesData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{11}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{11}
 }
 func (m *TLSyncPushUpdatesData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncPushUpdatesData.Unmarshal(m, b)
@@ -624,7 +624,7 @@ func (m *TLSyncPushRpcResultData) Reset()         { *m = TLSyncPushRpcResultData
 func (m *TLSyncPushRpcResultData) String() string { return proto.CompactTextString(m) }
 func (*TLSyncPushRpcResultData) ProtoMessage()    {}
 func (*TLSyncPushRpcResultData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{12}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{12}
 }
 func (m *TLSyncPushRpcResultData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncPushRpcResultData.Unmarshal(m, b)
@@ -666,7 +666,7 @@ func (m *Int32_Data) Reset()         { *m = Int32_Data{} }
 func (m *Int32_Data) String() string { return proto.CompactTextString(m) }
 func (*Int32_Data) ProtoMessage()    {}
 func (*Int32_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{13}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{13}
 }
 func (m *Int32_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Int32_Data.Unmarshal(m, b)
@@ -705,7 +705,7 @@ func (m *Int32) Reset()         { *m = Int32{} }
 func (m *Int32) String() string { return proto.CompactTextString(m) }
 func (*Int32) ProtoMessage()    {}
 func (*Int32) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{14}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{14}
 }
 func (m *Int32) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Int32.Unmarshal(m, b)
@@ -751,7 +751,7 @@ func (m *TLInt32) Reset()         { *m = TLInt32{} }
 func (m *TLInt32) String() string { return proto.CompactTextString(m) }
 func (*TLInt32) ProtoMessage()    {}
 func (*TLInt32) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{15}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{15}
 }
 func (m *TLInt32) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLInt32.Unmarshal(m, b)
@@ -793,7 +793,7 @@ func (m *Int64_Data) Reset()         { *m = Int64_Data{} }
 func (m *Int64_Data) String() string { return proto.CompactTextString(m) }
 func (*Int64_Data) ProtoMessage()    {}
 func (*Int64_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{16}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{16}
 }
 func (m *Int64_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Int64_Data.Unmarshal(m, b)
@@ -832,7 +832,7 @@ func (m *Int64) Reset()         { *m = Int64{} }
 func (m *Int64) String() string { return proto.CompactTextString(m) }
 func (*Int64) ProtoMessage()    {}
 func (*Int64) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{17}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{17}
 }
 func (m *Int64) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Int64.Unmarshal(m, b)
@@ -878,7 +878,7 @@ func (m *TLLong) Reset()         { *m = TLLong{} }
 func (m *TLLong) String() string { return proto.CompactTextString(m) }
 func (*TLLong) ProtoMessage()    {}
 func (*TLLong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{18}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{18}
 }
 func (m *TLLong) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLLong.Unmarshal(m, b)
@@ -913,6 +913,9 @@ type AuthKeyInfo_Data struct {
 	AuthKeyId            int64       `protobuf:"varint,1,opt,name=auth_key_id,json=authKeyId,proto3" json:"auth_key_id,omitempty"`
 	AuthKey              []byte      `protobuf:"bytes,2,opt,name=auth_key,json=authKey,proto3" json:"auth_key,omitempty"`
 	FutureSalt           *FutureSalt `protobuf:"bytes,3,opt,name=future_salt,json=futureSalt,proto3" json:"future_salt,omitempty"`
+	AuthKeyType          int32       `protobuf:"varint,4,opt,name=auth_key_type,json=authKeyType,proto3" json:"auth_key_type,omitempty"`
+	PermAuthKeyId        int64       `protobuf:"varint,5,opt,name=perm_auth_key_id,json=permAuthKeyId,proto3" json:"perm_auth_key_id,omitempty"`
+	ExpiresAt            int32       `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -922,7 +925,7 @@ func (m *AuthKeyInfo_Data) Reset()         { *m = AuthKeyInfo_Data{} }
 func (m *AuthKeyInfo_Data) String() string { return proto.CompactTextString(m) }
 func (*AuthKeyInfo_Data) ProtoMessage()    {}
 func (*AuthKeyInfo_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{19}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{19}
 }
 func (m *AuthKeyInfo_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthKeyInfo_Data.Unmarshal(m, b)
@@ -963,6 +966,27 @@ func (m *AuthKeyInfo_Data) GetFutureSalt() *FutureSalt {
 	return nil
 }
 
+func (m *AuthKeyInfo_Data) GetAuthKeyType() int32 {
+	if m != nil {
+		return m.AuthKeyType
+	}
+	return 0
+}
+
+func (m *AuthKeyInfo_Data) GetPermAuthKeyId() int64 {
+	if m != nil {
+		return m.PermAuthKeyId
+	}
+	return 0
+}
+
+func (m *AuthKeyInfo_Data) GetExpiresAt() int32 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
 type AuthKeyInfo struct {
 	Constructor          TLConstructor     `protobuf:"varint,1,opt,name=constructor,proto3,enum=mtproto.TLConstructor" json:"constructor,omitempty"`
 	Data2                *AuthKeyInfo_Data `protobuf:"bytes,2,opt,name=data2,proto3" json:"data2,omitempty"`
@@ -975,7 +999,7 @@ func (m *AuthKeyInfo) Reset()         { *m = AuthKeyInfo{} }
 func (m *AuthKeyInfo) String() string { return proto.CompactTextString(m) }
 func (*AuthKeyInfo) ProtoMessage()    {}
 func (*AuthKeyInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{20}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{20}
 }
 func (m *AuthKeyInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AuthKeyInfo.Unmarshal(m, b)
@@ -1009,7 +1033,7 @@ func (m *AuthKeyInfo) GetData2() *AuthKeyInfo_Data {
 	return nil
 }
 
-// authKeyInfo flags:# auth_key_id:long auth_key:bytes future_salt:flags.0?FutureSalt= AuthKeyInfo;
+// authKeyInfo flags:# auth_key_id:long auth_key:bytes future_salt:flags.0?FutureSalt auth_key_type:flags.1?int perm_auth_key_id:flags.1?long expires_at:flags.1?int = AuthKeyInfo;
 type TLAuthKeyInfo struct {
 	Data2                *AuthKeyInfo_Data `protobuf:"bytes,2,opt,name=data2,proto3" json:"data2,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
@@ -1021,7 +1045,7 @@ func (m *TLAuthKeyInfo) Reset()         { *m = TLAuthKeyInfo{} }
 func (m *TLAuthKeyInfo) String() string { return proto.CompactTextString(m) }
 func (*TLAuthKeyInfo) ProtoMessage()    {}
 func (*TLAuthKeyInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{21}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{21}
 }
 func (m *TLAuthKeyInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLAuthKeyInfo.Unmarshal(m, b)
@@ -1072,7 +1096,7 @@ func (m *ClientSession_Data) Reset()         { *m = ClientSession_Data{} }
 func (m *ClientSession_Data) String() string { return proto.CompactTextString(m) }
 func (*ClientSession_Data) ProtoMessage()    {}
 func (*ClientSession_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{22}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{22}
 }
 func (m *ClientSession_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ClientSession_Data.Unmarshal(m, b)
@@ -1174,7 +1198,7 @@ func (m *ClientSession) Reset()         { *m = ClientSession{} }
 func (m *ClientSession) String() string { return proto.CompactTextString(m) }
 func (*ClientSession) ProtoMessage()    {}
 func (*ClientSession) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{23}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{23}
 }
 func (m *ClientSession) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ClientSession.Unmarshal(m, b)
@@ -1220,7 +1244,7 @@ func (m *TLClientSessionInfo) Reset()         { *m = TLClientSessionInfo{} }
 func (m *TLClientSessionInfo) String() string { return proto.CompactTextString(m) }
 func (*TLClientSessionInfo) ProtoMessage()    {}
 func (*TLClientSessionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{24}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{24}
 }
 func (m *TLClientSessionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLClientSessionInfo.Unmarshal(m, b)
@@ -1260,7 +1284,7 @@ func (m *TLSessionSetClientSessionInfo) Reset()         { *m = TLSessionSetClien
 func (m *TLSessionSetClientSessionInfo) String() string { return proto.CompactTextString(m) }
 func (*TLSessionSetClientSessionInfo) ProtoMessage()    {}
 func (*TLSessionSetClientSessionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{25}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{25}
 }
 func (m *TLSessionSetClientSessionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionSetClientSessionInfo.Unmarshal(m, b)
@@ -1300,7 +1324,7 @@ func (m *TLSessionGetAuthorizations) Reset()         { *m = TLSessionGetAuthoriz
 func (m *TLSessionGetAuthorizations) String() string { return proto.CompactTextString(m) }
 func (*TLSessionGetAuthorizations) ProtoMessage()    {}
 func (*TLSessionGetAuthorizations) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{26}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{26}
 }
 func (m *TLSessionGetAuthorizations) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionGetAuthorizations.Unmarshal(m, b)
@@ -1341,7 +1365,7 @@ func (m *TLSessionResetAuthorization) Reset()         { *m = TLSessionResetAutho
 func (m *TLSessionResetAuthorization) String() string { return proto.CompactTextString(m) }
 func (*TLSessionResetAuthorization) ProtoMessage()    {}
 func (*TLSessionResetAuthorization) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{27}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{27}
 }
 func (m *TLSessionResetAuthorization) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionResetAuthorization.Unmarshal(m, b)
@@ -1388,7 +1412,7 @@ func (m *TLSessionGetLayer) Reset()         { *m = TLSessionGetLayer{} }
 func (m *TLSessionGetLayer) String() string { return proto.CompactTextString(m) }
 func (*TLSessionGetLayer) ProtoMessage()    {}
 func (*TLSessionGetLayer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{28}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{28}
 }
 func (m *TLSessionGetLayer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionGetLayer.Unmarshal(m, b)
@@ -1428,7 +1452,7 @@ func (m *TLSessionGetUserId) Reset()         { *m = TLSessionGetUserId{} }
 func (m *TLSessionGetUserId) String() string { return proto.CompactTextString(m) }
 func (*TLSessionGetUserId) ProtoMessage()    {}
 func (*TLSessionGetUserId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{29}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{29}
 }
 func (m *TLSessionGetUserId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionGetUserId.Unmarshal(m, b)
@@ -1468,7 +1492,7 @@ func (m *TLSessionQueryAuthKey) Reset()         { *m = TLSessionQueryAuthKey{} }
 func (m *TLSessionQueryAuthKey) String() string { return proto.CompactTextString(m) }
 func (*TLSessionQueryAuthKey) ProtoMessage()    {}
 func (*TLSessionQueryAuthKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{30}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{30}
 }
 func (m *TLSessionQueryAuthKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionQueryAuthKey.Unmarshal(m, b)
@@ -1508,7 +1532,7 @@ func (m *TLSessionSetAuthKey) Reset()         { *m = TLSessionSetAuthKey{} }
 func (m *TLSessionSetAuthKey) String() string { return proto.CompactTextString(m) }
 func (*TLSessionSetAuthKey) ProtoMessage()    {}
 func (*TLSessionSetAuthKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{31}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{31}
 }
 func (m *TLSessionSetAuthKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionSetAuthKey.Unmarshal(m, b)
@@ -1549,7 +1573,7 @@ func (m *TLSessionBindAuthKeyUser) Reset()         { *m = TLSessionBindAuthKeyUs
 func (m *TLSessionBindAuthKeyUser) String() string { return proto.CompactTextString(m) }
 func (*TLSessionBindAuthKeyUser) ProtoMessage()    {}
 func (*TLSessionBindAuthKeyUser) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{32}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{32}
 }
 func (m *TLSessionBindAuthKeyUser) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionBindAuthKeyUser.Unmarshal(m, b)
@@ -1597,7 +1621,7 @@ func (m *TLSessionUnbindAuthKeyUser) Reset()         { *m = TLSessionUnbindAuthK
 func (m *TLSessionUnbindAuthKeyUser) String() string { return proto.CompactTextString(m) }
 func (*TLSessionUnbindAuthKeyUser) ProtoMessage()    {}
 func (*TLSessionUnbindAuthKeyUser) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{33}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{33}
 }
 func (m *TLSessionUnbindAuthKeyUser) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSessionUnbindAuthKeyUser.Unmarshal(m, b)
@@ -1631,6 +1655,142 @@ func (m *TLSessionUnbindAuthKeyUser) GetUserId() int32 {
 	return 0
 }
 
+// /////////////////////////////////////////////////////////////////////////////
+// session.bindTempAuthKey temp_auth_key_id:long temp_session_id:long msg_id:long perm_auth_key_id:long nonce:long expires_at:int encrypted_message:bytes = Bool;
+type TLSessionBindTempAuthKey struct {
+	TempAuthKeyId        int64    `protobuf:"varint,1,opt,name=temp_auth_key_id,json=tempAuthKeyId,proto3" json:"temp_auth_key_id,omitempty"`
+	TempSessionId        int64    `protobuf:"varint,2,opt,name=temp_session_id,json=tempSessionId,proto3" json:"temp_session_id,omitempty"`
+	MsgId                int64    `protobuf:"varint,3,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	PermAuthKeyId        int64    `protobuf:"varint,4,opt,name=perm_auth_key_id,json=permAuthKeyId,proto3" json:"perm_auth_key_id,omitempty"`
+	Nonce                int64    `protobuf:"varint,5,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	ExpiresAt            int32    `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	EncryptedMessage     []byte   `protobuf:"bytes,7,opt,name=encrypted_message,json=encryptedMessage,proto3" json:"encrypted_message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TLSessionBindTempAuthKey) Reset()         { *m = TLSessionBindTempAuthKey{} }
+func (m *TLSessionBindTempAuthKey) String() string { return proto.CompactTextString(m) }
+func (*TLSessionBindTempAuthKey) ProtoMessage()    {}
+func (*TLSessionBindTempAuthKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{34}
+}
+func (m *TLSessionBindTempAuthKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TLSessionBindTempAuthKey.Unmarshal(m, b)
+}
+func (m *TLSessionBindTempAuthKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TLSessionBindTempAuthKey.Marshal(b, m, deterministic)
+}
+func (dst *TLSessionBindTempAuthKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TLSessionBindTempAuthKey.Merge(dst, src)
+}
+func (m *TLSessionBindTempAuthKey) XXX_Size() int {
+	return xxx_messageInfo_TLSessionBindTempAuthKey.Size(m)
+}
+func (m *TLSessionBindTempAuthKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_TLSessionBindTempAuthKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TLSessionBindTempAuthKey proto.InternalMessageInfo
+
+func (m *TLSessionBindTempAuthKey) GetTempAuthKeyId() int64 {
+	if m != nil {
+		return m.TempAuthKeyId
+	}
+	return 0
+}
+
+func (m *TLSessionBindTempAuthKey) GetTempSessionId() int64 {
+	if m != nil {
+		return m.TempSessionId
+	}
+	return 0
+}
+
+func (m *TLSessionBindTempAuthKey) GetMsgId() int64 {
+	if m != nil {
+		return m.MsgId
+	}
+	return 0
+}
+
+func (m *TLSessionBindTempAuthKey) GetPermAuthKeyId() int64 {
+	if m != nil {
+		return m.PermAuthKeyId
+	}
+	return 0
+}
+
+func (m *TLSessionBindTempAuthKey) GetNonce() int64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *TLSessionBindTempAuthKey) GetExpiresAt() int32 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *TLSessionBindTempAuthKey) GetEncryptedMessage() []byte {
+	if m != nil {
+		return m.EncryptedMessage
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// session.dropTempAuthKeys perm_auth_key_id:long except_auth_keys:Vector<long> = Bool;
+type TLSessionDropTempAuthKeys struct {
+	PermAuthKeyId        int64    `protobuf:"varint,1,opt,name=perm_auth_key_id,json=permAuthKeyId,proto3" json:"perm_auth_key_id,omitempty"`
+	ExceptAuthKeys       []int64  `protobuf:"varint,2,rep,packed,name=except_auth_keys,json=exceptAuthKeys,proto3" json:"except_auth_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TLSessionDropTempAuthKeys) Reset()         { *m = TLSessionDropTempAuthKeys{} }
+func (m *TLSessionDropTempAuthKeys) String() string { return proto.CompactTextString(m) }
+func (*TLSessionDropTempAuthKeys) ProtoMessage()    {}
+func (*TLSessionDropTempAuthKeys) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{35}
+}
+func (m *TLSessionDropTempAuthKeys) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TLSessionDropTempAuthKeys.Unmarshal(m, b)
+}
+func (m *TLSessionDropTempAuthKeys) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TLSessionDropTempAuthKeys.Marshal(b, m, deterministic)
+}
+func (dst *TLSessionDropTempAuthKeys) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TLSessionDropTempAuthKeys.Merge(dst, src)
+}
+func (m *TLSessionDropTempAuthKeys) XXX_Size() int {
+	return xxx_messageInfo_TLSessionDropTempAuthKeys.Size(m)
+}
+func (m *TLSessionDropTempAuthKeys) XXX_DiscardUnknown() {
+	xxx_messageInfo_TLSessionDropTempAuthKeys.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TLSessionDropTempAuthKeys proto.InternalMessageInfo
+
+func (m *TLSessionDropTempAuthKeys) GetPermAuthKeyId() int64 {
+	if m != nil {
+		return m.PermAuthKeyId
+	}
+	return 0
+}
+
+func (m *TLSessionDropTempAuthKeys) GetExceptAuthKeys() []int64 {
+	if m != nil {
+		return m.ExceptAuthKeys
+	}
+	return nil
+}
+
 // /////////////////////////////////////////////////////////////////////////////
 // sync.syncUpdates flags:# user_id:int auth_key_id:long server_id:flags.0?int updates:Updates = Bool;
 type TLSyncSyncUpdates struct {
@@ -1647,7 +1807,7 @@ func (m *TLSyncSyncUpdates) Reset()         { *m = TLSyncSyncUpdates{} }
 func (m *TLSyncSyncUpdates) String() string { return proto.CompactTextString(m) }
 func (*TLSyncSyncUpdates) ProtoMessage()    {}
 func (*TLSyncSyncUpdates) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{34}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{36}
 }
 func (m *TLSyncSyncUpdates) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncSyncUpdates.Unmarshal(m, b)
@@ -1709,7 +1869,7 @@ func (m *TLSyncPushUpdates) Reset()         { *m = TLSyncPushUpdates{} }
 func (m *TLSyncPushUpdates) String() string { return proto.CompactTextString(m) }
 func (*TLSyncPushUpdates) ProtoMessage()    {}
 func (*TLSyncPushUpdates) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{35}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{37}
 }
 func (m *TLSyncPushUpdates) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncPushUpdates.Unmarshal(m, b)
@@ -1760,7 +1920,7 @@ func (m *TLSyncSyncChannelUpdates) Reset()         { *m = TLSyncSyncChannelUpdat
 func (m *TLSyncSyncChannelUpdates) String() string { return proto.CompactTextString(m) }
 func (*TLSyncSyncChannelUpdates) ProtoMessage()    {}
 func (*TLSyncSyncChannelUpdates) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{36}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{38}
 }
 func (m *TLSyncSyncChannelUpdates) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncSyncChannelUpdates.Unmarshal(m, b)
@@ -1830,7 +1990,7 @@ func (m *TLSyncPushChannelUpdates) Reset()         { *m = TLSyncPushChannelUpdat
 func (m *TLSyncPushChannelUpdates) String() string { return proto.CompactTextString(m) }
 func (*TLSyncPushChannelUpdates) ProtoMessage()    {}
 func (*TLSyncPushChannelUpdates) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{37}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{39}
 }
 func (m *TLSyncPushChannelUpdates) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncPushChannelUpdates.Unmarshal(m, b)
@@ -1887,7 +2047,7 @@ func (m *TLSyncPushRpcResult) Reset()         { *m = TLSyncPushRpcResult{} }
 func (m *TLSyncPushRpcResult) String() string { return proto.CompactTextString(m) }
 func (*TLSyncPushRpcResult) ProtoMessage()    {}
 func (*TLSyncPushRpcResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{38}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{40}
 }
 func (m *TLSyncPushRpcResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncPushRpcResult.Unmarshal(m, b)
@@ -1949,7 +2109,7 @@ func (m *TLSyncGetState) Reset()         { *m = TLSyncGetState{} }
 func (m *TLSyncGetState) String() string { return proto.CompactTextString(m) }
 func (*TLSyncGetState) ProtoMessage()    {}
 func (*TLSyncGetState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{39}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{41}
 }
 func (m *TLSyncGetState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncGetState.Unmarshal(m, b)
@@ -2001,7 +2161,7 @@ func (m *TLSyncGetDifference) Reset()         { *m = TLSyncGetDifference{} }
 func (m *TLSyncGetDifference) String() string { return proto.CompactTextString(m) }
 func (*TLSyncGetDifference) ProtoMessage()    {}
 func (*TLSyncGetDifference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{40}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{42}
 }
 func (m *TLSyncGetDifference) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncGetDifference.Unmarshal(m, b)
@@ -2082,7 +2242,7 @@ func (m *TLSyncGetChannelDifference) Reset()         { *m = TLSyncGetChannelDiff
 func (m *TLSyncGetChannelDifference) String() string { return proto.CompactTextString(m) }
 func (*TLSyncGetChannelDifference) ProtoMessage()    {}
 func (*TLSyncGetChannelDifference) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_tl_1382bd66a7abe761, []int{41}
+	return fileDescriptor_service_tl_f8c2d9e6cff3680a, []int{43}
 }
 func (m *TLSyncGetChannelDifference) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TLSyncGetChannelDifference.Unmarshal(m, b)
@@ -2186,6 +2346,8 @@ func init() {
 	proto.RegisterType((*TLSessionSetAuthKey)(nil), "mtproto.TL_session_setAuthKey")
 	proto.RegisterType((*TLSessionBindAuthKeyUser)(nil), "mtproto.TL_session_bindAuthKeyUser")
 	proto.RegisterType((*TLSessionUnbindAuthKeyUser)(nil), "mtproto.TL_session_unbindAuthKeyUser")
+	proto.RegisterType((*TLSessionBindTempAuthKey)(nil), "mtproto.TL_session_bindTempAuthKey")
+	proto.RegisterType((*TLSessionDropTempAuthKeys)(nil), "mtproto.TL_session_dropTempAuthKeys")
 	proto.RegisterType((*TLSyncSyncUpdates)(nil), "mtproto.TL_sync_syncUpdates")
 	proto.RegisterType((*TLSyncPushUpdates)(nil), "mtproto.TL_sync_pushUpdates")
 	proto.RegisterType((*TLSyncSyncChannelUpdates)(nil), "mtproto.TL_sync_syncChannelUpdates")
@@ -2226,6 +2388,10 @@ type RPCSessionClient interface {
 	SessionBindAuthKeyUser(ctx context.Context, in *TLSessionBindAuthKeyUser, opts ...grpc.CallOption) (*Bool, error)
 	// session.unbindAuthKeyUser auth_key_id:long user_id:int = Bool;
 	SessionUnbindAuthKeyUser(ctx context.Context, in *TLSessionUnbindAuthKeyUser, opts ...grpc.CallOption) (*Bool, error)
+	// session.bindTempAuthKey temp_auth_key_id:long temp_session_id:long msg_id:long perm_auth_key_id:long nonce:long expires_at:int encrypted_message:bytes = Bool;
+	SessionBindTempAuthKey(ctx context.Context, in *TLSessionBindTempAuthKey, opts ...grpc.CallOption) (*Bool, error)
+	// session.dropTempAuthKeys perm_auth_key_id:long except_auth_keys:Vector<long> = Bool;
+	SessionDropTempAuthKeys(ctx context.Context, in *TLSessionDropTempAuthKeys, opts ...grpc.CallOption) (*Bool, error)
 }
 
 type rPCSessionClient struct {
@@ -2317,6 +2483,24 @@ func (c *rPCSessionClient) SessionUnbindAuthKeyUser(ctx context.Context, in *TLS
 	return out, nil
 }
 
+func (c *rPCSessionClient) SessionBindTempAuthKey(ctx context.Context, in *TLSessionBindTempAuthKey, opts ...grpc.CallOption) (*Bool, error) {
+	out := new(Bool)
+	err := c.cc.Invoke(ctx, "/mtproto.RPCSession/session_bindTempAuthKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCSessionClient) SessionDropTempAuthKeys(ctx context.Context, in *TLSessionDropTempAuthKeys, opts ...grpc.CallOption) (*Bool, error) {
+	out := new(Bool)
+	err := c.cc.Invoke(ctx, "/mtproto.RPCSession/session_dropTempAuthKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RPCSessionServer is the server API for RPCSession service.
 type RPCSessionServer interface {
 	// session.setClientSessionInfo session:ClientSession = Bool;
@@ -2337,6 +2521,10 @@ type RPCSessionServer interface {
 	SessionBindAuthKeyUser(context.Context, *TLSessionBindAuthKeyUser) (*Bool, error)
 	// session.unbindAuthKeyUser auth_key_id:long user_id:int = Bool;
 	SessionUnbindAuthKeyUser(context.Context, *TLSessionUnbindAuthKeyUser) (*Bool, error)
+	// session.bindTempAuthKey temp_auth_key_id:long temp_session_id:long msg_id:long perm_auth_key_id:long nonce:long expires_at:int encrypted_message:bytes = Bool;
+	SessionBindTempAuthKey(context.Context, *TLSessionBindTempAuthKey) (*Bool, error)
+	// session.dropTempAuthKeys perm_auth_key_id:long except_auth_keys:Vector<long> = Bool;
+	SessionDropTempAuthKeys(context.Context, *TLSessionDropTempAuthKeys) (*Bool, error)
 }
 
 func RegisterRPCSessionServer(s *grpc.Server, srv RPCSessionServer) {
@@ -2505,6 +2693,42 @@ func _RPCSession_SessionUnbindAuthKeyUser_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RPCSession_SessionBindTempAuthKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TLSessionBindTempAuthKey)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCSessionServer).SessionBindTempAuthKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mtproto.RPCSession/SessionBindTempAuthKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCSessionServer).SessionBindTempAuthKey(ctx, req.(*TLSessionBindTempAuthKey))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCSession_SessionDropTempAuthKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TLSessionDropTempAuthKeys)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCSessionServer).SessionDropTempAuthKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mtproto.RPCSession/SessionDropTempAuthKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCSessionServer).SessionDropTempAuthKeys(ctx, req.(*TLSessionDropTempAuthKeys))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RPCSession_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "mtproto.RPCSession",
 	HandlerType: (*RPCSessionServer)(nil),
@@ -2545,6 +2769,14 @@ var _RPCSession_serviceDesc = grpc.ServiceDesc{
 			MethodName: "session_unbindAuthKeyUser",
 			Handler:    _RPCSession_SessionUnbindAuthKeyUser_Handler,
 		},
+		{
+			MethodName: "session_bindTempAuthKey",
+			Handler:    _RPCSession_SessionBindTempAuthKey_Handler,
+		},
+		{
+			MethodName: "session_dropTempAuthKeys",
+			Handler:    _RPCSession_SessionDropTempAuthKeys_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.tl.proto",
@@ -2861,112 +3093,124 @@ var _RPCSync_serviceDesc = grpc.ServiceDesc{
 	Metadata: "service.tl.proto",
 }
 
-func init() { proto.RegisterFile("service.tl.proto", fileDescriptor_service_tl_1382bd66a7abe761) }
-
-var fileDescriptor_service_tl_1382bd66a7abe761 = []byte{
-	// 1650 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xeb, 0x6e, 0xdb, 0xc6,
-	0x12, 0x36, 0x25, 0xcb, 0x92, 0xc6, 0xd7, 0x30, 0x72, 0x62, 0xcb, 0x97, 0xf8, 0x30, 0x27, 0x81,
-	0x13, 0x1c, 0x38, 0xe7, 0xd8, 0x8e, 0x4f, 0x0b, 0x14, 0x28, 0x7c, 0x69, 0x1a, 0x21, 0x72, 0x6b,
-	0xd0, 0x72, 0x8b, 0xf6, 0x0f, 0xb1, 0x21, 0xd7, 0x12, 0x11, 0x8a, 0xa4, 0xb9, 0x2b, 0xa3, 0x2a,
-	0xfa, 0x23, 0x40, 0xff, 0xf5, 0x05, 0xfa, 0xaf, 0xcf, 0xd0, 0x17, 0x28, 0xd0, 0x47, 0xe9, 0x13,
-	0xf4, 0x0d, 0x5a, 0x14, 0x7b, 0xa1, 0x78, 0xb7, 0x62, 0x3b, 0xf9, 0x13, 0x50, 0x73, 0xf9, 0xe6,
-	0x9b, 0xd9, 0xd9, 0x9d, 0x89, 0x61, 0x81, 0xe0, 0xe0, 0xd2, 0x36, 0xf1, 0x16, 0x75, 0xb6, 0xfc,
-	0xc0, 0xa3, 0x9e, 0x5a, 0xed, 0x53, 0xfe, 0xd1, 0x5c, 0x24, 0x66, 0x0f, 0xf7, 0x11, 0xd3, 0x98,
-	0x81, 0xb9, 0xb3, 0x2d, 0xf4, 0xcd, 0x66, 0x4c, 0xec, 0x05, 0xd8, 0xa0, 0x43, 0x1f, 0x13, 0xa9,
-	0x5b, 0x8e, 0x74, 0x34, 0x40, 0x2e, 0xf1, 0xbd, 0x80, 0x4a, 0x55, 0x23, 0x52, 0x91, 0xa1, 0x6b,
-	0x0a, 0xa9, 0xf6, 0x87, 0x02, 0xf3, 0xfb, 0x94, 0x22, 0xb3, 0x77, 0x84, 0x28, 0x32, 0xd8, 0x3f,
-	0xea, 0x3a, 0x4c, 0xa3, 0x01, 0xed, 0x19, 0x6f, 0xf0, 0xd0, 0xb0, 0xad, 0x25, 0x65, 0x43, 0xd9,
-	0x2c, 0xeb, 0x75, 0x26, 0x7a, 0x85, 0x87, 0x2d, 0x4b, 0x5d, 0x03, 0x20, 0x98, 0x10, 0xdb, 0x73,
-	0x99, 0xba, 0x24, 0xd4, 0x52, 0x22, 0xd4, 0x7d, 0x4c, 0x08, 0xea, 0x62, 0xa6, 0x2e, 0x0b, 0xb5,
-	0x94, 0xb4, 0x2c, 0xb5, 0x01, 0x15, 0x07, 0x0d, 0x71, 0xb0, 0x34, 0xb9, 0xa1, 0x6c, 0x56, 0x74,
-	0xf1, 0x43, 0xbd, 0x0f, 0xd5, 0x01, 0xc1, 0x01, 0xf3, 0xa8, 0x70, 0xf9, 0x14, 0xfb, 0xd9, 0xb2,
-	0xd4, 0x07, 0x30, 0x8d, 0x4c, 0x13, 0x13, 0x62, 0xf4, 0x10, 0xe9, 0x2d, 0x4d, 0x71, 0x38, 0x10,
-	0xa2, 0x97, 0x88, 0xf4, 0xb8, 0x01, 0x4f, 0xc0, 0xb0, 0x10, 0x45, 0x4b, 0xd5, 0x0d, 0x65, 0x73,
-	0x46, 0x07, 0x34, 0xca, 0x49, 0xbb, 0x04, 0x88, 0x32, 0x54, 0x3f, 0x82, 0x69, 0xd3, 0x73, 0x09,
-	0x0d, 0x06, 0x26, 0xf5, 0x02, 0x9e, 0xdc, 0xdc, 0xf6, 0xbd, 0x2d, 0x59, 0xf3, 0xad, 0x4e, 0xfb,
-	0x30, 0xd2, 0xea, 0x71, 0x53, 0x75, 0x0b, 0x2a, 0x2c, 0xc2, 0x36, 0xcf, 0x78, 0x7a, 0x7b, 0x69,
-	0xe4, 0x93, 0xaa, 0x9f, 0x2e, 0xcc, 0xb4, 0x4f, 0x61, 0xb6, 0xd3, 0x36, 0x22, 0x22, 0xd7, 0x06,
-	0xf8, 0x04, 0x1a, 0x87, 0x9e, 0xeb, 0x62, 0x93, 0x76, 0xbc, 0x53, 0x1c, 0x5c, 0xe2, 0x40, 0x9c,
-	0xcf, 0xbf, 0x61, 0x8e, 0x9d, 0xa0, 0x41, 0x84, 0x4c, 0x1e, 0x51, 0x45, 0x9f, 0x61, 0x52, 0x61,
-	0xd8, 0xb2, 0xb4, 0xb7, 0x0a, 0xcc, 0xa7, 0xdc, 0x6f, 0x91, 0xfc, 0x4e, 0x92, 0xfb, 0xda, 0xc8,
-	0x27, 0x8f, 0x61, 0x98, 0xc0, 0x19, 0xac, 0x77, 0xda, 0x06, 0xe7, 0x6a, 0x46, 0x66, 0xbc, 0x4f,
-	0x24, 0xa1, 0x1b, 0xc1, 0x9a, 0xd0, 0x10, 0x52, 0x69, 0x84, 0x2d, 0x51, 0x97, 0xa7, 0x70, 0x27,
-	0xec, 0xcb, 0x74, 0x69, 0xe6, 0x49, 0x3c, 0xac, 0x68, 0x2b, 0x69, 0xe3, 0xa2, 0x3e, 0xe6, 0xe1,
-	0xeb, 0x3a, 0x08, 0xd1, 0x17, 0xa8, 0x8f, 0x79, 0xf9, 0x52, 0x51, 0x3e, 0x44, 0xf9, 0xf2, 0x12,
-	0xc9, 0x29, 0x5f, 0x82, 0x7e, 0x44, 0xe8, 0x46, 0xb0, 0xbf, 0x28, 0x30, 0x7b, 0x32, 0x20, 0xd7,
-	0xb8, 0xf0, 0x4f, 0xa1, 0x3a, 0xf0, 0x2d, 0x44, 0x31, 0x91, 0x81, 0x16, 0x46, 0x81, 0xce, 0x84,
-	0x5c, 0x0f, 0x0d, 0xd4, 0x27, 0x70, 0xc7, 0x74, 0x6c, 0xec, 0x52, 0x23, 0xc0, 0x17, 0x46, 0x9f,
-	0x74, 0xa3, 0x47, 0x60, 0x4e, 0x28, 0x74, 0x7c, 0x71, 0x4c, 0xba, 0x2d, 0x4b, 0xbd, 0x07, 0x53,
-	0x01, 0x26, 0x03, 0x87, 0xf2, 0xa7, 0x60, 0x46, 0x97, 0xbf, 0xb4, 0x00, 0x6a, 0x21, 0xbf, 0x5b,
-	0x94, 0xfc, 0x3f, 0xc9, 0xda, 0x44, 0x3e, 0x89, 0xdc, 0xc3, 0xa2, 0x7c, 0x0e, 0xf7, 0xc3, 0x5a,
-	0xfb, 0x03, 0xd2, 0x93, 0x69, 0x71, 0x0a, 0xd7, 0x03, 0x6a, 0xc1, 0x72, 0x1c, 0x48, 0xf7, 0x4d,
-	0x9d, 0x67, 0x75, 0x03, 0xa8, 0x26, 0x40, 0xcb, 0xa5, 0x3b, 0xdb, 0xe2, 0x90, 0x66, 0x40, 0xb9,
-	0x94, 0xdd, 0xac, 0x5c, 0x6a, 0x0e, 0x54, 0xb8, 0xee, 0x16, 0x05, 0x7a, 0x92, 0x24, 0x73, 0x77,
-	0xe4, 0x13, 0x05, 0x0d, 0x99, 0x3c, 0x87, 0x5a, 0xa7, 0x6d, 0xd8, 0x3c, 0xe0, 0x35, 0xdc, 0x44,
-	0x02, 0x7b, 0xbb, 0xa9, 0x04, 0xca, 0x51, 0x02, 0x7b, 0xbb, 0x1f, 0x28, 0x01, 0x19, 0x34, 0x64,
-	0xb2, 0x0b, 0xd5, 0x4e, 0xdb, 0x70, 0x3c, 0xb7, 0x7b, 0x1d, 0xaf, 0x1f, 0x15, 0x58, 0xd8, 0x97,
-	0xb7, 0xc0, 0x3d, 0xf7, 0xde, 0xed, 0xb2, 0x2c, 0x43, 0x2d, 0xd4, 0xf3, 0x10, 0x33, 0x7a, 0x55,
-	0x2a, 0xd5, 0x5d, 0x98, 0x3e, 0x1f, 0xd0, 0x41, 0x80, 0x0d, 0x82, 0x1c, 0xca, 0x6f, 0x45, 0x9c,
-	0xc0, 0x0b, 0xae, 0x3b, 0x45, 0x0e, 0xd5, 0xe1, 0x7c, 0xf4, 0xad, 0x7d, 0x07, 0xd3, 0x31, 0x12,
-	0xb7, 0xa8, 0xd7, 0xb3, 0x64, 0xe6, 0xcb, 0xd1, 0xfc, 0x49, 0xe5, 0x18, 0xe6, 0xbf, 0x0f, 0x73,
-	0x6c, 0x82, 0xc5, 0x82, 0x5f, 0x1b, 0xe2, 0xf7, 0x12, 0xa8, 0x87, 0xfc, 0xda, 0xcb, 0x87, 0xff,
-	0xdd, 0x8a, 0x38, 0x07, 0x25, 0xdb, 0x97, 0xaf, 0x72, 0xc9, 0xf6, 0xa3, 0xa5, 0xa1, 0x1c, 0x5f,
-	0x1a, 0x16, 0x61, 0x0a, 0xf9, 0x36, 0x03, 0x90, 0xbb, 0x04, 0xf2, 0xed, 0x96, 0xa5, 0xfe, 0x0b,
-	0x66, 0x2c, 0xcc, 0x76, 0x2a, 0xa3, 0xef, 0x59, 0xd8, 0xe1, 0x0b, 0x45, 0x5d, 0x9f, 0x16, 0xb2,
-	0x63, 0x26, 0x52, 0x1f, 0xb1, 0x11, 0x4a, 0x28, 0xee, 0x1b, 0x97, 0x38, 0x60, 0xb4, 0xf8, 0x62,
-	0x51, 0xd7, 0x67, 0x85, 0xf4, 0x2b, 0x21, 0xe4, 0xbb, 0x85, 0xef, 0x8f, 0x6c, 0xaa, 0x62, 0x4a,
-	0x20, 0xdf, 0x0f, 0x0d, 0x36, 0x61, 0x41, 0xe2, 0x38, 0xc8, 0xed, 0x1a, 0xa6, 0x67, 0xe1, 0xa5,
-	0x1a, 0xb7, 0x92, 0xf8, 0x6d, 0xe4, 0x76, 0x0f, 0x3d, 0x0b, 0xab, 0x2b, 0x50, 0xe7, 0x26, 0x3e,
-	0x32, 0xdf, 0x2c, 0xd5, 0xb9, 0x49, 0x8d, 0x09, 0x4e, 0x90, 0xf9, 0x66, 0xa4, 0xe4, 0xfe, 0x10,
-	0x29, 0x99, 0xa7, 0xf6, 0x03, 0xcc, 0x26, 0x2a, 0x78, 0x8b, 0x0e, 0xf8, 0x5f, 0xf2, 0xf8, 0x56,
-	0xa2, 0x71, 0x9b, 0x39, 0xa2, 0xe8, 0x3d, 0x6b, 0x74, 0xda, 0x86, 0x19, 0xd7, 0xf3, 0x4e, 0xb8,
-	0x01, 0xd4, 0x29, 0x3c, 0x60, 0x4f, 0xe3, 0x68, 0x44, 0xd3, 0xc3, 0x0c, 0xea, 0x7f, 0xa1, 0x2a,
-	0xf5, 0x3c, 0xad, 0xf8, 0x13, 0x99, 0x30, 0xd6, 0x43, 0x33, 0xed, 0xff, 0xb0, 0x1a, 0x03, 0xed,
-	0x62, 0xca, 0x3a, 0xd1, 0x0b, 0xec, 0xef, 0x11, 0xb5, 0x3d, 0x97, 0xc4, 0x17, 0x4b, 0x25, 0xbe,
-	0x58, 0x6a, 0x6d, 0x58, 0x8b, 0x39, 0x06, 0x98, 0xa4, 0x5c, 0x0b, 0x3d, 0x55, 0x15, 0x26, 0xf9,
-	0x2e, 0x2a, 0x36, 0x5f, 0xfe, 0xad, 0x3d, 0x87, 0xbb, 0x49, 0x1a, 0x6d, 0xde, 0xa1, 0x63, 0xfa,
-	0x5c, 0xdb, 0xe3, 0xd5, 0x8d, 0xb9, 0x9d, 0x89, 0x10, 0xe3, 0xfc, 0x3e, 0x16, 0xe3, 0x4a, 0xfa,
-	0x5d, 0x0c, 0x70, 0x30, 0x94, 0x37, 0x70, 0xac, 0xeb, 0x4b, 0x58, 0x4c, 0x9e, 0x42, 0xe8, 0xf8,
-	0x2c, 0xf6, 0x70, 0x89, 0xe2, 0x37, 0xf2, 0xae, 0xf7, 0xe8, 0x39, 0xd3, 0xce, 0xa0, 0x19, 0x43,
-	0x7a, 0x6d, 0xbb, 0x96, 0x34, 0x63, 0x49, 0x8c, 0xbd, 0xe2, 0xb1, 0xf2, 0x96, 0x12, 0x07, 0xf3,
-	0x75, 0xe2, 0x44, 0x07, 0xee, 0x7b, 0x03, 0xfe, 0x59, 0x11, 0x87, 0xc4, 0x17, 0xaa, 0xa1, 0x6b,
-	0xca, 0x21, 0x5f, 0xe8, 0x90, 0x8e, 0x54, 0x4e, 0x47, 0x5a, 0x81, 0x7a, 0xb4, 0x68, 0x8a, 0x27,
-	0xa8, 0x46, 0xc2, 0x0d, 0x33, 0xb6, 0x34, 0x55, 0xc6, 0x2c, 0x4d, 0xda, 0xb7, 0x11, 0xb1, 0xd8,
-	0xf6, 0x51, 0xdc, 0x81, 0xd7, 0x58, 0xc8, 0xb4, 0xdf, 0x14, 0x71, 0x4c, 0x61, 0xd6, 0x87, 0x3d,
-	0xe4, 0xba, 0xd8, 0x09, 0x63, 0xac, 0x01, 0x98, 0x42, 0x12, 0xe5, 0x5f, 0x97, 0x92, 0x64, 0x31,
-	0xcb, 0x57, 0xd5, 0x66, 0xf2, 0xca, 0xda, 0x54, 0x8a, 0x6b, 0x33, 0x35, 0x8e, 0xff, 0xdb, 0x18,
-	0x7f, 0x56, 0x9c, 0x2b, 0xf9, 0x2b, 0x57, 0xf0, 0x2f, 0x15, 0x95, 0xb0, 0x3c, 0x8e, 0xc2, 0x4f,
-	0x8a, 0xb8, 0x33, 0x99, 0xa5, 0x2e, 0x99, 0xa5, 0x92, 0xca, 0x32, 0x55, 0xa2, 0x52, 0xba, 0x44,
-	0xab, 0x00, 0x99, 0x1d, 0xb9, 0x16, 0x8c, 0xdb, 0x8e, 0x5f, 0xc1, 0x42, 0xc8, 0xa5, 0x8b, 0xe9,
-	0x29, 0x45, 0x14, 0xdf, 0xfc, 0x4a, 0xfc, 0x1a, 0xcb, 0xac, 0x8b, 0xe9, 0x91, 0x7d, 0x7e, 0x8e,
-	0x03, 0xec, 0x9a, 0x78, 0x2c, 0xf9, 0xc2, 0xc6, 0x58, 0x80, 0xb2, 0x4f, 0x89, 0xbc, 0x0e, 0xec,
-	0x53, 0x7d, 0x0c, 0xf3, 0x3e, 0x25, 0x06, 0xf5, 0x28, 0x72, 0x0c, 0xc7, 0xee, 0xdb, 0x54, 0x36,
-	0xc4, 0xac, 0x4f, 0x49, 0x87, 0x49, 0xdb, 0x4c, 0xc8, 0xde, 0x55, 0x56, 0x6f, 0xde, 0x12, 0x15,
-	0x9d, 0x7f, 0x33, 0xb4, 0x0b, 0x4a, 0xf8, 0xe4, 0xad, 0xe8, 0xec, 0x53, 0xfb, 0x5b, 0x11, 0xef,
-	0x83, 0xa4, 0x2c, 0xdb, 0xe1, 0x7d, 0x30, 0x6f, 0x40, 0xe5, 0xdc, 0x0b, 0x4c, 0xcc, 0xb9, 0xd7,
-	0x74, 0xf1, 0x43, 0x7d, 0x06, 0x55, 0xd9, 0x4e, 0xf2, 0x1e, 0x2f, 0xc6, 0x36, 0x46, 0x7f, 0x10,
-	0x12, 0xd0, 0x43, 0x2b, 0x75, 0x0f, 0xa6, 0xce, 0x6d, 0x87, 0xe2, 0x40, 0xf6, 0xf6, 0x7a, 0x34,
-	0xc2, 0x84, 0xc5, 0xb1, 0xf8, 0x5b, 0x08, 0x79, 0xc1, 0xad, 0x74, 0x69, 0x1d, 0x16, 0xae, 0x1a,
-	0x15, 0x8e, 0x6d, 0x3d, 0xbc, 0x5c, 0x35, 0xb9, 0xf5, 0xb0, 0x1f, 0xdb, 0x7f, 0x56, 0x00, 0xf4,
-	0x93, 0xc3, 0x70, 0x1b, 0xf8, 0x06, 0x56, 0xaf, 0x1c, 0xa9, 0x9b, 0xb1, 0xc5, 0xe0, 0xca, 0xe1,
-	0xdb, 0x9c, 0x1d, 0x59, 0x1e, 0x78, 0x9e, 0xa3, 0x4d, 0xa8, 0x18, 0x96, 0x8b, 0x07, 0xeb, 0xa3,
-	0x3c, 0xdc, 0x8c, 0x59, 0xf3, 0xc1, 0xc8, 0x0c, 0x99, 0xa6, 0x37, 0x70, 0xa9, 0x91, 0x34, 0xd0,
-	0x26, 0xd4, 0x33, 0x68, 0x5e, 0x31, 0x86, 0x1f, 0xe7, 0xc5, 0xc9, 0xda, 0x65, 0xd9, 0x1f, 0xc0,
-	0x42, 0x66, 0x1e, 0xaf, 0x16, 0x90, 0xe6, 0xda, 0xe6, 0x5c, 0xf2, 0xff, 0x32, 0xda, 0x84, 0x7a,
-	0x14, 0xfd, 0x49, 0x21, 0x1a, 0xce, 0x6b, 0x05, 0x20, 0x42, 0x9d, 0x83, 0x72, 0x02, 0x8d, 0xdc,
-	0x51, 0xbd, 0x91, 0x07, 0x14, 0xb7, 0x68, 0xe6, 0x4e, 0x60, 0x6d, 0x42, 0xfd, 0x0c, 0xd4, 0x9c,
-	0x09, 0xbe, 0x5e, 0x70, 0xd4, 0x21, 0x5a, 0xa6, 0x44, 0x5f, 0xc2, 0xfd, 0xa2, 0xf1, 0xfd, 0x30,
-	0x0f, 0x2b, 0x65, 0x94, 0x05, 0x3c, 0x8d, 0x3a, 0x26, 0x3b, 0xb8, 0x73, 0x3b, 0x26, 0x63, 0x96,
-	0x01, 0xdd, 0xfe, 0x6b, 0x12, 0xaa, 0xac, 0xe1, 0x87, 0xae, 0xa9, 0xee, 0xb3, 0x85, 0x3b, 0x35,
-	0xbf, 0x93, 0x87, 0x9a, 0xd2, 0x66, 0x39, 0x86, 0x10, 0xf1, 0x49, 0x9b, 0x85, 0x88, 0x69, 0xf3,
-	0xeb, 0x56, 0x30, 0x4f, 0x1f, 0xe6, 0x92, 0x49, 0x1a, 0x15, 0x03, 0xe6, 0x0c, 0xb8, 0x87, 0xb9,
-	0xd4, 0xc6, 0x01, 0xb2, 0x06, 0xc9, 0x8e, 0xab, 0xf5, 0x5c, 0xac, 0x91, 0x3e, 0x0b, 0x73, 0x04,
-	0xb3, 0xc9, 0x49, 0xb3, 0x9c, 0x41, 0x08, 0x55, 0xcd, 0x68, 0x55, 0x97, 0x73, 0xd3, 0xe0, 0x72,
-	0xde, 0x15, 0x6a, 0xde, 0x84, 0xc9, 0x83, 0x8a, 0xf4, 0xcd, 0x95, 0x0c, 0x5e, 0xa4, 0xd4, 0x26,
-	0xd4, 0x1e, 0x2c, 0x17, 0xcf, 0x80, 0x47, 0x79, 0xd8, 0x19, 0xb3, 0xa6, 0x96, 0x09, 0x91, 0xb1,
-	0xd1, 0x26, 0x0e, 0x36, 0x61, 0xc5, 0xf4, 0xfa, 0x5b, 0x2e, 0x7e, 0x3d, 0x70, 0x90, 0xdd, 0xdf,
-	0xc2, 0x6e, 0xd7, 0x76, 0x71, 0xe8, 0x7a, 0x50, 0x3d, 0xee, 0x9c, 0xb0, 0x8f, 0x97, 0xa5, 0xd7,
-	0x53, 0x5c, 0xb2, 0xf3, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe7, 0x87, 0x3d, 0x4b, 0xd5, 0x17,
-	0x00, 0x00,
+func init() { proto.RegisterFile("service.tl.proto", fileDescriptor_service_tl_f8c2d9e6cff3680a) }
+
+var fileDescriptor_service_tl_f8c2d9e6cff3680a = []byte{
+	// 1845 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0xdb, 0xc8,
+	0x15, 0x36, 0x25, 0xcb, 0x92, 0x8e, 0x2d, 0x47, 0x61, 0xe4, 0x8d, 0x2c, 0xff, 0x24, 0x65, 0x36,
+	0xbb, 0xde, 0x6d, 0xe1, 0xb4, 0x8e, 0xd7, 0x6d, 0x81, 0x02, 0x85, 0xe3, 0x74, 0x1b, 0x61, 0x95,
+	0xd6, 0xa5, 0xe5, 0x16, 0xed, 0x0d, 0x31, 0x21, 0xc7, 0x12, 0x11, 0x8a, 0xa4, 0x39, 0x23, 0x23,
+	0x2a, 0x7a, 0xb1, 0xd7, 0xbd, 0x29, 0xd0, 0x9b, 0xde, 0xf5, 0x19, 0xfa, 0x02, 0x05, 0xfa, 0x28,
+	0x7d, 0x83, 0x3e, 0x41, 0x8b, 0x62, 0x7e, 0x48, 0x0e, 0x7f, 0x24, 0xad, 0xed, 0xdd, 0x1b, 0x81,
+	0x3a, 0xe7, 0xcc, 0x37, 0xdf, 0x39, 0xf3, 0x73, 0xbe, 0x81, 0x36, 0xc1, 0xd1, 0x8d, 0x6b, 0xe3,
+	0x43, 0xea, 0x1d, 0x86, 0x51, 0x40, 0x03, 0xbd, 0x3e, 0xa1, 0xfc, 0xa3, 0xb7, 0x45, 0xec, 0x31,
+	0x9e, 0x20, 0xe6, 0xb1, 0x23, 0xfb, 0xe5, 0x91, 0xf0, 0xf7, 0x7a, 0x8a, 0x39, 0x88, 0xb0, 0x45,
+	0x67, 0x21, 0x26, 0xd2, 0xb7, 0x9d, 0xfa, 0x68, 0x84, 0x7c, 0x12, 0x06, 0x11, 0x95, 0xae, 0x4e,
+	0xea, 0x22, 0x33, 0xdf, 0x16, 0x56, 0xe3, 0xdf, 0x1a, 0x3c, 0x38, 0xa5, 0x14, 0xd9, 0xe3, 0xd7,
+	0x88, 0x22, 0x8b, 0xfd, 0xe8, 0xfb, 0xb0, 0x8e, 0xa6, 0x74, 0x6c, 0xbd, 0xc7, 0x33, 0xcb, 0x75,
+	0xba, 0xda, 0x53, 0xed, 0xa0, 0x6a, 0x36, 0x99, 0xe9, 0x2b, 0x3c, 0xeb, 0x3b, 0xfa, 0x1e, 0x00,
+	0xc1, 0x84, 0xb8, 0x81, 0xcf, 0xdc, 0x15, 0xe1, 0x96, 0x16, 0xe1, 0x9e, 0x60, 0x42, 0xd0, 0x08,
+	0x33, 0x77, 0x55, 0xb8, 0xa5, 0xa5, 0xef, 0xe8, 0x1d, 0xa8, 0x79, 0x68, 0x86, 0xa3, 0xee, 0xea,
+	0x53, 0xed, 0xa0, 0x66, 0x8a, 0x3f, 0xfa, 0x63, 0xa8, 0x4f, 0x09, 0x8e, 0xd8, 0x88, 0x1a, 0xb7,
+	0xaf, 0xb1, 0xbf, 0x7d, 0x47, 0x7f, 0x02, 0xeb, 0xc8, 0xb6, 0x31, 0x21, 0xd6, 0x18, 0x91, 0x71,
+	0x77, 0x8d, 0xc3, 0x81, 0x30, 0xbd, 0x41, 0x64, 0xcc, 0x03, 0x78, 0x02, 0x96, 0x83, 0x28, 0xea,
+	0xd6, 0x9f, 0x6a, 0x07, 0x1b, 0x26, 0xa0, 0x24, 0x27, 0xe3, 0x06, 0x20, 0xcd, 0x50, 0xff, 0x09,
+	0xac, 0xdb, 0x81, 0x4f, 0x68, 0x34, 0xb5, 0x69, 0x10, 0xf1, 0xe4, 0x36, 0x8f, 0x3e, 0x3a, 0x94,
+	0x35, 0x3f, 0x1c, 0x0e, 0xce, 0x52, 0xaf, 0xa9, 0x86, 0xea, 0x87, 0x50, 0x63, 0x33, 0x1c, 0xf1,
+	0x8c, 0xd7, 0x8f, 0xba, 0xc9, 0x98, 0x5c, 0xfd, 0x4c, 0x11, 0x66, 0xfc, 0x1c, 0x5a, 0xc3, 0x81,
+	0x95, 0x12, 0xb9, 0x35, 0xc0, 0xcf, 0xa0, 0x73, 0x16, 0xf8, 0x3e, 0xb6, 0xe9, 0x30, 0xb8, 0xc0,
+	0xd1, 0x0d, 0x8e, 0xc4, 0xfa, 0x7c, 0x0c, 0x9b, 0x6c, 0x05, 0x2d, 0x22, 0x6c, 0x72, 0x89, 0x6a,
+	0xe6, 0x06, 0xb3, 0x8a, 0xc0, 0xbe, 0x63, 0x7c, 0xad, 0xc1, 0x83, 0xdc, 0xf0, 0x7b, 0x24, 0xff,
+	0x32, 0xcb, 0x7d, 0x2f, 0x19, 0x53, 0xc6, 0x30, 0x4e, 0xe0, 0x12, 0xf6, 0x87, 0x03, 0x8b, 0x73,
+	0xb5, 0xd3, 0x30, 0xbe, 0x4f, 0x24, 0xa1, 0x3b, 0xc1, 0xda, 0xd0, 0x11, 0x56, 0x19, 0x84, 0x1d,
+	0x51, 0x97, 0xcf, 0xe1, 0x61, 0xbc, 0x2f, 0xf3, 0xa5, 0x79, 0x40, 0xd4, 0x69, 0xc5, 0xb6, 0x92,
+	0x31, 0x3e, 0x9a, 0x60, 0x3e, 0x7d, 0xd3, 0x04, 0x61, 0xfa, 0x15, 0x9a, 0x60, 0x5e, 0xbe, 0xdc,
+	0x2c, 0xdf, 0x45, 0xf9, 0xca, 0x12, 0x29, 0x29, 0x5f, 0x86, 0x7e, 0x4a, 0xe8, 0x4e, 0xb0, 0x7f,
+	0xd7, 0xa0, 0x75, 0x3e, 0x25, 0xb7, 0x38, 0xf0, 0x9f, 0x43, 0x7d, 0x1a, 0x3a, 0x88, 0x62, 0x22,
+	0x27, 0x6a, 0x27, 0x13, 0x5d, 0x0a, 0xbb, 0x19, 0x07, 0xe8, 0x9f, 0xc1, 0x43, 0xdb, 0x73, 0xb1,
+	0x4f, 0xad, 0x08, 0x5f, 0x5b, 0x13, 0x32, 0x4a, 0x2f, 0x81, 0x4d, 0xe1, 0x30, 0xf1, 0xf5, 0x5b,
+	0x32, 0xea, 0x3b, 0xfa, 0x47, 0xb0, 0x16, 0x61, 0x32, 0xf5, 0x28, 0xbf, 0x0a, 0x36, 0x4c, 0xf9,
+	0xcf, 0x88, 0xa0, 0x11, 0xf3, 0xbb, 0x47, 0xc9, 0x7f, 0x90, 0xad, 0x4d, 0x3a, 0x26, 0x93, 0x7b,
+	0x5c, 0x94, 0x5f, 0xc2, 0xe3, 0xb8, 0xd6, 0xe1, 0x94, 0x8c, 0x65, 0x5a, 0x9c, 0xc2, 0xed, 0x80,
+	0xfa, 0xb0, 0xad, 0x02, 0x99, 0xa1, 0x6d, 0xf2, 0xac, 0xee, 0x00, 0xd5, 0x03, 0xe8, 0xfb, 0xf4,
+	0xe5, 0x91, 0x58, 0xa4, 0x0d, 0xd0, 0x6e, 0xe4, 0x6e, 0xd6, 0x6e, 0x0c, 0x0f, 0x6a, 0xdc, 0x77,
+	0x8f, 0x02, 0x7d, 0x96, 0x25, 0xf3, 0x28, 0x19, 0x93, 0x4e, 0x1a, 0x33, 0xf9, 0x02, 0x1a, 0xc3,
+	0x81, 0xe5, 0xf2, 0x09, 0x6f, 0x31, 0x4c, 0x24, 0x70, 0x72, 0x9c, 0x4b, 0xa0, 0x9a, 0x26, 0x70,
+	0x72, 0xfc, 0x1d, 0x25, 0x70, 0x72, 0x9c, 0x61, 0x72, 0x0c, 0xf5, 0xe1, 0xc0, 0xf2, 0x02, 0x7f,
+	0x74, 0x9b, 0x51, 0xff, 0xd1, 0xa0, 0x7d, 0x2a, 0x4f, 0x81, 0x7f, 0x15, 0x7c, 0xb3, 0xc3, 0xb2,
+	0x0d, 0x8d, 0xd8, 0xcf, 0xa7, 0xd8, 0x30, 0xeb, 0xd2, 0xa9, 0x1f, 0xc3, 0xfa, 0xd5, 0x94, 0x4e,
+	0x23, 0x6c, 0x11, 0xe4, 0xd1, 0x6e, 0x35, 0x47, 0xe0, 0x4b, 0xee, 0xbb, 0x40, 0x1e, 0x35, 0xe1,
+	0x2a, 0xf9, 0xd6, 0x0d, 0x68, 0x25, 0x13, 0xb2, 0x5e, 0x2f, 0x1b, 0xe7, 0xba, 0x44, 0x1d, 0xce,
+	0x42, 0xac, 0x7f, 0x0a, 0xed, 0x10, 0x47, 0x13, 0x4b, 0x65, 0x56, 0xe3, 0xcc, 0x5a, 0xcc, 0x7e,
+	0xaa, 0xf6, 0x6e, 0xfc, 0x21, 0x74, 0x23, 0x4c, 0x2c, 0x44, 0x79, 0x37, 0xad, 0x99, 0x4d, 0x69,
+	0x39, 0xa5, 0xc6, 0x07, 0x58, 0x57, 0x12, 0xbe, 0xc7, 0xda, 0xbc, 0xc8, 0x56, 0x79, 0x3b, 0xed,
+	0x75, 0xb9, 0x7a, 0xc6, 0xb5, 0x3e, 0x85, 0x4d, 0xd6, 0x2d, 0x95, 0xc9, 0x6f, 0x0d, 0xf1, 0xaf,
+	0x0a, 0xe8, 0x67, 0xfc, 0x8a, 0x91, 0x4d, 0xe6, 0x9b, 0x2d, 0xd8, 0x26, 0x54, 0xdc, 0x50, 0x76,
+	0x80, 0x8a, 0x1b, 0xa6, 0x02, 0xa5, 0xaa, 0x0a, 0x94, 0x2d, 0x58, 0x43, 0xa1, 0xcb, 0x00, 0xa4,
+	0x6e, 0x41, 0xa1, 0xdb, 0x77, 0xf4, 0xef, 0xc1, 0x86, 0x83, 0x99, 0x7e, 0xb3, 0x26, 0x81, 0x83,
+	0x3d, 0x5e, 0xf4, 0xa6, 0xb9, 0x2e, 0x6c, 0x6f, 0x99, 0x49, 0x7f, 0xce, 0xda, 0x35, 0xa1, 0x78,
+	0x62, 0xdd, 0xe0, 0x88, 0xd1, 0xe2, 0x65, 0x6f, 0x9a, 0x2d, 0x61, 0xfd, 0xad, 0x30, 0x72, 0x1d,
+	0x13, 0x86, 0x49, 0x4c, 0x9d, 0xc7, 0x00, 0x0a, 0xc3, 0x38, 0xe0, 0x00, 0xda, 0x12, 0xc7, 0x43,
+	0xfe, 0xc8, 0xb2, 0x03, 0x07, 0x77, 0x1b, 0x3c, 0x4a, 0xe2, 0x0f, 0x90, 0x3f, 0x3a, 0x0b, 0x1c,
+	0xac, 0xef, 0x40, 0x93, 0x87, 0x84, 0xc8, 0x7e, 0xdf, 0x6d, 0xf2, 0x90, 0x06, 0x33, 0x9c, 0x23,
+	0xfb, 0x7d, 0xe2, 0xe4, 0xe3, 0x21, 0x75, 0xb2, 0x91, 0xc6, 0x9f, 0xa0, 0x95, 0xa9, 0xe0, 0x3d,
+	0x76, 0xc0, 0x8f, 0xb2, 0xcb, 0xb7, 0x93, 0xb6, 0xf6, 0xc2, 0x12, 0xa5, 0x77, 0x67, 0x67, 0x38,
+	0xb0, 0x6c, 0xd5, 0xcf, 0x77, 0xc2, 0x1d, 0xa0, 0x2e, 0xe0, 0x09, 0xbb, 0x86, 0x13, 0x39, 0x40,
+	0xcf, 0x0a, 0xa8, 0x3f, 0x84, 0xba, 0xf4, 0x77, 0xb5, 0xdc, 0x75, 0x9c, 0x09, 0x36, 0xe3, 0x30,
+	0xe3, 0xc7, 0xb0, 0xab, 0x80, 0x8e, 0x30, 0x65, 0x3b, 0x31, 0x88, 0xdc, 0x3f, 0x22, 0xea, 0x06,
+	0x3e, 0x51, 0x45, 0xac, 0xa6, 0x8a, 0x58, 0x63, 0x00, 0x7b, 0xca, 0xc0, 0x08, 0x93, 0xdc, 0xd0,
+	0xb9, 0x23, 0x75, 0x1d, 0x56, 0xb9, 0xee, 0x15, 0x2a, 0x9b, 0x7f, 0x1b, 0x5f, 0xc0, 0xa3, 0x2c,
+	0x8d, 0x01, 0xdf, 0xa1, 0x4b, 0xf6, 0xb9, 0x71, 0x02, 0x9d, 0xec, 0xb0, 0x4b, 0x31, 0xc5, 0xb2,
+	0x71, 0x3f, 0x85, 0xc7, 0xca, 0xb8, 0xeb, 0x29, 0x8e, 0x66, 0xf2, 0x04, 0x2e, 0x1d, 0xfa, 0x06,
+	0xb6, 0xb2, 0xab, 0x10, 0x0f, 0x7c, 0xa1, 0x5c, 0x92, 0xa2, 0xf8, 0x9d, 0xb2, 0xe3, 0x9d, 0x5c,
+	0x9d, 0xc6, 0x25, 0xf4, 0x14, 0xa4, 0x77, 0xae, 0xef, 0xc8, 0x30, 0x96, 0xc4, 0xd2, 0x23, 0xae,
+	0x94, 0xb7, 0x92, 0x59, 0x98, 0xdf, 0x65, 0x56, 0x74, 0xea, 0x7f, 0x6b, 0xc0, 0x7f, 0xa9, 0x14,
+	0x08, 0x0f, 0xf1, 0x24, 0x8c, 0xf3, 0xff, 0x14, 0xda, 0x14, 0x4f, 0x42, 0xab, 0x08, 0xde, 0xa2,
+	0x69, 0x58, 0xdf, 0xd1, 0x3f, 0x81, 0x07, 0x3c, 0xb0, 0xf0, 0xe0, 0xe2, 0x71, 0x17, 0xc9, 0xa3,
+	0x6b, 0x0b, 0xd6, 0x32, 0x5a, 0xab, 0x36, 0xe1, 0x12, 0xab, 0xac, 0x2f, 0xac, 0x96, 0xf5, 0x85,
+	0x0e, 0xd4, 0xfc, 0xc0, 0xb7, 0xb1, 0xec, 0x1a, 0xe2, 0xcf, 0x92, 0x6e, 0xa1, 0x7f, 0x1f, 0x1e,
+	0x62, 0xdf, 0x8e, 0x66, 0x21, 0x93, 0x98, 0xf2, 0x85, 0x27, 0x1f, 0x60, 0xed, 0xc4, 0xf1, 0x56,
+	0xd8, 0x8d, 0x10, 0x76, 0x94, 0x82, 0x38, 0x51, 0x10, 0x2a, 0x05, 0x21, 0xa5, 0x4c, 0xb5, 0x32,
+	0xa6, 0x07, 0xd0, 0xc6, 0x1f, 0x6c, 0x1c, 0xd2, 0x24, 0x94, 0xa9, 0xd2, 0x2a, 0xd3, 0x97, 0xc2,
+	0x1e, 0x43, 0x1a, 0x7f, 0xd3, 0xe0, 0x51, 0xac, 0xc5, 0xd8, 0x8f, 0x14, 0x75, 0x73, 0x17, 0x2d,
+	0xbf, 0xda, 0xd5, 0xfc, 0x6a, 0xef, 0x40, 0x33, 0x7d, 0x58, 0x88, 0x36, 0xd0, 0x20, 0xf1, 0x8b,
+	0x42, 0x11, 0xc9, 0xb5, 0x25, 0x22, 0xd9, 0xf8, 0x03, 0x3c, 0x52, 0x45, 0x62, 0x09, 0xb1, 0xec,
+	0x2d, 0x70, 0x0b, 0x01, 0x6e, 0xfc, 0x53, 0x83, 0x5e, 0x0c, 0xce, 0x7e, 0xce, 0xc6, 0xc8, 0xf7,
+	0xb1, 0x17, 0xcf, 0xb1, 0x07, 0x60, 0x0b, 0x4b, 0x9a, 0x7f, 0x53, 0x5a, 0xb2, 0x1b, 0xba, 0xba,
+	0xa8, 0x36, 0xab, 0x0b, 0x6b, 0x53, 0x9b, 0x5f, 0x9b, 0xb5, 0x65, 0xfc, 0xbf, 0x56, 0xf8, 0xb3,
+	0xe2, 0x2c, 0xe4, 0xaf, 0x2d, 0xe0, 0x5f, 0x99, 0x57, 0xc2, 0xea, 0x32, 0x0a, 0x7f, 0xd6, 0x60,
+	0x4b, 0xa5, 0x90, 0x88, 0xf8, 0x6c, 0x96, 0x5a, 0x2e, 0xcb, 0x5c, 0x89, 0x2a, 0xf9, 0x12, 0xed,
+	0x02, 0x14, 0xde, 0x44, 0x8d, 0x68, 0xd9, 0x6b, 0xe8, 0x2b, 0x68, 0xc7, 0x5c, 0x46, 0x98, 0x5e,
+	0x50, 0x44, 0xf1, 0xdd, 0xaf, 0xa5, 0x7f, 0x28, 0x99, 0x8d, 0x30, 0x7d, 0xed, 0x5e, 0x5d, 0xe1,
+	0x08, 0xfb, 0x76, 0x01, 0xb2, 0xb2, 0x00, 0x32, 0xbb, 0x31, 0xda, 0x50, 0x0d, 0x29, 0x91, 0xc7,
+	0x81, 0x7d, 0xb2, 0x3b, 0x2b, 0xa4, 0xc4, 0xa2, 0x01, 0x45, 0x9e, 0xe5, 0xb9, 0x13, 0x97, 0xca,
+	0x0d, 0xd1, 0x0a, 0x29, 0x19, 0x32, 0xeb, 0x80, 0x19, 0x59, 0x6f, 0x63, 0xf5, 0x96, 0xf7, 0x0a,
+	0xff, 0x66, 0x68, 0xd7, 0x94, 0xf0, 0x4b, 0xa4, 0x66, 0xb2, 0x4f, 0xe3, 0x7f, 0x1a, 0xec, 0x2a,
+	0x94, 0xe5, 0x76, 0xf8, 0x36, 0x98, 0x77, 0xa0, 0x76, 0x15, 0x44, 0xb6, 0x10, 0xd4, 0x0d, 0x53,
+	0xfc, 0xd1, 0x5f, 0x40, 0x5d, 0x6e, 0x27, 0x79, 0x8e, 0xb7, 0x94, 0x17, 0x42, 0x38, 0x8d, 0x09,
+	0x98, 0x71, 0x94, 0x7e, 0x02, 0x6b, 0x57, 0xae, 0x47, 0x71, 0x24, 0xf7, 0xf6, 0x7e, 0x2a, 0x23,
+	0x44, 0x84, 0xbc, 0x01, 0xc9, 0x97, 0x3c, 0xca, 0x94, 0xd1, 0x71, 0xe1, 0xea, 0x69, 0xe1, 0x98,
+	0xf2, 0xe4, 0xe5, 0x6a, 0x48, 0xe5, 0xc9, 0xfe, 0x1c, 0xfd, 0xb5, 0x0e, 0x60, 0x9e, 0x9f, 0xc5,
+	0x8a, 0xec, 0xf7, 0xb0, 0xbb, 0x50, 0xd6, 0x1c, 0x28, 0xe2, 0x6c, 0xa1, 0x00, 0xea, 0xb5, 0x92,
+	0xc8, 0x57, 0x41, 0xe0, 0x19, 0x2b, 0x3a, 0x86, 0xed, 0xf9, 0xe2, 0xe6, 0x79, 0x19, 0x6e, 0x21,
+	0xac, 0xf7, 0x24, 0x09, 0x43, 0xb6, 0x1d, 0x4c, 0x7d, 0x6a, 0x65, 0x03, 0x8c, 0x15, 0xfd, 0x12,
+	0x7a, 0x0b, 0xa4, 0xd0, 0x27, 0x65, 0xf3, 0x14, 0xe3, 0x8a, 0xec, 0x5f, 0x41, 0x3b, 0x0e, 0x4f,
+	0x34, 0xd1, 0xee, 0x1c, 0xd2, 0xdc, 0xdb, 0xdb, 0xcc, 0xbe, 0x5d, 0x8d, 0x15, 0xfd, 0x35, 0x3c,
+	0x2c, 0x0a, 0xa4, 0xbd, 0x39, 0x20, 0xc2, 0x5d, 0x82, 0x72, 0x0e, 0x9d, 0x52, 0xb9, 0xf4, 0xb4,
+	0x0c, 0x48, 0x8d, 0xe8, 0x95, 0xaa, 0x20, 0x63, 0x45, 0xff, 0x05, 0xe8, 0x25, 0x2a, 0x6a, 0x7f,
+	0xce, 0x52, 0xc7, 0x68, 0x85, 0x12, 0xfd, 0x1a, 0x1e, 0xcf, 0x93, 0x50, 0xcf, 0xca, 0xb0, 0x72,
+	0x41, 0x45, 0xc0, 0x0b, 0xd8, 0x9e, 0x2f, 0x9e, 0x4a, 0x77, 0xcc, 0xd4, 0x5f, 0x0a, 0x9a, 0x63,
+	0xa9, 0xea, 0xa6, 0xb9, 0x2c, 0x95, 0xa0, 0x22, 0xe0, 0x6f, 0xa0, 0x3b, 0x57, 0x77, 0x7c, 0x5c,
+	0x86, 0x98, 0x8f, 0x2a, 0x40, 0x1e, 0xfd, 0x77, 0x15, 0xea, 0xec, 0x50, 0xce, 0x7c, 0x5b, 0x3f,
+	0x85, 0x76, 0xd2, 0x6d, 0xe3, 0x36, 0x95, 0xdd, 0x78, 0x39, 0x6f, 0x91, 0x61, 0x0c, 0xa1, 0xaa,
+	0x81, 0x22, 0x84, 0xe2, 0x2d, 0xaf, 0xda, 0x9c, 0x9e, 0xff, 0xac, 0x94, 0x4c, 0x36, 0x68, 0x3e,
+	0x60, 0x49, 0x13, 0x7e, 0x56, 0x4a, 0x6d, 0x19, 0x20, 0xdb, 0xc4, 0xc5, 0x96, 0xba, 0x5f, 0x8a,
+	0x95, 0xf8, 0x8b, 0x30, 0xaf, 0xa1, 0x95, 0xed, 0x86, 0xdb, 0x05, 0x84, 0xd8, 0xd5, 0x4b, 0x9f,
+	0x74, 0xb2, 0xb7, 0x5b, 0xdc, 0xce, 0x77, 0xae, 0x5e, 0xd6, 0x05, 0xcb, 0xa0, 0x52, 0x7f, 0x6f,
+	0xa7, 0x80, 0x97, 0x3a, 0x8d, 0x15, 0x7d, 0x0c, 0xdb, 0xf3, 0xfb, 0xd4, 0xf3, 0x32, 0xec, 0x42,
+	0x58, 0xcf, 0x28, 0x4c, 0x51, 0x88, 0x31, 0x56, 0x5e, 0x1d, 0xc0, 0x8e, 0x1d, 0x4c, 0x0e, 0x7d,
+	0xfc, 0x6e, 0xea, 0x21, 0x77, 0x72, 0x88, 0xfd, 0x91, 0xeb, 0xe3, 0x78, 0xe8, 0xab, 0xfa, 0xdb,
+	0xe1, 0x39, 0xfb, 0x78, 0x53, 0x79, 0xb7, 0xc6, 0x2d, 0x2f, 0xff, 0x3f, 0x00, 0x04, 0xb8, 0x45,
+	0xa3, 0x69, 0x1a, 0x00, 0x00,
 }
@@ -18,7 +18,9 @@
 package zproto
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/nebulaim/telegramd/baselib/bytes2"
 	"hash/crc32"
 	"testing"
 )
@@ -34,3 +36,29 @@ func TestCRC32(t *testing.T) {
 	crc32Hash.Write(b2)
 	fmt.Println(crc32Hash.Sum32())
 }
+
+func TestSessionDataCompatible(t *testing.T) {
+	raw := []byte{1, 2, 3, 4, 5}
+
+	m, err := DecodeMessage(EncodeMessage(&ZProtoSessionData{ConnType: 1, SessionId: 2, QuickAck: true, MtpRawData: raw}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := m.(*ZProtoSessionData); d.ConnType != 1 || d.SessionId != 2 || !d.QuickAck || !bytes.Equal(d.MtpRawData, raw) {
+		t.Fatalf("invalid session data: %s", d)
+	}
+
+	// 旧版本编码的session_data没有quick_ack
+	x := bytes2.NewBufferOutput(64)
+	x.UInt32(SESSION_SESSION_DATA)
+	x.Int32(1)
+	x.UInt64(2)
+	bytes2.WriteBytes(x, raw)
+	m, err = DecodeMessage(x.Buf())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := m.(*ZProtoSessionData); d.QuickAck || !bytes.Equal(d.MtpRawData, raw) {
+		t.Fatalf("invalid session data: %s", d)
+	}
+}
@@ -75,6 +75,11 @@ func (s *ZProtoServer) Serve() {
 	go s.registry.Register()
 }
 
+// 只从etcd注销, 已有连接不受影响
+func (s *ZProtoServer) Deregister() {
+	s.registry.Deregister()
+}
+
 func (s *ZProtoServer) Stop() {
 	s.registry.Deregister()
 	s.server.Stop()
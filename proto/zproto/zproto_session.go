@@ -59,6 +59,8 @@ const (
 	SYNC_DATA                     = 0xFF03
 	SESSION_SESSION_CLIENT_NEW    = 0xFF04
 	SESSION_SESSION_CLIENT_CLOSED = 0xFF05
+	SESSION_QUICK_ACK             = 0xFF06
+	SESSION_TRANSPORT_ERROR       = 0xFF07
 )
 
 //func isHandshake(state int) bool {
@@ -154,13 +156,15 @@ func (m *ZProtoHandshakeMessage) Decode(dbuf *bytes2.BufferInput) error {
 type ZProtoSessionData struct {
 	ConnType   int
 	SessionId  uint64
+	QuickAck   bool // 客户端要求QuickAck
 	MtpRawData []byte
 }
 
 func (m *ZProtoSessionData) String() string {
-	return fmt.Sprintf("{conn_type: %d, session_id: %d, mtp_raw_data_len: %d, mtp_raw_data: %s}",
+	return fmt.Sprintf("{conn_type: %d, session_id: %d, quick_ack: %v, mtp_raw_data_len: %d, mtp_raw_data: %s}",
 		m.ConnType,
 		m.SessionId,
+		m.QuickAck,
 		len(m.MtpRawData),
 		bytes2.HexDump(m.MtpRawData))
 }
@@ -170,12 +174,68 @@ func (m *ZProtoSessionData) Encode(x *bytes2.BufferOutput) {
 	x.Int32(int32(m.ConnType))
 	x.UInt64(m.SessionId)
 	bytes2.WriteBytes(x, m.MtpRawData)
+	// quick_ack放在最后, 滚动升级时旧版本忽略多出来的字节
+	if m.QuickAck {
+		x.Byte(1)
+	} else {
+		x.Byte(0)
+	}
 }
 
 func (m *ZProtoSessionData) Decode(dbuf *bytes2.BufferInput) error {
 	m.ConnType = int(dbuf.Int32())
 	m.SessionId = dbuf.UInt64()
 	m.MtpRawData, _ = bytes2.ReadBytes(dbuf)
+	// 旧版本没有quick_ack
+	if b, err := dbuf.Buf(); err == nil && len(b) > 0 {
+		m.QuickAck = dbuf.Byte() == 1
+	}
+	return dbuf.Error()
+}
+
+///////////////////////////////////////////////////////////////////////////////////////////
+// session服务器确认收到消息后，由frontend向客户端发送QuickAck
+type ZProtoQuickAck struct {
+	SessionId uint64
+	Token     int32
+}
+
+func (m *ZProtoQuickAck) String() string {
+	return fmt.Sprintf("{session_id: %d, token: %d}", m.SessionId, m.Token)
+}
+
+func (m *ZProtoQuickAck) Encode(x *bytes2.BufferOutput) {
+	x.UInt32(SESSION_QUICK_ACK)
+	x.UInt64(m.SessionId)
+	x.Int32(m.Token)
+}
+
+func (m *ZProtoQuickAck) Decode(dbuf *bytes2.BufferInput) error {
+	m.SessionId = dbuf.UInt64()
+	m.Token = dbuf.Int32()
+	return dbuf.Error()
+}
+
+///////////////////////////////////////////////////////////////////////////////////////////
+// 后端服务(session、auth_key)要求frontend向客户端发送传输层错误码(-404/-429/-444)
+type ZProtoTransportError struct {
+	SessionId uint64
+	ErrorCode int32
+}
+
+func (m *ZProtoTransportError) String() string {
+	return fmt.Sprintf("{session_id: %d, error_code: %d}", m.SessionId, m.ErrorCode)
+}
+
+func (m *ZProtoTransportError) Encode(x *bytes2.BufferOutput) {
+	x.UInt32(SESSION_TRANSPORT_ERROR)
+	x.UInt64(m.SessionId)
+	x.Int32(m.ErrorCode)
+}
+
+func (m *ZProtoTransportError) Decode(dbuf *bytes2.BufferInput) error {
+	m.SessionId = dbuf.UInt64()
+	m.ErrorCode = dbuf.Int32()
 	return dbuf.Error()
 }
 
@@ -306,4 +366,6 @@ func init() {
 	zprotoFactories[SYNC_DATA] = func() MessageBase { return &ZProtoSyncData{} }
 	zprotoFactories[SESSION_SESSION_CLIENT_NEW] = func() MessageBase { return &ZProtoSessionClientNew{} }
 	zprotoFactories[SESSION_SESSION_CLIENT_CLOSED] = func() MessageBase { return &ZProtoSessionClientClosed{} }
+	zprotoFactories[SESSION_QUICK_ACK] = func() MessageBase { return &ZProtoQuickAck{} }
+	zprotoFactories[SESSION_TRANSPORT_ERROR] = func() MessageBase { return &ZProtoTransportError{} }
 }
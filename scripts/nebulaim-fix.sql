@@ -1 +1,12 @@
 ALTER TABLE channels AUTO_INCREMENT = 1073741824;
+ALTER TABLE auth_keys ADD COLUMN auth_key_type tinyint(4) NOT NULL DEFAULT '0' AFTER body, ADD COLUMN perm_auth_key_id bigint(20) NOT NULL DEFAULT '0' AFTER auth_key_type, ADD COLUMN expires_at int(11) NOT NULL DEFAULT '0' AFTER perm_auth_key_id, ADD KEY perm_auth_key_id (perm_auth_key_id), ADD KEY expires_at (expires_at);
+ALTER TABLE auth_keys ADD COLUMN key_version int(11) NOT NULL DEFAULT '0' AFTER body, ADD KEY key_version (key_version);
+ALTER TABLE user_pts_updates ADD KEY user_id (user_id, pts);
+ALTER TABLE auth_seq_updates ADD KEY user_id (user_id, date2);
+ALTER TABLE channel_pts_updates ADD KEY channel_id (channel_id, pts);
+ALTER TABLE user_pts_updates ADD KEY date2 (date2);
+ALTER TABLE user_qts_updates ADD KEY date2 (date2);
+ALTER TABLE auth_seq_updates ADD KEY date2 (date2);
+ALTER TABLE channel_pts_updates ADD KEY date2 (date2);
+CREATE TABLE user_update_floors (user_id int(11) NOT NULL, min_pts int(11) NOT NULL DEFAULT '0', min_date int(11) NOT NULL DEFAULT '0', updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, PRIMARY KEY (user_id)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+CREATE TABLE channel_update_floors (channel_id int(11) NOT NULL, min_pts int(11) NOT NULL DEFAULT '0', updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, PRIMARY KEY (channel_id)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
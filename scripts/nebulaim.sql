@@ -140,6 +140,10 @@ CREATE TABLE `auth_keys` (
   `id` int(11) NOT NULL,
   `auth_key_id` bigint(20) NOT NULL COMMENT 'auth_id',
   `body` varchar(512) COLLATE utf8mb4_unicode_ci NOT NULL COMMENT 'auth_key，原始数据为256的二进制数据，存储时转换成base64格式',
+  `key_version` int(11) NOT NULL DEFAULT '0' COMMENT '加密body的master key版本，0为未加密',
+  `auth_key_type` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0: 永久key, 1: 临时key',
+  `perm_auth_key_id` bigint(20) NOT NULL DEFAULT '0' COMMENT '临时key绑定的永久key',
+  `expires_at` int(11) NOT NULL DEFAULT '0' COMMENT '临时key过期时间',
   `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
@@ -467,6 +471,18 @@ CREATE TABLE `channel_pts_updates` (
 
 -- --------------------------------------------------------
 
+--
+-- 表的结构 `channel_update_floors`
+--
+
+CREATE TABLE `channel_update_floors` (
+  `channel_id` int(11) NOT NULL,
+  `min_pts` int(11) NOT NULL DEFAULT '0',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- --------------------------------------------------------
+
 --
 -- 表的结构 `chats`
 --
@@ -1147,6 +1163,19 @@ CREATE TABLE `user_sticker_sets` (
 
 -- --------------------------------------------------------
 
+--
+-- 表的结构 `user_update_floors`
+--
+
+CREATE TABLE `user_update_floors` (
+  `user_id` int(11) NOT NULL,
+  `min_pts` int(11) NOT NULL DEFAULT '0',
+  `min_date` int(11) NOT NULL DEFAULT '0',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- --------------------------------------------------------
+
 --
 -- 表的结构 `wall_papers`
 --
@@ -1210,7 +1239,10 @@ ALTER TABLE `auth_channel_updates_state`
 --
 ALTER TABLE `auth_keys`
   ADD PRIMARY KEY (`id`),
-  ADD UNIQUE KEY `auth_key_id` (`auth_key_id`);
+  ADD UNIQUE KEY `auth_key_id` (`auth_key_id`),
+  ADD KEY `perm_auth_key_id` (`perm_auth_key_id`),
+  ADD KEY `expires_at` (`expires_at`),
+  ADD KEY `key_version` (`key_version`);
 
 --
 -- Indexes for table `auth_op_logs`
@@ -1237,7 +1269,9 @@ ALTER TABLE `auth_salts`
 --
 ALTER TABLE `auth_seq_updates`
   ADD PRIMARY KEY (`id`),
-  ADD UNIQUE KEY `auth_id` (`auth_id`,`user_id`,`seq`);
+  ADD UNIQUE KEY `auth_id` (`auth_id`,`user_id`,`seq`),
+  ADD KEY `user_id` (`user_id`,`date2`),
+  ADD KEY `date2` (`date2`);
 
 --
 -- Indexes for table `auth_updates_state`
@@ -1313,7 +1347,15 @@ ALTER TABLE `channel_participants`
 -- Indexes for table `channel_pts_updates`
 --
 ALTER TABLE `channel_pts_updates`
-  ADD PRIMARY KEY (`id`);
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `channel_id` (`channel_id`,`pts`),
+  ADD KEY `date2` (`date2`);
+
+--
+-- Indexes for table `channel_update_floors`
+--
+ALTER TABLE `channel_update_floors`
+  ADD PRIMARY KEY (`channel_id`);
 
 --
 -- Indexes for table `chats`
@@ -1534,13 +1576,16 @@ ALTER TABLE `user_profile_photos`
 -- Indexes for table `user_pts_updates`
 --
 ALTER TABLE `user_pts_updates`
-  ADD PRIMARY KEY (`id`);
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `user_id` (`user_id`,`pts`),
+  ADD KEY `date2` (`date2`);
 
 --
 -- Indexes for table `user_qts_updates`
 --
 ALTER TABLE `user_qts_updates`
-  ADD PRIMARY KEY (`id`);
+  ADD PRIMARY KEY (`id`),
+  ADD KEY `date2` (`date2`);
 
 --
 -- Indexes for table `user_sticker_sets`
@@ -1550,6 +1595,12 @@ ALTER TABLE `user_sticker_sets`
   ADD UNIQUE KEY `uniq` (`user_id`,`sticker_set_id`) USING BTREE,
   ADD KEY `user_id` (`user_id`) USING BTREE;
 
+--
+-- Indexes for table `user_update_floors`
+--
+ALTER TABLE `user_update_floors`
+  ADD PRIMARY KEY (`user_id`);
+
 --
 -- Indexes for table `wall_papers`
 --
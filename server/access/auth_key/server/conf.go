@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"github.com/BurntSushi/toml"
 	"github.com/nebulaim/telegramd/baselib/mysql_client"
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
 	"github.com/nebulaim/telegramd/proto/zproto"
 	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
 )
@@ -32,10 +33,14 @@ var (
 )
 
 type authKeyConfig struct {
-	ServerId             int32 // 服务器ID
+	ServerId             int32  // 服务器ID
+	DebugAddr            string // 不为空时在此地址提供/debug/vars统计数据
 	Mysql                []mysql_client.MySQLConfig
 	Server               *zproto.ZProtoServerConfig
 	AuthSessionRpcClient service_discovery.ServiceDiscoveryClientConfig
+	HandshakeLimit       rate_limit.IPLimiterConfig // 按来源IP限制req_pq/req_DH_params的频率
+	RsaKeys              []string                   // RSA私钥文件(pkcs1或pkcs8), 为空时使用内置key
+	DhPrimes             []string                   // 2048位safe prime(hex), 为空时使用内置dh2048_p
 	// RpcServer *grpc_util.RPCServerConfig
 }
 
@@ -19,8 +19,10 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/golang/protobuf/proto"
@@ -28,11 +30,13 @@ import (
 	"github.com/nebulaim/telegramd/baselib/crypto"
 	"github.com/nebulaim/telegramd/baselib/logger"
 	"github.com/nebulaim/telegramd/baselib/net2"
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
+	"io/ioutil"
 	"math/big"
+	"math/rand"
 	"time"
-	"context"
 )
 
 const (
@@ -42,19 +46,13 @@ const (
 var (
 	headerRpcMetadata = "auth_key_metadata"
 
-	// TODO(@benqi): 预先计算出fingerprint
-	// 这里直接使用了0xc3b42b026ce86b21
-	fingerprint uint64 = 12240908862933197005
+	// 握手超过频率限制, 返回传输层错误-429
+	errHandshakeFlood = fmt.Errorf("handshake rate limit exceeded")
 
-	// TODO(@benqi): 使用算法生成PQ
-	// 这里直接指定了PQ值: {0x17, 0xED, 0x48, 0x94, 0x1A, 0x08, 0xF9, 0x81}
-	pq = string([]byte{0x17, 0xED, 0x48, 0x94, 0x1A, 0x08, 0xF9, 0x81})
+	// req_DH_params指定的RSA key不存在, 返回传输层错误-404
+	errHandshakeKeyNotFound = fmt.Errorf("rsa public key not found")
 
-	// TODO(@benqi): 直接指定了p和q
-	p = []byte{0x49, 0x4C, 0x55, 0x3B}
-	q = []byte{0x53, 0x91, 0x10, 0x73}
-
-	// TODO(@benqi): 直接指定了dh2048_p和dh2048_g!!!
+	// 默认的dh2048_p和dh2048_g, 未配置dhPrimes时使用
 	// andriod client 指定的good prime
 	//
 	// static const char *goodPrime = "
@@ -110,28 +108,109 @@ var (
 	dh2048_g = []byte{0x02}
 )
 
+// DH参数
+type dhParams struct {
+	p       []byte
+	g       int32
+	bigIntP *big.Int
+	bigIntG *big.Int
+}
+
+func newDHParams(p []byte, g int32) *dhParams {
+	return &dhParams{
+		p:       p,
+		g:       g,
+		bigIntP: new(big.Int).SetBytes(p),
+		bigIntG: big.NewInt(int64(g)),
+	}
+}
+
 type handshake struct {
-	rsa                  *crypto.RSACryptor
-	dh2048p              []byte
-	dh2048g              []byte
-	bigIntDH2048G        *big.Int
-	bigIntDH2048P        *big.Int
+	rsaKeys              map[uint64]*crypto.RSACryptor
+	fingerprints         []int64
+	dhParamsList         []*dhParams
 	authSessionRpcClient mtproto.RPCSessionClient
+	limit                *rate_limit.IPLimiter
 }
 
-func newHandshake(c mtproto.RPCSessionClient) *handshake {
+func newHandshake(rsaKeys []*crypto.RSACryptor, dhParamsList []*dhParams, c mtproto.RPCSessionClient, limit *rate_limit.IPLimiter) *handshake {
 	s := &handshake{
-		rsa:                  crypto.NewRSACryptor(),
-		dh2048p:              dh2048_p,
-		dh2048g:              dh2048_g,
-		bigIntDH2048P:        new(big.Int).SetBytes(dh2048_p),
-		bigIntDH2048G:        new(big.Int).SetBytes(dh2048_g),
+		rsaKeys:              make(map[uint64]*crypto.RSACryptor, len(rsaKeys)),
+		fingerprints:         make([]int64, 0, len(rsaKeys)),
+		dhParamsList:         dhParamsList,
 		authSessionRpcClient: c,
+		limit:                limit,
+	}
+
+	for _, k := range rsaKeys {
+		s.rsaKeys[k.Fingerprint()] = k
+		s.fingerprints = append(s.fingerprints, int64(k.Fingerprint()))
 	}
 	return s
 }
 
-func (s *handshake) onHandshake(conn *net2.TcpConnection, hmsg *zproto.ZProtoHandshakeMessage) (*zproto.ZProtoHandshakeMessage, error) {
+// 加载RSA私钥, 未配置时使用内置key
+func loadRSAKeys(keyFiles []string) ([]*crypto.RSACryptor, error) {
+	if len(keyFiles) == 0 {
+		return []*crypto.RSACryptor{crypto.NewRSACryptor()}, nil
+	}
+
+	keys := make([]*crypto.RSACryptor, 0, len(keyFiles))
+	fingerprints := make(map[uint64]string, len(keyFiles))
+	for _, f := range keyFiles {
+		pemData, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("read rsa key %s error: %v", f, err)
+		}
+		k, err := crypto.NewRSACryptorFromPEM(pemData)
+		if err != nil {
+			return nil, fmt.Errorf("load rsa key %s error: %v", f, err)
+		}
+		if f2, ok := fingerprints[k.Fingerprint()]; ok {
+			return nil, fmt.Errorf("duplicate rsa key: %s, %s", f2, f)
+		}
+		fingerprints[k.Fingerprint()] = f
+		glog.Infof("loadRSAKeys - load rsa key: {file: %s, fingerprint: %d}", f, k.Fingerprint())
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// 加载safe prime池, 未配置时使用dh2048_p
+func loadDHParams(primes []string) ([]*dhParams, error) {
+	if len(primes) == 0 {
+		return []*dhParams{newDHParams(dh2048_p, int32(dh2048_g[0]))}, nil
+	}
+
+	dhParamsList := make([]*dhParams, 0, len(primes))
+	for _, v := range primes {
+		p, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dh prime %s: %v", v, err)
+		}
+		bigIntP := new(big.Int).SetBytes(p)
+		if !crypto.IsSafePrime(bigIntP) {
+			return nil, fmt.Errorf("dh prime is not 2048-bit safe prime: %s", v)
+		}
+		dhParamsList = append(dhParamsList, newDHParams(bigIntP.Bytes(), crypto.FindDHGenerator(bigIntP)))
+	}
+	return dhParamsList, nil
+}
+
+func (s *handshake) randomDHParams() *dhParams {
+	return s.dhParamsList[rand.Intn(len(s.dhParamsList))]
+}
+
+func (s *handshake) findDHParams(p []byte) *dhParams {
+	for _, v := range s.dhParamsList {
+		if bytes.Equal(v.p, p) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *handshake) onHandshake(conn *net2.TcpConnection, md *zproto.ZProtoMetadata, hmsg *zproto.ZProtoHandshakeMessage) (*zproto.ZProtoHandshakeMessage, error) {
 	var (
 		state = hmsg.State
 		err   error
@@ -145,6 +224,15 @@ func (s *handshake) onHandshake(conn *net2.TcpConnection, hmsg *zproto.ZProtoHan
 		return nil, err
 	}
 
+	// req_pq和req_DH_params需要RSA和DH运算, 按客户端IP限流
+	switch mtpMessage.Object.(type) {
+	case *mtproto.TLReqPq, *mtproto.TLReqPqMulti, *mtproto.TLReq_DHParams:
+		if !s.limit.AllowAddr(md.ClientAddr) {
+			glog.Warningf("onHandshake - rate limited: {client_addr: %s, msg: %s}", md.ClientAddr, logger.JsonDebugData(mtpMessage.Object))
+			return nil, errHandshakeFlood
+		}
+	}
+
 	switch mtpMessage.Object.(type) {
 	case *mtproto.TLReqPq:
 		res, err = s.onReqPq(state, mtpMessage.Object.(*mtproto.TLReqPq))
@@ -167,7 +255,9 @@ func (s *handshake) onHandshake(conn *net2.TcpConnection, hmsg *zproto.ZProtoHan
 		return nil, err
 	}
 
-	if err != nil {
+	if err == errHandshakeKeyNotFound {
+		return nil, err
+	} else if err != nil {
 		state.ResState = zproto.RES_STATE_ERROR
 	} else {
 		state.ResState = zproto.RES_STATE_OK
@@ -212,11 +302,18 @@ func (s *handshake) onReqPq(state *zproto.HandshakeState, request *mtproto.TLReq
 		return nil, err
 	}
 
+	// 每次握手随机生成pq
+	pq, p, q, err := crypto.GeneratePQ()
+	if err != nil {
+		glog.Error(err)
+		return nil, err
+	}
+
 	resPQ := &mtproto.TLResPQ{Data2: &mtproto.ResPQ_Data{
 		Nonce:                       request.Nonce,
 		ServerNonce:                 crypto.GenerateNonce(16),
-		Pq:                          pq,
-		ServerPublicKeyFingerprints: []int64{int64(fingerprint)},
+		Pq:                          string(pq),
+		ServerPublicKeyFingerprints: s.fingerprints,
 	}}
 	//
 	//resPQ := mtproto.NewTLResPQ()
@@ -229,6 +326,9 @@ func (s *handshake) onReqPq(state *zproto.HandshakeState, request *mtproto.TLReq
 	// 缓存客户端Nonce
 	authKeyMD.Nonce = request.GetNonce()
 	authKeyMD.ServerNonce = resPQ.GetServerNonce()
+	authKeyMD.Pq = pq
+	authKeyMD.PqP = p
+	authKeyMD.PqQ = q
 
 	state.Ctx, _ = proto.Marshal(authKeyMD)
 
@@ -256,11 +356,18 @@ func (s *handshake) onReqPqMulti(state *zproto.HandshakeState, request *mtproto.
 		return nil, err
 	}
 
+	// 每次握手随机生成pq
+	pq, p, q, err := crypto.GeneratePQ()
+	if err != nil {
+		glog.Error(err)
+		return nil, err
+	}
+
 	resPQ := &mtproto.TLResPQ{Data2: &mtproto.ResPQ_Data{
 		Nonce:                       request.Nonce,
 		ServerNonce:                 crypto.GenerateNonce(16),
-		Pq:                          pq,
-		ServerPublicKeyFingerprints: []int64{int64(fingerprint)},
+		Pq:                          string(pq),
+		ServerPublicKeyFingerprints: s.fingerprints,
 	}}
 	//
 	//resPQ := mtproto.NewTLResPQ()
@@ -273,6 +380,9 @@ func (s *handshake) onReqPqMulti(state *zproto.HandshakeState, request *mtproto.
 	// 缓存客户端Nonce
 	authKeyMD.Nonce = request.GetNonce()
 	authKeyMD.ServerNonce = resPQ.GetServerNonce()
+	authKeyMD.Pq = pq
+	authKeyMD.PqP = p
+	authKeyMD.PqQ = q
 
 	state.Ctx, _ = proto.Marshal(authKeyMD)
 
@@ -310,24 +420,32 @@ func (s *handshake) onReq_DHParams(state *zproto.HandshakeState, request *mtprot
 		return nil, err
 	}
 
+	// 未完成req_pq
+	if len(authKeyMD.Pq) == 0 {
+		err = fmt.Errorf("onReq_DHParams - invalid state")
+		glog.Error(err)
+		return nil, err
+	}
+
 	// check P
-	if !bytes.Equal([]byte(request.P), p) {
+	if !bytes.Equal([]byte(request.P), authKeyMD.PqP) {
 		err = fmt.Errorf("onReq_DHParams - Invalid p valuee")
 		glog.Error(err)
 		return nil, err
 	}
 
 	// check Q
-	if !bytes.Equal([]byte(request.Q), q) {
+	if !bytes.Equal([]byte(request.Q), authKeyMD.PqQ) {
 		err = fmt.Errorf("onReq_DHParams - Invalid q value")
 		glog.Error(err)
 		return nil, err
 	}
 
-	if request.PublicKeyFingerprint != int64(fingerprint) {
-		err = fmt.Errorf("onReq_DHParams - Invalid PublicKeyFingerprint value")
-		glog.Error(err)
-		return nil, err
+	// 按fingerprint选择RSA key
+	rsaKey, ok := s.rsaKeys[uint64(request.PublicKeyFingerprint)]
+	if !ok {
+		glog.Errorf("onReq_DHParams - Invalid PublicKeyFingerprint value: %d", request.PublicKeyFingerprint)
+		return nil, errHandshakeKeyNotFound
 	}
 
 	// new_nonce := another (good) random number generated by the client;
@@ -351,45 +469,59 @@ func (s *handshake) onReq_DHParams(state *zproto.HandshakeState, request *mtprot
 	// glog.Info("EncryptedData: len = ", len(encryptedData), ", data: ", hex.EncodeToString(encryptedData))
 	//
 	// 1. 解密
-	encryptedPQInnerData := s.rsa.Decrypt([]byte(request.EncryptedData))
-
-	// TODO(@benqi): sha1_check
-	// sha1Check := sha1.Sum(encryptedPQInnerData[20:])
-	// glog.Info(hex.EncodeToString(sha1Check[:]))
-	// glog.Info(hex.EncodeToString(encryptedPQInnerData[:20]))
-	//if !bytes.Equal(sha1Check[:], encryptedPQInnerData[0:20]) {
-	//	glog.Error("process Req_DHParams - sha1Check error")
-	//	return nil, fmt.Errorf("process Req_DHParams - sha1Check error")
-	//}
-
-	// pqInnerData := new(mtproto.P_QInnerData)
-	// pqInnerData.Decode()
-	//
+	encryptedPQInnerData := rsaKey.Decrypt([]byte(request.EncryptedData))
+	if len(encryptedPQInnerData) <= SHA_DIGEST_LENGTH {
+		err = fmt.Errorf("onReq_DHParams - invalid encrypted_data")
+		glog.Error(err)
+		return nil, err
+	}
+
 	// 2. 反序列化出pqInnerData
-	pqInnerData := mtproto.NewTLPQInnerData()
-	dbuf := mtproto.NewDecodeBuf(encryptedPQInnerData[SHA_DIGEST_LENGTH+4:])
-	err = pqInnerData.Decode(dbuf)
+	// p_q_inner_data, p_q_inner_data_dc, p_q_inner_data_temp, p_q_inner_data_temp_dc
+	innerData := &mtproto.P_QInnerData{}
+	dbuf := mtproto.NewDecodeBuf(encryptedPQInnerData[SHA_DIGEST_LENGTH:])
+	err = innerData.Decode(dbuf)
 	if err != nil {
 		glog.Errorf("process Req_DHParams - TLPQInnerData decode error: %v", err)
 		return nil, fmt.Errorf("process Req_DHParams - TLPQInnerData decode error: %v", err)
 	}
 
+	// data_with_hash := SHA1(data) + data + (any random bytes)
+	sha1Check := sha1.Sum(innerData.Encode())
+	if !bytes.Equal(sha1Check[:], encryptedPQInnerData[:SHA_DIGEST_LENGTH]) {
+		glog.Error("process Req_DHParams - sha1Check error")
+		return nil, fmt.Errorf("process Req_DHParams - sha1Check error")
+	}
+
+	pqInnerData := innerData.GetData2()
+	switch innerData.GetConstructor() {
+	case mtproto.TLConstructor_CRC32_p_q_inner_data_temp, mtproto.TLConstructor_CRC32_p_q_inner_data_temp_dc:
+		// 临时key
+		if pqInnerData.GetExpiresIn() <= 0 {
+			glog.Error("process Req_DHParams - Invalid p_q_inner_data.expires_in value")
+			return nil, fmt.Errorf("process Req_DHParams - Invalid p_q_inner_data.expires_in value")
+		}
+		authKeyMD.ExpiresIn = pqInnerData.GetExpiresIn()
+	default:
+		authKeyMD.ExpiresIn = 0
+	}
+
 	// 2. 再检查一遍p_q_inner_data里的pq, p, q, nonce, server_nonce合法性
 	// 客户端传输数据解析
 	// PQ
-	if !bytes.Equal([]byte(pqInnerData.GetPq()), []byte(pq)) {
+	if !bytes.Equal([]byte(pqInnerData.GetPq()), authKeyMD.Pq) {
 		glog.Error("process Req_DHParams - Invalid p_q_inner_data.pq value")
 		return nil, fmt.Errorf("process Req_DHParams - Invalid p_q_inner_data.pq value")
 	}
 
 	// P
-	if !bytes.Equal([]byte(pqInnerData.GetP()), p) {
+	if !bytes.Equal([]byte(pqInnerData.GetP()), authKeyMD.PqP) {
 		glog.Error("process Req_DHParams - Invalid p_q_inner_data.p value")
 		return nil, fmt.Errorf("process Req_DHParams - Invalid p_q_inner_data.p value")
 	}
 
 	// Q
-	if !bytes.Equal([]byte(pqInnerData.GetQ()), q) {
+	if !bytes.Equal([]byte(pqInnerData.GetQ()), authKeyMD.PqQ) {
 		glog.Error("process Req_DHParams - Invalid p_q_inner_data.q value")
 		return nil, fmt.Errorf("process Req_DHParams - Invalid p_q_inner_data.q value")
 	}
@@ -411,26 +543,28 @@ func (s *handshake) onReq_DHParams(state *zproto.HandshakeState, request *mtprot
 	// 检查NewNonce的长度(int256)
 	// 缓存NewNonce
 	authKeyMD.NewNonce = pqInnerData.GetNewNonce()
+	if len(authKeyMD.NewNonce) != 32 {
+		glog.Error("process Req_DHParams - Invalid NewNonce")
+		return nil, fmt.Errorf("process Req_DHParams - Invalid NewNonce")
+	}
+
+	dh := s.randomDHParams()
 	authKeyMD.A = crypto.GenerateNonce(256)
-	authKeyMD.P = s.dh2048p
+	authKeyMD.P = dh.p
 
 	bigIntA := new(big.Int).SetBytes(authKeyMD.A)
-	// bigIntP := new(big.Int).SetBytes(authKeyMD.P)
-
-	//c.A = new(big.Int).SetBytes()
-	//c.P = new(big.Int).SetBytes(dh2048_p)
 
 	// 服务端计算GA = g^a mod p
 	g_a := new(big.Int)
-	g_a.Exp(s.bigIntDH2048G, bigIntA, s.bigIntDH2048P)
+	g_a.Exp(dh.bigIntG, bigIntA, dh.bigIntP)
 
 	// ServerNonce
 	server_DHInnerData := &mtproto.TLServer_DHInnerData{Data2: &mtproto.Server_DHInnerData_Data{
 		Nonce:       authKeyMD.Nonce,
 		ServerNonce: authKeyMD.ServerNonce,
-		G:           int32(s.dh2048g[0]),
+		G:           dh.g,
 		GA:          string(g_a.Bytes()),
-		DhPrime:     string(s.dh2048p),
+		DhPrime:     string(dh.p),
 		ServerTime:  int32(time.Now().Unix()),
 	}}
 
@@ -486,7 +620,6 @@ func (s *handshake) onSetClient_DHParams(state *zproto.HandshakeState, request *
 	glog.Infof("onSetClient_DHParams - state: %d, res_state: %d, metadata: {%v}, request: %s",
 		state.State, state.ResState, authKeyMD, logger.JsonDebugData(request))
 
-	// TODO(@benqi): Impl SetClient_DHParams logic
 	// 客户端传输数据解析
 	// Nonce
 	if !bytes.Equal(request.Nonce, authKeyMD.Nonce) {
@@ -502,6 +635,13 @@ func (s *handshake) onSetClient_DHParams(state *zproto.HandshakeState, request *
 		return nil, err
 	}
 
+	// 未完成req_DH_params
+	if len(authKeyMD.NewNonce) != 32 || len(authKeyMD.A) == 0 {
+		err := fmt.Errorf("process SetClient_DHParams - invalid state")
+		glog.Error(err)
+		return nil, err
+	}
+
 	bEncryptedData := []byte(request.EncryptedData)
 
 	// 创建aes和iv key
@@ -522,8 +662,13 @@ func (s *handshake) onSetClient_DHParams(state *zproto.HandshakeState, request *
 		return nil, err
 	}
 
-	// TODO(@benqi): 检查签名是否合法
-	dbuf := mtproto.NewDecodeBuf(decryptedData[24:])
+	if len(decryptedData) <= SHA_DIGEST_LENGTH+4 {
+		err := fmt.Errorf("process SetClient_DHParams - invalid encrypted_data")
+		glog.Error(err)
+		return nil, err
+	}
+
+	dbuf := mtproto.NewDecodeBuf(decryptedData[SHA_DIGEST_LENGTH+4:])
 	client_DHInnerData := mtproto.NewTLClient_DHInnerData()
 	// &TLClient_DHInnerData{}
 	err = client_DHInnerData.Decode(dbuf)
@@ -534,6 +679,14 @@ func (s *handshake) onSetClient_DHParams(state *zproto.HandshakeState, request *
 
 	glog.Info("processSetClient_DHParams - client_DHInnerData: ", client_DHInnerData.String())
 
+	// data_with_hash := SHA1(data) + data + (0-15 random bytes)
+	sha1Check := sha1.Sum(client_DHInnerData.Encode())
+	if !bytes.Equal(sha1Check[:], decryptedData[:SHA_DIGEST_LENGTH]) {
+		err := fmt.Errorf("process SetClient_DHParams - sha1Check error")
+		glog.Error(err)
+		return nil, err
+	}
+
 	//
 	if !bytes.Equal(client_DHInnerData.GetNonce(), authKeyMD.Nonce) {
 		err := fmt.Errorf("process SetClient_DHParams - Wrong client_DHInnerData's Nonce")
@@ -548,58 +701,91 @@ func (s *handshake) onSetClient_DHParams(state *zproto.HandshakeState, request *
 		return nil, err
 	}
 
+	// retry_id: 第一次为0, dh_gen_retry后为上一次auth_key的auth_key_aux_hash
+	var retryId int64
+	if len(authKeyMD.AuthKey) > 0 {
+		retryId = calcAuthKeyAuxHash(authKeyMD.AuthKey)
+	}
+	if client_DHInnerData.GetRetryId() != retryId {
+		err := fmt.Errorf("process SetClient_DHParams - Wrong client_DHInnerData's RetryId: %d (need %d)",
+			client_DHInnerData.GetRetryId(), retryId)
+		glog.Error(err)
+		return nil, err
+	}
+
+	dh := s.findDHParams(authKeyMD.P)
+	if dh == nil {
+		err := fmt.Errorf("process SetClient_DHParams - not found dh_prime")
+		glog.Error(err)
+		return nil, err
+	}
+
+	// g_b不合法, 客户端需要重新握手
+	bigIntGB := new(big.Int).SetBytes([]byte(client_DHInnerData.GetGB()))
+	if !crypto.CheckDHParams(bigIntGB, dh.bigIntP) {
+		glog.Errorf("process SetClient_DHParams - invalid g_b: %s", bytes2.HexDump([]byte(client_DHInnerData.GetGB())))
+		return s.dhGenFail(state, authKeyMD, authKeyMD.AuthKey), nil
+	}
+
 	bigIntA := new(big.Int).SetBytes(authKeyMD.A)
-	// bigIntP := new(big.Int).SetBytes(authKeyMD.P)
 
 	// hash_key
 	authKeyNum := new(big.Int)
-	authKeyNum.Exp(new(big.Int).SetBytes([]byte(client_DHInnerData.GetGB())), bigIntA, s.bigIntDH2048P)
+	authKeyNum.Exp(bigIntGB, bigIntA, dh.bigIntP)
 
 	authKey := make([]byte, 256)
-
-	// TODO(@benqi): dhGenRetry and dhGenFail
 	copy(authKey[256-len(authKeyNum.Bytes()):], authKeyNum.Bytes())
 
-	authKeyAuxHash := make([]byte, len(authKeyMD.NewNonce))
-	copy(authKeyAuxHash, authKeyMD.NewNonce)
-	authKeyAuxHash = append(authKeyAuxHash, byte(0x01))
-	sha1_d := sha1.Sum(authKey)
-	authKeyAuxHash = append(authKeyAuxHash, sha1_d[:]...)
-	sha1_e := sha1.Sum(authKeyAuxHash[:len(authKeyAuxHash)-12])
-	authKeyAuxHash = append(authKeyAuxHash, sha1_e[:]...)
-
-	// 至此key已经创建成功
-	authKeyId := int64(binary.LittleEndian.Uint64(authKeyAuxHash[len(authKeyMD.NewNonce)+1+12 : len(authKeyMD.NewNonce)+1+12+8]))
-
-	// TODO(@benqi): authKeyId生成后要检查在数据库里是否已经存在，有非常小的概率会碰撞
-	// 如果碰撞让客户端重新再来一轮
+	// 至此key已经创建成功, auth_key_id为SHA1(auth_key)的低64位
+	sha1AuthKey := sha1.Sum(authKey)
+	authKeyId := int64(binary.LittleEndian.Uint64(sha1AuthKey[12:]))
 
+	// authKeyId生成后要检查在数据库里是否已经存在，有非常小的概率会碰撞
+	// 如果碰撞让客户端使用新的b重新再来一轮
 	authKeyMD.AuthKeyId = authKeyId
 	authKeyMD.AuthKey = authKey
 
 	state.Ctx, _ = proto.Marshal(authKeyMD)
 
-	if s.saveAuthKeyInfo(authKeyMD) {
-		dhGenOk := &mtproto.TLDhGenOk{Data2: &mtproto.SetClient_DHParamsAnswer_Data{
-			Nonce:         authKeyMD.Nonce,
-			ServerNonce:   authKeyMD.ServerNonce,
-			NewNonceHash1: calcNewNonceHash(authKeyMD.NewNonce, authKey, 0x01),
-		}}
-
-		glog.Infof("onSetClient_DHParams - metadata: {%v}, reply: %s", authKeyMD, logger.JsonDebugData(dhGenOk))
-		return dhGenOk.To_SetClient_DHParamsAnswer(), nil
-	} else {
-		// TODO(@benqi): dhGenFail
+	if s.checkAuthKeyIdExists(authKeyId) {
+		glog.Errorf("onSetClient_DHParams - auth_key_id collision: %d", authKeyId)
 		dhGenRetry := &mtproto.TLDhGenRetry{Data2: &mtproto.SetClient_DHParamsAnswer_Data{
 			Nonce:         authKeyMD.Nonce,
 			ServerNonce:   authKeyMD.ServerNonce,
-			// NewNonceHash1: authKeyAuxHash[len(authKeyAuxHash)-16 : len(authKeyAuxHash)],
 			NewNonceHash2: calcNewNonceHash(authKeyMD.NewNonce, authKey, 0x02),
 		}}
 
 		glog.Infof("onSetClient_DHParams - metadata: {%v}, reply: %s", authKeyMD, logger.JsonDebugData(dhGenRetry))
 		return dhGenRetry.To_SetClient_DHParamsAnswer(), nil
 	}
+
+	if !s.saveAuthKeyInfo(authKeyMD) {
+		return s.dhGenFail(state, authKeyMD, authKey), nil
+	}
+
+	dhGenOk := &mtproto.TLDhGenOk{Data2: &mtproto.SetClient_DHParamsAnswer_Data{
+		Nonce:         authKeyMD.Nonce,
+		ServerNonce:   authKeyMD.ServerNonce,
+		NewNonceHash1: calcNewNonceHash(authKeyMD.NewNonce, authKey, 0x01),
+	}}
+
+	glog.Infof("onSetClient_DHParams - metadata: {%v}, reply: %s", authKeyMD, logger.JsonDebugData(dhGenOk))
+	return dhGenOk.To_SetClient_DHParamsAnswer(), nil
+}
+
+// dh_gen_fail后客户端需要从req_pq重新开始
+func (s *handshake) dhGenFail(state *zproto.HandshakeState, md *mtproto.AuthKeyMetadata, authKey []byte) *mtproto.SetClient_DHParamsAnswer {
+	dhGenFail := &mtproto.TLDhGenFail{Data2: &mtproto.SetClient_DHParamsAnswer_Data{
+		Nonce:         md.Nonce,
+		ServerNonce:   md.ServerNonce,
+		NewNonceHash3: calcNewNonceHash(md.NewNonce, authKey, 0x03),
+	}}
+
+	// 清空状态, 不允许继续set_client_DH_params
+	state.Ctx, _ = proto.Marshal(&mtproto.AuthKeyMetadata{})
+
+	glog.Infof("onSetClient_DHParams - metadata: {%v}, reply: %s", md, logger.JsonDebugData(dhGenFail))
+	return dhGenFail.To_SetClient_DHParamsAnswer()
 }
 
 // msgs_ack#62d6b459 msg_ids:Vector<long> = MsgsAck;
@@ -645,23 +831,45 @@ func (s *handshake) saveAuthKeyInfo(md *mtproto.AuthKeyMetadata) bool {
 		AuthKey:    md.AuthKey,
 		FutureSalt: serverSalt.To_FutureSalt(),
 	}}
+	if md.ExpiresIn > 0 {
+		// 临时key, 需要通过auth.bindTempAuthKey绑定到永久key
+		authKeyInfo.SetAuthKeyType(mtproto.AUTH_KEY_TYPE_TEMP)
+		authKeyInfo.SetExpiresAt(now + md.ExpiresIn)
+	}
 
 	request := &mtproto.TLSessionSetAuthKey{
 		AuthKey: authKeyInfo.To_AuthKeyInfo(),
 	}
 	r, err := s.authSessionRpcClient.SessionSetAuthKey(context.Background(), request)
-
 	if err != nil {
 		glog.Error(err)
+		return false
 	}
 
 	if !mtproto.FromBool(r) {
 		glog.Error("saveAuthKeyInfo not successful - ", md.AuthKeyId)
+		return false
 	}
 
 	return true
 }
 
+// 查询auth_keys里是否已存在authKeyId
+func (s *handshake) checkAuthKeyIdExists(authKeyId int64) bool {
+	keyInfo, err := s.authSessionRpcClient.SessionQueryAuthKey(context.Background(), &mtproto.TLSessionQueryAuthKey{AuthKeyId: authKeyId})
+	if err != nil {
+		// 未找到
+		return false
+	}
+	return len(keyInfo.GetData2().GetAuthKey()) > 0
+}
+
+// auth_key_aux_hash为SHA1(auth_key)的高64位
+func calcAuthKeyAuxHash(authKey []byte) int64 {
+	sha1D := sha1.Sum(authKey)
+	return int64(binary.LittleEndian.Uint64(sha1D[:8]))
+}
+
 func calcNewNonceHash(newNonce, authKey []byte, b byte) []byte {
 	authKeyAuxHash := make([]byte, len(newNonce))
 	copy(authKeyAuxHash, newNonce)
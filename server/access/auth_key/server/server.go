@@ -18,19 +18,26 @@
 package server
 
 import (
+	_ "expvar"
 	"fmt"
 	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/crypto"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/mysql_client"
 	"github.com/nebulaim/telegramd/baselib/net2"
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
 	"github.com/nebulaim/telegramd/server/access/auth_key/dal/dao"
+	"net/http"
 	"time"
 )
 
 type AuthKeyServer struct {
+	rsaKeys              []*crypto.RSACryptor
+	dhParamsList         []*dhParams
 	handshake            *handshake
+	handshakeLimit       *rate_limit.IPLimiter
 	server               *zproto.ZProtoServer
 	authSessionRpcClient mtproto.RPCSessionClient
 }
@@ -54,6 +61,24 @@ func (s *AuthKeyServer) Initialize() error {
 	// 初始化redis_dao、mysql_dao
 	dao.InstallMysqlDAOManager(mysql_client.GetMysqlClientManager())
 
+	s.handshakeLimit, err = rate_limit.NewIPLimiter("handshake", Conf.HandshakeLimit)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
+	s.rsaKeys, err = loadRSAKeys(Conf.RsaKeys)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
+	s.dhParamsList, err = loadDHParams(Conf.DhPrimes)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
 	s.server = zproto.NewZProtoServer(Conf.Server, s)
 	// s.rpcServer = grpc_util.NewRpcServer(Conf.RpcServer.Addr, &Conf.RpcServer.RpcDiscovery)
 
@@ -63,9 +88,15 @@ func (s *AuthKeyServer) Initialize() error {
 func (s *AuthKeyServer) RunLoop() {
 	c, _ := grpc_util.NewRPCClient(&Conf.AuthSessionRpcClient)
 	s.authSessionRpcClient = mtproto.NewRPCSessionClient(c.GetClientConn())
-	s.handshake = newHandshake(s.authSessionRpcClient)
+	s.handshake = newHandshake(s.rsaKeys, s.dhParamsList, s.authSessionRpcClient, s.handshakeLimit)
 
 	go s.server.Serve()
+
+	if Conf.DebugAddr != "" {
+		go func() {
+			glog.Error(http.ListenAndServe(Conf.DebugAddr, nil))
+		}()
+	}
 }
 
 func (s *AuthKeyServer) Destroy() {
@@ -90,10 +121,22 @@ func (s *AuthKeyServer) OnServerMessageDataArrived(conn *net2.TcpConnection, md
 		return err
 	}
 
-	hrsp, err := s.handshake.onHandshake(conn, hmsg)
+	hrsp, err := s.handshake.onHandshake(conn, md, hmsg)
 	if err != nil {
 		glog.Error(err)
-		return nil
+		switch err {
+		case errHandshakeFlood:
+			return sendTransportError(conn, md, hmsg.SessionId, mtproto.TRANSPORT_ERROR_FLOOD)
+		case errHandshakeKeyNotFound:
+			return sendTransportError(conn, md, hmsg.SessionId, mtproto.TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND)
+		default:
+			// 无法处理的握手消息, 通知frontend关闭连接
+			hmsg.State.ResState = zproto.RES_STATE_ERROR
+			return zproto.SendMessageByConn(conn, md, &zproto.ZProtoHandshakeMessage{
+				SessionId: hmsg.SessionId,
+				State:     hmsg.State,
+			})
+		}
 	}
 
 	// Fix onMsgAck return nil bug.
@@ -107,3 +150,11 @@ func (s *AuthKeyServer) OnServerMessageDataArrived(conn *net2.TcpConnection, md
 func (s *AuthKeyServer) OnServerConnectionClosed(conn *net2.TcpConnection) {
 	glog.Infof("onConnectionClosed - %v", conn.RemoteAddr())
 }
+
+// 通知frontend返回传输层错误
+func sendTransportError(conn *net2.TcpConnection, md *zproto.ZProtoMetadata, sessionId uint64, errorCode int32) error {
+	return zproto.SendMessageByConn(conn, md, &zproto.ZProtoTransportError{
+		SessionId: sessionId,
+		ErrorCode: errorCode,
+	})
+}
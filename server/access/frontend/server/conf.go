@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/BurntSushi/toml"
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
 )
@@ -31,19 +32,19 @@ var (
 )
 
 type frontendConfig struct {
-	ServerId   int32 // 服务器ID
-	Server80   *mtproto.MTProtoServerConfig
-	Server443  *mtproto.MTProtoServerConfig
-	Server5222 *mtproto.MTProtoServerConfig
-	Clients    *zproto.ZProtoClientConfig
+	ServerId  int32                      // 服务器ID
+	DebugAddr string                     // 不为空时在此地址提供/debug/vars统计数据
+	ConnLimit rate_limit.IPLimiterConfig // 按来源IP限制新建连接的频率
+	Listeners []*mtproto.MTProtoServerConfig
+	Clients   *zproto.ZProtoClientConfig
 }
 
 func (c *frontendConfig) String() string {
-	return fmt.Sprintf("{server_id: %d, server80: %v. server443: %v, server5222: %v, clients: %v}",
+	return fmt.Sprintf("{server_id: %d, debug_addr: %s, conn_limit: %v, listeners: %v, clients: %v}",
 		c.ServerId,
-		c.Server80,
-		c.Server443,
-		c.Server5222,
+		c.DebugAddr,
+		&c.ConnLimit,
+		c.Listeners,
 		c.Clients)
 }
 
@@ -51,8 +52,32 @@ func InitializeConfig() (err error) {
 	_, err = toml.DecodeFile(confPath, &Conf)
 	if err != nil {
 		err = fmt.Errorf("decode file %s error: %v", confPath, err)
+		return
 	}
-	return
+
+	return checkListeners(Conf.Listeners)
+}
+
+// 监听的序号保存在session_id的高8位，名字用于从连接找到监听
+func checkListeners(listeners []*mtproto.MTProtoServerConfig) error {
+	if len(listeners) == 0 {
+		return fmt.Errorf("listeners is empty")
+	}
+	if len(listeners) > maxListeners {
+		return fmt.Errorf("too many listeners: %d (max %d)", len(listeners), maxListeners)
+	}
+
+	names := make(map[string]bool, len(listeners))
+	for _, l := range listeners {
+		if l.Server.Name == "" {
+			return fmt.Errorf("listener name is empty: %s", l.Server.Addr)
+		}
+		if names[l.Server.Name] {
+			return fmt.Errorf("duplicate listener name: %s", l.Server.Name)
+		}
+		names[l.Server.Name] = true
+	}
+	return nil
 }
 
 func init() {
@@ -18,17 +18,26 @@
 package server
 
 import (
+	_ "expvar"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/base"
 	"github.com/nebulaim/telegramd/baselib/net2"
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
 	"github.com/nebulaim/telegramd/service/idgen/client"
+	"net/http"
 	"sync"
 	"time"
 )
 
+// session_id的高8位为监听的序号
+const (
+	listenerIdShift = 56
+	maxListeners    = 1 << (64 - listenerIdShift)
+)
+
 type handshakeState struct {
 	state    int    // 状态
 	resState int    // 后端握手返回的结果
@@ -74,11 +83,11 @@ func (ctx *connContext) encryptedMessageAble() bool {
 }
 
 type FrontendServer struct {
-	idgen      idgen.UUIDGen
-	server80   *mtproto.MTProtoServer
-	server443  *mtproto.MTProtoServer
-	server5222 *mtproto.MTProtoServer
-	client     *zproto.ZProtoClient
+	idgen     idgen.UUIDGen
+	servers   []*mtproto.MTProtoServer
+	serverIds map[string]uint64 // name -> 序号
+	connLimit *rate_limit.IPLimiter
+	client    *zproto.ZProtoClient
 }
 
 func NewFrontendServer() *FrontendServer {
@@ -98,10 +107,19 @@ func (s *FrontendServer) Initialize() error {
 	// idgen
 	s.idgen, _ = idgen.NewUUIDGen("snowflake", base.Int32ToString(Conf.ServerId))
 
+	s.connLimit, err = rate_limit.NewIPLimiter("frontend_conn", Conf.ConnLimit)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
 	// mtproto_server
-	s.server80 = mtproto.NewMTProtoServer(Conf.Server80, s)
-	s.server443 = mtproto.NewMTProtoServer(Conf.Server443, s)
-	s.server5222 = mtproto.NewMTProtoServer(Conf.Server5222, s)
+	s.servers = make([]*mtproto.MTProtoServer, 0, len(Conf.Listeners))
+	s.serverIds = make(map[string]uint64, len(Conf.Listeners))
+	for i, l := range Conf.Listeners {
+		s.servers = append(s.servers, mtproto.NewMTProtoServer(l, s))
+		s.serverIds[l.Server.Name] = uint64(i)
+	}
 
 	// client
 	s.client = zproto.NewZProtoClient("zproto", Conf.Clients, s)
@@ -109,16 +127,22 @@ func (s *FrontendServer) Initialize() error {
 }
 
 func (s *FrontendServer) RunLoop() {
-	s.server80.Serve()
-	s.server443.Serve()
-	s.server5222.Serve()
+	for _, server := range s.servers {
+		server.Serve()
+	}
 	s.client.Serve()
+
+	if Conf.DebugAddr != "" {
+		go func() {
+			glog.Error(http.ListenAndServe(Conf.DebugAddr, nil))
+		}()
+	}
 }
 
 func (s *FrontendServer) Destroy() {
-	s.server80.Stop()
-	s.server443.Stop()
-	s.server5222.Stop()
+	for _, server := range s.servers {
+		server.Stop()
+	}
 	s.client.Stop()
 }
 
@@ -139,6 +163,13 @@ func (s *FrontendServer) newMetadata(conn *net2.TcpConnection) *zproto.ZProtoMet
 ////////////////////////////////////////////////////////////////////////////////////////////////////
 // MTProtoServerCallback
 func (s *FrontendServer) OnServerNewConnection(conn *net2.TcpConnection) {
+	// 此时还不知道传输协议, 无法返回-429, 直接关闭
+	if !s.connLimit.AllowAddr(conn.RemoteAddr().String()) {
+		glog.Warningf("onServerNewConnection - rate limited, close: {peer: %s}", conn)
+		conn.Close()
+		return
+	}
+
 	conn.Context = &connContext{
 		state: zproto.STATE_CONNECTED2,
 		md: &zproto.ZProtoMetadata{
@@ -160,7 +191,11 @@ func (s *FrontendServer) OnServerMessageDataArrived(conn *net2.TcpConnection, ms
 	md := s.newMetadata(conn)
 	glog.Infof("onServerMessageDataArrived - receive data: {peer: %s, md: %s, msg: %s}", conn, md, msg)
 
-	ctx, _ := conn.Context.(*connContext)
+	ctx, ok := conn.Context.(*connContext)
+	if !ok {
+		// 被限流关闭的连接
+		return fmt.Errorf("conn closed: %s", conn)
+	}
 
 	var err error
 	if msg.AuthKeyId() == 0 {
@@ -175,7 +210,8 @@ func (s *FrontendServer) OnServerMessageDataArrived(conn *net2.TcpConnection, ms
 		if !ctx.encryptedMessageAble() {
 			err = fmt.Errorf("invalid state: {state: %d, handshakeState: {%v}}", ctx.state, ctx.handshakeState)
 			glog.Errorf("process msg error: {%v} - {peer: %s, md: %s, msg: %s}", err, conn, md, msg)
-			conn.Close()
+			// -404写出去后再关闭, 否则还在发送队列里就被丢掉了
+			conn.SendAndClose(mtproto.NewMTPTransportError(mtproto.TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND))
 		} else {
 			err = s.onServerEncryptedRawMessage(ctx, conn, md, msg)
 		}
@@ -203,6 +239,10 @@ func (s *FrontendServer) OnClientMessageArrived(client *net2.TcpClient, md *zpro
 		err = s.onClientHandshakeMessage(client, md, msg.(*zproto.ZProtoHandshakeMessage))
 	case *zproto.ZProtoSessionData:
 		err = s.onClientSessionData(client, md, msg.(*zproto.ZProtoSessionData))
+	case *zproto.ZProtoQuickAck:
+		err = s.onClientQuickAck(client, md, msg.(*zproto.ZProtoQuickAck))
+	case *zproto.ZProtoTransportError:
+		err = s.onClientTransportError(client, md, msg.(*zproto.ZProtoTransportError))
 	default:
 		err = fmt.Errorf("invalid msg: %v", msg)
 		glog.Errorf("onClientMessageArrived - invalid msg: peer(%s), zmsg: {%v}",
@@ -237,9 +277,9 @@ func (s *FrontendServer) onClientHandshakeMessage(client *net2.TcpClient, md *zp
 	}
 
 	if handshake.State.ResState == zproto.RES_STATE_ERROR {
-		// TODO(@benqi): Close.
+		// 握手失败, auth_key已经不会再处理这个连接了
 		glog.Warning(" handshake.State.ResState error, connID = ", handshake.SessionId)
-		// conn.Close()
+		conn.Close()
 		return nil
 	} else {
 		ctx := conn.Context.(*connContext)
@@ -272,35 +312,46 @@ func (s *FrontendServer) onClientSessionData(client *net2.TcpClient, md *zproto.
 	return conn.Send(&mtproto.MTPRawMessage{Payload: sessData.MtpRawData})
 }
 
-func (s *FrontendServer) genSessionId(conn *net2.TcpConnection) uint64 {
-	var sid = conn.GetConnID()
-	if conn.Name() == "frontend443" {
-		// sid = sid | 0 << 60
-	} else if conn.Name() == "frontend80" {
-		sid = sid | 1<<60
-	} else if conn.Name() == "frontend5222" {
-		sid = sid | 2<<60
+func (s *FrontendServer) onClientQuickAck(client *net2.TcpClient, md *zproto.ZProtoMetadata, quickAck *zproto.ZProtoQuickAck) error {
+	conn := s.getConnBySessionID(quickAck.SessionId)
+	if conn == nil {
+		glog.Warning("conn closed, connID = ", quickAck.SessionId)
+		return nil
 	}
 
-	return sid
+	glog.Infof("onClientQuickAck - sendToClient to: {peer: %s, md: %s, quickAck: %s}",
+		conn,
+		md,
+		quickAck)
+	return conn.Send(mtproto.NewMTPQuickAckMessage(quickAck.Token))
+}
+
+func (s *FrontendServer) onClientTransportError(client *net2.TcpClient, md *zproto.ZProtoMetadata, transportError *zproto.ZProtoTransportError) error {
+	conn := s.getConnBySessionID(transportError.SessionId)
+	if conn == nil {
+		glog.Warning("conn closed, connID = ", transportError.SessionId)
+		return nil
+	}
+
+	glog.Infof("onClientTransportError - sendToClient to: {peer: %s, md: %s, transportError: %s}",
+		conn,
+		md,
+		transportError)
+	return conn.Send(mtproto.NewMTPTransportError(transportError.ErrorCode))
+}
+
+func (s *FrontendServer) genSessionId(conn *net2.TcpConnection) uint64 {
+	return conn.GetConnID() | s.serverIds[conn.Name()]<<listenerIdShift
 }
 
 func (s *FrontendServer) getConnBySessionID(id uint64) *net2.TcpConnection {
-	//
-	var server *mtproto.MTProtoServer
-	sid := id >> 60
-	if sid == 0 {
-		server = s.server443
-	} else if sid == 1 {
-		server = s.server80
-	} else if sid == 2 {
-		server = s.server5222
-	} else {
+	sid := id >> listenerIdShift
+	if sid >= uint64(len(s.servers)) {
 		return nil
 	}
 
-	id = id & 0xffffffffffffff
-	return server.GetConnection(id)
+	id = id & (1<<listenerIdShift - 1)
+	return s.servers[sid].GetConnection(id)
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -333,6 +384,7 @@ func (s *FrontendServer) onServerEncryptedRawMessage(ctx *connContext, conn *net
 	sessData := &zproto.ZProtoSessionData{
 		ConnType:   mmsg.ConnType(),
 		SessionId:  s.genSessionId(conn),
+		QuickAck:   mmsg.NeedQuickAck(),
 		MtpRawData: mmsg.Payload,
 	}
 
@@ -0,0 +1,588 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: session_admin.proto
+
+package session_admin
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// user_id和auth_key_id都为0时返回全部, 每个节点最多返回1000个auth_key
+type ListSessionsRequest struct {
+	UserId               int32    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AuthKeyId            int64    `protobuf:"varint,2,opt,name=auth_key_id,json=authKeyId,proto3" json:"auth_key_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSessionsRequest) Reset()         { *m = ListSessionsRequest{} }
+func (m *ListSessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsRequest) ProtoMessage()    {}
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_session_admin_2a7cad0b29c749e8, []int{0}
+}
+func (m *ListSessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsRequest.Unmarshal(m, b)
+}
+func (m *ListSessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsRequest.Merge(dst, src)
+}
+func (m *ListSessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsRequest.Size(m)
+}
+func (m *ListSessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsRequest proto.InternalMessageInfo
+
+func (m *ListSessionsRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *ListSessionsRequest) GetAuthKeyId() int64 {
+	if m != nil {
+		return m.AuthKeyId
+	}
+	return 0
+}
+
+type SessionInfo struct {
+	SessionId int64  `protobuf:"varint,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	IsUpdates bool   `protobuf:"varint,2,opt,name=is_updates,json=isUpdates,proto3" json:"is_updates,omitempty"`
+	NextSeqNo uint32 `protobuf:"varint,3,opt,name=next_seq_no,json=nextSeqNo,proto3" json:"next_seq_no,omitempty"`
+	LastMsgId int64  `protobuf:"varint,4,opt,name=last_msg_id,json=lastMsgId,proto3" json:"last_msg_id,omitempty"`
+	// 待发送给客户端的消息
+	PendingMessages int32 `protobuf:"varint,5,opt,name=pending_messages,json=pendingMessages,proto3" json:"pending_messages,omitempty"`
+	// 还未转发给biz的rpc请求
+	RpcMessages int32 `protobuf:"varint,6,opt,name=rpc_messages,json=rpcMessages,proto3" json:"rpc_messages,omitempty"`
+	// 等待依赖完成的invokeAfterMsg(s)
+	InvokeAfterMessages int32 `protobuf:"varint,7,opt,name=invoke_after_messages,json=invokeAfterMessages,proto3" json:"invoke_after_messages,omitempty"`
+	// 已发送未确认的消息
+	UnackedMessages      int32    `protobuf:"varint,8,opt,name=unacked_messages,json=unackedMessages,proto3" json:"unacked_messages,omitempty"`
+	LastActive           int64    `protobuf:"varint,9,opt,name=last_active,json=lastActive,proto3" json:"last_active,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionInfo) Reset()         { *m = SessionInfo{} }
+func (m *SessionInfo) String() string { return proto.CompactTextString(m) }
+func (*SessionInfo) ProtoMessage()    {}
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_session_admin_2a7cad0b29c749e8, []int{1}
+}
+func (m *SessionInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionInfo.Unmarshal(m, b)
+}
+func (m *SessionInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionInfo.Marshal(b, m, deterministic)
+}
+func (dst *SessionInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionInfo.Merge(dst, src)
+}
+func (m *SessionInfo) XXX_Size() int {
+	return xxx_messageInfo_SessionInfo.Size(m)
+}
+func (m *SessionInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionInfo proto.InternalMessageInfo
+
+func (m *SessionInfo) GetSessionId() int64 {
+	if m != nil {
+		return m.SessionId
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetIsUpdates() bool {
+	if m != nil {
+		return m.IsUpdates
+	}
+	return false
+}
+
+func (m *SessionInfo) GetNextSeqNo() uint32 {
+	if m != nil {
+		return m.NextSeqNo
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetLastMsgId() int64 {
+	if m != nil {
+		return m.LastMsgId
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetPendingMessages() int32 {
+	if m != nil {
+		return m.PendingMessages
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetRpcMessages() int32 {
+	if m != nil {
+		return m.RpcMessages
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetInvokeAfterMessages() int32 {
+	if m != nil {
+		return m.InvokeAfterMessages
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetUnackedMessages() int32 {
+	if m != nil {
+		return m.UnackedMessages
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetLastActive() int64 {
+	if m != nil {
+		return m.LastActive
+	}
+	return 0
+}
+
+type AuthKeySessions struct {
+	AuthKeyId     int64 `protobuf:"varint,1,opt,name=auth_key_id,json=authKeyId,proto3" json:"auth_key_id,omitempty"`
+	AuthKeyType   int32 `protobuf:"varint,2,opt,name=auth_key_type,json=authKeyType,proto3" json:"auth_key_type,omitempty"`
+	PermAuthKeyId int64 `protobuf:"varint,3,opt,name=perm_auth_key_id,json=permAuthKeyId,proto3" json:"perm_auth_key_id,omitempty"`
+	UserId        int32 `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Layer         int32 `protobuf:"varint,5,opt,name=layer,proto3" json:"layer,omitempty"`
+	// 已转发给biz还未返回的rpc请求批次
+	RpcPendings int32 `protobuf:"varint,6,opt,name=rpc_pendings,json=rpcPendings,proto3" json:"rpc_pendings,omitempty"`
+	// 没有updates连接时缓存的推送
+	SyncMessages         int32          `protobuf:"varint,7,opt,name=sync_messages,json=syncMessages,proto3" json:"sync_messages,omitempty"`
+	LastActive           int64          `protobuf:"varint,8,opt,name=last_active,json=lastActive,proto3" json:"last_active,omitempty"`
+	Sessions             []*SessionInfo `protobuf:"bytes,9,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AuthKeySessions) Reset()         { *m = AuthKeySessions{} }
+func (m *AuthKeySessions) String() string { return proto.CompactTextString(m) }
+func (*AuthKeySessions) ProtoMessage()    {}
+func (*AuthKeySessions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_session_admin_2a7cad0b29c749e8, []int{2}
+}
+func (m *AuthKeySessions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthKeySessions.Unmarshal(m, b)
+}
+func (m *AuthKeySessions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthKeySessions.Marshal(b, m, deterministic)
+}
+func (dst *AuthKeySessions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthKeySessions.Merge(dst, src)
+}
+func (m *AuthKeySessions) XXX_Size() int {
+	return xxx_messageInfo_AuthKeySessions.Size(m)
+}
+func (m *AuthKeySessions) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthKeySessions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthKeySessions proto.InternalMessageInfo
+
+func (m *AuthKeySessions) GetAuthKeyId() int64 {
+	if m != nil {
+		return m.AuthKeyId
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetAuthKeyType() int32 {
+	if m != nil {
+		return m.AuthKeyType
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetPermAuthKeyId() int64 {
+	if m != nil {
+		return m.PermAuthKeyId
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetLayer() int32 {
+	if m != nil {
+		return m.Layer
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetRpcPendings() int32 {
+	if m != nil {
+		return m.RpcPendings
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetSyncMessages() int32 {
+	if m != nil {
+		return m.SyncMessages
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetLastActive() int64 {
+	if m != nil {
+		return m.LastActive
+	}
+	return 0
+}
+
+func (m *AuthKeySessions) GetSessions() []*SessionInfo {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+type ListSessionsResponse struct {
+	ServerId             int32              `protobuf:"varint,1,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	AuthKeys             []*AuthKeySessions `protobuf:"bytes,2,rep,name=auth_keys,json=authKeys,proto3" json:"auth_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ListSessionsResponse) Reset()         { *m = ListSessionsResponse{} }
+func (m *ListSessionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsResponse) ProtoMessage()    {}
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_session_admin_2a7cad0b29c749e8, []int{3}
+}
+func (m *ListSessionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionsResponse.Unmarshal(m, b)
+}
+func (m *ListSessionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListSessionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionsResponse.Merge(dst, src)
+}
+func (m *ListSessionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSessionsResponse.Size(m)
+}
+func (m *ListSessionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionsResponse proto.InternalMessageInfo
+
+func (m *ListSessionsResponse) GetServerId() int32 {
+	if m != nil {
+		return m.ServerId
+	}
+	return 0
+}
+
+func (m *ListSessionsResponse) GetAuthKeys() []*AuthKeySessions {
+	if m != nil {
+		return m.AuthKeys
+	}
+	return nil
+}
+
+// auth_key_id和session_id都不为0时关闭单个session;
+// 只有auth_key_id时关闭整个auth_key, 客户端收到-404;
+// 只有user_id时关闭该用户除except_auth_key_id外的所有auth_key
+type CloseSessionsRequest struct {
+	UserId               int32    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AuthKeyId            int64    `protobuf:"varint,2,opt,name=auth_key_id,json=authKeyId,proto3" json:"auth_key_id,omitempty"`
+	SessionId            int64    `protobuf:"varint,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	ExceptAuthKeyId      int64    `protobuf:"varint,4,opt,name=except_auth_key_id,json=exceptAuthKeyId,proto3" json:"except_auth_key_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloseSessionsRequest) Reset()         { *m = CloseSessionsRequest{} }
+func (m *CloseSessionsRequest) String() string { return proto.CompactTextString(m) }
+func (*CloseSessionsRequest) ProtoMessage()    {}
+func (*CloseSessionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_session_admin_2a7cad0b29c749e8, []int{4}
+}
+func (m *CloseSessionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloseSessionsRequest.Unmarshal(m, b)
+}
+func (m *CloseSessionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloseSessionsRequest.Marshal(b, m, deterministic)
+}
+func (dst *CloseSessionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloseSessionsRequest.Merge(dst, src)
+}
+func (m *CloseSessionsRequest) XXX_Size() int {
+	return xxx_messageInfo_CloseSessionsRequest.Size(m)
+}
+func (m *CloseSessionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloseSessionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloseSessionsRequest proto.InternalMessageInfo
+
+func (m *CloseSessionsRequest) GetUserId() int32 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *CloseSessionsRequest) GetAuthKeyId() int64 {
+	if m != nil {
+		return m.AuthKeyId
+	}
+	return 0
+}
+
+func (m *CloseSessionsRequest) GetSessionId() int64 {
+	if m != nil {
+		return m.SessionId
+	}
+	return 0
+}
+
+func (m *CloseSessionsRequest) GetExceptAuthKeyId() int64 {
+	if m != nil {
+		return m.ExceptAuthKeyId
+	}
+	return 0
+}
+
+type CloseSessionsResponse struct {
+	Closed               int32    `protobuf:"varint,1,opt,name=closed,proto3" json:"closed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CloseSessionsResponse) Reset()         { *m = CloseSessionsResponse{} }
+func (m *CloseSessionsResponse) String() string { return proto.CompactTextString(m) }
+func (*CloseSessionsResponse) ProtoMessage()    {}
+func (*CloseSessionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_session_admin_2a7cad0b29c749e8, []int{5}
+}
+func (m *CloseSessionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CloseSessionsResponse.Unmarshal(m, b)
+}
+func (m *CloseSessionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CloseSessionsResponse.Marshal(b, m, deterministic)
+}
+func (dst *CloseSessionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CloseSessionsResponse.Merge(dst, src)
+}
+func (m *CloseSessionsResponse) XXX_Size() int {
+	return xxx_messageInfo_CloseSessionsResponse.Size(m)
+}
+func (m *CloseSessionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CloseSessionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CloseSessionsResponse proto.InternalMessageInfo
+
+func (m *CloseSessionsResponse) GetClosed() int32 {
+	if m != nil {
+		return m.Closed
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ListSessionsRequest)(nil), "session_admin.ListSessionsRequest")
+	proto.RegisterType((*SessionInfo)(nil), "session_admin.SessionInfo")
+	proto.RegisterType((*AuthKeySessions)(nil), "session_admin.AuthKeySessions")
+	proto.RegisterType((*ListSessionsResponse)(nil), "session_admin.ListSessionsResponse")
+	proto.RegisterType((*CloseSessionsRequest)(nil), "session_admin.CloseSessionsRequest")
+	proto.RegisterType((*CloseSessionsResponse)(nil), "session_admin.CloseSessionsResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RPCSessionAdminClient is the client API for RPCSessionAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RPCSessionAdminClient interface {
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	CloseSessions(ctx context.Context, in *CloseSessionsRequest, opts ...grpc.CallOption) (*CloseSessionsResponse, error)
+}
+
+type rPCSessionAdminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRPCSessionAdminClient(cc *grpc.ClientConn) RPCSessionAdminClient {
+	return &rPCSessionAdminClient{cc}
+}
+
+func (c *rPCSessionAdminClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, "/session_admin.RPCSessionAdmin/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCSessionAdminClient) CloseSessions(ctx context.Context, in *CloseSessionsRequest, opts ...grpc.CallOption) (*CloseSessionsResponse, error) {
+	out := new(CloseSessionsResponse)
+	err := c.cc.Invoke(ctx, "/session_admin.RPCSessionAdmin/CloseSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RPCSessionAdminServer is the server API for RPCSessionAdmin service.
+type RPCSessionAdminServer interface {
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	CloseSessions(context.Context, *CloseSessionsRequest) (*CloseSessionsResponse, error)
+}
+
+func RegisterRPCSessionAdminServer(s *grpc.Server, srv RPCSessionAdminServer) {
+	s.RegisterService(&_RPCSessionAdmin_serviceDesc, srv)
+}
+
+func _RPCSessionAdmin_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCSessionAdminServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session_admin.RPCSessionAdmin/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCSessionAdminServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCSessionAdmin_CloseSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCSessionAdminServer).CloseSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/session_admin.RPCSessionAdmin/CloseSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCSessionAdminServer).CloseSessions(ctx, req.(*CloseSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RPCSessionAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "session_admin.RPCSessionAdmin",
+	HandlerType: (*RPCSessionAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _RPCSessionAdmin_ListSessions_Handler,
+		},
+		{
+			MethodName: "CloseSessions",
+			Handler:    _RPCSessionAdmin_CloseSessions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "session_admin.proto",
+}
+
+func init() { proto.RegisterFile("session_admin.proto", fileDescriptor_session_admin_2a7cad0b29c749e8) }
+
+var fileDescriptor_session_admin_2a7cad0b29c749e8 = []byte{
+	// 596 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0x95, 0x93, 0x26, 0xb5, 0x27, 0xb5, 0x52, 0xb6, 0x2d, 0x58, 0x45, 0x94, 0xe0, 0x22, 0x91,
+	0x0a, 0x29, 0x88, 0x22, 0x71, 0xe1, 0x94, 0xf6, 0x82, 0x05, 0xad, 0x22, 0x17, 0x84, 0xc4, 0xc5,
+	0x72, 0xed, 0x69, 0xb0, 0x9a, 0xac, 0x5d, 0x8f, 0x5d, 0xd5, 0xdf, 0xc2, 0xff, 0x70, 0xe4, 0xce,
+	0xdf, 0xa0, 0xf5, 0x6e, 0x1c, 0xdb, 0x2a, 0x3d, 0x71, 0xdc, 0x37, 0x6f, 0xdf, 0xce, 0xbc, 0x99,
+	0x59, 0xd8, 0x21, 0x24, 0x8a, 0x62, 0xee, 0xf9, 0xe1, 0x32, 0xe2, 0x93, 0x24, 0x8d, 0xb3, 0x98,
+	0x99, 0x0d, 0xd0, 0x3e, 0x87, 0x9d, 0xcf, 0x11, 0x65, 0x17, 0x12, 0x24, 0x17, 0x6f, 0x72, 0xa4,
+	0x8c, 0x3d, 0x81, 0xcd, 0x9c, 0x30, 0xf5, 0xa2, 0xd0, 0xd2, 0x46, 0xda, 0xb8, 0xe7, 0xf6, 0xc5,
+	0xd1, 0x09, 0xd9, 0x01, 0x0c, 0xfc, 0x3c, 0xfb, 0xe1, 0x5d, 0x63, 0x21, 0x82, 0x9d, 0x91, 0x36,
+	0xee, 0xba, 0x86, 0x80, 0x3e, 0x61, 0xe1, 0x84, 0xf6, 0x9f, 0x0e, 0x0c, 0x94, 0x98, 0xc3, 0xaf,
+	0x62, 0xf6, 0x0c, 0x60, 0xf5, 0xa0, 0xd2, 0xea, 0xba, 0x86, 0x42, 0x9c, 0x50, 0x84, 0x23, 0xf2,
+	0xf2, 0x24, 0xf4, 0x33, 0xa4, 0x52, 0x4d, 0x77, 0x8d, 0x88, 0xbe, 0x4a, 0x40, 0xbc, 0xc6, 0xf1,
+	0x2e, 0xf3, 0x08, 0x6f, 0x3c, 0x1e, 0x5b, 0xdd, 0x91, 0x36, 0x36, 0x5d, 0x43, 0x40, 0x17, 0x78,
+	0x73, 0x1e, 0x8b, 0xf8, 0xc2, 0xa7, 0xcc, 0x5b, 0xd2, 0x5c, 0xc8, 0x6f, 0x48, 0x79, 0x01, 0x9d,
+	0xd1, 0xdc, 0x09, 0xd9, 0x11, 0x6c, 0x27, 0xc8, 0xc3, 0x88, 0xcf, 0xbd, 0x25, 0x12, 0xf9, 0x73,
+	0x24, 0xab, 0x57, 0xd6, 0x33, 0x54, 0xf8, 0x99, 0x82, 0xd9, 0x0b, 0xd8, 0x4a, 0x93, 0x60, 0x4d,
+	0xeb, 0x97, 0xb4, 0x41, 0x9a, 0x04, 0x15, 0xe5, 0x18, 0xf6, 0x22, 0x7e, 0x1b, 0x5f, 0xa3, 0xe7,
+	0x5f, 0x65, 0x98, 0xae, 0xb9, 0x9b, 0x25, 0x77, 0x47, 0x06, 0xa7, 0x22, 0x56, 0xdd, 0x39, 0x82,
+	0xed, 0x9c, 0xfb, 0xc1, 0x35, 0x86, 0x6b, 0xba, 0x2e, 0x33, 0x50, 0x78, 0x45, 0x7d, 0xae, 0x8a,
+	0xf1, 0x83, 0x2c, 0xba, 0x45, 0xcb, 0x28, 0x8b, 0x01, 0x01, 0x4d, 0x4b, 0xc4, 0xfe, 0xdd, 0x81,
+	0xe1, 0x54, 0x3a, 0xbd, 0xea, 0x57, 0xbb, 0x1f, 0x5a, 0xab, 0x1f, 0xcc, 0x06, 0xb3, 0x8a, 0x67,
+	0x45, 0x82, 0xa5, 0xc7, 0x3d, 0x77, 0xa0, 0x18, 0x5f, 0x8a, 0x04, 0xd9, 0x2b, 0xe1, 0x52, 0xba,
+	0xf4, 0xea, 0x42, 0xdd, 0x52, 0xc8, 0x14, 0xf8, 0xb4, 0x12, 0xab, 0x4d, 0xc5, 0x46, 0x63, 0x2a,
+	0x76, 0xa1, 0xb7, 0xf0, 0x0b, 0x4c, 0x95, 0xb9, 0xf2, 0xb0, 0xb2, 0x54, 0x39, 0x5d, 0xb7, 0x74,
+	0xa6, 0x20, 0x76, 0x08, 0x26, 0x15, 0x3c, 0x68, 0x5b, 0xb9, 0x25, 0xc0, 0x7f, 0x19, 0xa3, 0xb7,
+	0x8d, 0x61, 0xef, 0x41, 0x57, 0x23, 0x45, 0x96, 0x31, 0xea, 0x8e, 0x07, 0xc7, 0xfb, 0x93, 0xe6,
+	0xec, 0xd7, 0x46, 0xd2, 0xad, 0xb8, 0x76, 0x02, 0xbb, 0xcd, 0xe1, 0xa7, 0x24, 0xe6, 0x84, 0xec,
+	0x29, 0x18, 0x84, 0xe9, 0x6d, 0x7d, 0xfe, 0x75, 0x09, 0x38, 0x21, 0xfb, 0x00, 0xc6, 0xca, 0x28,
+	0x31, 0xb1, 0xe2, 0xb5, 0x83, 0xd6, 0x6b, 0xad, 0x26, 0xb9, 0xba, 0x72, 0x9b, 0xec, 0x9f, 0x1a,
+	0xec, 0x9e, 0x2e, 0x62, 0xc2, 0xff, 0xb5, 0x70, 0xad, 0x05, 0xeb, 0xb6, 0x17, 0xec, 0x35, 0x30,
+	0xbc, 0x0b, 0x30, 0xc9, 0x1a, 0xdd, 0x95, 0x8b, 0x32, 0x94, 0x91, 0xaa, 0xbf, 0xf6, 0x1b, 0xd8,
+	0x6b, 0x25, 0xa7, 0x0c, 0x79, 0x0c, 0xfd, 0x40, 0x04, 0xaa, 0xe4, 0xe4, 0xe9, 0xf8, 0x97, 0x06,
+	0x43, 0x77, 0x76, 0xaa, 0xf8, 0x53, 0x51, 0x3c, 0xfb, 0x06, 0x5b, 0x75, 0x53, 0x99, 0xdd, 0x32,
+	0xe7, 0x9e, 0xef, 0x66, 0xff, 0xf0, 0x41, 0x8e, 0x4a, 0xe2, 0x3b, 0x98, 0x8d, 0xec, 0x58, 0xfb,
+	0xd6, 0x7d, 0xc6, 0xee, 0xbf, 0x7c, 0x98, 0x24, 0xb5, 0x4f, 0xde, 0x82, 0x15, 0x2d, 0x27, 0x1c,
+	0x2f, 0xf3, 0x85, 0x3f, 0x91, 0xad, 0x5e, 0xdd, 0x3b, 0x79, 0x54, 0x2f, 0x6f, 0x26, 0x3e, 0xd1,
+	0x8f, 0x9d, 0x99, 0x76, 0xd9, 0x2f, 0xff, 0xd3, 0x77, 0x7f, 0x07, 0x00, 0x91, 0x31, 0xd7, 0xfa,
+	0x66, 0x05, 0x00, 0x00,
+}
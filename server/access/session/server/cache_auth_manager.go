@@ -25,6 +25,7 @@ import (
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
 	"github.com/nebulaim/telegramd/proto/mtproto"
+	"time"
 )
 
 type CacheAuthInterface interface {
@@ -34,9 +35,12 @@ type CacheAuthInterface interface {
 }
 
 type cacheAuthValue struct {
-	AuthKey []byte
-	UserId  int32
-	Layer   int32
+	AuthKey       []byte
+	UserId        int32
+	Layer         int32
+	AuthKeyType   int32
+	PermAuthKeyId int64 // 临时key绑定的永久key
+	ExpiresAt     int32 // 临时key的过期时间
 }
 
 // Impl cache.Value interface
@@ -44,6 +48,18 @@ func (cv *cacheAuthValue) Size() int {
 	return 1
 }
 
+func (cv *cacheAuthValue) expired(now int32) bool {
+	return cv.AuthKeyType == mtproto.AUTH_KEY_TYPE_TEMP && cv.ExpiresAt <= now
+}
+
+// 用户和授权信息使用的auth_key_id: 已绑定的临时key使用永久key, 未绑定的临时key为0
+func (cv *cacheAuthValue) permAuthKeyId(authKeyId int64) int64 {
+	if cv.AuthKeyType == mtproto.AUTH_KEY_TYPE_TEMP {
+		return cv.PermAuthKeyId
+	}
+	return authKeyId
+}
+
 type cacheAuthManager struct {
 	cache  *cache.LRUCache
 	client mtproto.RPCSessionClient
@@ -64,30 +80,53 @@ func InitCacheAuthManager(cap int64, discovery *service_discovery.ServiceDiscove
 	}
 }
 
-func (c *cacheAuthManager) GetAuthKey(authKeyId int64) ([]byte, bool) {
+// 过期的临时key从缓存中删除并返回false
+func (c *cacheAuthManager) GetAuthKeyValue(authKeyId int64) (cacheAuthValue, bool) {
 	var (
 		cacheK = base.Int64ToString(authKeyId)
+		now    = int32(time.Now().Unix())
 	)
 
 	if v, ok := c.cache.Get(cacheK); !ok {
 		keyInfo, err := c.client.SessionQueryAuthKey(context.Background(), &mtproto.TLSessionQueryAuthKey{AuthKeyId: authKeyId})
 		if err != nil {
 			glog.Error(err)
-			return nil, false
+			return cacheAuthValue{}, false
 		}
 		//if r.Result != 0 {
 		//	glog.Errorf("queryAuthKey err: {%v}", r)
 		//	return nil, false
 		//}
-		c.cache.Set(cacheK, &cacheAuthValue{AuthKey: keyInfo.GetData2().GetAuthKey()})
+		cv := &cacheAuthValue{
+			AuthKey:       keyInfo.GetData2().GetAuthKey(),
+			AuthKeyType:   keyInfo.GetData2().GetAuthKeyType(),
+			PermAuthKeyId: keyInfo.GetData2().GetPermAuthKeyId(),
+			ExpiresAt:     keyInfo.GetData2().GetExpiresAt(),
+		}
+		if cv.expired(now) {
+			glog.Errorf("temp key expired - keyId = %d, expires_at = %d", authKeyId, cv.ExpiresAt)
+			return cacheAuthValue{}, false
+		}
+		c.cache.Set(cacheK, cv)
 
 		// TODO(@benqi): salt.
-		return keyInfo.GetData2().GetAuthKey(), true
+		return *cv, true
 	} else {
-		return v.(*cacheAuthValue).AuthKey, true
+		cv := v.(*cacheAuthValue)
+		if cv.expired(now) {
+			glog.Errorf("temp key expired - keyId = %d, expires_at = %d", authKeyId, cv.ExpiresAt)
+			c.cache.Delete(cacheK)
+			return cacheAuthValue{}, false
+		}
+		return *cv, true
 	}
 }
 
+func (c *cacheAuthManager) GetAuthKey(authKeyId int64) ([]byte, bool) {
+	cv, ok := c.GetAuthKeyValue(authKeyId)
+	return cv.AuthKey, ok
+}
+
 func (c *cacheAuthManager) GetUserID(authKeyId int64) (int32, bool) {
 	var (
 		cacheK = base.Int64ToString(authKeyId)
@@ -99,7 +138,12 @@ func (c *cacheAuthManager) GetUserID(authKeyId int64) (int32, bool) {
 	} else {
 		cv, _ := v.(*cacheAuthValue)
 		if cv.UserId == 0 {
-			id, err := c.client.SessionGetUserId(context.Background(), &mtproto.TLSessionGetUserId{AuthKeyId: authKeyId})
+			permAuthKeyId := cv.permAuthKeyId(authKeyId)
+			if permAuthKeyId == 0 {
+				// 未绑定的临时key
+				return 0, true
+			}
+			id, err := c.client.SessionGetUserId(context.Background(), &mtproto.TLSessionGetUserId{AuthKeyId: permAuthKeyId})
 			if err != nil {
 				glog.Error(err)
 				return 0, false
@@ -128,7 +172,11 @@ func (c *cacheAuthManager) GetApiLayer(authKeyId int64) (int32, bool) {
 	} else {
 		cv, _ := v.(*cacheAuthValue)
 		if cv.Layer == 0 {
-			id, err := c.client.SessionGetLayer(context.Background(), &mtproto.TLSessionGetLayer{AuthKeyId: authKeyId})
+			permAuthKeyId := cv.permAuthKeyId(authKeyId)
+			if permAuthKeyId == 0 {
+				permAuthKeyId = authKeyId
+			}
+			id, err := c.client.SessionGetLayer(context.Background(), &mtproto.TLSessionGetLayer{AuthKeyId: permAuthKeyId})
 			if err != nil {
 				glog.Error(err)
 				return 0, false
@@ -158,6 +206,30 @@ func (c *cacheAuthManager) PutUserID(authKeyId int64, userId int32) {
 	}
 }
 
+// auth.bindTempAuthKey成功后更新, 用户和layer改为从永久key获取
+func (c *cacheAuthManager) PutPermAuthKeyId(authKeyId, permAuthKeyId int64, expiresAt int32) {
+	var (
+		cacheK = base.Int64ToString(authKeyId)
+	)
+
+	if v, ok := c.cache.Peek(cacheK); ok {
+		cv := v.(*cacheAuthValue)
+		cv.PermAuthKeyId = permAuthKeyId
+		if expiresAt < cv.ExpiresAt {
+			cv.ExpiresAt = expiresAt
+		}
+		cv.UserId = 0
+		cv.Layer = 0
+	} else {
+		glog.Error("not found authKeyId, bug???")
+	}
+}
+
+// 吊销授权后删除, 下次从auth_session重新读取
+func (c *cacheAuthManager) Delete(authKeyId int64) {
+	c.cache.Delete(base.Int64ToString(authKeyId))
+}
+
 func getCacheUserID(authKeyId int64) int32 {
 	if _cacheAuthManager == nil {
 		panic("not init cacheAuthManager.")
@@ -184,6 +256,30 @@ func getCacheAuthKey(authKeyId int64) []byte {
 	return key
 }
 
+func deleteCacheAuthKey(authKeyId int64) {
+	if _cacheAuthManager == nil {
+		panic("not init cacheAuthManager.")
+	}
+
+	_cacheAuthManager.Delete(authKeyId)
+}
+
+func getCacheAuthKeyValue(authKeyId int64) (cacheAuthValue, bool) {
+	if _cacheAuthManager == nil {
+		panic("not init cacheAuthManager.")
+	}
+
+	return _cacheAuthManager.GetAuthKeyValue(authKeyId)
+}
+
+func putCachePermAuthKeyId(authKeyId, permAuthKeyId int64, expiresAt int32) {
+	if _cacheAuthManager == nil {
+		panic("not init cacheAuthManager.")
+	}
+
+	_cacheAuthManager.PutPermAuthKeyId(authKeyId, permAuthKeyId, expiresAt)
+}
+
 func getCacheApiLayer(authKeyId int64) int32 {
 	if _cacheAuthManager == nil {
 		panic("not init cacheAuthManager.")
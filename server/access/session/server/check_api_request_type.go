@@ -96,11 +96,26 @@ func checkRpcPushType(tl mtproto.TLObject) bool {
 // TL_account_getPassword
 // TL_account_updatePasswordSettings
 // TL_account_getPasswordSettings
+// 未绑定永久key的临时key只能调用auth.bindTempAuthKey和不需要授权的help接口
+func checkRpcWithoutPermAuthKey(tl mtproto.TLObject) bool {
+	switch tl.(type) {
+	case *mtproto.TLAuthBindTempAuthKey,
+		*mtproto.TLHelpGetConfig,
+		*mtproto.TLHelpGetCdnConfig,
+		*mtproto.TLHelpGetNearestDc:
+
+		return true
+	}
+
+	return false
+}
+
 func checkRpcWithoutLogin(tl mtproto.TLObject) bool {
 	switch tl.(type) {
 	case //*mtproto.TLAuthCheckPhone,
 		// *mtproto.TLAuthSendCodeLayer51,
 		*mtproto.TLAuthLogOut,
+		*mtproto.TLAuthBindTempAuthKey,
 		*mtproto.TLAuthSendCode,
 		*mtproto.TLAuthSignIn,
 		*mtproto.TLAuthSignUp,
@@ -74,6 +74,11 @@ type clientSessionHandler struct {
 	pendingMessages  []*pendingMessage
 	isUpdates        bool
 	rpcMessages      []*networkApiMessage
+	msgStates        *messageStates
+	lastConnID       ClientConnID
+	invokeAfterQueue *invokeAfterQueue
+	lastMsgId        int64 // 收到的最大msg_id, 客户端msg_id必须单调递增
+	lastActiveDate   int64
 }
 
 func newClientSessionHandler(sessionId, salt, firstMsgId int64, m *clientSessionManager) *clientSessionHandler {
@@ -89,6 +94,8 @@ func newClientSessionHandler(sessionId, salt, firstMsgId int64, m *clientSession
 		clientState:      kStateCreated,
 		pendingMessages:  []*pendingMessage{},
 		isUpdates:        false,
+		msgStates:        newMessageStates(firstMsgId),
+		invokeAfterQueue: newInvokeAfterQueue(),
 	}
 }
 
@@ -110,6 +117,13 @@ func (c *clientSessionHandler) onTimer() bool {
 			c.apiMessages.Remove(e)
 		}
 	}
+	c.msgStates.expire(date)
+
+	for _, r := range c.invokeAfterQueue.popExpired(date) {
+		glog.Errorf("onTimer - invokeAfterMsg timeout: {sess: %s, request: %s}", c, r)
+		c.onInvokeAfterFailed(r)
+	}
+	c.dispatchInvokeAfterRequests()
 
 	//for e := c.syncMessages.Front(); e != nil; e = e.Next() {
 	//	if date - e.Value.(*networkSyncMessage).date > 300 {
@@ -141,10 +155,10 @@ func (c *clientSessionHandler) onTimer() bool {
 }
 
 //============================================================================================
-func (c *clientSessionHandler) encodeMessage(authKeyId int64, authKey []byte, messageId int64, confirm bool, tl mtproto.TLObject) ([]byte, error) {
+func (c *clientSessionHandler) encodeMessage(authKeyId int64, authKey []byte, messageId int64, seqNo int32, tl mtproto.TLObject) ([]byte, error) {
 	message := &mtproto.EncryptedMessage2{
 		Salt:      c.salt,
-		SeqNo:     c.generateMessageSeqNo(confirm),
+		SeqNo:     seqNo,
 		MessageId: messageId,
 		SessionId: c.sessionId,
 		Object:    tl,
@@ -164,8 +178,12 @@ func (c *clientSessionHandler) generateMessageSeqNo(increment bool) int32 {
 }
 
 func (c *clientSessionHandler) sendToClient(connID ClientConnID, md *zproto.ZProtoMetadata, messageId int64, confirm bool, obj mtproto.TLObject) error {
+	return c.sendMessageToClient(connID, md, messageId, c.generateMessageSeqNo(confirm), obj)
+}
+
+func (c *clientSessionHandler) sendMessageToClient(connID ClientConnID, md *zproto.ZProtoMetadata, messageId int64, seqNo int32, obj mtproto.TLObject) error {
 	// glog.Infof("sendToClient - manager: %v", c.manager)
-	b, err := c.encodeMessage(c.manager.authKeyId, c.manager.authKey, messageId, confirm, obj)
+	b, err := c.encodeMessage(c.manager.authKeyId, c.manager.authKey, messageId, seqNo, obj)
 	if err != nil {
 		glog.Error(err)
 		return err
@@ -187,33 +205,70 @@ func (c *clientSessionHandler) sendPendingMessagesToClient(connID ClientConnID,
 
 	// glog.Infof("sendPendingMessagesToClient - connID: {%v}, pendingLen: {%v}", connID, len(pendingMessages))
 	if len(pendingMessages) == 1 {
-		// return c.sendToClient(connID, md, pendingMessages[0].messageId, pendingMessages[0].confirm, pendingMessages[0].tl)
-		return c.sendToClient(connID, md, 0, pendingMessages[0].confirm, pendingMessages[0].tl)
+		message2 := c.makeMessage2(pendingMessages[0])
+		return c.sendMessageToClient(connID, md, message2.MsgId, message2.Seqno, message2.Object)
 	} else {
 		msgContainer := &mtproto.TLMsgContainer{
 			Messages: make([]mtproto.TLMessage2, 0, len(pendingMessages)),
 		}
-		// var seqno int32
 		for _, m := range pendingMessages {
-			//msgId := m.messageId
-			//if msgId == 0 {
-			//	msgId = mtproto.GenerateMessageId()
-			//}
-			message2 := mtproto.TLMessage2{
-				//MsgId:  msgId,
-				MsgId:  mtproto.GenerateMessageId(),
-				Seqno:  c.generateMessageSeqNo(m.confirm),
-				Bytes:  int32(len(m.tl.EncodeToLayer(int(c.manager.Layer)))),
-				// Bytes:  int32(len(m.tl.Encode())),
-				Object: m.tl,
-			}
-			msgContainer.Messages = append(msgContainer.Messages, message2)
+			msgContainer.Messages = append(msgContainer.Messages, *c.makeMessage2(m))
 		}
 
 		return c.sendToClient(connID, md, 0, false, msgContainer)
 	}
 }
 
+// 分配msg_id和seqno并记录到msgStates, 重发的消息沿用原来的msg_id和seqno
+func (c *clientSessionHandler) makeMessage2(m *pendingMessage) *mtproto.TLMessage2 {
+	if m.messageId != 0 {
+		if v := c.msgStates.lookupOutbound(m.messageId); v != nil {
+			return &mtproto.TLMessage2{MsgId: v.msgId, Seqno: v.seqNo, Bytes: v.bytes, Object: v.obj}
+		}
+	}
+
+	msgId := m.messageId
+	if msgId == 0 {
+		msgId = mtproto.GenerateMessageId()
+	}
+	obj := c.gzipPackRpcResult(m.tl)
+	message2 := &mtproto.TLMessage2{
+		MsgId:  msgId,
+		Seqno:  c.generateMessageSeqNo(m.confirm),
+		Bytes:  int32(len(obj.EncodeToLayer(int(c.manager.Layer)))),
+		Object: obj,
+	}
+	c.msgStates.onSent(message2)
+	return message2
+}
+
+// 超过阈值的rpc_result使用gzip_packed压缩, 压缩后没有变小则原样发送
+func (c *clientSessionHandler) gzipPackRpcResult(tl mtproto.TLObject) mtproto.TLObject {
+	rpcResult, ok := tl.(*mtproto.TLRpcResult)
+	if !ok || rpcResult.Result == nil {
+		return tl
+	}
+	if _, ok := rpcResult.Result.(*mtproto.TLGzipPacked); ok {
+		return tl
+	}
+
+	threshold := getGzipThreshold()
+	if threshold < 0 {
+		return tl
+	}
+	data := rpcResult.Result.EncodeToLayer(int(c.manager.Layer))
+	if len(data) < threshold {
+		return tl
+	}
+
+	gzipPacked := mtproto.NewTLGzipPackedByData(data)
+	if gzipPacked == nil {
+		return tl
+	}
+	glog.Infof("gzipPackRpcResult - {req_msg_id: %d, bytes: %d, gzip_bytes: %d}", rpcResult.ReqMsgId, len(data), len(gzipPacked.Encode()))
+	return &mtproto.TLRpcResult{ReqMsgId: rpcResult.ReqMsgId, Result: gzipPacked}
+}
+
 //// Check Server Salt
 func (c *clientSessionHandler) CheckBadServerSalt(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, salt int64) bool {
 	// Notice of Ignored Error Message
@@ -294,7 +349,7 @@ func (c *clientSessionHandler) CheckBadMsgNotification(connID ClientConnID, md *
 	//
 
 	//=============================================================================================
-	// TODO(@benqi): Time Synchronization, https://core.telegram.org/mtproto#time-synchronization
+	// Time Synchronization, https://core.telegram.org/mtproto#time-synchronization
 	//
 	// Time Synchronization
 	//
@@ -320,15 +375,31 @@ func (c *clientSessionHandler) CheckBadMsgNotification(connID ClientConnID, md *
 
 	var errorCode int32 = 0
 
-	timeMessage := msgId / 4294967296.0
+	// 同一个auth_key收到过的msg_id: 重复的容器回复19;
+	// 本session重发的消息交给onMessageData, 由onDuplicateMessage重发应答; 其他session的msg_id为重放, 直接忽略
+	if c.manager.msgIdWindow.contains(msgId) {
+		if isContainer {
+			glog.Errorf("duplicate container msg_id: %d, sess: %s", msgId, c)
+			errorCode = 19
+		} else if c.msgStates.getState(msgId)&kMsgStateReceived == 0 {
+			glog.Errorf("replayed msg_id: %d, sess: %s", msgId, c)
+			return false
+		}
+	} else if c.manager.msgIdWindow.tooOld(msgId) {
+		errorCode = 20
+	}
+
+	// 返回的bad_msg_notification使用服务端时间生成的msg_id, 客户端据此校正时间
+	timeMessage := msgId >> 32
 	date := time.Now().Unix()
 	// glog.Info("date: ", date, ", timeMessage: ", timeMessage)
 
-	if timeMessage+30 < date {
-		errorCode = 16
-	}
-	if date > timeMessage+300 {
-		errorCode = 17
+	if errorCode == 0 {
+		if timeMessage < date-kMsgIdTimeTooLow {
+			errorCode = 16
+		} else if timeMessage > date+kMsgIdTimeTooHigh {
+			errorCode = 17
+		}
 	}
 
 	//=================================================================================================
@@ -353,11 +424,17 @@ func (c *clientSessionHandler) CheckBadMsgNotification(connID ClientConnID, md *
 	// by the client must not be empty and must present a fractional
 	// part of the time point when the message was created.
 	//
-	if msgId%4 != 0 {
+	if errorCode == 0 && msgId%4 != 0 {
 		errorCode = 18
 	}
 
-	// TODO(@benqi): other error code??
+	// 同一个session里msg_id必须单调递增, 重发的消息可以使用原来的msg_id
+	if errorCode == 0 && msgId <= c.lastMsgId {
+		if isContainer || c.msgStates.getState(msgId)&kMsgStateReceived == 0 {
+			glog.Errorf("msg_id not increasing: %d (last %d), sess: %s", msgId, c.lastMsgId, c)
+			errorCode = 16
+		}
+	}
 
 	if errorCode != 0 {
 		badMsgNotification := &mtproto.TLBadMsgNotification{Data2: &mtproto.BadMsgNotification_Data{
@@ -371,6 +448,11 @@ func (c *clientSessionHandler) CheckBadMsgNotification(connID ClientConnID, md *
 		return false
 	}
 
+	// 校验通过, 记录msg_id
+	c.manager.msgIdWindow.add(msgId, date)
+	if msgId > c.lastMsgId {
+		c.lastMsgId = msgId
+	}
 	return true
 }
 
@@ -383,24 +465,51 @@ func (c *clientSessionHandler) onNewSessionCreated(connID ClientConnID, md *zpro
 	}}
 
 	// c.sendToClient(connID, md, 0, true, newSessionCreated)
+	// 客户端未确认时, 重连后会重发
 	c.pendingMessages = append(c.pendingMessages, makePendingMessage(0, true, newSessionCreated))
-	// TODO(@benqi): if not receive new_session_created confirm, will resend the message.
 }
 
 func (c *clientSessionHandler) onCloseSession() {
 	// TODO(@benqi): remove queue???
 }
 
-func (c *clientSessionHandler) notifyMsgsStateReq() {
-	// TODO(@benqi):
+// 回复msgs_state_req, msg_resend_req和msg_resend_ans_req也会返回msgs_state_info
+func (c *clientSessionHandler) notifyMsgsStateReq(reqMsgId int64, msgIds []int64) {
+	msgsStateInfo := &mtproto.TLMsgsStateInfo{Data2: &mtproto.MsgsStateInfo_Data{
+		ReqMsgId: reqMsgId,
+		Info:     c.msgStates.getStates(msgIds),
+	}}
+
+	glog.Info("notifyMsgsStateReq - reply data: ", msgsStateInfo)
+	c.pendingMessages = append(c.pendingMessages, makePendingMessage(0, false, msgsStateInfo))
 }
 
+// 主动告知客户端消息的状态
 func (c *clientSessionHandler) notifyMsgsAllInfo() {
-	// TODO(@benqi):
+	msgIds, info := c.msgStates.getAllInfo()
+	if len(msgIds) == 0 {
+		return
+	}
+
+	msgsAllInfo := &mtproto.TLMsgsAllInfo{Data2: &mtproto.MsgsAllInfo_Data{
+		MsgIds: msgIds,
+		Info:   info,
+	}}
+
+	glog.Info("notifyMsgsAllInfo - data: ", msgsAllInfo)
+	c.pendingMessages = append(c.pendingMessages, makePendingMessage(0, false, msgsAllInfo))
 }
 
-func (c *clientSessionHandler) notifyMsgDetailedInfo() {
-	// TODO(@benqi):
+func (c *clientSessionHandler) notifyMsgDetailedInfo(msgId int64, answer *outboundMessage) {
+	msgDetailedInfo := &mtproto.TLMsgDetailedInfo{Data2: &mtproto.MsgDetailedInfo_Data{
+		MsgId:       msgId,
+		AnswerMsgId: answer.msgId,
+		Bytes:       answer.bytes,
+		Status:      0,
+	}}
+
+	glog.Info("notifyMsgDetailedInfo - data: ", msgDetailedInfo)
+	c.pendingMessages = append(c.pendingMessages, makePendingMessage(0, false, msgDetailedInfo.To_MsgDetailedInfo()))
 
 	// Extended Voluntary Communication of Status of One Message
 	//
@@ -422,9 +531,31 @@ func (c *clientSessionHandler) notifyMsgDetailedInfo() {
 	//
 }
 
-func (c *clientSessionHandler) notifyMsgResendAnsSeq() {
-	// TODO(@benqi):
+// 重发未收到msgs_ack的消息, 大的消息只发送msg_detailed_info, 客户端需要时再用msg_resend_req取回
+func (c *clientSessionHandler) resendUnackedMessages() {
+	for _, m := range c.msgStates.unackedMessages() {
+		if m.bytes <= kMaxResendBytes {
+			c.pendingMessages = append(c.pendingMessages, makePendingMessage(m.msgId, true, m.obj))
+		} else if m.reqMsgId != 0 {
+			c.notifyMsgDetailedInfo(m.reqMsgId, m)
+		} else {
+			c.notifyMsgNewDetailedInfo(m)
+		}
+	}
+}
 
+func (c *clientSessionHandler) notifyMsgNewDetailedInfo(m *outboundMessage) {
+	msgNewDetailedInfo := &mtproto.TLMsgNewDetailedInfo{Data2: &mtproto.MsgDetailedInfo_Data{
+		AnswerMsgId: m.msgId,
+		Bytes:       m.bytes,
+		Status:      0,
+	}}
+
+	glog.Info("notifyMsgNewDetailedInfo - data: ", msgNewDetailedInfo)
+	c.pendingMessages = append(c.pendingMessages, makePendingMessage(0, false, msgNewDetailedInfo.To_MsgDetailedInfo()))
+}
+
+func (c *clientSessionHandler) notifyMsgResendAnsSeq(msgId int64, msgIds []int64) {
 	// Explicit Request to Re-Send Answers
 	//
 	// msg_resend_ans_req#8610baeb msg_ids:Vector long = MsgResendReq;
@@ -434,6 +565,34 @@ func (c *clientSessionHandler) notifyMsgResendAnsSeq() {
 	// MsgsStateInfo is returned for all messages requested
 	// as if the MsgResendReq query had been a MsgsStateReq query as well.
 	//
+	for _, id := range msgIds {
+		if answer := c.msgStates.lookupAnswer(id); answer != nil {
+			c.pendingMessages = append(c.pendingMessages, makePendingMessage(answer.msgId, answer.confirm(), answer.obj))
+		}
+	}
+	c.notifyMsgsStateReq(msgId, msgIds)
+}
+
+// 重复的msg_id: 已有应答时, 小的应答直接重发, 大的应答回复msg_detailed_info
+func (c *clientSessionHandler) onDuplicateMessage(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32) {
+	glog.Infof("onDuplicateMessage - {sess: %s, conn_id: %s, md: %s, msg_id: %d, seq_no: %d}",
+		c,
+		connID,
+		md,
+		msgId,
+		seqNo)
+
+	answer := c.msgStates.lookupAnswer(msgId)
+	if answer == nil {
+		// 正在处理或不需要应答, 忽略
+		return
+	}
+
+	if answer.bytes <= kMaxResendBytes {
+		c.pendingMessages = append(c.pendingMessages, makePendingMessage(answer.msgId, answer.confirm(), answer.obj))
+	} else {
+		c.notifyMsgDetailedInfo(msgId, answer)
+	}
 }
 
 func (c *clientSessionHandler) onMessageData(connID ClientConnID, md *zproto.ZProtoMetadata, messages []*mtproto.TLMessage2) {
@@ -463,6 +622,15 @@ func (c *clientSessionHandler) onMessageData(connID ClientConnID, md *zproto.ZPr
 		ok            bool
 	)
 
+	// 客户端换了tcp连接, 可能丢失了应答, 重发未确认的消息并主动告知消息状态
+	if connID.connType == mtproto.TRANSPORT_TCP {
+		if c.lastConnID.clientConnID != 0 && !c.lastConnID.Equal(connID) {
+			c.resendUnackedMessages()
+			c.notifyMsgsAllInfo()
+		}
+		c.lastConnID = connID
+	}
+
 	for _, message := range messages {
 		// glog.Info("onMessageData - ", message)
 
@@ -470,6 +638,11 @@ func (c *clientSessionHandler) onMessageData(connID ClientConnID, md *zproto.ZPr
 			continue
 		}
 
+		if !c.msgStates.onReceived(message.MsgId, message.Seqno) {
+			c.onDuplicateMessage(connID, md, message.MsgId, message.Seqno)
+			continue
+		}
+
 		switch message.Object.(type) {
 		case *mtproto.TLRpcDropAnswer: // 所有链接都有可能
 			rpcDropAnswer, _ := message.Object.(*mtproto.TLRpcDropAnswer)
@@ -495,13 +668,19 @@ func (c *clientSessionHandler) onMessageData(connID ClientConnID, md *zproto.ZPr
 			c.onMsgsAck(connID, md, message.MsgId, message.Seqno, msgsAck)
 			// TODO(@benqi): check c.isUpdates
 		case *mtproto.TLMsgsStateReq: // android未用
-			c.onMsgsStateReq(connID, md, message.MsgId, message.Seqno, message.Object)
+			msgsStateReq, _ := message.Object.(*mtproto.TLMsgsStateReq)
+			c.onMsgsStateReq(connID, md, message.MsgId, message.Seqno, msgsStateReq)
 		case *mtproto.TLMsgsStateInfo: // android未用
 			c.onMsgsStateInfo(connID, md, message.MsgId, message.Seqno, message.Object)
 		case *mtproto.TLMsgsAllInfo: // android未用
-			c.onMsgsAllInfo(connID, md, message.MsgId, message.Seqno, message.Object)
+			msgsAllInfo, _ := message.Object.(*mtproto.TLMsgsAllInfo)
+			c.onMsgsAllInfo(connID, md, message.MsgId, message.Seqno, msgsAllInfo)
 		case *mtproto.TLMsgResendReq: // 都有可能
-			c.onMsgResendReq(connID, md, message.MsgId, message.Seqno, message.Object)
+			msgResendReq, _ := message.Object.(*mtproto.TLMsgResendReq)
+			c.onMsgResendReq(connID, md, message.MsgId, message.Seqno, msgResendReq)
+		case *mtproto.TLMsgResendAnsReq: // 都有可能
+			msgResendAnsReq, _ := message.Object.(*mtproto.TLMsgResendAnsReq)
+			c.notifyMsgResendAnsSeq(message.MsgId, msgResendAnsReq.GetMsgIds())
 		case *mtproto.TLMsgDetailedInfo: // 都有可能
 			// glog.Error("client side msg: ", object)
 		case *mtproto.TLMsgNewDetailedInfo: // 都有可能
@@ -598,12 +777,31 @@ func (c *clientSessionHandler) onMessageData(connID ClientConnID, md *zproto.ZPr
 
 	_ = hasHttpWait
 
+	c.pushRpcMessages(connID, md)
+}
+
+func (c *clientSessionHandler) pushRpcMessages(connID ClientConnID, md *zproto.ZProtoMetadata) {
 	if len(c.rpcMessages) > 0 {
+		for _, m := range c.rpcMessages {
+			c.manager.rpcInflights.push(m.rpcRequest.MsgId)
+		}
 		c.manager.rpcQueue.Push(&rpcApiMessages{connID: connID, md: md, sessionId: c.sessionId, rpcMessages: c.rpcMessages})
+		c.manager.rpcPendings++
 		c.rpcMessages = []*networkApiMessage{}
 	}
 }
 
+// 从还未转发的rpcMessages里删除
+func (c *clientSessionHandler) removeRpcMessage(msgId int64) bool {
+	for i, m := range c.rpcMessages {
+		if m.rpcRequest.MsgId == msgId {
+			c.rpcMessages = append(c.rpcMessages[:i], c.rpcMessages[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 //============================================================================================
 func (c *clientSessionHandler) onPing(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, ping *mtproto.TLPing) {
 	glog.Infof("onPing - request data: {sess: %s, conn_id: %s, md: %s, msg_id: %d, seq_no: %d, request: {%s}}",
@@ -654,6 +852,7 @@ func (c *clientSessionHandler) onMsgsAck(connID ClientConnID, md *zproto.ZProtoM
 		seqNo,
 		logger.JsonDebugData(request))
 
+	c.msgStates.onAck(request.GetMsgIds())
 	for _, id := range request.GetMsgIds() {
 		// reqMsgId := msgId
 		for e := c.apiMessages.Front(); e != nil; e = e.Next() {
@@ -689,7 +888,7 @@ func (c *clientSessionHandler) onHttpWait(connID ClientConnID, md *zproto.ZProto
 	// c.manager.updatesSession.SubscribeHttpUpdates(c, connID)
 }
 
-func (c *clientSessionHandler) onMsgsStateReq(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request mtproto.TLObject) {
+func (c *clientSessionHandler) onMsgsStateReq(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request *mtproto.TLMsgsStateReq) {
 	glog.Infof("onMsgsStateReq - request data: {sess: %s, conn_id: %s, md: %s, msg_id: %d, seq_no: %d, request: {%s}}",
 		c,
 		connID,
@@ -732,6 +931,7 @@ func (c *clientSessionHandler) onMsgsStateReq(connID ClientConnID, md *zproto.ZP
 	// the duplicate will be ignored. (If too much time has passed,
 	// and the original msg_id is not longer valid, the message is to be wrapped in msg_copy).
 	//
+	c.notifyMsgsStateReq(msgId, request.GetMsgIds())
 }
 
 func (c *clientSessionHandler) onInitConnectionEx(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request *TLInitConnectionExt) bool {
@@ -744,11 +944,11 @@ func (c *clientSessionHandler) onInitConnectionEx(connID ClientConnID, md *zprot
 		request)
 	// glog.Infof("onInitConnection - request: %s", request.String())
 	// auth_session_client.BindAuthKeyUser()
-	uploadInitConnection(c.manager.authKeyId, c.manager.Layer, md.ClientAddr, request)
+	uploadInitConnection(c.manager.userAuthKeyId(), c.manager.Layer, md.ClientAddr, request)
 	return c.onRpcRequest(connID, md, msgId, seqNo, request.Query)
 }
 
-func (c *clientSessionHandler) onMsgResendReq(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request mtproto.TLObject) {
+func (c *clientSessionHandler) onMsgResendReq(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request *mtproto.TLMsgResendReq) {
 	glog.Infof("onMsgResendReq - request data: {sess: %s, conn_id: %s, md: %s, msg_id: %d, seq_no: %d, request: {%s}}",
 		c,
 		connID,
@@ -768,6 +968,16 @@ func (c *clientSessionHandler) onMsgResendReq(connID ClientConnID, md *zproto.ZP
 	// MsgsStateInfo is returned for all messages requested
 	// as if the MsgResendReq query had been a MsgsStateReq query as well.
 	//
+	resendList := make([]*pendingMessage, 0, len(request.GetMsgIds()))
+	for _, id := range request.GetMsgIds() {
+		m := c.msgStates.lookupOutbound(id)
+		if m == nil {
+			c.notifyMsgsStateReq(msgId, request.GetMsgIds())
+			return
+		}
+		resendList = append(resendList, makePendingMessage(m.msgId, m.confirm(), m.obj))
+	}
+	c.pendingMessages = append(c.pendingMessages, resendList...)
 }
 
 func (c *clientSessionHandler) onMsgsStateInfo(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request mtproto.TLObject) {
@@ -780,7 +990,7 @@ func (c *clientSessionHandler) onMsgsStateInfo(connID ClientConnID, md *zproto.Z
 		request)
 }
 
-func (c *clientSessionHandler) onMsgsAllInfo(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request mtproto.TLObject) {
+func (c *clientSessionHandler) onMsgsAllInfo(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request *mtproto.TLMsgsAllInfo) {
 	glog.Infof("onMsgsAllInfo - request data: {sess: %s, conn_id: %s, md: %s, msg_id: %d, seq_no: %d, request: {%s}}",
 		c,
 		connID,
@@ -801,6 +1011,26 @@ func (c *clientSessionHandler) onMsgsAllInfo(connID ClientConnID, md *zproto.ZPr
 	//
 	// This message does not require an acknowledgment.
 	//
+	msgIds := request.GetMsgIds()
+	info := request.GetInfo()
+	if len(info) != len(msgIds) {
+		glog.Errorf("onMsgsAllInfo - invalid info len: %d (msg_ids len: %d)", len(info), len(msgIds))
+		return
+	}
+
+	for i, id := range msgIds {
+		m := c.msgStates.lookupOutbound(id)
+		if m == nil {
+			continue
+		}
+		switch info[i] & 0x07 {
+		case kMsgStateReceived:
+			c.msgStates.onAck([]int64{id})
+		case kMsgStateNotReceived, kMsgStateTooHigh:
+			// 客户端没有收到, 重发
+			c.pendingMessages = append(c.pendingMessages, makePendingMessage(m.msgId, m.confirm(), m.obj))
+		}
+	}
 }
 
 func (c *clientSessionHandler) onDestroySession(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request *mtproto.TLDestroySession) {
@@ -886,27 +1116,22 @@ func (c *clientSessionHandler) onRpcDropAnswer(connID ClientConnID, md *zproto.Z
 
 	rpcAnswer := &mtproto.RpcDropAnswer{Data2: &mtproto.RpcDropAnswer_Data{}}
 
-	var found = false
-	for e := c.apiMessages.Front(); e != nil; e = e.Next() {
-		v, _ := e.Value.(*networkApiMessage)
-		if v.rpcRequest.MsgId == request.ReqMsgId {
-			if v.state == kNetworkMessageStateReceived {
-				rpcAnswer.Constructor = mtproto.TLConstructor_CRC32_rpc_answer_dropped
-				rpcAnswer.Data2.MsgId = request.ReqMsgId
-				// TODO(@benqi): set seqno and bytes
-				// rpcAnswer.Data2.SeqNo = 0
-				// rpcAnswer.Data2.Bytes = 0
-			} else if v.state == kNetworkMessageStateInvoked {
-				rpcAnswer.Constructor = mtproto.TLConstructor_CRC32_rpc_answer_dropped_running
-			} else {
-				rpcAnswer.Constructor = mtproto.TLConstructor_CRC32_rpc_answer_unknown
-			}
-			found = true
-			break
-		}
-	}
-
-	if !found {
+	reqMsgId := request.ReqMsgId
+	if c.removeRpcMessage(reqMsgId) || c.invokeAfterQueue.remove(reqMsgId) != nil {
+		// 还未转发给biz_server
+		rpcAnswer.Constructor = mtproto.TLConstructor_CRC32_rpc_answer_dropped_running
+		c.msgStates.onCompleted(reqMsgId)
+		defer c.dispatchInvokeAfterRequests()
+	} else if answer := c.msgStates.dropAnswer(reqMsgId); answer != nil {
+		// 应答已生成, 告知被丢弃的应答
+		rpcAnswer.Constructor = mtproto.TLConstructor_CRC32_rpc_answer_dropped
+		rpcAnswer.Data2.MsgId = answer.msgId
+		rpcAnswer.Data2.SeqNo = answer.seqNo
+		rpcAnswer.Data2.Bytes = answer.bytes
+	} else if c.manager.rpcInflights.drop(reqMsgId) {
+		// 正在执行或在rpcQueue里等待执行, 取消grpc调用, 不再返回应答
+		rpcAnswer.Constructor = mtproto.TLConstructor_CRC32_rpc_answer_dropped_running
+	} else {
 		rpcAnswer.Constructor = mtproto.TLConstructor_CRC32_rpc_answer_unknown
 	}
 
@@ -951,49 +1176,7 @@ func (c *clientSessionHandler) onInvokeAfterMsgExt(connID ClientConnID, md *zpro
 		seqNo,
 		request)
 
-	//		if invokeAfterMsg.GetQuery() == nil {
-	//			glog.Errorf("invokeAfterMsg Query is nil, query: {%v}", invokeAfterMsg)
-	//			return
-	//		}
-	//
-	//		dbuf := mtproto.NewDecodeBuf(invokeAfterMsg.Query)
-	//		query := dbuf.Object()
-	//		if query == nil {
-	//			glog.Errorf("Decode query error: %s", hex.EncodeToString(invokeAfterMsg.Query))
-	//			return
-	//		}
-	//
-	//		var found = false
-	//		for j := 0; j < i; j++ {
-	//			if messages[j].MsgId == invokeAfterMsg.MsgId {
-	//				messages[i].Object = query
-	//				found = true
-	//				break
-	//			}
-	//		}
-	//
-	//		if !found {
-	//			for j := i + 1; j < len(messages); j++ {
-	//				if messages[j].MsgId == invokeAfterMsg.MsgId {
-	//					// c.messages[i].Object = query
-	//					messages[i].Object = query
-	//					found = true
-	//					messages = append(messages, messages[i])
-	//
-	//					// set messages[i] = nil, will ignore this.
-	//					messages[i] = nil
-	//					break
-	//				}
-	//			}
-	//		}
-	//
-	//		if !found {
-	//			// TODO(@benqi): backup message, wait.
-	//
-	//			messages[i].Object = query
-	//		}
-
-	return c.onRpcRequest(connID, md, msgId, seqNo, request.Query)
+	return c.onInvokeAfter(connID, md, msgId, seqNo, []int64{request.MsgId}, request.Query)
 }
 
 func (c *clientSessionHandler) onInvokeAfterMsgsExt(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request *TLInvokeAfterMsgsExt) bool {
@@ -1004,62 +1187,62 @@ func (c *clientSessionHandler) onInvokeAfterMsgsExt(connID ClientConnID, md *zpr
 		msgId,
 		seqNo,
 		request)
-	//		if invokeAfterMsgs.GetQuery() == nil {
-	//			glog.Errorf("invokeAfterMsgs Query is nil, query: {%v}", invokeAfterMsgs)
-	//			return
-	//		}
-	//
-	//		dbuf := mtproto.NewDecodeBuf(invokeAfterMsgs.Query)
-	//		query := dbuf.Object()
-	//		if query == nil {
-	//			glog.Errorf("Decode query error: %s", hex.EncodeToString(invokeAfterMsgs.Query))
-	//			return
-	//		}
-	//
-	//		if len(invokeAfterMsgs.MsgIds) == 0 {
-	//			// TODO(@benqi): invalid msgIds, ignore??
-	//
-	//			messages[i].Object = query
-	//		} else {
-	//			var maxMsgId = invokeAfterMsgs.MsgIds[0]
-	//			for j := 1; j < len(invokeAfterMsgs.MsgIds); j++ {
-	//				if maxMsgId > invokeAfterMsgs.MsgIds[j] {
-	//					maxMsgId = invokeAfterMsgs.MsgIds[j]
-	//				}
-	//			}
-	//
-	//
-	//			var found = false
-	//			for j := 0; j < i; j++ {
-	//				if messages[j].MsgId == maxMsgId {
-	//					messages[i].Object = query
-	//					found = true
-	//					break
-	//				}
-	//			}
-	//
-	//			if !found {
-	//				for j := i + 1; j < len(messages); j++ {
-	//					if messages[j].MsgId == maxMsgId {
-	//						// c.messages[i].Object = query
-	//						messages[i].Object = query
-	//						found = true
-	//						messages = append(messages, messages[i])
-	//
-	//						// set messages[i] = nil, will ignore this.
-	//						messages[i] = nil
-	//						break
-	//					}
-	//				}
-	//			}
-	//
-	//			if !found {
-	//				// TODO(@benqi): backup message, wait.
-	//
-	//				messages[i].Object = query
-	//			}
 
-	return c.onRpcRequest(connID, md, msgId, seqNo, request.Query)
+	return c.onInvokeAfter(connID, md, msgId, seqNo, request.MsgIds, request.Query)
+}
+
+// 依赖的请求都已完成时直接执行, 否则放入invokeAfterQueue等待
+func (c *clientSessionHandler) onInvokeAfter(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, afterMsgIds []int64, query mtproto.TLObject) bool {
+	r := &invokeAfterRequest{
+		connID:      connID,
+		md:          md,
+		msgId:       msgId,
+		seqNo:       seqNo,
+		afterMsgIds: afterMsgIds,
+		query:       query,
+		date:        time.Now().Unix(),
+	}
+
+	// 等待中的请求也算处理中, 依赖它的请求需要继续等待
+	c.msgStates.setState(msgId, kMsgStateRpcRunning)
+	if !c.invokeAfterQueue.push(r) {
+		glog.Errorf("onInvokeAfter - too many waiting requests: {sess: %s, request: %s}", c, r)
+		c.onInvokeAfterFailed(r)
+		return true
+	}
+
+	c.dispatchInvokeAfterRequests()
+	return true
+}
+
+// 执行依赖已完成的请求, 使用各自请求的连接返回结果
+func (c *clientSessionHandler) dispatchInvokeAfterRequests() {
+	ready := c.invokeAfterQueue.popReady(c.msgStates.isCompleted)
+	if len(ready) == 0 {
+		return
+	}
+
+	// 当前正在接收的请求不能混到其它连接里
+	rpcMessages := c.rpcMessages
+	c.rpcMessages = []*networkApiMessage{}
+	for _, r := range ready {
+		glog.Infof("dispatchInvokeAfterRequests - {sess: %s, request: %s}", c, r)
+		if !c.onRpcRequest(r.connID, r.md, r.msgId, r.seqNo, r.query) {
+			c.msgStates.onCompleted(r.msgId)
+			continue
+		}
+		c.pushRpcMessages(r.connID, r.md)
+	}
+	c.rpcMessages = rpcMessages
+}
+
+func (c *clientSessionHandler) onInvokeAfterFailed(r *invokeAfterRequest) {
+	c.msgStates.onCompleted(r.msgId)
+	rpcResult := &mtproto.TLRpcResult{
+		ReqMsgId: r.msgId,
+		Result:   mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_MSG_WAIT_FAILED),
+	}
+	c.sendPendingMessagesToClient(r.connID, r.md, []*pendingMessage{makePendingMessage(0, true, rpcResult)})
 }
 
 func (c *clientSessionHandler) onInvokeWithoutUpdatesExt(connID ClientConnID, md *zproto.ZProtoMetadata, msgId int64, seqNo int32, request *TLInvokeWithoutUpdatesExt) bool {
@@ -1130,6 +1313,7 @@ func (c *clientSessionHandler) onRpcRequest(connID ClientConnID, md *zproto.ZPro
 	glog.Info("onRpcRequest - ", apiMessage)
 	// c.apiMessages = append(c.apiMessages, apiMessage)
 	c.apiMessages.PushBack(apiMessage)
+	c.msgStates.setState(msgId, kMsgStateRpcRunning)
 
 	c.rpcMessages = append(c.rpcMessages, apiMessage)
 	// c.manager.rpcQueue.Push(&rpcApiMessage{connID: connID, sessionId: c.sessionId, rpcMessage: apiMessage})
@@ -0,0 +1,66 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nebulaim/telegramd/proto/mtproto"
+)
+
+func TestResendAnsweredRequest(t *testing.T) {
+	s := &clientSessionManager{authKeyId: 100, msgIdWindow: newMsgIdWindow()}
+	msgId := time.Now().Unix()<<32 | 4
+	sess := newClientSessionHandler(1, 2, msgId, s)
+
+	// 与client_session_manager、onMessageData的处理顺序一致
+	receive := func(sess *clientSessionHandler, msgId int64) bool {
+		if !sess.CheckBadMsgNotification(ClientConnID{}, nil, msgId, 1, false) {
+			return false
+		}
+		if !sess.msgStates.onReceived(msgId, 1) {
+			sess.onDuplicateMessage(ClientConnID{}, nil, msgId, 1)
+		}
+		return true
+	}
+
+	if !receive(sess, msgId) {
+		t.Fatal("request rejected")
+	}
+	sess.msgStates.onSent(&mtproto.TLMessage2{
+		MsgId:  msgId + 1,
+		Seqno:  1,
+		Bytes:  16,
+		Object: &mtproto.TLRpcResult{ReqMsgId: msgId, Result: mtproto.NewTLBoolTrue()},
+	})
+
+	// 客户端没有收到应答, 用原来的msg_id重发
+	if !receive(sess, msgId) {
+		t.Fatal("resent request rejected")
+	}
+	if len(sess.pendingMessages) != 1 || sess.pendingMessages[0].messageId != msgId+1 {
+		t.Fatalf("answer not resent: %v", sess.pendingMessages)
+	}
+
+	// 其他session使用同一个msg_id为重放
+	sess2 := newClientSessionHandler(2, 2, msgId, s)
+	if receive(sess2, msgId) || len(sess2.pendingMessages) != 0 {
+		t.Fatal("replayed request accepted")
+	}
+}
@@ -104,9 +104,10 @@ type rpcApiMessages struct {
 }
 
 type sessionData struct {
-	connID ClientConnID
-	md     *zproto.ZProtoMetadata
-	buf    []byte
+	connID   ClientConnID
+	md       *zproto.ZProtoMetadata
+	quickAck bool
+	buf      []byte
 }
 
 type syncData struct {
@@ -120,6 +121,17 @@ type connData struct {
 	connID ClientConnID
 }
 
+// 停服前等待rpc完成并保存session状态
+type drainData struct {
+	done chan struct{}
+}
+
+// 管理接口的请求, 在runLoop里执行
+type adminData struct {
+	f    func()
+	done chan struct{}
+}
+
 ////////////////////////////////////////
 const (
 // inited --> work --> idle --> quit
@@ -129,6 +141,8 @@ type clientSessionManager struct {
 	Layer           int32
 	authKeyId       int64
 	authKey         []byte
+	authKeyType     int32
+	permAuthKeyId   sync2.AtomicInt64 // 临时key绑定的永久key
 	AuthUserId      int32
 	sessions        map[int64]*clientSessionHandler
 	updatesSession  *clientUpdatesHandler
@@ -139,12 +153,21 @@ type clientSessionManager struct {
 	sessionDataChan chan interface{} // receive from client
 	rpcDataChan     chan interface{} // rpc reply
 	rpcQueue        *queue2.SyncQueue
+	msgIdWindow     *msgIdWindow
+	rpcInflights    *rpcInflightRequests
+	rpcPendings     int // 已提交到rpcQueue还未返回结果的请求批次
+	restoredStates  map[int64]*sessionState
+	stateDirty      bool
+	stateOwnerLost  bool
+	stateSaveDate   int64
+	lastActiveDate  int64 // 最后收到客户端数据的时间
+	draining        *drainData
 	finish          sync.WaitGroup
 	running         sync2.AtomicInt32
 	state           int
 }
 
-func newClientSessionManager(authKeyId int64, authKey []byte, userId int32) *clientSessionManager {
+func newClientSessionManager(authKeyId int64, authKey []byte, userId int32, authKeyType int32, permAuthKeyId int64) *clientSessionManager {
 	bizRPCClient, _ := getBizRPCClient()
 	nbfsRPCClient, _ := getNbfsRPCClient()
 	syncRpcClient, _ := getSyncRPCClient()
@@ -152,6 +175,8 @@ func newClientSessionManager(authKeyId int64, authKey []byte, userId int32) *cli
 	return &clientSessionManager{
 		authKeyId:       authKeyId,
 		authKey:         authKey,
+		authKeyType:     authKeyType,
+		permAuthKeyId:   sync2.NewAtomicInt64(permAuthKeyId),
 		AuthUserId:      userId,
 		sessions:        make(map[int64]*clientSessionHandler),
 		updatesSession:  newClientUpdatesHandler(),
@@ -162,6 +187,9 @@ func newClientSessionManager(authKeyId int64, authKey []byte, userId int32) *cli
 		sessionDataChan: make(chan interface{}, 1024),
 		rpcDataChan:     make(chan interface{}, 1024),
 		rpcQueue:        queue2.NewSyncQueue(),
+		msgIdWindow:     newMsgIdWindow(),
+		rpcInflights:    newRpcInflightRequests(),
+		restoredStates:  make(map[int64]*sessionState),
 		finish:          sync.WaitGroup{},
 	}
 }
@@ -170,6 +198,21 @@ func (s *clientSessionManager) String() string {
 	return fmt.Sprintf("{auth_key_id: %d, user_id: %d}", s.authKeyId, s.AuthUserId)
 }
 
+// 用户和授权信息使用的auth_key_id, 临时key使用绑定的永久key
+func (s *clientSessionManager) userAuthKeyId() int64 {
+	if s.authKeyType == mtproto.AUTH_KEY_TYPE_TEMP {
+		if permAuthKeyId := s.permAuthKeyId.Get(); permAuthKeyId != 0 {
+			return permAuthKeyId
+		}
+	}
+	return s.authKeyId
+}
+
+// 临时key未绑定永久key
+func (s *clientSessionManager) isUnboundTempAuthKey() bool {
+	return s.authKeyType == mtproto.AUTH_KEY_TYPE_TEMP && s.permAuthKeyId.Get() == 0
+}
+
 func (s *clientSessionManager) Start() {
 	s.running.Set(1)
 	s.finish.Add(1)
@@ -200,19 +243,37 @@ func (s *clientSessionManager) runLoop() {
 			switch sessionMsg.(type) {
 			case *sessionData:
 				s.onSessionData(sessionMsg.(*sessionData))
+				s.stateDirty = true
 			case *syncData:
 				s.onSyncData(sessionMsg.(*syncData))
+				s.stateDirty = true
 			case *connData:
 
+			case *drainData:
+				s.draining = sessionMsg.(*drainData)
+				s.stateDirty = true
+			case *adminData:
+				admin := sessionMsg.(*adminData)
+				admin.f()
+				close(admin.done)
 			default:
 				panic("receive invalid type msg")
 			}
 		case rpcMessages, _ := <-s.rpcDataChan:
 			results, _ := rpcMessages.(*rpcApiMessages)
 			s.onRpcResult(results)
+			s.stateDirty = true
 		case <-time.After(time.Second):
 			s.onTimer()
 		}
+
+		if s.draining != nil && s.rpcPendings == 0 {
+			s.saveSessionStates(true)
+			close(s.draining.done)
+			glog.Infof("runLoop - drained: %s", s)
+			return
+		}
+		s.saveSessionStates(false)
 	}
 
 	glog.Info("quit runLoop...")
@@ -239,9 +300,9 @@ func (s *clientSessionManager) onSessionClientNew(connID ClientConnID) error {
 	return nil
 }
 
-func (s *clientSessionManager) OnSessionDataArrived(connID ClientConnID, md *zproto.ZProtoMetadata, buf []byte) error {
+func (s *clientSessionManager) OnSessionDataArrived(connID ClientConnID, md *zproto.ZProtoMetadata, quickAck bool, buf []byte) error {
 	select {
-	case s.sessionDataChan <- &sessionData{connID, md, buf}:
+	case s.sessionDataChan <- &sessionData{connID, md, quickAck, buf}:
 		return nil
 	}
 	return nil
@@ -283,14 +344,24 @@ func (s *clientSessionManager) onSessionData(sessionMsg *sessionData) {
 		err = fmt.Errorf("the lower 32 bits of msg_id passed by the client must not be empty: %d", message.MessageId)
 		glog.Error(err)
 
-		// TODO(@benqi): replay-attack, close client conn.
+		// 可能是重放攻击, 忽略该消息
 		return
 	}
 
+	s.checkSessionStateOwner()
+	s.lastActiveDate = time.Now().Unix()
+
 	sess, ok := s.sessions[message.SessionId]
 	if !ok {
-		sess = newClientSessionHandler(message.SessionId, message.Salt, message.MessageId, s)
+		// 其他节点保存过状态的session直接重建, 不需要new_session_created
+		if sess = s.restoreSession(message.SessionId, message.Salt); sess != nil {
+			s.sessions[message.SessionId] = sess
+			ok = true
+		} else {
+			sess = newClientSessionHandler(message.SessionId, message.Salt, message.MessageId, s)
+		}
 	}
+	sess.lastActiveDate = s.lastActiveDate
 
 	if !sess.CheckBadServerSalt(sessionMsg.connID, sessionMsg.md, message.MessageId, message.SeqNo, message.Salt) {
 		glog.Infof("salt invalid - {sess: %s, conn_id: %s, md: %s}", s, sessionMsg.connID, sessionMsg.md)
@@ -305,6 +376,11 @@ func (s *clientSessionManager) onSessionData(sessionMsg *sessionData) {
 		return
 	}
 
+	// 消息已被接受，回复QuickAck
+	if sessionMsg.quickAck {
+		s.sendQuickAck(sessionMsg.connID, sessionMsg.md, message.QuickAckToken())
+	}
+
 	/*
 		//=============================================================================================
 		// Check Message Sequence Number (msg_seqno)
@@ -364,6 +440,16 @@ func (s *clientSessionManager) onSessionData(sessionMsg *sessionData) {
 	sess.onMessageData(sessionMsg.connID, sessionMsg.md, messages.messages)
 }
 
+func (s *clientSessionManager) sendQuickAck(connID ClientConnID, md *zproto.ZProtoMetadata, token int32) error {
+	quickAck := &zproto.ZProtoQuickAck{
+		SessionId: connID.frontendConnID,
+		Token:     token,
+	}
+
+	glog.Infof("sendQuickAckByConnID - {sess: %s, connID: %s, md: %s, quickAck: %s}", s, connID, md, quickAck)
+	return sendQuickAckByConnID(connID.clientConnID, md, quickAck)
+}
+
 func (s *clientSessionManager) onTimer() {
 	var delList = []int64{}
 	for k, v := range s.sessions {
@@ -374,9 +460,15 @@ func (s *clientSessionManager) onTimer() {
 
 	for _, id := range delList {
 		delete(s.sessions, id)
+		s.stateDirty = true
 	}
 
 	if len(s.sessions) == 0 {
+		// 接管后还没收到数据的session保留在redis里
+		if len(s.restoredStates) == 0 {
+			s.deleteSessionStates()
+		}
+		s.stateDirty = false
 		deleteClientSessionManager(s.authKeyId)
 	}
 }
@@ -397,15 +489,24 @@ func (s *clientSessionManager) onConnData(connMsg *connData) {
 }
 
 func (s *clientSessionManager) onRpcResult(rpcResults *rpcApiMessages) {
+	s.rpcPendings--
 	if sess, ok := s.sessions[rpcResults.sessionId]; ok {
 		msgList := sess.pendingMessages
 		sess.pendingMessages = []*pendingMessage{}
 		for _, m := range rpcResults.rpcMessages {
+			sess.msgStates.onCompleted(m.rpcRequest.MsgId)
+			if m.rpcResult == nil {
+				// 不需要回复客户端
+				continue
+			}
 			msgList = append(msgList, &pendingMessage{mtproto.GenerateMessageId(), true, m.rpcResult})
 		}
 		if len(msgList) > 0 {
 			sess.sendPendingMessagesToClient(rpcResults.connID, rpcResults.md, msgList)
 		}
+
+		// 依赖这些请求的invokeAfterMsg(s)可以执行了
+		sess.dispatchInvokeAfterRequests()
 	}
 }
 
@@ -425,11 +526,51 @@ func (s *clientSessionManager) onRpcRequest(requests *rpcApiMessages) {
 			rpcResult   mtproto.TLObject
 		)
 
+		reply := &mtproto.TLRpcResult{
+			ReqMsgId: requests.rpcMessages[i].rpcRequest.MsgId,
+		}
+
+		ctx, ok := s.rpcInflights.start(reply.ReqMsgId)
+		if !ok {
+			// 客户端已发送rpc_drop_answer, 不再执行
+			glog.Infof("onRpcRequest - dropped: {sess: %s, msg_id: %d}", s, reply.ReqMsgId)
+			requests.rpcMessages[i].state = kNetworkMessageStateInvoked
+			rpcMessageList = append(rpcMessageList, requests.rpcMessages[i])
+			continue
+		}
+
+		if s.isUnboundTempAuthKey() && !checkRpcWithoutPermAuthKey(requests.rpcMessages[i].rpcRequest.Object) {
+			glog.Errorf("onRpcRequest - temp key not bound: %s", s)
+			s.rpcInflights.finish(reply.ReqMsgId)
+			reply.Result = mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_AUTH_KEY_PERM_EMPTY)
+			requests.rpcMessages[i].state = kNetworkMessageStateInvoked
+			requests.rpcMessages[i].rpcResult = reply
+			rpcMessageList = append(rpcMessageList, requests.rpcMessages[i])
+			continue
+		}
+
+		if rpcErr := s.checkFloodWait(requests.md, requests.rpcMessages[i].rpcRequest.Object); rpcErr != nil {
+			glog.Errorf("onRpcRequest - %s: {sess: %s, msg_id: %d}", rpcErr, s, reply.ReqMsgId)
+			s.rpcInflights.finish(reply.ReqMsgId)
+			reply.Result = rpcErr
+			requests.rpcMessages[i].state = kNetworkMessageStateInvoked
+			requests.rpcMessages[i].rpcResult = reply
+			rpcMessageList = append(rpcMessageList, requests.rpcMessages[i])
+			continue
+		}
+
+		// auth.bindTempAuthKey使用临时key, 其它请求使用绑定的永久key
+		authId := s.userAuthKeyId()
+		bindTempAuthKey, _ := requests.rpcMessages[i].rpcRequest.Object.(*mtproto.TLAuthBindTempAuthKey)
+		if bindTempAuthKey != nil {
+			authId = s.authKeyId
+		}
+
 		// 初始化metadata
 		rpcMetadata := &grpc_util.RpcMetadata{
 			ServerId:        getServerID(),
 			NetlibSessionId: int64(requests.connID.clientConnID),
-			AuthId:          s.authKeyId,
+			AuthId:          authId,
 			SessionId:       requests.sessionId,
 			TraceId:         requests.md.TraceId,
 			SpanId:          getUUID(),
@@ -448,21 +589,33 @@ func (s *clientSessionManager) onRpcRequest(requests *rpcApiMessages) {
 
 		// TODO(@benqi): rpc proxy
 		if checkRpcUploadRequest(requests.rpcMessages[i].rpcRequest.Object) {
-			rpcResult, err = s.nbfsRPCClient.Invoke(rpcMetadata, requests.rpcMessages[i].rpcRequest.Object)
+			rpcResult, err = s.nbfsRPCClient.InvokeContext(ctx, rpcMetadata, requests.rpcMessages[i].rpcRequest.Object)
 		} else if checkRpcDownloadRequest(requests.rpcMessages[i].rpcRequest.Object) {
-			rpcResult, err = s.nbfsRPCClient.Invoke(rpcMetadata, requests.rpcMessages[i].rpcRequest.Object)
+			rpcResult, err = s.nbfsRPCClient.InvokeContext(ctx, rpcMetadata, requests.rpcMessages[i].rpcRequest.Object)
 		} else {
-			rpcResult, err = s.bizRPCClient.Invoke(rpcMetadata, requests.rpcMessages[i].rpcRequest.Object)
+			rpcResult, err = s.bizRPCClient.InvokeContext(ctx, rpcMetadata, requests.rpcMessages[i].rpcRequest.Object)
 		}
+		dropped := ctx.Err() != nil
+		s.rpcInflights.finish(reply.ReqMsgId)
 
-		reply := &mtproto.TLRpcResult{
-			ReqMsgId: requests.rpcMessages[i].rpcRequest.MsgId,
+		if bindTempAuthKey != nil && err == nil {
+			s.onBindTempAuthKey(bindTempAuthKey)
+		}
+
+		if dropped {
+			// 执行过程中被rpc_drop_answer取消, 已回复rpc_answer_dropped_running
+			glog.Infof("onRpcRequest - dropped running: {sess: %s, msg_id: %d}", s, reply.ReqMsgId)
+			requests.rpcMessages[i].state = kNetworkMessageStateInvoked
+			rpcMessageList = append(rpcMessageList, requests.rpcMessages[i])
+			continue
 		}
 
 		if err != nil {
 			glog.Error(err)
 			rpcErr, _ := err.(*mtproto.TLRpcError)
 			if rpcErr.GetErrorCode() == int32(mtproto.TLRpcErrorCodes_NOTRETURN_CLIENT) {
+				requests.rpcMessages[i].state = kNetworkMessageStateInvoked
+				rpcMessageList = append(rpcMessageList, requests.rpcMessages[i])
 				continue
 			}
 			reply.Result = rpcErr
@@ -482,6 +635,16 @@ func (s *clientSessionManager) onRpcRequest(requests *rpcApiMessages) {
 	s.rpcDataChan <- requests
 }
 
+// 绑定成功后用户和layer改为从永久key获取
+func (s *clientSessionManager) onBindTempAuthKey(request *mtproto.TLAuthBindTempAuthKey) {
+	glog.Infof("onBindTempAuthKey - bind {%s} to perm key: %d", s, request.GetPermAuthKeyId())
+
+	s.permAuthKeyId.Set(request.GetPermAuthKeyId())
+	putCachePermAuthKeyId(s.authKeyId, request.GetPermAuthKeyId(), request.GetExpiresAt())
+	s.AuthUserId = 0
+	s.Layer = 0
+}
+
 // TODO(@benqi): status_client
 func (s *clientSessionManager) setUserOnline(sessionId int64, connID ClientConnID) {
 	defer func() {
@@ -490,7 +653,7 @@ func (s *clientSessionManager) setUserOnline(sessionId int64, connID ClientConnI
 		}
 	}()
 
-	setOnline(s.AuthUserId, s.authKeyId, getServerID(), s.Layer)
+	setOnline(s.AuthUserId, s.userAuthKeyId(), getServerID(), s.Layer)
 }
 
 //==================================================================================================
@@ -564,10 +727,8 @@ func extractClientMessage(msgId int64, seqNo int32, object mtproto.TLObject, mes
 		gzipPacked, _ := object.(*mtproto.TLGzipPacked)
 		glog.Info("processGzipPacked - request data: ", gzipPacked)
 
-		dbuf := mtproto.NewDecodeBuf(gzipPacked.PackedData)
-		o := dbuf.Object()
+		o := unpackGzipPacked(gzipPacked)
 		if o == nil {
-			glog.Errorf("Decode query error: %s", hex.EncodeToString(gzipPacked.PackedData))
 			return
 		}
 		// return s.onGzipPacked(sessionId, msgId, seqNo, request)
@@ -598,17 +759,9 @@ func extractClientMessage(msgId int64, seqNo int32, object mtproto.TLObject, mes
 			glog.Errorf("invokeWithLayer Query is nil, query: {%v}", invokeWithLayer)
 			return
 		} else {
-			dbuf := mtproto.NewDecodeBuf(invokeWithLayer.Query)
-			classID := dbuf.Int()
-			if classID != int32(mtproto.TLConstructor_CRC32_initConnection) {
-				glog.Errorf("Not initConnection classID: %d", classID)
-				return
-			}
-
-			initConnection := &mtproto.TLInitConnection{}
-			err := initConnection.Decode(dbuf)
-			if err != nil {
-				glog.Error("Decode initConnection error: ", err)
+			initConnection, ok := decodeQuery(invokeWithLayer.Query).(*mtproto.TLInitConnection)
+			if !ok {
+				glog.Errorf("Not initConnection: %s", hex.EncodeToString(invokeWithLayer.Query))
 				return
 			}
 
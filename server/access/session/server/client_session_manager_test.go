@@ -19,17 +19,85 @@ package server
 
 import (
 	"fmt"
+	"github.com/nebulaim/telegramd/proto/mtproto"
 	"testing"
 )
 
 func TestClientSessionManager(t *testing.T) {
-	s := newClientSessionManager(100000, []byte{1}, 1)
+	t.Skip()
+	s := newClientSessionManager(100000, []byte{1}, 1, mtproto.AUTH_KEY_TYPE_PERM, 0)
 	s.Start()
 
 	fmt.Println("ready.")
 	for i := 0; i < 10; i++ {
-		s.onSessionData(&sessionData{ClientConnID{1, 1, 1}, nil, []byte{1}})
+		s.onSessionData(&sessionData{ClientConnID{1, 1, 1}, nil, false, []byte{1}})
 	}
 
 	s.Stop()
 }
+
+func TestExtractClientMessageGzipPacked(t *testing.T) {
+	// invokeWithLayer{initConnection{gzip_packed{help.getConfig}}}
+	initConnection := &mtproto.TLInitConnection{
+		ApiId: 1,
+		Query: (&mtproto.TLGzipPacked{PackedData: mtproto.NewTLHelpGetConfig().Encode()}).Encode(),
+	}
+	invokeWithLayer := &mtproto.TLInvokeWithLayer{
+		Layer: 85,
+		Query: (&mtproto.TLGzipPacked{PackedData: initConnection.Encode()}).Encode(),
+	}
+
+	msgContainer := &mtproto.TLMsgContainer{}
+	for i, obj := range []mtproto.TLObject{
+		&mtproto.TLGzipPacked{PackedData: (&mtproto.TLPing{PingId: 1}).Encode()},
+		invokeWithLayer,
+	} {
+		b := obj.Encode()
+		msgContainer.Messages = append(msgContainer.Messages, mtproto.TLMessage2{MsgId: int64(i+1) * 4, Seqno: 1, Bytes: int32(len(b)), Object: obj})
+	}
+
+	obj := mtproto.NewDecodeBuf(msgContainer.Encode()).Object()
+	messages := &messageListWrapper{}
+	layer := int32(0)
+	extractClientMessage(12, 2, obj, messages, func(l int32) { layer = l })
+
+	if len(messages.messages) != 2 || layer != 85 {
+		t.Fatalf("invalid messages: %v, layer: %d", messages.messages, layer)
+	}
+	if ping, ok := messages.messages[0].Object.(*mtproto.TLPing); !ok || ping.PingId != 1 || messages.messages[0].MsgId != 4 {
+		t.Fatalf("invalid ping: %v", messages.messages[0])
+	}
+	initConnectionExt, ok := messages.messages[1].Object.(*TLInitConnectionExt)
+	if !ok || initConnectionExt.ApiId != 1 {
+		t.Fatalf("invalid initConnection: %v", messages.messages[1])
+	}
+	if _, ok := initConnectionExt.Query.(*mtproto.TLHelpGetConfig); !ok {
+		t.Fatalf("invalid query: %v", initConnectionExt.Query)
+	}
+}
+
+func TestGzipPackRpcResult(t *testing.T) {
+	c := &clientSessionHandler{manager: &clientSessionManager{Layer: 85}}
+
+	small := &mtproto.TLRpcResult{ReqMsgId: 4, Result: mtproto.NewTLBoolTrue()}
+	if c.gzipPackRpcResult(small) != small {
+		t.Fatal("small rpc_result must not be compressed")
+	}
+
+	vector := &mtproto.VectorLong{}
+	for i := 0; i < kDefaultGzipThreshold; i++ {
+		vector.Datas = append(vector.Datas, 1)
+	}
+	large := &mtproto.TLRpcResult{ReqMsgId: 8, Result: vector}
+	obj := c.gzipPackRpcResult(large)
+	rpcResult, ok := obj.(*mtproto.TLRpcResult)
+	if !ok || rpcResult.ReqMsgId != 8 {
+		t.Fatalf("invalid rpc_result: %v", obj)
+	}
+	if _, ok := rpcResult.Result.(*mtproto.TLGzipPacked); !ok {
+		t.Fatalf("large rpc_result must be compressed: %v", rpcResult.Result)
+	}
+	if len(rpcResult.Encode()) >= len(large.Encode()) {
+		t.Fatal("compressed rpc_result must be smaller")
+	}
+}
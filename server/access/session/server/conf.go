@@ -21,7 +21,11 @@ import (
 	"flag"
 	"fmt"
 	"github.com/BurntSushi/toml"
+	"github.com/nebulaim/telegramd/baselib/base"
+	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
+	"github.com/nebulaim/telegramd/baselib/nsq_client"
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
 	"github.com/nebulaim/telegramd/baselib/redis_client"
 	"github.com/nebulaim/telegramd/proto/zproto"
 )
@@ -41,12 +45,31 @@ type sessionConfig struct {
 	SyncRpcClient        service_discovery.ServiceDiscoveryClientConfig
 	AuthSessionRpcClient service_discovery.ServiceDiscoveryClientConfig
 	Server               *zproto.ZProtoServerConfig
+	GzipThreshold        int // rpc_result超过该字节数时gzip压缩, 0使用默认值, 小于0不压缩
+	FloodLimit           rate_limit.FloodLimiterConfig // 按方法限制user_id/auth_key_id/ip/手机号的调用频率
+	SessionState         sessionStateConfig
+	AdminServer          *grpc_util.RPCServerConfig // 管理接口: 查看和强制关闭session
+	PushQueue            *nsq_client.ConsumerConfig // 不为空时从nsq消费sync的推送(topic: session_push_{serverId})
+}
+
+type sessionStateConfig struct {
+	Redis        string        // session状态保存到该redis, 为空时不保存
+	DrainTimeout base.Duration // 停服时等待rpc完成并保存状态的最长时间
 }
 
 func init() {
 	flag.StringVar(&confPath, "conf", "./session.toml", "config path")
 }
 
+const kDefaultGzipThreshold = 1024
+
+func getGzipThreshold() int {
+	if Conf == nil || Conf.GzipThreshold == 0 {
+		return kDefaultGzipThreshold
+	}
+	return Conf.GzipThreshold
+}
+
 func InitializeConfig() (err error) {
 	_, err = toml.DecodeFile(confPath, &Conf)
 	if err != nil {
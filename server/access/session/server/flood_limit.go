@@ -0,0 +1,81 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
+	"github.com/nebulaim/telegramd/biz/base"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/proto/zproto"
+	"net"
+	"regexp"
+	"strings"
+)
+
+var (
+	floodLimiter *rate_limit.FloodLimiter
+
+	layerSuffixRegexp = regexp.MustCompile(`Layer\d+$`)
+)
+
+// TL方法名, 不区分layer, 如/mtproto.RPCAuth/auth_sendCodeLayer51 -> auth.sendCode
+func getRpcMethodName(request mtproto.TLObject) string {
+	t := mtproto.FindRPCContextTuple(request)
+	if t == nil {
+		return ""
+	}
+
+	method := t.Method[strings.LastIndex(t.Method, "/")+1:]
+	return layerSuffixRegexp.ReplaceAllString(strings.Replace(method, "_", ".", 1), "")
+}
+
+// 超过调用频率限制时返回FLOOD_WAIT_X
+func (s *clientSessionManager) checkFloodWait(md *zproto.ZProtoMetadata, request mtproto.TLObject) *mtproto.TLRpcError {
+	if floodLimiter == nil || !floodLimiter.Enabled() {
+		return nil
+	}
+
+	method := getRpcMethodName(request)
+	if method == "" {
+		return nil
+	}
+
+	if wait := floodLimiter.Check(method, s.makeFloodKeys(md, request)); wait > 0 {
+		return mtproto.NewFloodWaitX2(wait)
+	}
+	return nil
+}
+
+func (s *clientSessionManager) makeFloodKeys(md *zproto.ZProtoMetadata, request mtproto.TLObject) *rate_limit.FloodKeys {
+	keys := &rate_limit.FloodKeys{
+		UserId:    s.AuthUserId,
+		AuthKeyId: s.userAuthKeyId(),
+		IP:        md.ClientAddr,
+	}
+	if host, _, err := net.SplitHostPort(md.ClientAddr); err == nil {
+		keys.IP = host
+	}
+	// auth.sendCode, auth.signIn等请求带有手机号, 和biz一样归一化, 否则换个格式就是新的计数
+	// 无效的手机号biz会直接返回PHONE_NUMBER_INVALID, 不按手机号计数
+	if r, ok := request.(interface{ GetPhoneNumber() string }); ok {
+		if phone, err := base.CheckAndGetPhoneNumber(r.GetPhoneNumber()); err == nil {
+			keys.Phone = phone
+		}
+	}
+	return keys
+}
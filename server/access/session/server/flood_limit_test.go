@@ -0,0 +1,59 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/proto/zproto"
+	"testing"
+)
+
+func TestGetRpcMethodName(t *testing.T) {
+	cases := []struct {
+		request mtproto.TLObject
+		method  string
+	}{
+		{mtproto.NewTLAuthSendCode(), "auth.sendCode"},
+		{mtproto.NewTLAuthSendCodeLayer51(), "auth.sendCode"},
+		{mtproto.NewTLHelpGetConfig(), "help.getConfig"},
+		{mtproto.NewTLPing(), ""},
+	}
+	for _, c := range cases {
+		if method := getRpcMethodName(c.request); method != c.method {
+			t.Errorf("invalid method: %s (need %s)", method, c.method)
+		}
+	}
+}
+
+func TestMakeFloodKeysPhone(t *testing.T) {
+	s := &clientSessionManager{authKeyId: 100}
+	md := &zproto.ZProtoMetadata{ClientAddr: "1.2.3.4:10000"}
+
+	// 同一个号码的不同格式使用同一个计数key
+	for _, phone := range []string{"+86 135 8888 8888", "8613588888888", "+86-135-8888-8888"} {
+		keys := s.makeFloodKeys(md, &mtproto.TLAuthSendCode{PhoneNumber: phone})
+		if keys.Phone != "8613588888888" || keys.IP != "1.2.3.4" || keys.AuthKeyId != 100 {
+			t.Errorf("invalid keys: %v (phone: %s)", keys, phone)
+		}
+	}
+
+	// 无效的手机号不按手机号计数
+	if keys := s.makeFloodKeys(md, &mtproto.TLAuthSendCode{PhoneNumber: "12"}); keys.Phone != "" {
+		t.Errorf("invalid phone key: %s", keys.Phone)
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/proto/zproto"
+)
+
+const (
+	kMaxInvokeAfterRequests = 128
+	kInvokeAfterTimeout     = 60 // 等待超过60秒返回MSG_WAIT_FAILED
+)
+
+// 等待依赖的请求完成后才能执行的invokeAfterMsg(s)
+type invokeAfterRequest struct {
+	connID      ClientConnID
+	md          *zproto.ZProtoMetadata
+	msgId       int64
+	seqNo       int32
+	afterMsgIds []int64
+	query       mtproto.TLObject
+	date        int64
+}
+
+func (r *invokeAfterRequest) String() string {
+	return fmt.Sprintf("{msg_id: %d, after_msg_ids: %v, date: %d}", r.msgId, r.afterMsgIds, r.date)
+}
+
+// 每个session一个等待队列, 只在clientSessionManager.runLoop里访问
+type invokeAfterQueue struct {
+	requests []*invokeAfterRequest
+}
+
+func newInvokeAfterQueue() *invokeAfterQueue {
+	return &invokeAfterQueue{
+		requests: []*invokeAfterRequest{},
+	}
+}
+
+func (q *invokeAfterQueue) push(r *invokeAfterRequest) bool {
+	if len(q.requests) >= kMaxInvokeAfterRequests {
+		return false
+	}
+	q.requests = append(q.requests, r)
+	return true
+}
+
+// 取出依赖都已完成的请求, 保持收到的顺序
+func (q *invokeAfterQueue) popReady(isCompleted func(msgId int64) bool) []*invokeAfterRequest {
+	var ready []*invokeAfterRequest
+	waiting := q.requests[:0]
+	for _, r := range q.requests {
+		completed := true
+		for _, id := range r.afterMsgIds {
+			if !isCompleted(id) {
+				completed = false
+				break
+			}
+		}
+		if completed {
+			ready = append(ready, r)
+		} else {
+			waiting = append(waiting, r)
+		}
+	}
+	q.requests = waiting
+	return ready
+}
+
+// 取出等待超时的请求
+func (q *invokeAfterQueue) popExpired(date int64) []*invokeAfterRequest {
+	var expired []*invokeAfterRequest
+	waiting := q.requests[:0]
+	for _, r := range q.requests {
+		if date-r.date > kInvokeAfterTimeout {
+			expired = append(expired, r)
+		} else {
+			waiting = append(waiting, r)
+		}
+	}
+	q.requests = waiting
+	return expired
+}
+
+// rpc_drop_answer取消还在等待的请求
+func (q *invokeAfterQueue) remove(msgId int64) *invokeAfterRequest {
+	for i, r := range q.requests {
+		if r.msgId == msgId {
+			q.requests = append(q.requests[:i], q.requests[i+1:]...)
+			return r
+		}
+	}
+	return nil
+}
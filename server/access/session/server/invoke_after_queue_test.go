@@ -0,0 +1,147 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"testing"
+)
+
+const testFirstMsgId = 6500000000000000000
+
+// 模拟session收到rpc请求
+func receiveTestRequest(s *messageStates, q *invokeAfterQueue, msgId int64, afterMsgIds ...int64) {
+	s.onReceived(msgId, 1)
+	s.setState(msgId, kMsgStateRpcRunning)
+	if len(afterMsgIds) > 0 {
+		q.push(&invokeAfterRequest{msgId: msgId, afterMsgIds: afterMsgIds})
+	}
+}
+
+func popReadyMsgIds(s *messageStates, q *invokeAfterQueue) []int64 {
+	var msgIds []int64
+	for _, r := range q.popReady(s.isCompleted) {
+		msgIds = append(msgIds, r.msgId)
+	}
+	return msgIds
+}
+
+func checkMsgIds(t *testing.T, msgIds []int64, need ...int64) {
+	if len(msgIds) != len(need) {
+		t.Fatalf("invalid msg_ids: %v (need %v)", msgIds, need)
+	}
+	for i := range need {
+		if msgIds[i] != need[i] {
+			t.Fatalf("invalid msg_ids: %v (need %v)", msgIds, need)
+		}
+	}
+}
+
+// a <- b <- c
+func TestInvokeAfterQueueChain(t *testing.T) {
+	s := newMessageStates(testFirstMsgId)
+	q := newInvokeAfterQueue()
+
+	a, b, c := int64(testFirstMsgId), int64(testFirstMsgId+4), int64(testFirstMsgId+8)
+	receiveTestRequest(s, q, a)
+	receiveTestRequest(s, q, b, a)
+	receiveTestRequest(s, q, c, b)
+
+	checkMsgIds(t, popReadyMsgIds(s, q))
+
+	s.onCompleted(a)
+	checkMsgIds(t, popReadyMsgIds(s, q), b)
+
+	// b已开始执行但还未完成
+	checkMsgIds(t, popReadyMsgIds(s, q))
+
+	s.onCompleted(b)
+	checkMsgIds(t, popReadyMsgIds(s, q), c)
+	if len(q.requests) != 0 {
+		t.Fatalf("invalid waiting requests: %v", q.requests)
+	}
+}
+
+// 同一个容器里被依赖的请求排在后面, 以及invokeAfterMsgs依赖多个请求
+func TestInvokeAfterQueueContainer(t *testing.T) {
+	s := newMessageStates(testFirstMsgId)
+	q := newInvokeAfterQueue()
+
+	a, b, c, d := int64(testFirstMsgId+4), int64(testFirstMsgId+8), int64(testFirstMsgId+12), int64(testFirstMsgId+16)
+	receiveTestRequest(s, q, c, a)
+	checkMsgIds(t, popReadyMsgIds(s, q))
+	receiveTestRequest(s, q, a)
+	receiveTestRequest(s, q, b)
+	receiveTestRequest(s, q, d, a, b)
+	checkMsgIds(t, popReadyMsgIds(s, q))
+
+	s.onCompleted(a)
+	checkMsgIds(t, popReadyMsgIds(s, q), c)
+
+	s.onCompleted(b)
+	checkMsgIds(t, popReadyMsgIds(s, q), d)
+}
+
+func TestInvokeAfterQueueUnknownMsgId(t *testing.T) {
+	s := newMessageStates(testFirstMsgId)
+	q := newInvokeAfterQueue()
+
+	// 依赖非rpc消息或已被遗忘的msg_id, 不用等待
+	s.onReceived(testFirstMsgId, 0)
+	receiveTestRequest(s, q, testFirstMsgId+4, testFirstMsgId)
+	receiveTestRequest(s, q, testFirstMsgId+8, testFirstMsgId-4)
+	checkMsgIds(t, popReadyMsgIds(s, q), testFirstMsgId+4, testFirstMsgId+8)
+
+	// 还未收到的msg_id需要等待
+	receiveTestRequest(s, q, testFirstMsgId+12, testFirstMsgId+100)
+	checkMsgIds(t, popReadyMsgIds(s, q))
+}
+
+func TestInvokeAfterQueueTimeout(t *testing.T) {
+	q := newInvokeAfterQueue()
+
+	for i := 0; i < kMaxInvokeAfterRequests; i++ {
+		if !q.push(&invokeAfterRequest{msgId: testFirstMsgId + int64(i)*4, date: int64(i)}) {
+			t.Fatalf("push %d failed", i)
+		}
+	}
+	if q.push(&invokeAfterRequest{msgId: testFirstMsgId - 4}) {
+		t.Fatal("queue must be full")
+	}
+
+	expired := q.popExpired(kInvokeAfterTimeout + 2)
+	if len(expired) != 2 || expired[0].msgId != testFirstMsgId || expired[1].msgId != testFirstMsgId+4 {
+		t.Fatalf("invalid expired requests: %v", expired)
+	}
+	if len(q.requests) != kMaxInvokeAfterRequests-2 {
+		t.Fatalf("invalid waiting requests: %d", len(q.requests))
+	}
+}
+
+func TestInvokeAfterQueueRemove(t *testing.T) {
+	q := newInvokeAfterQueue()
+	for i := 0; i < 3; i++ {
+		q.push(&invokeAfterRequest{msgId: testFirstMsgId + int64(i)*4})
+	}
+
+	if r := q.remove(testFirstMsgId + 4); r == nil || r.msgId != testFirstMsgId+4 {
+		t.Fatalf("invalid removed request: %v", r)
+	}
+	if q.remove(testFirstMsgId+4) != nil || len(q.requests) != 2 || q.requests[1].msgId != testFirstMsgId+8 {
+		t.Fatalf("invalid waiting requests: %v", q.requests)
+	}
+}
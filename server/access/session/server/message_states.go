@@ -0,0 +1,275 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"time"
+)
+
+// msgs_state_info的状态值
+// https://core.telegram.org/mtproto/service_messages_about_messages
+const (
+	kMsgStateUnknown     = 1 // msg_id太小, 已被遗忘
+	kMsgStateNotReceived = 2 // msg_id在记录范围内, 但未收到
+	kMsgStateTooHigh     = 3 // msg_id太大, 还未收到
+	kMsgStateReceived    = 4
+
+	kMsgStateAcked       = 8
+	kMsgStateNoAck       = 16
+	kMsgStateRpcRunning  = 32  // rpc正在处理或已处理完成
+	kMsgStateRpcAnswered = 64  // 已生成应答
+	kMsgStateKnown       = 128 // 客户端已知服务端收到该消息
+)
+
+const (
+	kMaxInboundMessages  = 1024
+	kMaxOutboundMessages = 1024
+	kMaxOutboundBytes    = 4 * 1024 * 1024
+
+	// 超过300秒客户端会认为msg_id无效, 不再重发
+	kMaxOutboundAge = 300
+
+	// 不超过该大小的消息直接重发, 否则只回复msg_detailed_info
+	kMaxResendBytes = 1024
+)
+
+// 客户端发来的消息
+type inboundMessage struct {
+	msgId       int64
+	state       uint8
+	answerMsgId int64
+	completed   bool // rpc请求已处理完成, 不论成功失败
+}
+
+// 发给客户端的消息
+type outboundMessage struct {
+	msgId    int64
+	seqNo    int32
+	bytes    int32
+	reqMsgId int64 // rpc_result的req_msg_id
+	acked    bool
+	date     int64
+	obj      mtproto.TLObject
+}
+
+func (m *outboundMessage) confirm() bool {
+	return m.seqNo&1 == 1
+}
+
+// 会话收发消息的记录, 超过上限时淘汰最早的记录
+// 只在clientSessionManager.runLoop里访问, 不需要加锁
+type messageStates struct {
+	inbound       map[int64]*inboundMessage
+	inboundList   []int64
+	lowMsgId      int64 // 小于lowMsgId的已被遗忘
+	highMsgId     int64
+	outbound      map[int64]*outboundMessage
+	outboundList  []int64
+	outboundBytes int
+}
+
+func newMessageStates(firstMsgId int64) *messageStates {
+	return &messageStates{
+		inbound:  make(map[int64]*inboundMessage),
+		lowMsgId: firstMsgId,
+		outbound: make(map[int64]*outboundMessage),
+	}
+}
+
+// 记录收到的消息, 重复的msg_id返回false
+func (s *messageStates) onReceived(msgId int64, seqNo int32) bool {
+	if _, ok := s.inbound[msgId]; ok {
+		return false
+	}
+
+	m := &inboundMessage{msgId: msgId, state: kMsgStateReceived}
+	if seqNo&1 == 0 {
+		m.state |= kMsgStateNoAck
+	}
+	s.inbound[msgId] = m
+	s.inboundList = append(s.inboundList, msgId)
+	if msgId < s.lowMsgId {
+		s.lowMsgId = msgId
+	}
+	if msgId > s.highMsgId {
+		s.highMsgId = msgId
+	}
+
+	for len(s.inboundList) > kMaxInboundMessages {
+		id := s.inboundList[0]
+		s.inboundList = s.inboundList[1:]
+		delete(s.inbound, id)
+		if id >= s.lowMsgId {
+			s.lowMsgId = id + 1
+		}
+	}
+	return true
+}
+
+func (s *messageStates) setState(msgId int64, state uint8) {
+	if m, ok := s.inbound[msgId]; ok {
+		m.state |= state
+	}
+}
+
+func (s *messageStates) getState(msgId int64) uint8 {
+	if m, ok := s.inbound[msgId]; ok {
+		return m.state
+	}
+
+	switch {
+	case msgId < s.lowMsgId:
+		return kMsgStateUnknown
+	case msgId > s.highMsgId:
+		return kMsgStateTooHigh
+	default:
+		return kMsgStateNotReceived
+	}
+}
+
+func (s *messageStates) onCompleted(msgId int64) {
+	if m, ok := s.inbound[msgId]; ok {
+		m.completed = true
+	}
+}
+
+// invokeAfterMsg(s)依赖的消息是否已完成: 非rpc消息收到即完成, 已被遗忘的也视为完成
+func (s *messageStates) isCompleted(msgId int64) bool {
+	m, ok := s.inbound[msgId]
+	if !ok {
+		return msgId < s.lowMsgId
+	}
+	return m.state&kMsgStateRpcRunning == 0 || m.completed
+}
+
+// msgs_state_info.info, 每个msg_id一个字节
+func (s *messageStates) getStates(msgIds []int64) string {
+	info := make([]byte, len(msgIds))
+	for i, id := range msgIds {
+		info[i] = s.getState(id)
+	}
+	return string(info)
+}
+
+// msgs_all_info, 不包括+128和+16的消息, 但+32未+64的仍需告知
+func (s *messageStates) getAllInfo() ([]int64, string) {
+	msgIds := make([]int64, 0)
+	info := make([]byte, 0)
+	for _, id := range s.inboundList {
+		state := s.inbound[id].state
+		running := state&kMsgStateRpcRunning != 0 && state&kMsgStateRpcAnswered == 0
+		if !running && state&(kMsgStateKnown|kMsgStateNoAck) != 0 {
+			continue
+		}
+		msgIds = append(msgIds, id)
+		info = append(info, state)
+	}
+	return msgIds, string(info)
+}
+
+// 记录发出的消息, rpc_result同时标记对应请求已应答
+func (s *messageStates) onSent(message *mtproto.TLMessage2) {
+	if _, ok := s.outbound[message.MsgId]; ok {
+		return
+	}
+
+	m := &outboundMessage{
+		msgId: message.MsgId,
+		seqNo: message.Seqno,
+		bytes: message.Bytes,
+		date:  time.Now().Unix(),
+		obj:   message.Object,
+	}
+	if rpcResult, ok := message.Object.(*mtproto.TLRpcResult); ok {
+		m.reqMsgId = rpcResult.ReqMsgId
+		if req, ok := s.inbound[m.reqMsgId]; ok {
+			// rpc_result同时也是对请求的确认
+			req.state |= kMsgStateRpcRunning | kMsgStateRpcAnswered | kMsgStateAcked
+			req.answerMsgId = m.msgId
+		}
+	}
+	s.outbound[m.msgId] = m
+	s.outboundList = append(s.outboundList, m.msgId)
+	s.outboundBytes += int(m.bytes)
+
+	for len(s.outboundList) > kMaxOutboundMessages || (len(s.outboundList) > 1 && s.outboundBytes > kMaxOutboundBytes) {
+		s.removeFrontOutbound()
+	}
+}
+
+func (s *messageStates) removeFrontOutbound() {
+	id := s.outboundList[0]
+	s.outboundList = s.outboundList[1:]
+	s.outboundBytes -= int(s.outbound[id].bytes)
+	delete(s.outbound, id)
+}
+
+// 删除超过kMaxOutboundAge的发送记录
+func (s *messageStates) expire(date int64) {
+	for len(s.outboundList) > 0 && date-s.outbound[s.outboundList[0]].date > kMaxOutboundAge {
+		s.removeFrontOutbound()
+	}
+}
+
+// 还未收到msgs_ack的content-related消息, 按发送顺序
+func (s *messageStates) unackedMessages() []*outboundMessage {
+	var messages []*outboundMessage
+	for _, id := range s.outboundList {
+		if m := s.outbound[id]; m.confirm() && !m.acked {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+func (s *messageStates) lookupOutbound(msgId int64) *outboundMessage {
+	return s.outbound[msgId]
+}
+
+// 查找请求对应的rpc_result
+func (s *messageStates) lookupAnswer(reqMsgId int64) *outboundMessage {
+	if req, ok := s.inbound[reqMsgId]; ok && req.answerMsgId != 0 {
+		return s.outbound[req.answerMsgId]
+	}
+	return nil
+}
+
+// rpc_drop_answer丢弃已生成但客户端还未确认的应答, 之后不再重发
+func (s *messageStates) dropAnswer(reqMsgId int64) *outboundMessage {
+	m := s.lookupAnswer(reqMsgId)
+	if m == nil || m.acked {
+		return nil
+	}
+	m.acked = true
+	return m
+}
+
+// 客户端确认收到, 如果是rpc_result, 客户端也就知道服务端收到了请求
+func (s *messageStates) onAck(msgIds []int64) {
+	for _, id := range msgIds {
+		m, ok := s.outbound[id]
+		if !ok {
+			continue
+		}
+		m.acked = true
+		if m.reqMsgId != 0 {
+			s.setState(m.reqMsgId, kMsgStateKnown)
+		}
+	}
+}
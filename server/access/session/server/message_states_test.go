@@ -0,0 +1,165 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"testing"
+)
+
+func TestMessageStates(t *testing.T) {
+	const firstMsgId = 6500000000000000000
+	s := newMessageStates(firstMsgId)
+
+	// 4: rpc请求, 8: msgs_ack
+	if !s.onReceived(firstMsgId+4, 1) || !s.onReceived(firstMsgId+8, 2) {
+		t.Fatal("new msg_id must be accepted")
+	}
+	if s.onReceived(firstMsgId+4, 1) {
+		t.Fatal("duplicate msg_id must be rejected")
+	}
+	s.setState(firstMsgId+4, kMsgStateRpcRunning)
+
+	info := s.getStates([]int64{firstMsgId - 4, firstMsgId + 4, firstMsgId + 8, firstMsgId + 12, firstMsgId + 100})
+	need := []byte{
+		kMsgStateUnknown,
+		kMsgStateReceived | kMsgStateRpcRunning,
+		kMsgStateReceived | kMsgStateNoAck,
+		kMsgStateTooHigh,
+		kMsgStateTooHigh,
+	}
+	if info != string(need) {
+		t.Fatalf("invalid info: %v (need %v)", []byte(info), need)
+	}
+
+	// 正在处理的rpc请求需要告知
+	msgIds, allInfo := s.getAllInfo()
+	if len(msgIds) != 1 || msgIds[0] != firstMsgId+4 || allInfo != string([]byte{kMsgStateReceived | kMsgStateRpcRunning}) {
+		t.Fatalf("invalid all info: %v, %v", msgIds, []byte(allInfo))
+	}
+
+	// 发送应答
+	answer := &mtproto.TLMessage2{
+		MsgId:  firstMsgId + 1,
+		Seqno:  1,
+		Bytes:  16,
+		Object: &mtproto.TLRpcResult{ReqMsgId: firstMsgId + 4, Result: mtproto.NewTLBoolTrue()},
+	}
+	s.onSent(answer)
+	if m := s.lookupAnswer(firstMsgId + 4); m == nil || m.msgId != answer.MsgId || !m.confirm() {
+		t.Fatalf("invalid answer: %v", m)
+	}
+	if state := s.getState(firstMsgId + 4); state != kMsgStateReceived|kMsgStateAcked|kMsgStateRpcRunning|kMsgStateRpcAnswered {
+		t.Fatalf("invalid state: %d", state)
+	}
+
+	// 客户端确认收到应答
+	s.onAck([]int64{answer.MsgId})
+	if s.dropAnswer(firstMsgId+4) != nil {
+		t.Fatal("acked answer must not be dropped")
+	}
+	if !s.lookupOutbound(answer.MsgId).acked || s.getState(firstMsgId+4)&kMsgStateKnown == 0 {
+		t.Fatal("answer must be acked")
+	}
+	if msgIds, _ = s.getAllInfo(); len(msgIds) != 0 {
+		t.Fatalf("invalid all info: %v", msgIds)
+	}
+}
+
+func TestMessageStatesLimit(t *testing.T) {
+	const firstMsgId = 6500000000000000000
+	s := newMessageStates(firstMsgId)
+
+	for i := 0; i < kMaxInboundMessages+10; i++ {
+		s.onReceived(firstMsgId+int64(i)*4, 1)
+	}
+	if len(s.inbound) != kMaxInboundMessages {
+		t.Fatalf("invalid inbound len: %d", len(s.inbound))
+	}
+	if state := s.getState(firstMsgId + 4); state != kMsgStateUnknown {
+		t.Fatalf("forgotten msg_id state: %d", state)
+	}
+	if state := s.getState(firstMsgId + 40); state != kMsgStateReceived {
+		t.Fatalf("invalid state: %d", state)
+	}
+
+	for i := 0; i < kMaxOutboundMessages+10; i++ {
+		s.onSent(&mtproto.TLMessage2{MsgId: firstMsgId + int64(i)*4 + 1, Seqno: 1, Bytes: 16, Object: mtproto.NewTLBoolTrue()})
+	}
+	if len(s.outbound) != kMaxOutboundMessages || s.lookupOutbound(firstMsgId+1) != nil {
+		t.Fatalf("invalid outbound len: %d", len(s.outbound))
+	}
+
+	// 超过字节上限
+	s.onSent(&mtproto.TLMessage2{MsgId: firstMsgId + 1<<32 + 1, Seqno: 1, Bytes: kMaxOutboundBytes, Object: mtproto.NewTLBoolTrue()})
+	if len(s.outbound) != 1 || s.outboundBytes != kMaxOutboundBytes {
+		t.Fatalf("invalid outbound: %d, %d", len(s.outbound), s.outboundBytes)
+	}
+}
+
+func TestMessageStatesUnacked(t *testing.T) {
+	const firstMsgId = 6500000000000000000
+	s := newMessageStates(firstMsgId)
+
+	// 1: 需要确认, 5: 不需要确认, 9: 需要确认
+	s.onSent(&mtproto.TLMessage2{MsgId: firstMsgId + 1, Seqno: 1, Bytes: 16, Object: mtproto.NewTLBoolTrue()})
+	s.onSent(&mtproto.TLMessage2{MsgId: firstMsgId + 5, Seqno: 2, Bytes: 16, Object: mtproto.NewTLBoolTrue()})
+	s.onSent(&mtproto.TLMessage2{MsgId: firstMsgId + 9, Seqno: 3, Bytes: 16, Object: mtproto.NewTLBoolTrue()})
+
+	s.onAck([]int64{firstMsgId + 1})
+	unacked := s.unackedMessages()
+	if len(unacked) != 1 || unacked[0].msgId != firstMsgId+9 {
+		t.Fatalf("invalid unacked messages: %v", unacked)
+	}
+
+	// 超时后不再重发
+	s.expire(unacked[0].date + kMaxOutboundAge)
+	if len(s.unackedMessages()) != 1 {
+		t.Fatal("message must not be expired")
+	}
+	s.expire(unacked[0].date + kMaxOutboundAge + 1)
+	if len(s.unackedMessages()) != 0 || len(s.outbound) != 0 || s.outboundBytes != 0 {
+		t.Fatalf("messages must be expired: %d, %d", len(s.outbound), s.outboundBytes)
+	}
+}
+
+func TestMessageStatesDropAnswer(t *testing.T) {
+	const firstMsgId = 6500000000000000000
+	s := newMessageStates(firstMsgId)
+
+	s.onReceived(firstMsgId+4, 1)
+	s.setState(firstMsgId+4, kMsgStateRpcRunning)
+	if s.dropAnswer(firstMsgId+4) != nil {
+		t.Fatal("answer not generated")
+	}
+
+	s.onSent(&mtproto.TLMessage2{
+		MsgId:  firstMsgId + 1,
+		Seqno:  1,
+		Bytes:  16,
+		Object: &mtproto.TLRpcResult{ReqMsgId: firstMsgId + 4, Result: mtproto.NewTLBoolTrue()},
+	})
+	if m := s.dropAnswer(firstMsgId + 4); m == nil || m.msgId != firstMsgId+1 || m.seqNo != 1 || m.bytes != 16 {
+		t.Fatalf("invalid dropped answer: %v", m)
+	}
+
+	// 被丢弃的应答不再重发
+	if len(s.unackedMessages()) != 0 || s.dropAnswer(firstMsgId+4) != nil {
+		t.Fatal("answer must be dropped")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+const (
+	// 客户端msg_id允许的时间范围
+	kMsgIdTimeTooLow  = 300
+	kMsgIdTimeTooHigh = 30
+
+	kMsgIdWindowSize = 4096
+)
+
+// 每个auth_key最近收到的msg_id, 用于拒绝重放的消息
+// 超过300秒的msg_id已经不能通过时间校验, 可以直接淘汰
+type msgIdWindow struct {
+	ids     map[int64]struct{}
+	idList  []int64
+	floorId int64 // 因数量上限淘汰的最大msg_id, 不大于它的无法确认是否收到过
+}
+
+func newMsgIdWindow() *msgIdWindow {
+	return &msgIdWindow{
+		ids: make(map[int64]struct{}),
+	}
+}
+
+func (w *msgIdWindow) contains(msgId int64) bool {
+	_, ok := w.ids[msgId]
+	return ok
+}
+
+// msg_id已被淘汰, 无法确认是否收到过
+func (w *msgIdWindow) tooOld(msgId int64) bool {
+	return msgId <= w.floorId && !w.contains(msgId)
+}
+
+func (w *msgIdWindow) add(msgId int64, date int64) {
+	if w.contains(msgId) {
+		return
+	}
+	w.ids[msgId] = struct{}{}
+	w.idList = append(w.idList, msgId)
+
+	for len(w.idList) > 0 {
+		id := w.idList[0]
+		if len(w.idList) > kMsgIdWindowSize {
+			if id > w.floorId {
+				w.floorId = id
+			}
+		} else if id>>32 >= date-kMsgIdTimeTooLow {
+			break
+		}
+		w.idList = w.idList[1:]
+		delete(w.ids, id)
+	}
+}
@@ -0,0 +1,51 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"testing"
+)
+
+func TestMsgIdWindow(t *testing.T) {
+	const date = 1500000000
+	w := newMsgIdWindow()
+
+	msgId := int64(date)<<32 | 4
+	w.add(msgId, date)
+	if !w.contains(msgId) || w.contains(msgId+4) || w.tooOld(msgId+4) {
+		t.Fatal("invalid window")
+	}
+
+	// 超过300秒的淘汰掉
+	w.add(int64(date+kMsgIdTimeTooLow+1)<<32, date+kMsgIdTimeTooLow+1)
+	if w.contains(msgId) || len(w.idList) != 1 {
+		t.Fatalf("msg_id must be expired: %v", w.idList)
+	}
+
+	// 超过数量上限淘汰的msg_id无法确认
+	w = newMsgIdWindow()
+	for i := 0; i <= kMsgIdWindowSize; i++ {
+		w.add(msgId+int64(i)*4, date)
+	}
+	if len(w.ids) != kMsgIdWindowSize || w.contains(msgId) || !w.tooOld(msgId) {
+		t.Fatal("first msg_id must be evicted")
+	}
+	if w.tooOld(msgId+4) || !w.contains(msgId+4) || w.tooOld(msgId+int64(kMsgIdWindowSize+1)*4) {
+		t.Fatal("invalid window")
+	}
+}
@@ -19,9 +19,50 @@ package server
 
 import (
 	"fmt"
+	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 )
 
+// gzip_packed里直接嵌套gzip_packed最多解开的层数
+const kMaxGzipPackedDepth = 2
+
+// query:!X可能被gzip_packed压缩
+func decodeQuery(b []byte) mtproto.TLObject {
+	dbuf := mtproto.NewDecodeBuf(b)
+	query := dbuf.Object()
+	if dbuf.GetError() != nil {
+		glog.Errorf("decode query error: %v", dbuf.GetError())
+		return nil
+	}
+
+	if gzipPacked, ok := query.(*mtproto.TLGzipPacked); ok {
+		return unpackGzipPacked(gzipPacked)
+	}
+	return query
+}
+
+// 解开gzip_packed, 嵌套超过kMaxGzipPackedDepth层时返回nil
+func unpackGzipPacked(gzipPacked *mtproto.TLGzipPacked) mtproto.TLObject {
+	for depth := 1; ; depth++ {
+		dbuf := mtproto.NewDecodeBuf(gzipPacked.PackedData)
+		o := dbuf.Object()
+		if o == nil || dbuf.GetError() != nil {
+			glog.Errorf("decode gzip_packed error: %v", dbuf.GetError())
+			return nil
+		}
+
+		next, ok := o.(*mtproto.TLGzipPacked)
+		if !ok {
+			return o
+		}
+		if depth >= kMaxGzipPackedDepth {
+			glog.Errorf("decode gzip_packed error: too many nested gzip_packed")
+			return nil
+		}
+		gzipPacked = next
+	}
+}
+
 // invokeAfterMsg#cb9f372d {X:Type} msg_id:long query:!X = X;
 type TLInvokeAfterMsgExt struct {
 	MsgId int64
@@ -29,8 +70,7 @@ type TLInvokeAfterMsgExt struct {
 }
 
 func NewInvokeAfterMsgExt(invokeAfterMsg *mtproto.TLInvokeAfterMsg) *TLInvokeAfterMsgExt {
-	dbuf := mtproto.NewDecodeBuf(invokeAfterMsg.Query)
-	query := dbuf.Object()
+	query := decodeQuery(invokeAfterMsg.Query)
 
 	return &TLInvokeAfterMsgExt{
 		MsgId: invokeAfterMsg.MsgId,
@@ -61,8 +101,7 @@ type TLInvokeAfterMsgsExt struct {
 }
 
 func NewInvokeAfterMsgsExt(invokeAfterMsgs *mtproto.TLInvokeAfterMsgs) *TLInvokeAfterMsgsExt {
-	dbuf := mtproto.NewDecodeBuf(invokeAfterMsgs.Query)
-	query := dbuf.Object()
+	query := decodeQuery(invokeAfterMsgs.Query)
 
 	return &TLInvokeAfterMsgsExt{
 		MsgIds: invokeAfterMsgs.MsgIds,
@@ -99,8 +138,7 @@ type TLInitConnectionExt struct {
 }
 
 func NewInitConnectionExt(initConnection *mtproto.TLInitConnection) *TLInitConnectionExt {
-	dbuf := mtproto.NewDecodeBuf(initConnection.Query)
-	query := dbuf.Object()
+	query := decodeQuery(initConnection.Query)
 
 	return &TLInitConnectionExt{
 		ApiId:          initConnection.ApiId,
@@ -138,8 +176,7 @@ type TLInvokeWithLayerExt struct {
 }
 
 func NewInvokeWithLayerExt(invokeWithLayer *mtproto.TLInvokeWithLayer) *TLInvokeWithLayerExt {
-	dbuf := mtproto.NewDecodeBuf(invokeWithLayer.Query)
-	query := dbuf.Object()
+	query := decodeQuery(invokeWithLayer.Query)
 
 	return &TLInvokeWithLayerExt{
 		Query: query,
@@ -168,8 +205,7 @@ type TLInvokeWithoutUpdatesExt struct {
 }
 
 func NewInvokeWithoutUpdatesExt(invokeWithoutUpdates *mtproto.TLInvokeWithoutUpdates) *TLInvokeWithoutUpdatesExt {
-	dbuf := mtproto.NewDecodeBuf(invokeWithoutUpdates.Query)
-	query := dbuf.Object()
+	query := decodeQuery(invokeWithoutUpdates.Query)
 
 	return &TLInvokeWithoutUpdatesExt{
 		Query: query,
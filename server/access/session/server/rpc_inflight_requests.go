@@ -0,0 +1,84 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+// 已转发或等待转发给biz_server的rpc请求, 用于rpc_drop_answer取消请求
+// runLoop加入和取消, rpcRunLoop开始和结束, 需要加锁
+type rpcInflightRequests struct {
+	mu       sync.Mutex
+	requests map[int64]context.CancelFunc // 等待执行的请求为nil
+}
+
+func newRpcInflightRequests() *rpcInflightRequests {
+	return &rpcInflightRequests{
+		requests: make(map[int64]context.CancelFunc),
+	}
+}
+
+func (r *rpcInflightRequests) push(msgId int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests[msgId] = nil
+}
+
+// 开始执行请求, 已被取消的请求返回false
+func (r *rpcInflightRequests) start(msgId int64) (context.Context, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.requests[msgId]; !ok {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.requests[msgId] = cancel
+	return ctx, true
+}
+
+func (r *rpcInflightRequests) finish(msgId int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cancel := r.requests[msgId]; cancel != nil {
+		cancel()
+	}
+	delete(r.requests, msgId)
+}
+
+// 取消请求, 正在执行的取消grpc调用, 还未开始执行的不再执行
+// 请求已执行完成返回false
+func (r *rpcInflightRequests) drop(msgId int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cancel, ok := r.requests[msgId]
+	if !ok {
+		return false
+	}
+	if cancel != nil {
+		cancel()
+	}
+	delete(r.requests, msgId)
+	return true
+}
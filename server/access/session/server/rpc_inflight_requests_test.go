@@ -0,0 +1,54 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"testing"
+)
+
+func TestRpcInflightRequests(t *testing.T) {
+	r := newRpcInflightRequests()
+
+	// 正在执行的请求被取消
+	r.push(4)
+	ctx, ok := r.start(4)
+	if !ok || ctx.Err() != nil {
+		t.Fatal("request must be started")
+	}
+	if !r.drop(4) || ctx.Err() == nil {
+		t.Fatal("running request must be canceled")
+	}
+	r.finish(4)
+
+	// 还未开始执行的请求被取消
+	r.push(8)
+	if !r.drop(8) {
+		t.Fatal("queued request must be dropped")
+	}
+	if _, ok := r.start(8); ok {
+		t.Fatal("dropped request must not be started")
+	}
+
+	// 已执行完成的请求
+	r.push(12)
+	ctx, _ = r.start(12)
+	r.finish(12)
+	if r.drop(12) || len(r.requests) != 0 {
+		t.Fatalf("invalid requests: %v", r.requests)
+	}
+}
@@ -23,12 +23,17 @@ import (
 	"github.com/nebulaim/telegramd/baselib/base"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/net2"
+	"github.com/nebulaim/telegramd/baselib/nsq_client"
+	"github.com/nebulaim/telegramd/baselib/rate_limit"
 	"github.com/nebulaim/telegramd/baselib/redis_client"
 	"github.com/nebulaim/telegramd/biz/dal/dao"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
+	"github.com/nebulaim/telegramd/server/access/session/proto"
+	"github.com/nebulaim/telegramd/server/sync/sync_client"
 	"github.com/nebulaim/telegramd/service/idgen/client"
 	"github.com/nebulaim/telegramd/service/status/client"
+	"google.golang.org/grpc"
 	"time"
 )
 
@@ -42,6 +47,8 @@ type SessionServer struct {
 	authSessionRpcClient mtproto.RPCSessionClient
 	sessionManager       *sessionManager
 	syncHandler          *syncHandler
+	adminServer          *grpc_util.RPCServer
+	pushConsumer         nsq_client.Consumer
 }
 
 func NewSessionServer() *SessionServer {
@@ -65,6 +72,18 @@ func (s *SessionServer) Initialize() error {
 
 	s.status, _ = status_client.NewStatusClient("redis", "cache")
 
+	floodLimiter, err = rate_limit.NewFloodLimiter("session_flood", Conf.FloodLimit)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
+	err = initSessionStateStore(Conf.SessionState.Redis)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
 	// 初始化redis_dao、mysql_dao
 	dao.InstallRedisDAOManager(redis_client.GetRedisClientManager())
 	// TODO(@benqi): config cap
@@ -73,6 +92,19 @@ func (s *SessionServer) Initialize() error {
 	s.sessionManager = newSessionManager()
 	s.syncHandler = newSyncHandler(s.sessionManager)
 	s.server = zproto.NewZProtoServer(Conf.Server, s)
+	if Conf.AdminServer != nil {
+		s.adminServer = grpc_util.NewRpcServer(Conf.AdminServer.Addr, &Conf.AdminServer.RpcDiscovery)
+	}
+	if Conf.PushQueue != nil {
+		s.pushConsumer, err = nsq_client.NewNsqConsumer(Conf.PushQueue)
+		if err != nil {
+			glog.Fatal(err)
+			return err
+		}
+		topic := sync_client.SessionPushTopic(getServerID())
+		s.pushConsumer.AddHandler(topic, s.syncHandler.onQueueMessage)
+		s.pushConsumer.AddFailHandler(topic, s.syncHandler.onQueueMessageFailed)
+	}
 
 	return nil
 }
@@ -92,12 +124,34 @@ func (s *SessionServer) RunLoop() {
 	c, _ = grpc_util.NewRPCClient(&Conf.AuthSessionRpcClient)
 	s.authSessionRpcClient = mtproto.NewRPCSessionClient(c.GetClientConn())
 
+	if s.adminServer != nil {
+		go s.adminServer.Serve2(func(s2 *grpc.Server) {
+			session_admin.RegisterRPCSessionAdminServer(s2, newSessionAdminServer(s.sessionManager))
+		})
+	}
+
+	if s.pushConsumer != nil {
+		if err := s.pushConsumer.Run(); err != nil {
+			glog.Fatal(err)
+		}
+	}
+
 	s.server.Serve()
 }
 
 func (s *SessionServer) Destroy() {
 	glog.Infof("sessionServer - destroy...")
+	// 先从etcd注销让frontend把auth_key路由到其他节点, 再等待rpc完成并保存session状态
+	s.server.Deregister()
+	if s.pushConsumer != nil {
+		// 停止消费, 没确认的推送留在队列里
+		s.pushConsumer.Stop()
+	}
+	s.sessionManager.drain(getDrainTimeout())
 	s.server.Stop()
+	if s.adminServer != nil {
+		s.adminServer.Stop()
+	}
 	time.Sleep(1 * time.Second)
 	// s.client.Stop()
 }
@@ -0,0 +1,249 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/proto/zproto"
+	"github.com/nebulaim/telegramd/server/access/session/proto"
+	"golang.org/x/net/context"
+)
+
+// 管理接口: 查看在线的auth_key和session, 吊销授权时强制断开
+const (
+	kAdminRequestTimeout = 5 * time.Second
+	kMaxListAuthKeys     = 1000
+)
+
+type sessionAdminServer struct {
+	sessionManager *sessionManager
+}
+
+func newSessionAdminServer(sessionManager *sessionManager) *sessionAdminServer {
+	return &sessionAdminServer{sessionManager: sessionManager}
+}
+
+func (a *sessionAdminServer) ListSessions(ctx context.Context, request *session_admin.ListSessionsRequest) (*session_admin.ListSessionsResponse, error) {
+	glog.Infof("ListSessions - request: {%s}", request)
+
+	ctx, cancel := context.WithTimeout(ctx, kAdminRequestTimeout)
+	defer cancel()
+
+	reply := &session_admin.ListSessionsResponse{ServerId: getServerID()}
+
+	a.sessionManager.forEachManager(request.AuthKeyId, func(sessList *clientSessionManager) bool {
+		var sessions *session_admin.AuthKeySessions
+		ok := sessList.runInLoop(func() {
+			if request.UserId == 0 || sessList.AuthUserId == request.UserId {
+				sessions = sessList.authKeySessions()
+			}
+		}, ctx)
+		if ok && sessions != nil {
+			reply.AuthKeys = append(reply.AuthKeys, sessions)
+		}
+		return len(reply.AuthKeys) < kMaxListAuthKeys && ctx.Err() == nil
+	})
+
+	glog.Infof("ListSessions - reply: {auth_keys: %d}", len(reply.AuthKeys))
+	return reply, nil
+}
+
+func (a *sessionAdminServer) CloseSessions(ctx context.Context, request *session_admin.CloseSessionsRequest) (*session_admin.CloseSessionsResponse, error) {
+	glog.Infof("CloseSessions - request: {%s}", request)
+
+	ctx, cancel := context.WithTimeout(ctx, kAdminRequestTimeout)
+	defer cancel()
+
+	var closed int32
+
+	switch {
+	case request.AuthKeyId != 0 && request.SessionId != 0:
+		a.sessionManager.forEachManager(request.AuthKeyId, func(sessList *clientSessionManager) bool {
+			if sessList.authKeyId != request.AuthKeyId {
+				return true
+			}
+			var ok bool
+			if sessList.runInLoop(func() { ok = sessList.closeSession(request.SessionId) }, ctx) && ok {
+				closed++
+			}
+			return false
+		})
+	case request.AuthKeyId != 0:
+		// 包括绑定到该永久key的临时key
+		a.sessionManager.forEachManager(request.AuthKeyId, func(sessList *clientSessionManager) bool {
+			if sessList.runInLoop(func() { sessList.closeAuthKey() }, ctx) {
+				closed++
+			}
+			return ctx.Err() == nil
+		})
+	case request.UserId != 0:
+		a.sessionManager.forEachManager(0, func(sessList *clientSessionManager) bool {
+			var ok bool
+			sessList.runInLoop(func() {
+				if sessList.AuthUserId == request.UserId && sessList.userAuthKeyId() != request.ExceptAuthKeyId {
+					sessList.closeAuthKey()
+					ok = true
+				}
+			}, ctx)
+			if ok {
+				closed++
+			}
+			return ctx.Err() == nil
+		})
+	default:
+		err := fmt.Errorf("CloseSessions - invalid request: {%s}", request)
+		glog.Error(err)
+		return nil, err
+	}
+
+	glog.Infof("CloseSessions - reply: {closed: %d}", closed)
+	return &session_admin.CloseSessionsResponse{Closed: closed}, nil
+}
+
+// authKeyId不为0时只遍历该auth_key以及绑定到它的临时key, f返回false时停止
+func (s *sessionManager) forEachManager(authKeyId int64, f func(sessList *clientSessionManager) bool) {
+	s.sessions.Range(func(key, value interface{}) bool {
+		sessList := value.(*clientSessionManager)
+		if authKeyId != 0 && sessList.authKeyId != authKeyId && sessList.permAuthKeyId.Get() != authKeyId {
+			return true
+		}
+		return f(sessList)
+	})
+}
+
+// 在runLoop里执行f, ctx超时或者已关闭时返回false
+func (s *clientSessionManager) runInLoop(f func(), ctx context.Context) bool {
+	admin := &adminData{f: f, done: make(chan struct{})}
+
+	select {
+	case s.sessionDataChan <- admin:
+	case <-s.closeChan:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+
+	select {
+	case <-admin.done:
+		return true
+	case <-s.closeChan:
+		// f里关闭了auth_key时runLoop会在done之后退出
+		select {
+		case <-admin.done:
+			return true
+		default:
+			return false
+		}
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *clientSessionManager) authKeySessions() *session_admin.AuthKeySessions {
+	sessions := &session_admin.AuthKeySessions{
+		AuthKeyId:     s.authKeyId,
+		AuthKeyType:   s.authKeyType,
+		PermAuthKeyId: s.permAuthKeyId.Get(),
+		UserId:        s.AuthUserId,
+		Layer:         s.Layer,
+		RpcPendings:   int32(s.rpcPendings),
+		SyncMessages:  int32(len(s.updatesSession.syncMessages)),
+		LastActive:    s.lastActiveDate,
+		Sessions:      make([]*session_admin.SessionInfo, 0, len(s.sessions)),
+	}
+
+	for _, sess := range s.sessions {
+		sessions.Sessions = append(sessions.Sessions, &session_admin.SessionInfo{
+			SessionId:           sess.sessionId,
+			IsUpdates:           sess.isUpdates,
+			NextSeqNo:           sess.nextSeqNo,
+			LastMsgId:           sess.lastMsgId,
+			PendingMessages:     int32(len(sess.pendingMessages)),
+			RpcMessages:         int32(len(sess.rpcMessages)),
+			InvokeAfterMessages: int32(len(sess.invokeAfterQueue.requests)),
+			UnackedMessages:     int32(len(sess.msgStates.unackedMessages())),
+			LastActive:          sess.lastActiveDate,
+		})
+	}
+	return sessions
+}
+
+// 关闭单个session, 客户端再用这个session_id时会收到new_session_created
+func (s *clientSessionManager) closeSession(sessionId int64) bool {
+	if _, ok := s.restoredStates[sessionId]; ok {
+		delete(s.restoredStates, sessionId)
+		s.stateDirty = true
+		return true
+	}
+
+	sess, ok := s.sessions[sessionId]
+	if !ok {
+		return false
+	}
+
+	glog.Infof("closeSession - {sess: %s, session_id: %d}", s, sessionId)
+	delete(s.sessions, sessionId)
+	if s.updatesSession.session == sess {
+		s.updatesSession.session = nil
+		s.updatesSession.connState = kConnUnknown
+	}
+	s.stateDirty = true
+	return true
+}
+
+// 关闭整个auth_key: 客户端收到-404后会重新生成auth_key
+func (s *clientSessionManager) closeAuthKey() {
+	glog.Infof("closeAuthKey - {sess: %s}", s)
+
+	var connIDs []ClientConnID
+	addConnID := func(connID ClientConnID) {
+		if connID.clientConnID == 0 {
+			return
+		}
+		for _, id := range connIDs {
+			if id.Equal(connID) {
+				return
+			}
+		}
+		connIDs = append(connIDs, connID)
+	}
+	for _, sess := range s.sessions {
+		addConnID(sess.lastConnID)
+	}
+	if s.updatesSession.connState == kTcpConn {
+		addConnID(s.updatesSession.tcpConnID)
+	}
+
+	for _, connID := range connIDs {
+		sendTransportErrorByConnID(connID.clientConnID, &zproto.ZProtoMetadata{}, &zproto.ZProtoTransportError{
+			SessionId: connID.frontendConnID,
+			ErrorCode: mtproto.TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND,
+		})
+	}
+
+	s.sessions = make(map[int64]*clientSessionHandler)
+	s.restoredStates = make(map[int64]*sessionState)
+	s.stateDirty = false
+	s.deleteSessionStates()
+	deleteCacheAuthKey(s.authKeyId)
+	deleteClientSessionManager(s.authKeyId)
+}
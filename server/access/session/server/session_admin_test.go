@@ -0,0 +1,83 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestSessionAdminCloseSession(t *testing.T) {
+	withTestSessionStateStore(t, "node1", func(store *testSessionStateStore) {
+		s := newTestStateSessionManager(100)
+		s.AuthUserId = 1
+		s.Layer = 85
+
+		sess1 := newClientSessionHandler(1, 2, testFirstMsgId, s)
+		sess1.isUpdates = true
+		s.sessions[1] = sess1
+		s.sessions[2] = newClientSessionHandler(2, 2, testFirstMsgId, s)
+		s.updatesSession.session = sess1
+		s.updatesSession.connState = kTcpConn
+		s.restoredStates[3] = &sessionState{SessionId: 3}
+
+		sessions := s.authKeySessions()
+		if sessions.AuthKeyId != 100 || sessions.UserId != 1 || sessions.Layer != 85 || len(sessions.Sessions) != 2 {
+			t.Fatalf("invalid sessions: %v", sessions)
+		}
+
+		if s.closeSession(4) {
+			t.Fatal("unknown session closed")
+		}
+		if !s.closeSession(3) || len(s.restoredStates) != 0 {
+			t.Fatal("close restored session failed")
+		}
+		if !s.closeSession(1) || len(s.sessions) != 1 || !s.stateDirty {
+			t.Fatal("close session failed")
+		}
+		if s.updatesSession.session != nil || s.updatesSession.connState != kConnUnknown {
+			t.Fatal("updates session not reset")
+		}
+
+		// 关闭后保存的状态里不再有该session
+		s.saveSessionStates(true)
+		s2 := newTestStateSessionManager(100)
+		if len(s2.restoredStates) != 1 || s2.restoredStates[2] == nil {
+			t.Fatalf("invalid restored states: %v", s2.restoredStates)
+		}
+	})
+}
+
+func TestSessionAdminRunInLoopTimeout(t *testing.T) {
+	// runLoop没有运行, 每次等待都受同一个ctx的超时限制
+	s := &clientSessionManager{closeChan: make(chan struct{}), sessionDataChan: make(chan interface{})}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if s.runInLoop(func() {}, ctx) {
+			t.Fatal("runInLoop must time out")
+		}
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("runInLoop timeout too long: %v", d)
+	}
+}
@@ -21,12 +21,16 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/sync2"
+	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
 	"sync"
+	"time"
 )
 
 type sessionManager struct {
 	sessions sync.Map // map[int64]*sessionClientList
+	draining sync2.AtomicInt32
 }
 
 func newSessionManager() *sessionManager {
@@ -36,6 +40,10 @@ func newSessionManager() *sessionManager {
 func (s *sessionManager) onSessionClientNew(clientConnID uint64, md *zproto.ZProtoMetadata, sessData *zproto.ZProtoSessionClientNew) error {
 	glog.Infof("onSessionClientNew - receive data: {client_conn_id: %s, md: %s, sess_data: %s}", clientConnID, md, sessData)
 
+	if s.draining.Get() == 1 {
+		return errSessionServerDraining
+	}
+
 	var sessList *clientSessionManager
 
 	if vv, ok := s.sessions.Load(sessData.AuthKeyId); !ok {
@@ -57,27 +65,54 @@ func (s *sessionManager) onSessionData(clientConnID uint64, md *zproto.ZProtoMet
 		md,
 		sessData)
 
+	// 停服中, 客户端收不到ack会在接管的节点上重发
+	if s.draining.Get() == 1 {
+		return errSessionServerDraining
+	}
+
 	////
 	authKeyId := int64(binary.LittleEndian.Uint64(sessData.MtpRawData))
 
 	// TODO(@benqi): sync s.sessions
 	var sessList *clientSessionManager
 	if vv, ok := s.sessions.Load(authKeyId); !ok {
-		authKey := getCacheAuthKey(authKeyId)
-		if authKey == nil {
+		authKeyValue, ok := getCacheAuthKeyValue(authKeyId)
+		if !ok || authKeyValue.AuthKey == nil {
 			err := fmt.Errorf("onSessionData - not found authKeyId: {%d}", authKeyId)
 			glog.Error(err)
+
+			// 客户端收到-404后会重新生成auth_key
+			sendTransportErrorByConnID(clientConnID, md, &zproto.ZProtoTransportError{
+				SessionId: sessData.SessionId,
+				ErrorCode: mtproto.TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND,
+			})
 			return err
 		}
 
-		sessList = newClientSessionManager(authKeyId, authKey, 0)
+		sessList = newClientSessionManager(authKeyId, authKeyValue.AuthKey, 0, authKeyValue.AuthKeyType, authKeyValue.PermAuthKeyId)
+		sessList.takeOverSessionStates()
 		s.sessions.Store(authKeyId, sessList)
 		s.onNewSessionClientManager(sessList)
 	} else {
 		sessList, _ = vv.(*clientSessionManager)
+
+		// 临时key过期后客户端需要重新生成临时key
+		if sessList.authKeyType == mtproto.AUTH_KEY_TYPE_TEMP {
+			if _, ok := getCacheAuthKeyValue(authKeyId); !ok {
+				err := fmt.Errorf("onSessionData - temp authKeyId expired: {%d}", authKeyId)
+				glog.Error(err)
+
+				sendTransportErrorByConnID(clientConnID, md, &zproto.ZProtoTransportError{
+					SessionId: sessData.SessionId,
+					ErrorCode: mtproto.TRANSPORT_ERROR_AUTH_KEY_NOT_FOUND,
+				})
+				s.onCloseSessionClientManager(authKeyId)
+				return err
+			}
+		}
 	}
 
-	return sessList.OnSessionDataArrived(makeClientConnID(sessData.ConnType, clientConnID, sessData.SessionId), md, sessData.MtpRawData)
+	return sessList.OnSessionDataArrived(makeClientConnID(sessData.ConnType, clientConnID, sessData.SessionId), md, sessData.QuickAck, sessData.MtpRawData)
 }
 
 func (s *sessionManager) onSessionClientClosed(clientConnID uint64, md *zproto.ZProtoMetadata, sessData *zproto.ZProtoSessionClientClosed) error {
@@ -87,6 +122,10 @@ func (s *sessionManager) onSessionClientClosed(clientConnID uint64, md *zproto.Z
 		md,
 		sessData)
 
+	if s.draining.Get() == 1 {
+		return errSessionServerDraining
+	}
+
 	var sessList *clientSessionManager
 
 	if vv, ok := s.sessions.Load(sessData.AuthKeyId); !ok {
@@ -106,6 +145,10 @@ func (s *sessionManager) onSyncData(authKeyId int64, md *zproto.ZProtoMetadata,
 		md,
 		data)
 
+	if s.draining.Get() == 1 {
+		return errSessionServerDraining
+	}
+
 	var sessList *clientSessionManager
 
 	if vv, ok := s.sessions.Load(authKeyId); !ok {
@@ -131,3 +174,63 @@ func (s *sessionManager) onCloseSessionClientManager(authKeyId int64) {
 		s.sessions.Delete(authKeyId)
 	}
 }
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////
+var errSessionServerDraining = fmt.Errorf("session server draining")
+
+// 停服前调用: 不再接收新数据, 等待各auth_key正在执行的rpc完成并把session状态保存到redis,
+// 由frontend重新路由到的节点接管
+func (s *sessionManager) drain(timeout time.Duration) {
+	s.draining.Set(1)
+
+	var (
+		managers []*clientSessionManager
+		drains   []*drainData
+		timeoutC = time.After(timeout)
+	)
+
+	s.sessions.Range(func(key, value interface{}) bool {
+		managers = append(managers, value.(*clientSessionManager))
+		return true
+	})
+	glog.Infof("drain - {sessions: %d, timeout: %v}", len(managers), timeout)
+
+	drained := 0
+	for _, sessList := range managers {
+		d := &drainData{done: make(chan struct{})}
+		select {
+		case sessList.sessionDataChan <- d:
+			drains = append(drains, d)
+		case <-sessList.closeChan:
+			drains = append(drains, nil)
+		case <-timeoutC:
+			glog.Warningf("drain - timeout: {drained: %d, total: %d}", drained, len(managers))
+			s.closeAll(managers)
+			return
+		}
+	}
+
+	for i, d := range drains {
+		if d == nil {
+			continue
+		}
+		select {
+		case <-d.done:
+			drained++
+		case <-managers[i].closeChan:
+		case <-timeoutC:
+			glog.Warningf("drain - timeout: {drained: %d, total: %d}", drained, len(managers))
+			s.closeAll(managers)
+			return
+		}
+	}
+
+	glog.Infof("drain - done: {drained: %d, total: %d}", drained, len(managers))
+	s.closeAll(managers)
+}
+
+func (s *sessionManager) closeAll(managers []*clientSessionManager) {
+	for _, sessList := range managers {
+		s.onCloseSessionClientManager(sessList.authKeyId)
+	}
+}
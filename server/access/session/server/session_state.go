@@ -0,0 +1,305 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/gomodule/redigo/redis"
+	"github.com/nebulaim/telegramd/baselib/redis_client"
+)
+
+// session状态交接:
+// session节点增减后frontend的ketama会把auth_key路由到其他节点,
+// 把每个session的seqno、msg_id、是否订阅updates以及layer保存到redis, 接管的节点据此重建session,
+// 客户端不会看到seqno错乱, 也不会丢失updates订阅
+const (
+	kSessionStateTTL          = 24 * 60 * 60 // redis里状态的过期时间, 每次保存时刷新
+	kSessionStateSaveInterval = 1            // 有变化时最多每秒保存一次
+	kSessionStateCheckIdle    = 3            // 空闲超过该秒数后收到数据, 先检查是否已被其他节点接管
+	kDefaultDrainTimeout      = 10 * time.Second
+)
+
+type sessionState struct {
+	SessionId  int64  `json:"session_id"`
+	NextSeqNo  uint32 `json:"next_seq_no"`
+	FirstMsgId int64  `json:"first_msg_id"`
+	LastMsgId  int64  `json:"last_msg_id"`
+	IsUpdates  bool   `json:"is_updates"`
+}
+
+type authKeySessionStates struct {
+	Layer    int32           `json:"layer"`
+	Sessions []*sessionState `json:"sessions"`
+}
+
+type sessionStateStore interface {
+	// 记录owner并返回之前保存的状态, 没有时返回nil
+	TakeOver(authKeyId int64, owner string) (*authKeySessionStates, error)
+	// 已被其他节点接管时返回false
+	Save(authKeyId int64, owner string, states *authKeySessionStates) (bool, error)
+	Owner(authKeyId int64) (string, error)
+	Delete(authKeyId int64, owner string) error
+}
+
+var (
+	sessionStates     sessionStateStore // nil: 不保存session状态
+	sessionStateOwner string            // 本进程的标识, 重启后也不同
+)
+
+func initSessionStateStore(redisName string) error {
+	sessionStateOwner = fmt.Sprintf("%d_%d", getServerID(), rand.Int63())
+	if redisName == "" {
+		return nil
+	}
+
+	pool := redis_client.GetRedisClient(redisName)
+	if pool == nil {
+		return fmt.Errorf("not found redis: %s", redisName)
+	}
+	sessionStates = &redisSessionStateStore{pool: pool}
+	return nil
+}
+
+func getDrainTimeout() time.Duration {
+	if Conf == nil || Conf.SessionState.DrainTimeout <= 0 {
+		return kDefaultDrainTimeout
+	}
+	return time.Duration(Conf.SessionState.DrainTimeout)
+}
+
+func (c *clientSessionHandler) sessionState() *sessionState {
+	return &sessionState{
+		SessionId:  c.sessionId,
+		NextSeqNo:  c.nextSeqNo,
+		FirstMsgId: c.firstMsgId,
+		LastMsgId:  c.lastMsgId,
+		IsUpdates:  c.isUpdates,
+	}
+}
+
+func restoreClientSessionHandler(st *sessionState, salt int64, m *clientSessionManager) *clientSessionHandler {
+	c := newClientSessionHandler(st.SessionId, salt, st.FirstMsgId, m)
+	c.nextSeqNo = st.NextSeqNo
+	c.lastMsgId = st.LastMsgId
+	c.isUpdates = st.IsUpdates
+	c.clientState = kStateOnline
+	return c
+}
+
+// 新建clientSessionManager后(Start之前)调用, 接管auth_key并取回其他节点保存的状态
+func (s *clientSessionManager) takeOverSessionStates() {
+	s.restoredStates = make(map[int64]*sessionState)
+	s.lastActiveDate = time.Now().Unix()
+	if sessionStates == nil {
+		return
+	}
+
+	states, err := sessionStates.TakeOver(s.authKeyId, sessionStateOwner)
+	if err != nil {
+		glog.Errorf("takeOverSessionStates - error: {sess: %s, err: %v}", s, err)
+		return
+	}
+	if states == nil {
+		return
+	}
+
+	if states.Layer != 0 {
+		s.Layer = states.Layer
+	}
+	for _, st := range states.Sessions {
+		s.restoredStates[st.SessionId] = st
+	}
+	glog.Infof("takeOverSessionStates - {sess: %s, layer: %d, sessions: %d}", s, states.Layer, len(states.Sessions))
+}
+
+// 空闲一段时间后收到客户端数据时, auth_key可能已被其他节点接管过又路由回来,
+// 这时内存里的状态已经过期, 丢弃后重新接管
+func (s *clientSessionManager) checkSessionStateOwner() {
+	if sessionStates == nil {
+		return
+	}
+
+	if !s.stateOwnerLost {
+		if time.Now().Unix()-s.lastActiveDate < kSessionStateCheckIdle {
+			return
+		}
+		owner, err := sessionStates.Owner(s.authKeyId)
+		if err != nil {
+			glog.Errorf("checkSessionStateOwner - error: {sess: %s, err: %v}", s, err)
+			return
+		}
+		// 过期后没有owner, 下次保存时直接认领
+		if owner == "" || owner == sessionStateOwner {
+			return
+		}
+	}
+
+	glog.Warningf("checkSessionStateOwner - taken over by other node: {sess: %s}", s)
+	s.stateOwnerLost = false
+	s.stateDirty = false
+	s.sessions = make(map[int64]*clientSessionHandler)
+	s.updatesSession = newClientUpdatesHandler()
+	s.takeOverSessionStates()
+}
+
+// 接管后还没收到数据的session使用保存的状态重建
+func (s *clientSessionManager) restoreSession(sessionId, salt int64) *clientSessionHandler {
+	st, ok := s.restoredStates[sessionId]
+	if !ok {
+		return nil
+	}
+	delete(s.restoredStates, sessionId)
+
+	glog.Infof("restoreSession - {sess: %s, state: %v}", s, st)
+	return restoreClientSessionHandler(st, salt, s)
+}
+
+func (s *clientSessionManager) snapshotSessionStates() *authKeySessionStates {
+	states := &authKeySessionStates{
+		Layer:    s.Layer,
+		Sessions: make([]*sessionState, 0, len(s.sessions)+len(s.restoredStates)),
+	}
+	for _, sess := range s.sessions {
+		states.Sessions = append(states.Sessions, sess.sessionState())
+	}
+	// 还没收到数据的session也要保留
+	for _, st := range s.restoredStates {
+		states.Sessions = append(states.Sessions, st)
+	}
+	return states
+}
+
+// force为true时不检查保存间隔
+func (s *clientSessionManager) saveSessionStates(force bool) {
+	if sessionStates == nil || !s.stateDirty {
+		return
+	}
+
+	now := time.Now().Unix()
+	if !force && now-s.stateSaveDate < kSessionStateSaveInterval {
+		return
+	}
+	s.stateSaveDate = now
+
+	ok, err := sessionStates.Save(s.authKeyId, sessionStateOwner, s.snapshotSessionStates())
+	if err != nil {
+		// 保持dirty, 下次重试
+		glog.Errorf("saveSessionStates - error: {sess: %s, err: %v}", s, err)
+		return
+	}
+	s.stateDirty = false
+	if !ok {
+		glog.Warningf("saveSessionStates - taken over by other node: {sess: %s}", s)
+		s.stateOwnerLost = true
+	}
+}
+
+func (s *clientSessionManager) deleteSessionStates() {
+	if sessionStates == nil {
+		return
+	}
+	if err := sessionStates.Delete(s.authKeyId, sessionStateOwner); err != nil {
+		glog.Errorf("deleteSessionStates - error: {sess: %s, err: %v}", s, err)
+	}
+}
+
+// 一个auth_key一个hash: owner为当前负责的节点, state为json
+func genSessionStateKey(authKeyId int64) string {
+	return fmt.Sprintf("session_state:%d", authKeyId)
+}
+
+var sessionStateTakeOverScript = redis.NewScript(1, `
+redis.call('HSET', KEYS[1], 'owner', ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[2])
+return redis.call('HGET', KEYS[1], 'state')
+`)
+
+var sessionStateSaveScript = redis.NewScript(1, `
+local owner = redis.call('HGET', KEYS[1], 'owner')
+if owner and owner ~= ARGV[1] then
+	return 0
+end
+redis.call('HMSET', KEYS[1], 'owner', ARGV[1], 'state', ARGV[2])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+var sessionStateDeleteScript = redis.NewScript(1, `
+if redis.call('HGET', KEYS[1], 'owner') == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+type redisSessionStateStore struct {
+	pool *redis_client.RedisPool
+}
+
+func (r *redisSessionStateStore) TakeOver(authKeyId int64, owner string) (*authKeySessionStates, error) {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	b, err := redis.Bytes(sessionStateTakeOverScript.Do(conn, genSessionStateKey(authKeyId), owner, kSessionStateTTL))
+	if err == redis.ErrNil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	states := &authKeySessionStates{}
+	if err = json.Unmarshal(b, states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (r *redisSessionStateStore) Save(authKeyId int64, owner string, states *authKeySessionStates) (bool, error) {
+	b, err := json.Marshal(states)
+	if err != nil {
+		return false, err
+	}
+
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	return redis.Bool(sessionStateSaveScript.Do(conn, genSessionStateKey(authKeyId), owner, b, kSessionStateTTL))
+}
+
+func (r *redisSessionStateStore) Owner(authKeyId int64) (string, error) {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	owner, err := redis.String(conn.Do("HGET", genSessionStateKey(authKeyId), "owner"))
+	if err == redis.ErrNil {
+		return "", nil
+	}
+	return owner, err
+}
+
+func (r *redisSessionStateStore) Delete(authKeyId int64, owner string) error {
+	conn := r.pool.Get()
+	defer conn.Close()
+
+	_, err := sessionStateDeleteScript.Do(conn, genSessionStateKey(authKeyId), owner)
+	return err
+}
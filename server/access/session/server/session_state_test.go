@@ -0,0 +1,201 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// 模拟redis: 和redisSessionStateStore一样按json保存
+type testSessionStateStore struct {
+	owners map[int64]string
+	states map[int64][]byte
+}
+
+func newTestSessionStateStore() *testSessionStateStore {
+	return &testSessionStateStore{
+		owners: make(map[int64]string),
+		states: make(map[int64][]byte),
+	}
+}
+
+func (r *testSessionStateStore) TakeOver(authKeyId int64, owner string) (*authKeySessionStates, error) {
+	r.owners[authKeyId] = owner
+	b, ok := r.states[authKeyId]
+	if !ok {
+		return nil, nil
+	}
+	states := &authKeySessionStates{}
+	err := json.Unmarshal(b, states)
+	return states, err
+}
+
+func (r *testSessionStateStore) Save(authKeyId int64, owner string, states *authKeySessionStates) (bool, error) {
+	if o, ok := r.owners[authKeyId]; ok && o != owner {
+		return false, nil
+	}
+	b, err := json.Marshal(states)
+	if err != nil {
+		return false, err
+	}
+	r.owners[authKeyId] = owner
+	r.states[authKeyId] = b
+	return true, nil
+}
+
+func (r *testSessionStateStore) Owner(authKeyId int64) (string, error) {
+	return r.owners[authKeyId], nil
+}
+
+func (r *testSessionStateStore) Delete(authKeyId int64, owner string) error {
+	if r.owners[authKeyId] == owner {
+		delete(r.owners, authKeyId)
+		delete(r.states, authKeyId)
+	}
+	return nil
+}
+
+func newTestStateSessionManager(authKeyId int64) *clientSessionManager {
+	s := &clientSessionManager{
+		authKeyId:      authKeyId,
+		sessions:       make(map[int64]*clientSessionHandler),
+		updatesSession: newClientUpdatesHandler(),
+	}
+	s.takeOverSessionStates()
+	return s
+}
+
+func withTestSessionStateStore(t *testing.T, owner string, f func(store *testSessionStateStore)) {
+	oldStore, oldOwner := sessionStates, sessionStateOwner
+	defer func() {
+		sessionStates, sessionStateOwner = oldStore, oldOwner
+	}()
+
+	store := newTestSessionStateStore()
+	sessionStates, sessionStateOwner = store, owner
+	f(store)
+}
+
+func TestSessionStateHandoff(t *testing.T) {
+	withTestSessionStateStore(t, "node1", func(store *testSessionStateStore) {
+		s := newTestStateSessionManager(100)
+		s.Layer = 85
+
+		sess := newClientSessionHandler(1, 2, testFirstMsgId, s)
+		sess.generateMessageSeqNo(true)
+		sess.generateMessageSeqNo(true)
+		sess.lastMsgId = testFirstMsgId + 8
+		sess.isUpdates = true
+		s.sessions[1] = sess
+
+		s.stateDirty = true
+		s.saveSessionStates(true)
+		if s.stateDirty || store.owners[100] != "node1" {
+			t.Fatalf("save failed: %v", store.owners)
+		}
+
+		// 其他节点接管
+		sessionStateOwner = "node2"
+		s2 := newTestStateSessionManager(100)
+		if s2.Layer != 85 || len(s2.restoredStates) != 1 {
+			t.Fatalf("invalid restored states: {layer: %d, states: %v}", s2.Layer, s2.restoredStates)
+		}
+		if s2.restoreSession(2, 3) != nil {
+			t.Fatal("unknown session must not be restored")
+		}
+
+		sess2 := s2.restoreSession(1, 3)
+		if sess2 == nil {
+			t.Fatal("session not restored")
+		}
+		if sess2.nextSeqNo != 2 || sess2.lastMsgId != testFirstMsgId+8 || sess2.firstMsgId != testFirstMsgId || !sess2.isUpdates {
+			t.Fatalf("invalid restored session: %v", sess2.sessionState())
+		}
+		if sess2.clientState != kStateOnline || sess2.salt != 3 {
+			t.Fatalf("invalid restored session: {state: %d, salt: %d}", sess2.clientState, sess2.salt)
+		}
+		if seqNo := sess2.generateMessageSeqNo(true); seqNo != 5 {
+			t.Fatalf("invalid seq_no: %d", seqNo)
+		}
+		if len(s2.restoredStates) != 0 {
+			t.Fatal("restored state must be removed")
+		}
+	})
+}
+
+func TestSessionStateOwnerLost(t *testing.T) {
+	withTestSessionStateStore(t, "node1", func(store *testSessionStateStore) {
+		s := newTestStateSessionManager(100)
+		s.sessions[1] = newClientSessionHandler(1, 2, testFirstMsgId, s)
+		s.stateDirty = true
+		s.saveSessionStates(true)
+
+		// node2接管后又保存了新的状态
+		sessionStateOwner = "node2"
+		s2 := newTestStateSessionManager(100)
+		sess2 := s2.restoreSession(1, 2)
+		sess2.generateMessageSeqNo(true)
+		s2.sessions[1] = sess2
+		s2.stateDirty = true
+		s2.saveSessionStates(true)
+
+		// node1内存里的状态已过期, 不能覆盖
+		sessionStateOwner = "node1"
+		s.stateDirty = true
+		s.saveSessionStates(true)
+		if !s.stateOwnerLost {
+			t.Fatal("owner lost not detected")
+		}
+
+		s.checkSessionStateOwner()
+		if s.stateOwnerLost || len(s.sessions) != 0 || store.owners[100] != "node1" {
+			t.Fatalf("take over again failed: %v", store.owners)
+		}
+		if sess := s.restoreSession(1, 2); sess == nil || sess.nextSeqNo != 1 {
+			t.Fatalf("invalid restored session: %v", sess)
+		}
+	})
+}
+
+func TestSessionStateSaveInterval(t *testing.T) {
+	withTestSessionStateStore(t, "node1", func(store *testSessionStateStore) {
+		s := newTestStateSessionManager(100)
+		s.sessions[1] = newClientSessionHandler(1, 2, testFirstMsgId, s)
+		s.stateDirty = true
+		s.saveSessionStates(false)
+		if s.stateDirty {
+			t.Fatal("first save must not be delayed")
+		}
+
+		s.stateDirty = true
+		s.saveSessionStates(false)
+		if !s.stateDirty {
+			t.Fatal("save must be delayed")
+		}
+		s.saveSessionStates(true)
+		if s.stateDirty {
+			t.Fatal("force save failed")
+		}
+
+		s.deleteSessionStates()
+		if _, ok := store.states[100]; ok {
+			t.Fatal("delete failed")
+		}
+	})
+}
@@ -45,6 +45,14 @@ func sendSessionDataByConnID(connID uint64, md *zproto.ZProtoMetadata, sessData
 	return app.GAppInstance.(*SessionServer).server.SendMessageByConnID(connID, md, sessData)
 }
 
+func sendQuickAckByConnID(connID uint64, md *zproto.ZProtoMetadata, quickAck *zproto.ZProtoQuickAck) error {
+	return app.GAppInstance.(*SessionServer).server.SendMessageByConnID(connID, md, quickAck)
+}
+
+func sendTransportErrorByConnID(connID uint64, md *zproto.ZProtoMetadata, transportError *zproto.ZProtoTransportError) error {
+	return app.GAppInstance.(*SessionServer).server.SendMessageByConnID(connID, md, transportError)
+}
+
 func getBizRPCClient() (*grpc_util.RPCClient, error) {
 	return app.GAppInstance.(*SessionServer).bizRpcClient, nil
 }
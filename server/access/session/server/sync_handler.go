@@ -18,11 +18,13 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/net2"
+	"github.com/nebulaim/telegramd/baselib/nsq_client"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
 )
@@ -58,17 +60,24 @@ func protoToSyncData(m proto.Message) (*zproto.ZProtoSyncData, error) {
 	return &zproto.ZProtoSyncData{SyncRawData: x.GetBuf()}, err
 }
 
-func (s *syncHandler) onSyncData(conn *net2.TcpConnection, syncData *zproto.ZProtoSyncData) (*zproto.ZProtoSyncData, error) {
-	dbuf := mtproto.NewDecodeBuf(syncData.SyncRawData)
+func decodeSyncData(raw []byte) (proto.Message, error) {
+	dbuf := mtproto.NewDecodeBuf(raw)
 	len2 := int(dbuf.Int())
 	messageName := string(dbuf.Bytes(len2))
 	message, err := grpc_util.NewMessageByName(messageName)
 	if err != nil {
-		glog.Error(err)
 		return nil, err
 	}
 
-	err = proto.Unmarshal(syncData.SyncRawData[4+len2:], message)
+	err = proto.Unmarshal(raw[4+len2:], message)
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (s *syncHandler) onSyncData(conn *net2.TcpConnection, syncData *zproto.ZProtoSyncData) (*zproto.ZProtoSyncData, error) {
+	message, err := decodeSyncData(syncData.SyncRawData)
 	if err != nil {
 		glog.Error(err)
 		return nil, err
@@ -84,46 +93,9 @@ func (s *syncHandler) onSyncData(conn *net2.TcpConnection, syncData *zproto.ZPro
 	case *mtproto.PushData:
 		glog.Infof("onSyncData - request(PushUpdatesData): {%v}", message)
 
-		// TODO(@benqi): dispatch to session_client
-		pushData, _ := message.(*mtproto.PushData)
-		// dbuf := mtproto.NewDecodeBuf(pushData.GetUpdatesData())
-		mdata := &messageData{
-			confirmFlag:  true,
-			compressFlag: false,
-			// obj:          pushData.GetUpdates(),
-		}
-		md := &zproto.ZProtoMetadata{}
-
-		switch pushData.GetConstructor() {
-		case mtproto.TLConstructor_CRC32_sync_pushUpdatesData:
-			mdata.obj = pushData.Data2.Updates
-			if mdata.obj == nil {
-				glog.Errorf("onSyncData - recv invalid pushData: {%v}", message)
-				return protoToSyncData(mtproto.ToBool(false))
-			}
-			// push
-			// s.smgr.pushToSessionData(pushData.GetAuthKeyId(), pushData.GetSessionId(), md, mdata)
-			s.smgr.onSyncData(pushData.Data2.AuthKeyId, md, mdata)
-		case mtproto.TLConstructor_CRC32_sync_pushRpcResultData:
-			dbuf := mtproto.NewDecodeBuf(pushData.Data2.Result)
-			result := dbuf.Object()
-			glog.Info(result)
-			if result == nil {
-				glog.Errorf("onSyncData - recv invalid pushData: {%v}", message)
-				return protoToSyncData(mtproto.ToBool(false))
-			}
-			mdata.obj = &mtproto.TLRpcResult{
-				ReqMsgId: pushData.Data2.ClientReqMsgId,
-				Result:   result,
-			}
-			// push
-			// s.smgr.pushToSessionData(pushData.GetAuthKeyId(), pushData.GetSessionId(), md, mdata)
-			s.smgr.onSyncData(pushData.Data2.AuthKeyId, md, mdata)
-		default:
-			glog.Errorf("invalid pushData - ", pushData)
+		if err = s.onPushData(message.(*mtproto.PushData)); err == errInvalidPushData {
 			return protoToSyncData(mtproto.ToBool(false))
 		}
-
 		return protoToSyncData(mtproto.ToBool(true))
 	default:
 		err := fmt.Errorf("invalid register proto type: {%v}", message)
@@ -131,3 +103,74 @@ func (s *syncHandler) onSyncData(conn *net2.TcpConnection, syncData *zproto.ZPro
 		return nil, err
 	}
 }
+
+var errInvalidPushData = errors.New("invalid pushData")
+
+func (s *syncHandler) onPushData(pushData *mtproto.PushData) error {
+	// TODO(@benqi): dispatch to session_client
+	// dbuf := mtproto.NewDecodeBuf(pushData.GetUpdatesData())
+	mdata := &messageData{
+		confirmFlag:  true,
+		compressFlag: false,
+		// obj:          pushData.GetUpdates(),
+	}
+	md := &zproto.ZProtoMetadata{}
+
+	switch pushData.GetConstructor() {
+	case mtproto.TLConstructor_CRC32_sync_pushUpdatesData:
+		mdata.obj = pushData.Data2.Updates
+		if mdata.obj == nil {
+			glog.Errorf("onSyncData - recv invalid pushData: {%v}", pushData)
+			return errInvalidPushData
+		}
+		// push
+		// s.smgr.pushToSessionData(pushData.GetAuthKeyId(), pushData.GetSessionId(), md, mdata)
+		return s.smgr.onSyncData(pushData.Data2.AuthKeyId, md, mdata)
+	case mtproto.TLConstructor_CRC32_sync_pushRpcResultData:
+		dbuf := mtproto.NewDecodeBuf(pushData.Data2.Result)
+		result := dbuf.Object()
+		glog.Info(result)
+		if result == nil {
+			glog.Errorf("onSyncData - recv invalid pushData: {%v}", pushData)
+			return errInvalidPushData
+		}
+		mdata.obj = &mtproto.TLRpcResult{
+			ReqMsgId: pushData.Data2.ClientReqMsgId,
+			Result:   result,
+		}
+		// push
+		// s.smgr.pushToSessionData(pushData.GetAuthKeyId(), pushData.GetSessionId(), md, mdata)
+		return s.smgr.onSyncData(pushData.Data2.AuthKeyId, md, mdata)
+	default:
+		glog.Errorf("invalid pushData - %v", pushData)
+		return errInvalidPushData
+	}
+}
+
+// 从队列收到的推送: 返回error时消息延迟后重新投递, 只有停服时需要重试,
+// auth_key不在本节点(已下线)时确认掉, 客户端重连后通过getDifference补齐
+func (s *syncHandler) onQueueMessage(message *nsq_client.Message) error {
+	m, err := decodeSyncData(message.Body)
+	if err != nil {
+		glog.Errorf("onQueueMessage - decode error: {id: %s, err: %v}", message.ID, err)
+		return nil
+	}
+
+	pushData, ok := m.(*mtproto.PushData)
+	if !ok {
+		glog.Errorf("onQueueMessage - invalid message: {id: %s, message: %v}", message.ID, m)
+		return nil
+	}
+
+	glog.Infof("onQueueMessage - pushData: {id: %s, attempts: %d, pushData: %v}", message.ID, message.Attempts, pushData)
+	if err = s.onPushData(pushData); err == errSessionServerDraining {
+		return err
+	}
+	return nil
+}
+
+// 重试多次仍然失败的推送
+func (s *syncHandler) onQueueMessageFailed(message nsq_client.FailMessage) error {
+	glog.Errorf("onQueueMessageFailed - drop pushData: {id: %s, attempts: %d, err: %s}", message.MessageID, message.Attempt, message.FailMsg)
+	return nil
+}
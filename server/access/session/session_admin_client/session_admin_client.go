@@ -0,0 +1,113 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package session_admin_client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/grpc_util"
+	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
+	"github.com/nebulaim/telegramd/server/access/session/proto"
+	"google.golang.org/grpc"
+)
+
+// auth_key可能在任意session节点上, 管理请求发给所有节点
+const kSessionAdminTimeout = 10 * time.Second
+
+type sessionAdminClient struct {
+	client *grpc_util.RPCBroadcastClient
+}
+
+var (
+	sessionAdminInstance = &sessionAdminClient{}
+)
+
+func GetSessionAdminClient() *sessionAdminClient {
+	return sessionAdminInstance
+}
+
+func InstallSessionAdminClient(discovery *service_discovery.ServiceDiscoveryClientConfig) {
+	client, err := grpc_util.NewRPCBroadcastClient(discovery)
+	if err != nil {
+		glog.Error(err)
+		panic(err)
+	}
+
+	sessionAdminInstance.client = client
+}
+
+func (c *sessionAdminClient) closeSessions(request *session_admin.CloseSessionsRequest) (int32, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kSessionAdminTimeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		closed int32
+	)
+	_, err := c.client.Broadcast(ctx, func(ctx context.Context, conn *grpc.ClientConn) error {
+		r, err := session_admin.NewRPCSessionAdminClient(conn).CloseSessions(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		closed += r.Closed
+		mu.Unlock()
+		return nil
+	})
+	return closed, err
+}
+
+// 关闭auth_key(以及绑定到它的临时key)的所有连接, 返回关闭的auth_key数
+func (c *sessionAdminClient) CloseAuthKey(authKeyId int64) (int32, error) {
+	return c.closeSessions(&session_admin.CloseSessionsRequest{AuthKeyId: authKeyId})
+}
+
+// 关闭用户除exceptAuthKeyId以外的所有auth_key
+func (c *sessionAdminClient) CloseUserAuthKeys(userId int32, exceptAuthKeyId int64) (int32, error) {
+	return c.closeSessions(&session_admin.CloseSessionsRequest{UserId: userId, ExceptAuthKeyId: exceptAuthKeyId})
+}
+
+func (c *sessionAdminClient) CloseSession(authKeyId, sessionId int64) (int32, error) {
+	return c.closeSessions(&session_admin.CloseSessionsRequest{AuthKeyId: authKeyId, SessionId: sessionId})
+}
+
+// 汇总所有节点的结果
+func (c *sessionAdminClient) ListSessions(userId int32, authKeyId int64) ([]*session_admin.AuthKeySessions, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kSessionAdminTimeout)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		authKeys []*session_admin.AuthKeySessions
+	)
+	request := &session_admin.ListSessionsRequest{UserId: userId, AuthKeyId: authKeyId}
+	_, err := c.client.Broadcast(ctx, func(ctx context.Context, conn *grpc.ClientConn) error {
+		r, err := session_admin.NewRPCSessionAdminClient(conn).ListSessions(ctx, request)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		authKeys = append(authKeys, r.AuthKeys...)
+		mu.Unlock()
+		return nil
+	})
+	return authKeys, err
+}
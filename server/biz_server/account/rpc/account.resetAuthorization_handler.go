@@ -22,6 +22,7 @@ import (
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/logger"
 	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/access/session/session_admin_client"
 	"golang.org/x/net/context"
 )
 
@@ -37,9 +38,14 @@ func (s *AccountServiceImpl) AccountResetAuthorization(ctx context.Context, requ
 		return nil, err
 	}
 
-	// TODO(@benqi): found session, kick off.
 	s.AccountModel.DeleteAuthorization(authKeyId)
 
-	glog.Infof("account.checkUsername#2714d86c - reply: {true}")
+	// 断开该auth_key的在线连接, 客户端收到-404
+	closed, err := session_admin_client.GetSessionAdminClient().CloseAuthKey(authKeyId)
+	if err != nil {
+		glog.Errorf("account.resetAuthorization#df77f3bc - close auth_key error: {auth_key_id: %d, closed: %d, err: %v}", authKeyId, closed, err)
+	}
+
+	glog.Infof("account.resetAuthorization#df77f3bc - reply: {true}")
 	return mtproto.ToBool(true), nil
 }
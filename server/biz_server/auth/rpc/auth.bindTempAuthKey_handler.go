@@ -18,20 +18,34 @@
 package rpc
 
 import (
-	"fmt"
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/logger"
 	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/service/auth_session/client"
 	"golang.org/x/net/context"
 )
 
 // auth.bindTempAuthKey#cdd42a05 perm_auth_key_id:long nonce:long expires_at:int encrypted_message:bytes = Bool;
 func (s *AuthServiceImpl) AuthBindTempAuthKey(ctx context.Context, request *mtproto.TLAuthBindTempAuthKey) (*mtproto.Bool, error) {
 	md := grpc_util.RpcMetadataFromIncoming(ctx)
-	glog.Infof("AuthBindTempAuthKey - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
+	glog.Infof("auth.bindTempAuthKey#cdd42a05 - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
-	// TODO(@benqi): Impl AuthBindTempAuthKey logic
+	// 必须使用待绑定的临时key发送, session服务器不会把md.AuthId替换为永久key
+	err := auth_session_client.BindTempAuthKey(&mtproto.TLSessionBindTempAuthKey{
+		TempAuthKeyId:    md.AuthId,
+		TempSessionId:    md.SessionId,
+		MsgId:            md.ClientMsgId,
+		PermAuthKeyId:    request.GetPermAuthKeyId(),
+		Nonce:            request.GetNonce(),
+		ExpiresAt:        request.GetExpiresAt(),
+		EncryptedMessage: request.GetEncryptedMessage(),
+	})
+	if err != nil {
+		glog.Error(err)
+		return nil, err
+	}
 
-	return nil, fmt.Errorf("Not impl AuthBindTempAuthKey")
+	glog.Info("auth.bindTempAuthKey#cdd42a05 - reply: {true}")
+	return mtproto.ToBool(true), nil
 }
@@ -18,20 +18,23 @@
 package rpc
 
 import (
-	"fmt"
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/logger"
 	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/service/auth_session/client"
 	"golang.org/x/net/context"
 )
 
 // auth.dropTempAuthKeys#8e48a188 except_auth_keys:Vector<long> = Bool;
 func (s *AuthServiceImpl) AuthDropTempAuthKeys(ctx context.Context, request *mtproto.TLAuthDropTempAuthKeys) (*mtproto.Bool, error) {
 	md := grpc_util.RpcMetadataFromIncoming(ctx)
-	glog.Infof("AuthDropTempAuthKeys - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
+	glog.Infof("auth.dropTempAuthKeys#8e48a188 - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
-	// TODO(@benqi): Impl AuthDropTempAuthKeys logic
+	// 临时key已绑定时md.AuthId为永久key
+	// TODO(@benqi): 其它session服务器缓存的临时key在过期前仍然有效
+	r := auth_session_client.DropTempAuthKeys(md.AuthId, request.GetExceptAuthKeys())
 
-	return nil, fmt.Errorf("Not impl AuthDropTempAuthKeys")
+	glog.Infof("auth.dropTempAuthKeys#8e48a188 - reply: {%v}", r)
+	return mtproto.ToBool(r), nil
 }
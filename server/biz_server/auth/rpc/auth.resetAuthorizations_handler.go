@@ -18,11 +18,11 @@
 package rpc
 
 import (
-	"fmt"
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/logger"
 	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/access/session/session_admin_client"
 	"golang.org/x/net/context"
 )
 
@@ -31,7 +31,14 @@ func (s *AuthServiceImpl) AuthResetAuthorizations(ctx context.Context, request *
 	md := grpc_util.RpcMetadataFromIncoming(ctx)
 	glog.Infof("AuthResetAuthorizations - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
-	// TODO(@benqi): Impl AuthResetAuthorizations logic
+	// 保留当前auth_key, 其他设备全部登出
+	authKeyIds := s.AccountModel.ResetAuthorizations(md.AuthId, md.UserId)
 
-	return nil, fmt.Errorf("Not impl AuthResetAuthorizations")
+	closed, err := session_admin_client.GetSessionAdminClient().CloseUserAuthKeys(md.UserId, md.AuthId)
+	if err != nil {
+		glog.Errorf("AuthResetAuthorizations - close auth_keys error: {user_id: %d, closed: %d, err: %v}", md.UserId, closed, err)
+	}
+
+	glog.Infof("AuthResetAuthorizations - reply: {true}, {reset: %v, closed: %d}", authKeyIds, closed)
+	return mtproto.ToBool(true), nil
 }
@@ -152,9 +152,8 @@ func (s *AuthServiceImpl) AuthSendCode(ctx context.Context, request *mtproto.TLA
 	code := s.AuthModel.MakeCodeData(md.AuthId, phoneNumber)
 
 	// 检查phoneNumber是否异常
-	// TODO(@benqi): 定义sendCode限制规则
-	// PhoneNumberFlood
-	// FLOOD_WAIT
+	// FLOOD_WAIT由session按floodLimit配置的规则检查(按手机号/IP/auth_key)
+	// TODO(@benqi): PhoneNumberFlood
 	phoneRegistered := s.AuthModel.CheckPhoneNumberExist(phoneNumber)
 	err = code.DoSendCode(phoneRegistered, request.AllowFlashcall, currentNumber, request.ApiId, request.ApiHash)
 	if err != nil {
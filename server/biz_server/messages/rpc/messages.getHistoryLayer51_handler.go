@@ -40,7 +40,11 @@ func (s *MessagesServiceImpl) MessagesGetHistoryLayer51(ctx context.Context, req
         MinId:      request.GetMinId(),
         Hash:       0,
     }
-    messagesMessages := s.getHistoryMessages(md, request2)
+    messagesMessages, err := s.getHistoryMessages(ctx, md, request2)
+    if err != nil {
+        glog.Errorf("messages.getHistory#afa92846 - error: {%v}", err)
+        return nil, err
+    }
 
     glog.Infof("messages.getHistory#dcbb8260 - reply: %s", logger.JsonDebugData(messagesMessages))
     return messagesMessages, nil
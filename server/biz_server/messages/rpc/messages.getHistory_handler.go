@@ -293,7 +293,7 @@ func calcLoadHistoryType(isChannel bool, offsetId, offsetDate, addOffset, limit,
 	return kLoadTypeBackward
 }
 
-func (s *MessagesServiceImpl) loadHistoryMessage(loadType int, selfUserId int32, peer *base.PeerUtil, offsetId, offsetDate, addOffset, limit, maxId, minId int32) []*mtproto.Message {
+func (s *MessagesServiceImpl) loadHistoryMessage(ctx context.Context, loadType int, selfUserId int32, peer *base.PeerUtil, offsetId, offsetDate, addOffset, limit, maxId, minId int32) ([]*mtproto.Message, error) {
 	messages := []*mtproto.Message{}
 
 	switch loadType {
@@ -312,6 +312,10 @@ func (s *MessagesServiceImpl) loadHistoryMessage(loadType int, selfUserId int32,
 			messages1[i], messages1[j] = messages1[j], messages1[i]
 		}
 		messages = append(messages, messages1...)
+		// 客户端已经rpc_drop_answer, 不用再查第二段了
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		// 降序
 		messages2 := s.MessageModel.LoadBackwardHistoryMessages(selfUserId, peer.PeerType, peer.PeerId, offsetId, limit+addOffset)
 		messages = append(messages, messages2...)
@@ -324,10 +328,15 @@ func (s *MessagesServiceImpl) loadHistoryMessage(loadType int, selfUserId int32,
 		messages = s.MessageModel.LoadBackwardHistoryMessages(selfUserId, peer.PeerType, peer.PeerId, offsetId, addOffset+limit)
 	}
 
-	return messages
+	return messages, nil
 }
 
-func (s *MessagesServiceImpl) getHistoryMessages(md *grpc_util.RpcMetadata, request *mtproto.TLMessagesGetHistory) (messagesMessages *mtproto.Messages_Messages) {
+// ctx被取消(客户端rpc_drop_answer)后, 在各查询阶段之间直接返回
+func (s *MessagesServiceImpl) getHistoryMessages(ctx context.Context, md *grpc_util.RpcMetadata, request *mtproto.TLMessagesGetHistory) (*mtproto.Messages_Messages, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	peer := base.FromInputPeer(request.GetPeer())
 	if peer.PeerType == base.PEER_SELF {
 		peer.PeerType = base.PEER_USER
@@ -345,10 +354,16 @@ func (s *MessagesServiceImpl) getHistoryMessages(md *grpc_util.RpcMetadata, requ
 	)
 
 	loadType := calcLoadHistoryType(isChannel, offsetId, request.GetOffsetDate(), addOffset, limit, request.GetMaxId(), request.GetMinId())
-	messages := s.loadHistoryMessage(loadType, md.UserId, peer, offsetId, request.GetOffsetDate(), addOffset, limit, request.GetMaxId(), request.GetMinId())
+	messages, err := s.loadHistoryMessage(ctx, loadType, md.UserId, peer, offsetId, request.GetOffsetDate(), addOffset, limit, request.GetMaxId(), request.GetMinId())
+	if err != nil {
+		return nil, err
+	}
 
 	// messagesMessages.SetMessages(messages)
 	userIdList, chatIdList, _ := message.PickAllIDListByMessages(messages)
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
 	if len(userIdList) > 0 {
 		users = s.UserModel.GetUsersBySelfAndIDList(md.UserId, userIdList)
 		// messagesMessages.Data2.Users = users
@@ -356,6 +371,9 @@ func (s *MessagesServiceImpl) getHistoryMessages(md *grpc_util.RpcMetadata, requ
 		users = []*mtproto.User{}
 	}
 
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
 	if len(chatIdList) > 0 {
 		chats = s.ChatModel.GetChatListBySelfAndIDList(md.UserId, chatIdList)
 	} else {
@@ -368,9 +386,7 @@ func (s *MessagesServiceImpl) getHistoryMessages(md *grpc_util.RpcMetadata, requ
 		Chats:    chats,
 		Users:    users,
 	}}
-	messagesMessages = messagesSlice.To_Messages_Messages()
-
-	return
+	return messagesSlice.To_Messages_Messages(), nil
 }
 
 // request: {"peer":{"constructor":2072935910,"data2":{"user_id":5,"access_hash":1006843769775067136}},"offset_id":1,"add_offset":-25,"limit":50}
@@ -381,7 +397,11 @@ func (s *MessagesServiceImpl) MessagesGetHistory(ctx context.Context, request *m
 	md := grpc_util.RpcMetadataFromIncoming(ctx)
 	glog.Infof("messages.getHistory#dcbb8260 - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
-	messagesMessages := s.getHistoryMessages(md, request)
+	messagesMessages, err := s.getHistoryMessages(ctx, md, request)
+	if err != nil {
+		glog.Errorf("messages.getHistory#dcbb8260 - error: {%v}", err)
+		return nil, err
+	}
 
 	glog.Infof("messages.getHistory#dcbb8260 - reply: %s", logger.JsonDebugData(messagesMessages))
 	return messagesMessages, nil
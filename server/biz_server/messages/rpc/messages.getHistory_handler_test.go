@@ -0,0 +1,49 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/nebulaim/telegramd/baselib/grpc_util"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"golang.org/x/net/context"
+)
+
+// 客户端rpc_drop_answer后ctx被取消, getHistory不能再去查库
+// (s的各个Model都是nil, 只要查了就会panic)
+func TestGetHistoryMessagesCanceled(t *testing.T) {
+	s := &MessagesServiceImpl{}
+	md := &grpc_util.RpcMetadata{UserId: 1}
+	peer := &mtproto.TLInputPeerUser{Data2: &mtproto.InputPeer_Data{UserId: 2}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, addOffset := range []int32{0, -25} {
+		request := &mtproto.TLMessagesGetHistory{
+			Peer:      peer.To_InputPeer(),
+			OffsetId:  100,
+			AddOffset: addOffset,
+			Limit:     50,
+		}
+		if r, err := s.getHistoryMessages(ctx, md, request); err != context.Canceled || r != nil {
+			t.Fatalf("addOffset %d: expect canceled, got %v, %v", addOffset, r, err)
+		}
+	}
+}
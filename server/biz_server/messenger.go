@@ -45,6 +45,7 @@ import (
 	stickers "github.com/nebulaim/telegramd/server/biz_server/stickers/rpc"
 	updates "github.com/nebulaim/telegramd/server/biz_server/updates/rpc"
 	users "github.com/nebulaim/telegramd/server/biz_server/users/rpc"
+	"github.com/nebulaim/telegramd/server/access/session/session_admin_client"
 	"github.com/nebulaim/telegramd/server/sync/sync_client"
 	"github.com/nebulaim/telegramd/service/document/client"
 	"google.golang.org/grpc"
@@ -59,15 +60,16 @@ var (
 )
 
 type messengerConfig struct {
-	ServerId             int32 // 服务器ID
-	RelayIp              string
-	RpcServer            *grpc_util.RPCServerConfig
-	Mysql                []mysql_client.MySQLConfig
-	Redis                []redis_client.RedisConfig
-	NbfsRpcClient        *service_discovery.ServiceDiscoveryClientConfig
-	SyncRpcClient1       *service_discovery.ServiceDiscoveryClientConfig
-	SyncRpcClient2       *service_discovery.ServiceDiscoveryClientConfig
-	AuthSessionRpcClient *service_discovery.ServiceDiscoveryClientConfig
+	ServerId              int32 // 服务器ID
+	RelayIp               string
+	RpcServer             *grpc_util.RPCServerConfig
+	Mysql                 []mysql_client.MySQLConfig
+	Redis                 []redis_client.RedisConfig
+	NbfsRpcClient         *service_discovery.ServiceDiscoveryClientConfig
+	SyncRpcClient1        *service_discovery.ServiceDiscoveryClientConfig
+	SyncRpcClient2        *service_discovery.ServiceDiscoveryClientConfig
+	AuthSessionRpcClient  *service_discovery.ServiceDiscoveryClientConfig
+	SessionAdminRpcClient *service_discovery.ServiceDiscoveryClientConfig
 }
 
 func init() {
@@ -118,6 +120,7 @@ func (s *messengerServer) Initialize() error {
 		document_client.InstallNbfsClient(Conf.NbfsRpcClient)
 		sync_client.InstallSyncClient(Conf.SyncRpcClient2)
 		auth_session_client.InstallAuthSessionClient(Conf.AuthSessionRpcClient)
+		session_admin_client.InstallSessionAdminClient(Conf.SessionAdminRpcClient)
 	})
 
 	s.rpcServer = grpc_util.NewRpcServer(Conf.RpcServer.Addr, &Conf.RpcServer.RpcDiscovery)
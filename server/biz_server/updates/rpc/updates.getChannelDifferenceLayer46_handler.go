@@ -18,20 +18,31 @@
 package rpc
 
 import (
-    "github.com/golang/glog"
-    "github.com/nebulaim/telegramd/proto/mtproto"
-    "golang.org/x/net/context"
-    "fmt"
-    "github.com/nebulaim/telegramd/baselib/grpc_util"
-    "github.com/nebulaim/telegramd/baselib/logger"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/grpc_util"
+	"github.com/nebulaim/telegramd/baselib/logger"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"golang.org/x/net/context"
 )
 
 // updates.getChannelDifference#bb32d7c0 channel:InputChannel filter:ChannelMessagesFilter pts:int limit:int = updates.ChannelDifference;
 func (s *UpdatesServiceImpl) UpdatesGetChannelDifferenceLayer46(ctx context.Context, request *mtproto.TLUpdatesGetChannelDifferenceLayer46) (*mtproto.Updates_ChannelDifference, error) {
-    md := grpc_util.RpcMetadataFromIncoming(ctx)
-    glog.Infof("UpdatesGetChannelDifferenceLayer46 - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
+	md := grpc_util.RpcMetadataFromIncoming(ctx)
+	glog.Infof("updates.getChannelDifference#bb32d7c0 - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
-    // TODO(@benqi): Impl UpdatesGetChannelDifferenceLayer46 logic
+	request2 := &mtproto.TLUpdatesGetChannelDifference{
+		Force:   false,
+		Channel: request.GetChannel(),
+		Filter:  request.GetFilter(),
+		Pts:     request.GetPts(),
+		Limit:   request.GetLimit(),
+	}
+	difference, err := s.getChannelDifference(md, request2)
+	if err != nil {
+		glog.Error("updates.getChannelDifference#bb32d7c0 - error: ", err)
+		return nil, err
+	}
 
-    return nil, fmt.Errorf("Not impl UpdatesGetChannelDifferenceLayer46")
+	glog.Infof("updates.getChannelDifference#bb32d7c0 - reply: %s", logger.JsonDebugData(difference))
+	return difference, nil
 }
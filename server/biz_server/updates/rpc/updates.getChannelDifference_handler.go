@@ -21,7 +21,9 @@ import (
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/logger"
+	"github.com/nebulaim/telegramd/biz/core/update"
 	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/sync_client"
 	"golang.org/x/net/context"
 )
 
@@ -30,109 +32,92 @@ func (s *UpdatesServiceImpl) UpdatesGetChannelDifference(ctx context.Context, re
 	md := grpc_util.RpcMetadataFromIncoming(ctx)
 	glog.Infof("updates.getChannelDifference#3173d78 - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
-	var (
-	// lastPts = request.GetPts()
-	//otherUpdates []*mtproto.Update
-	// messages []*mtproto.Message
-	//userList []*mtproto.User
-	//chatList []*mtproto.Chat
+	difference, err := s.getChannelDifference(md, request)
+	if err != nil {
+		glog.Error("updates.getChannelDifference#3173d78 - error: ", err)
+		return nil, err
+	}
+
+	glog.Infof("updates.getChannelDifference#3173d78 - reply: %s", logger.JsonDebugData(difference))
+	return difference, nil
+}
 
-	)
+func (s *UpdatesServiceImpl) getChannelDifference(md *grpc_util.RpcMetadata, request *mtproto.TLUpdatesGetChannelDifference) (*mtproto.Updates_ChannelDifference, error) {
+	if request.GetChannel().GetConstructor() != mtproto.TLConstructor_CRC32_inputChannel {
+		return nil, mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_CHANNEL_PRIVATE)
+	}
 
-	// var difference *mtproto.Updates_ChannelDifference
+	// TODO(@benqi): check access_hash
+	channelId := request.GetChannel().GetData2().GetChannelId()
+	channelLogic, err := s.ChannelModel.NewChannelLogicById(channelId)
+	if err != nil {
+		return nil, err
+	}
 
-	channelId := request.GetChannel().GetData2().ChannelId
-	channelLogic, _ := s.ChannelModel.NewChannelLogicById(channelId)
 	participant := channelLogic.GetChannelParticipant(md.UserId)
 	switch participant.GetConstructor() {
-	case mtproto.TLConstructor_CRC32_channelParticipantsBanned:
-		// TODO(@benqi):
-		//banned := channel.MakeChannelBannedRights(participant.GetData2().GetBannedRights().To_ChannelBannedRights())
-		//if banned.IsForbidden() {
-		//	return nil, mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_CHANNEL_PRIVATE)
-		//}
+	case mtproto.TLConstructor_CRC32_channelParticipantBanned:
+		// TODO(@benqi): 只禁止了发言的用户仍然可以看
 		return nil, mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_CHANNEL_PRIVATE)
-
 	}
 
-	// updateList, _ := sync_client.GetSyncClient().SyncGetDifference()
-	// .GetChannelUpdateListByGtPts(request.GetChannel().GetData2().GetChannelId(), lastPts)
-	//
-	//for _, update := range updateList {
-	//	switch update.GetConstructor() {
-	//	case mtproto.TLConstructor_CRC32_updateNewChannelMessage:
-	//		newMessage := update.To_UpdateNewChannelMessage()
-	//		messages = append(messages, newMessage.GetMessage())
-	//		// otherUpdates = append(otherUpdates, update)
-	//
-	//	case mtproto.TLConstructor_CRC32_updateDeleteChannelMessages:
-	//		// readHistoryOutbox := update.To_UpdateReadHistoryOutbox()
-	//		// readHistoryOutbox.SetPtsCount(0)
-	//		// otherUpdates = append(otherUpdates, readHistoryOutbox.To_Update())
-	//	case mtproto.TLConstructor_CRC32_updateEditChannelMessage:
-	//		// readHistoryInbox := update.To_UpdateReadHistoryInbox()
-	//		// readHistoryInbox.SetPtsCount(0)
-	//		// otherUpdates = append(otherUpdates, readHistoryInbox.To_Update())
-	//	case mtproto.TLConstructor_CRC32_updateChannelWebPage:
-	//	default:
-	//		continue
-	//	}
-	//	if update.Data2.GetPts() > lastPts {
-	//		lastPts = update.Data2.GetPts()
-	//	}
-	//}
-
-	//otherUpdates, boxIDList, lastPts := model.GetUpdatesModel().GetUpdatesByGtPts(md.UserId, request.GetPts())
-	//messages := model.GetMessageModel().GetMessagesByPeerAndMessageIdList2(md.UserId, boxIDList)
-	// userIdList, chatIdList, _ := message.PickAllIDListByMessages(messages)
-	// userList = user.GetUsersBySelfAndIDList(md.UserId, userIdList)
-	// chatList = chat.GetChatListBySelfAndIDList(md.UserId, chatIdList)
-	//
-	//state := &mtproto.TLUpdatesState{Data2: &mtproto.Updates_State_Data{
-	//	Pts:         lastPts,
-	//	Date:        int32(time.Now().Unix()),
-	//	UnreadCount: 0,
-	//	Seq:         int32(model.GetSequenceModel().CurrentSeqId(base2.Int32ToString(md.UserId))),
-	//	Seq:         0,
-	//}}
-
-	// updates.channelDifference#2064674e flags:# final:flags.0?true pts:int timeout:flags.1?int new_messages:Vector<Message> other_updates:Vector<Update> chats:Vector<Chat> users:Vector<User> = updates.ChannelDifference;
-	var difference *mtproto.Updates_ChannelDifference
+	difference, err := sync_client.GetSyncClient().SyncGetChannelDifference(md.AuthId,
+		md.UserId,
+		request.GetForce(),
+		request.GetChannel(),
+		request.GetFilter(),
+		request.GetPts(),
+		request.GetLimit())
+	if err != nil {
+		return nil, err
+	}
 
-	//if len(updateList) == 0 {
-	// pts, _ := sync_client.GetSyncClient().GetCurrentChannelPts(request.GetChannel().GetData2().GetChannelId())
-	difference = &mtproto.Updates_ChannelDifference{
-		Constructor: mtproto.TLConstructor_CRC32_updates_channelDifferenceEmpty,
-		Data2: &mtproto.Updates_ChannelDifference_Data{
-			Final:   true,
-			Pts:     0,
-			Timeout: 30,
-		},
+	diff := difference.GetData2()
+	switch difference.GetConstructor() {
+	case mtproto.TLConstructor_CRC32_updates_channelDifferenceEmpty:
+		return difference, nil
+	case mtproto.TLConstructor_CRC32_updates_channelDifferenceTooLong:
+		// 客户端丢弃本地消息, 用top_message附近的消息和已读状态重建对话
+		diff.TopMessage = channelLogic.GetTopMessage()
+		diff.ReadInboxMaxId, diff.ReadOutboxMaxId = channelLogic.GetReadHistoryState(md.UserId)
+		if diff.ReadInboxMaxId < diff.TopMessage {
+			diff.UnreadCount = diff.TopMessage - diff.ReadInboxMaxId
+		}
+		diff.UnreadMentionsCount = 0
+		diff.Messages = s.MessageModel.GetChannelMessageList(md.UserId, channelId, makeTopMessageIdList(diff.TopMessage, request.GetLimit()))
+	case mtproto.TLConstructor_CRC32_updates_channelDifference:
+	default:
+		glog.Errorf("getChannelDifference - invalid difference: %s", logger.JsonDebugData(difference))
+		return difference, nil
 	}
-	//} else {
-	//	difference = &mtproto.Updates_ChannelDifference{
-	//		Constructor: mtproto.TLConstructor_CRC32_updates_channelDifferenceEmpty,
-	//		Data2:  &mtproto.Updates_ChannelDifference_Data{
-	//			Final:   true,
-	//			Pts:     2,
-	//			Timeout: 3,
-	//		},
-	//	}
-	//	//difference := &mtproto.TLUpdatesChannelDifference{Data2: &mtproto.Updates_ChannelDifference_Data{
-	//	//	Pts: lastPts,
-	//	//	Timeout: 3,
-	//	//	NewMessages:  messages,
-	//	//	OtherUpdates: otherUpdates,
-	//	//	Users:        userList,
-	//	//	Chats:        chatList,
-	//	//	// State:        state.To_Updates_State(),
-	//	//}}
-	//	//
-	//	// TODO(@benqi): remove to received ack handler.
-	//	// update2.UpdateAuthStateSeq(md.AuthId, lastPts, 0)
-	//}
 
-	glog.Infof("updates.getChannelDifference#3173d78 - reply: %s", logger.JsonDebugData(difference))
+	userIdList, chatIdList, channelIdList := updates.PickAllIDListByMessagesAndUpdates(append(diff.GetNewMessages(), diff.GetMessages()...), diff.GetOtherUpdates())
+	if !containsId(channelIdList, channelId) {
+		channelIdList = append(channelIdList, channelId)
+	}
+	diff.Users = s.UserModel.GetUsersBySelfAndIDList(md.UserId, userIdList)
+	diff.Chats = s.ChatModel.GetChatListBySelfAndIDList(md.UserId, chatIdList)
+	diff.Chats = append(diff.Chats, s.ChannelModel.GetChannelListBySelfAndIDList(md.UserId, channelIdList)...)
 	return difference, nil
+}
 
+// 频道消息id是连续的, 取top_message往前limit条
+func makeTopMessageIdList(topMessage, limit int32) []int32 {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+	idList := make([]int32, 0, limit)
+	for id := topMessage; id > 0 && int32(len(idList)) < limit; id-- {
+		idList = append(idList, id)
+	}
+	return idList
+}
+
+func containsId(idList []int32, id int32) bool {
+	for _, i := range idList {
+		if i == id {
+			return true
+		}
+	}
+	return false
 }
@@ -24,8 +24,7 @@ import (
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"golang.org/x/net/context"
 	"github.com/nebulaim/telegramd/server/sync/sync_client"
-	"github.com/nebulaim/telegramd/biz/core/message"
-	"fmt"
+	"github.com/nebulaim/telegramd/biz/core/update"
 )
 
 // updates.getDifference#25939651 flags:# pts:int pts_total_limit:flags.0?int date:int qts:int = updates.Difference;
@@ -33,7 +32,12 @@ func (s *UpdatesServiceImpl) UpdatesGetDifference(ctx context.Context, request *
 	md := grpc_util.RpcMetadataFromIncoming(ctx)
 	glog.Infof("updates.getDifference#25939651 - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
-	difference, err := sync_client.GetSyncClient().SyncGetDifference(md.AuthId, md.UserId, request.GetPts())
+	difference, err := sync_client.GetSyncClient().SyncGetDifference(md.AuthId,
+		md.UserId,
+		request.GetPts(),
+		request.GetPtsTotalLimit(),
+		request.GetDate(),
+		request.GetQts())
 	if err != nil {
 		glog.Error("sync.getDifference error - ", err)
 		return nil, err
@@ -41,17 +45,17 @@ func (s *UpdatesServiceImpl) UpdatesGetDifference(ctx context.Context, request *
 
 	switch difference.GetConstructor() {
 	case mtproto.TLConstructor_CRC32_updates_differenceEmpty:
-	case mtproto.TLConstructor_CRC32_updates_difference:
-		diff := difference.To_UpdatesDifference()
-
-		userIdList, chatIdList, _ := message.PickAllIDListByMessages(diff.GetNewMessages())
-		userList := s.UserModel.GetUsersBySelfAndIDList(md.UserId, userIdList)
-		chatList := s.ChatModel.GetChatListBySelfAndIDList(md.UserId, chatIdList)
-		diff.SetUsers(userList)
-		diff.SetChats(chatList)
-
+	case mtproto.TLConstructor_CRC32_updates_differenceTooLong:
+	case mtproto.TLConstructor_CRC32_updates_difference,
+		mtproto.TLConstructor_CRC32_updates_differenceSlice:
+		// new_messages和other_updates里引用到的所有user和chat
+		diff := difference.GetData2()
+		userIdList, chatIdList, channelIdList := updates.PickAllIDListByMessagesAndUpdates(diff.GetNewMessages(), diff.GetOtherUpdates())
+		diff.Users = s.UserModel.GetUsersBySelfAndIDList(md.UserId, userIdList)
+		diff.Chats = s.ChatModel.GetChatListBySelfAndIDList(md.UserId, chatIdList)
+		diff.Chats = append(diff.Chats, s.ChannelModel.GetChannelListBySelfAndIDList(md.UserId, channelIdList)...)
 	default:
-		err = fmt.Errorf("not impl")
+		glog.Errorf("updates.getDifference#25939651 - invalid difference: %s", logger.JsonDebugData(difference))
 	}
 
 	glog.Infof("updates.getDifference#25939651 - reply: %s", logger.JsonDebugData(difference))
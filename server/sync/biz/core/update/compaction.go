@@ -0,0 +1,152 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package update
+
+import (
+	"fmt"
+
+	"github.com/nebulaim/telegramd/server/sync/biz/dal/dataobject"
+)
+
+// 每次压缩最多扫描的批数, 没扫完的下次从cursor继续
+const kMaxCompactionBatches = 100
+
+// 按id增量扫描pts队列的位置, 由调用方在两次压缩之间保存
+// 进程重启后从0开始, 分多次把已有的update扫一遍
+type CompactionCursor struct {
+	UserPtsId    int64
+	ChannelPtsId int64
+}
+
+// 清理pts/qts/seq队列:
+// 删除date2早于before的update, 以及每个user/channel超过maxPtsUpdates个pts的update;
+// 删除前记录已删除的最大pts(min_pts)和date(min_date), 客户端状态落后于它时getDifference返回differenceTooLong
+func (m *UpdateModel) CompactUpdates(before, maxPtsUpdates, limit int32, cursor *CompactionCursor) (n int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("compact updates error: %v", r)
+		}
+	}()
+
+	// user_pts_updates
+	for _, do := range m.dao.UserPtsUpdatesDAO.SelectExpiredList(before, limit) {
+		n += m.compactUserPtsUpdates(do.UserId, do.Pts)
+	}
+	if maxPtsUpdates > 0 {
+		n += m.compactUserPtsUpdatesByCount(cursor, maxPtsUpdates, limit)
+	}
+
+	// channel_pts_updates
+	for _, do := range m.dao.ChannelPtsUpdatesDAO.SelectExpiredList(before, limit) {
+		n += m.compactChannelPtsUpdates(do.ChannelId, do.Pts)
+	}
+	if maxPtsUpdates > 0 {
+		n += m.compactChannelPtsUpdatesByCount(cursor, maxPtsUpdates, limit)
+	}
+
+	// auth_seq_updates按date取, 只按时间清理
+	for _, do := range m.dao.AuthSeqUpdatesDAO.SelectExpiredList(before, limit) {
+		m.dao.UserUpdateFloorsDAO.InsertOrUpdateDate(&dataobject.UserUpdateFloorsDO{UserId: do.UserId, MinDate: do.Date2})
+		n += m.dao.AuthSeqUpdatesDAO.DeleteByLeDate(do.UserId, do.Date2)
+	}
+
+	// TODO(@benqi): qts还没有用到, 不记录下限
+	n += m.dao.UserQtsUpdatesDAO.DeleteExpired(before, limit)
+	return
+}
+
+// 只检查cursor之后写入过update的user:
+// 当前pts减去已记录的下限超过maxPtsUpdates时, 只保留最新的maxPtsUpdates个pts
+func (m *UpdateModel) compactUserPtsUpdatesByCount(cursor *CompactionCursor, maxPtsUpdates, limit int32) (n int64) {
+	for i := 0; i < kMaxCompactionBatches; i++ {
+		doList := m.dao.UserPtsUpdatesDAO.SelectListByGtId(cursor.UserPtsId, limit)
+		if len(doList) == 0 {
+			break
+		}
+
+		userIdList := make(map[int32]bool, len(doList))
+		for j := 0; j < len(doList); j++ {
+			userIdList[doList[j].UserId] = true
+		}
+		for userId := range userIdList {
+			pts := int32(m.CurrentPtsId(userId))
+			if minPts, _ := m.GetUserUpdateFloor(userId); pts-minPts > maxPtsUpdates {
+				n += m.compactUserPtsUpdates(userId, pts-maxPtsUpdates)
+			}
+		}
+
+		// 整批处理完再移动cursor, 中途出错下次重新处理这一批
+		cursor.UserPtsId = doList[len(doList)-1].Id
+		if len(doList) < int(limit) {
+			break
+		}
+	}
+	return
+}
+
+func (m *UpdateModel) compactChannelPtsUpdatesByCount(cursor *CompactionCursor, maxPtsUpdates, limit int32) (n int64) {
+	for i := 0; i < kMaxCompactionBatches; i++ {
+		doList := m.dao.ChannelPtsUpdatesDAO.SelectListByGtId(cursor.ChannelPtsId, limit)
+		if len(doList) == 0 {
+			break
+		}
+
+		channelIdList := make(map[int32]bool, len(doList))
+		for j := 0; j < len(doList); j++ {
+			channelIdList[doList[j].ChannelId] = true
+		}
+		for channelId := range channelIdList {
+			pts := int32(m.CurrentChannelPtsId(channelId))
+			if pts-m.GetChannelPtsFloor(channelId) > maxPtsUpdates {
+				n += m.compactChannelPtsUpdates(channelId, pts-maxPtsUpdates)
+			}
+		}
+
+		cursor.ChannelPtsId = doList[len(doList)-1].Id
+		if len(doList) < int(limit) {
+			break
+		}
+	}
+	return
+}
+
+// 先记录下限再删除, 中途失败时客户端最多多收到一次differenceTooLong
+func (m *UpdateModel) compactUserPtsUpdates(userId, pts int32) int64 {
+	m.dao.UserUpdateFloorsDAO.InsertOrUpdatePts(&dataobject.UserUpdateFloorsDO{UserId: userId, MinPts: pts})
+	return m.dao.UserPtsUpdatesDAO.DeleteByLePts(userId, pts)
+}
+
+func (m *UpdateModel) compactChannelPtsUpdates(channelId, pts int32) int64 {
+	m.dao.ChannelUpdateFloorsDAO.InsertOrUpdate(&dataobject.ChannelUpdateFloorsDO{ChannelId: channelId, MinPts: pts})
+	return m.dao.ChannelPtsUpdatesDAO.DeleteByLePts(channelId, pts)
+}
+
+// minPts: 已删除的最大pts, minDate: 已删除的最大seq update的date
+func (m *UpdateModel) GetUserUpdateFloor(userId int32) (minPts, minDate int32) {
+	if do := m.dao.UserUpdateFloorsDAO.Select(userId); do != nil {
+		minPts, minDate = do.MinPts, do.MinDate
+	}
+	return
+}
+
+func (m *UpdateModel) GetChannelPtsFloor(channelId int32) int32 {
+	if do := m.dao.ChannelUpdateFloorsDAO.Select(channelId); do != nil {
+		return do.MinPts
+	}
+	return 0
+}
@@ -0,0 +1,128 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package update
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/biz/dal/dataobject"
+)
+
+// getDifference使用:
+// pts队列按pts分页, 没有pts的update(改名、置顶、草稿、群成员变化等)存在auth_seq_updates里,
+// 按客户端state里的date取
+type PtsUpdate struct {
+	Pts      int32
+	PtsCount int32
+	Date     int32
+	Update   *mtproto.Update
+}
+
+type SeqUpdate struct {
+	Seq    int32
+	Date   int32
+	Update *mtproto.Update
+}
+
+// 返回分配的seq
+func (m *UpdateModel) AddToSeqQueue(authKeyId int64, userId int32, update *mtproto.Update) int32 {
+	// TODO(@benqi): check error
+	updateData, _ := json.Marshal(update)
+
+	seq := int32(m.NextSeqId(userId))
+	do := &dataobject.AuthSeqUpdatesDO{
+		AuthId:     authKeyId,
+		UserId:     userId,
+		Seq:        seq,
+		UpdateType: int32(update.GetConstructor()),
+		UpdateData: updateData,
+		Date2:      int32(time.Now().Unix()),
+	}
+	m.dao.AuthSeqUpdatesDAO.Insert(do)
+	return seq
+}
+
+func (m *UpdateModel) GetPtsUpdateListByGtPts(userId, pts, limit int32) []*PtsUpdate {
+	doList := m.dao.UserPtsUpdatesDAO.SelectByGtPtsLimit(userId, pts, limit)
+
+	updates := make([]*PtsUpdate, 0, len(doList))
+	for _, do := range doList {
+		update := &mtproto.Update{Constructor: mtproto.TLConstructor_CRC32_UNKNOWN, Data2: &mtproto.Update_Data{}}
+		err := json.Unmarshal([]byte(do.UpdateData), update)
+		if err != nil {
+			glog.Errorf("unmarshal pts's update(%d)error: %v", do.Pts, err)
+			continue
+		}
+		if getUpdateType(update) != do.UpdateType {
+			glog.Errorf("update data error.")
+			continue
+		}
+		updates = append(updates, &PtsUpdate{Pts: do.Pts, PtsCount: do.PtsCount, Date: do.Date2, Update: update})
+	}
+	return updates
+}
+
+func (m *UpdateModel) GetSeqUpdateListByGtDate(userId, date, limit int32) []*SeqUpdate {
+	return makeSeqUpdateList(m.dao.AuthSeqUpdatesDAO.SelectByUserGtDate(userId, date, limit))
+}
+
+// 取某一秒全部的seq update
+func (m *UpdateModel) GetSeqUpdateListByDate(userId, date int32) []*SeqUpdate {
+	return makeSeqUpdateList(m.dao.AuthSeqUpdatesDAO.SelectByUserDate(userId, date))
+}
+
+func makeSeqUpdateList(doList []dataobject.AuthSeqUpdatesDO) []*SeqUpdate {
+	updates := make([]*SeqUpdate, 0, len(doList))
+	for _, do := range doList {
+		update := &mtproto.Update{Constructor: mtproto.TLConstructor_CRC32_UNKNOWN, Data2: &mtproto.Update_Data{}}
+		err := json.Unmarshal(do.UpdateData, update)
+		if err != nil {
+			glog.Errorf("unmarshal seq's update(%d)error: %v", do.Seq, err)
+			continue
+		}
+		if int32(update.GetConstructor()) != do.UpdateType {
+			glog.Errorf("update data error.")
+			continue
+		}
+		updates = append(updates, &SeqUpdate{Seq: do.Seq, Date: do.Date2, Update: update})
+	}
+	return updates
+}
+
+func (m *UpdateModel) GetChannelPtsUpdateListByGtPts(channelId, pts, limit int32) []*PtsUpdate {
+	doList := m.dao.ChannelPtsUpdatesDAO.SelectByGtPtsLimit(channelId, pts, limit)
+
+	updates := make([]*PtsUpdate, 0, len(doList))
+	for _, do := range doList {
+		update := &mtproto.Update{Constructor: mtproto.TLConstructor_CRC32_UNKNOWN, Data2: &mtproto.Update_Data{}}
+		err := json.Unmarshal([]byte(do.UpdateData), update)
+		if err != nil {
+			glog.Errorf("unmarshal channel pts's update(%d)error: %v", do.Pts, err)
+			continue
+		}
+		if getUpdateType(update) != do.UpdateType {
+			glog.Errorf("update data error.")
+			continue
+		}
+		updates = append(updates, &PtsUpdate{Pts: do.Pts, PtsCount: do.PtsCount, Date: do.Date2, Update: update})
+	}
+	return updates
+}
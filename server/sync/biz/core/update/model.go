@@ -31,6 +31,8 @@ type updatesDAO struct {
 	*mysql_dao.UserQtsUpdatesDAO
 	*mysql_dao.UserPtsUpdatesDAO
 	*mysql_dao.ChannelPtsUpdatesDAO
+	*mysql_dao.UserUpdateFloorsDAO
+	*mysql_dao.ChannelUpdateFloorsDAO
 
 	// idgen.UUIDGen
 	idgen.SeqIDGen
@@ -53,6 +55,8 @@ func NewUpdateModel(serverId int32, dbName, redisName string) *UpdateModel {
 	m.dao.UserQtsUpdatesDAO = mysql_dao.NewUserQtsUpdatesDAO(db)
 	m.dao.UserPtsUpdatesDAO = mysql_dao.NewUserPtsUpdatesDAO(db)
 	m.dao.ChannelPtsUpdatesDAO = mysql_dao.NewChannelPtsUpdatesDAO(db)
+	m.dao.UserUpdateFloorsDAO = mysql_dao.NewUserUpdateFloorsDAO(db)
+	m.dao.ChannelUpdateFloorsDAO = mysql_dao.NewChannelUpdateFloorsDAO(db)
 
 	var err error
 	//m.dao.UUIDGen, err = idgen.NewUUIDGen("snowflake", base.Int32ToString(serverId))
@@ -69,6 +69,6 @@ func (m *UpdateModel) NextChannelPtsId(key int32) (seq int64) {
 }
 
 func (m *UpdateModel) CurrentChannelPtsId(key int32) (seq int64) {
-	seq, _ = m.dao.SeqIDGen.GetNextSeqID(channelPtsUpdatesNgenId + base.Int32ToString(key))
+	seq, _ = m.dao.SeqIDGen.GetCurrentSeqID(channelPtsUpdatesNgenId + base.Int32ToString(key))
 	return
 }
@@ -126,3 +126,139 @@ func (dao *AuthSeqUpdatesDAO) SelectByGtSeq(auth_id int64, user_id int32, seq in
 
 	return values
 }
+
+// select auth_id, user_id, seq, update_type, update_data, date2 from auth_seq_updates where user_id = :user_id and date2 > :date2 order by seq asc limit :limit
+// TODO(@benqi): sqlmap
+func (dao *AuthSeqUpdatesDAO) SelectByUserGtDate(user_id int32, date2 int32, limit int32) []dataobject.AuthSeqUpdatesDO {
+	var query = "select auth_id, user_id, seq, update_type, update_data, date2 from auth_seq_updates where user_id = ? and date2 > ? order by seq asc limit ?"
+	rows, err := dao.db.Queryx(query, user_id, date2, limit)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectByUserGtDate(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.AuthSeqUpdatesDO
+	for rows.Next() {
+		v := dataobject.AuthSeqUpdatesDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectByUserGtDate(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectByUserGtDate(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// select auth_id, user_id, seq, update_type, update_data, date2 from auth_seq_updates where user_id = :user_id and date2 = :date2 order by seq asc
+// TODO(@benqi): sqlmap
+func (dao *AuthSeqUpdatesDAO) SelectByUserDate(user_id int32, date2 int32) []dataobject.AuthSeqUpdatesDO {
+	var query = "select auth_id, user_id, seq, update_type, update_data, date2 from auth_seq_updates where user_id = ? and date2 = ? order by seq asc"
+	rows, err := dao.db.Queryx(query, user_id, date2)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectByUserDate(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.AuthSeqUpdatesDO
+	for rows.Next() {
+		v := dataobject.AuthSeqUpdatesDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectByUserDate(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectByUserDate(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// select user_id, max(date2) as date2 from auth_seq_updates where date2 < :date2 group by user_id limit :limit
+// TODO(@benqi): sqlmap
+func (dao *AuthSeqUpdatesDAO) SelectExpiredList(date2 int32, limit int32) []dataobject.AuthSeqUpdatesDO {
+	var query = "select user_id, max(date2) as date2 from auth_seq_updates where date2 < ? group by user_id limit ?"
+	rows, err := dao.db.Queryx(query, date2, limit)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectExpiredList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.AuthSeqUpdatesDO
+	for rows.Next() {
+		v := dataobject.AuthSeqUpdatesDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectExpiredList(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectExpiredList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// delete from auth_seq_updates where user_id = :user_id and date2 <= :date2
+// TODO(@benqi): sqlmap
+func (dao *AuthSeqUpdatesDAO) DeleteByLeDate(user_id int32, date2 int32) int64 {
+	var query = "delete from auth_seq_updates where user_id = ? and date2 <= ?"
+	r, err := dao.db.Exec(query, user_id, date2)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Exec in DeleteByLeDate(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	rows, err := r.RowsAffected()
+	if err != nil {
+		errDesc := fmt.Sprintf("RowsAffected in DeleteByLeDate(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return rows
+}
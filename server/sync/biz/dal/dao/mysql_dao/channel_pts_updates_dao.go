@@ -126,3 +126,139 @@ func (dao *ChannelPtsUpdatesDAO) SelectByGtPts(channel_id int32, pts int32) []da
 
 	return values
 }
+
+// select channel_id, pts, pts_count, update_type, update_data, date2 from channel_pts_updates where channel_id = :channel_id and pts > :pts order by pts asc limit :limit
+// TODO(@benqi): sqlmap
+func (dao *ChannelPtsUpdatesDAO) SelectByGtPtsLimit(channel_id int32, pts int32, limit int32) []dataobject.ChannelPtsUpdatesDO {
+	var query = "select channel_id, pts, pts_count, update_type, update_data, date2 from channel_pts_updates where channel_id = ? and pts > ? order by pts asc limit ?"
+	rows, err := dao.db.Queryx(query, channel_id, pts, limit)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectByGtPtsLimit(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.ChannelPtsUpdatesDO
+	for rows.Next() {
+		v := dataobject.ChannelPtsUpdatesDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectByGtPtsLimit(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectByGtPtsLimit(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// select channel_id, max(pts) as pts from channel_pts_updates where date2 < :date2 group by channel_id limit :limit
+// TODO(@benqi): sqlmap
+func (dao *ChannelPtsUpdatesDAO) SelectExpiredList(date2 int32, limit int32) []dataobject.ChannelPtsUpdatesDO {
+	var query = "select channel_id, max(pts) as pts from channel_pts_updates where date2 < ? group by channel_id limit ?"
+	rows, err := dao.db.Queryx(query, date2, limit)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectExpiredList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.ChannelPtsUpdatesDO
+	for rows.Next() {
+		v := dataobject.ChannelPtsUpdatesDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectExpiredList(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectExpiredList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// select id, channel_id, pts from channel_pts_updates where id > :id order by id asc limit :limit
+// TODO(@benqi): sqlmap
+func (dao *ChannelPtsUpdatesDAO) SelectListByGtId(id int64, limit int32) []dataobject.ChannelPtsUpdatesDO {
+	var query = "select id, channel_id, pts from channel_pts_updates where id > ? order by id asc limit ?"
+	rows, err := dao.db.Queryx(query, id, limit)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectListByGtId(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.ChannelPtsUpdatesDO
+	for rows.Next() {
+		v := dataobject.ChannelPtsUpdatesDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectListByGtId(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectListByGtId(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// delete from channel_pts_updates where channel_id = :channel_id and pts <= :pts
+// TODO(@benqi): sqlmap
+func (dao *ChannelPtsUpdatesDAO) DeleteByLePts(channel_id int32, pts int32) int64 {
+	var query = "delete from channel_pts_updates where channel_id = ? and pts <= ?"
+	r, err := dao.db.Exec(query, channel_id, pts)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Exec in DeleteByLePts(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	rows, err := r.RowsAffected()
+	if err != nil {
+		errDesc := fmt.Sprintf("RowsAffected in DeleteByLePts(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return rows
+}
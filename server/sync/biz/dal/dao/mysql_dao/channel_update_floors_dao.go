@@ -0,0 +1,90 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mysql_dao
+
+import (
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/jmoiron/sqlx"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/biz/dal/dataobject"
+)
+
+type ChannelUpdateFloorsDAO struct {
+	db *sqlx.DB
+}
+
+func NewChannelUpdateFloorsDAO(db *sqlx.DB) *ChannelUpdateFloorsDAO {
+	return &ChannelUpdateFloorsDAO{db}
+}
+
+// insert into channel_update_floors(channel_id, min_pts) values (:channel_id, :min_pts) on duplicate key update min_pts = greatest(min_pts, values(min_pts))
+// TODO(@benqi): sqlmap
+func (dao *ChannelUpdateFloorsDAO) InsertOrUpdate(do *dataobject.ChannelUpdateFloorsDO) int64 {
+	var query = "insert into channel_update_floors(channel_id, min_pts) values (:channel_id, :min_pts) on duplicate key update min_pts = greatest(min_pts, values(min_pts))"
+	r, err := dao.db.NamedExec(query, do)
+	if err != nil {
+		errDesc := fmt.Sprintf("NamedExec in InsertOrUpdate(%v), error: %v", do, err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	id, err := r.LastInsertId()
+	if err != nil {
+		errDesc := fmt.Sprintf("LastInsertId in InsertOrUpdate(%v)_error: %v", do, err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+	return id
+}
+
+// select channel_id, min_pts from channel_update_floors where channel_id = :channel_id
+// TODO(@benqi): sqlmap
+func (dao *ChannelUpdateFloorsDAO) Select(channel_id int32) *dataobject.ChannelUpdateFloorsDO {
+	var query = "select channel_id, min_pts from channel_update_floors where channel_id = ?"
+	rows, err := dao.db.Queryx(query, channel_id)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in Select(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	do := &dataobject.ChannelUpdateFloorsDO{}
+	if rows.Next() {
+		err = rows.StructScan(do)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in Select(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+	} else {
+		return nil
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in Select(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return do
+}
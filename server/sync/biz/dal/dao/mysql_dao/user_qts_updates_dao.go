@@ -126,3 +126,25 @@ func (dao *UserQtsUpdatesDAO) SelectByGtQts(user_id int32, qts int32) []dataobje
 
 	return values
 }
+
+// delete from user_qts_updates where date2 < :date2 limit :limit
+// TODO(@benqi): sqlmap
+func (dao *UserQtsUpdatesDAO) DeleteExpired(date2 int32, limit int32) int64 {
+	var query = "delete from user_qts_updates where date2 < ? limit ?"
+	r, err := dao.db.Exec(query, date2, limit)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Exec in DeleteExpired(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	rows, err := r.RowsAffected()
+	if err != nil {
+		errDesc := fmt.Sprintf("RowsAffected in DeleteExpired(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return rows
+}
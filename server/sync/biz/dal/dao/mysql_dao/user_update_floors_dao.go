@@ -0,0 +1,110 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mysql_dao
+
+import (
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/jmoiron/sqlx"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/biz/dal/dataobject"
+)
+
+type UserUpdateFloorsDAO struct {
+	db *sqlx.DB
+}
+
+func NewUserUpdateFloorsDAO(db *sqlx.DB) *UserUpdateFloorsDAO {
+	return &UserUpdateFloorsDAO{db}
+}
+
+// insert into user_update_floors(user_id, min_pts) values (:user_id, :min_pts) on duplicate key update min_pts = greatest(min_pts, values(min_pts))
+// TODO(@benqi): sqlmap
+func (dao *UserUpdateFloorsDAO) InsertOrUpdatePts(do *dataobject.UserUpdateFloorsDO) int64 {
+	var query = "insert into user_update_floors(user_id, min_pts) values (:user_id, :min_pts) on duplicate key update min_pts = greatest(min_pts, values(min_pts))"
+	r, err := dao.db.NamedExec(query, do)
+	if err != nil {
+		errDesc := fmt.Sprintf("NamedExec in InsertOrUpdatePts(%v), error: %v", do, err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	id, err := r.LastInsertId()
+	if err != nil {
+		errDesc := fmt.Sprintf("LastInsertId in InsertOrUpdatePts(%v)_error: %v", do, err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+	return id
+}
+
+// insert into user_update_floors(user_id, min_date) values (:user_id, :min_date) on duplicate key update min_date = greatest(min_date, values(min_date))
+// TODO(@benqi): sqlmap
+func (dao *UserUpdateFloorsDAO) InsertOrUpdateDate(do *dataobject.UserUpdateFloorsDO) int64 {
+	var query = "insert into user_update_floors(user_id, min_date) values (:user_id, :min_date) on duplicate key update min_date = greatest(min_date, values(min_date))"
+	r, err := dao.db.NamedExec(query, do)
+	if err != nil {
+		errDesc := fmt.Sprintf("NamedExec in InsertOrUpdateDate(%v), error: %v", do, err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	id, err := r.LastInsertId()
+	if err != nil {
+		errDesc := fmt.Sprintf("LastInsertId in InsertOrUpdateDate(%v)_error: %v", do, err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+	return id
+}
+
+// select user_id, min_pts, min_date from user_update_floors where user_id = :user_id
+// TODO(@benqi): sqlmap
+func (dao *UserUpdateFloorsDAO) Select(user_id int32) *dataobject.UserUpdateFloorsDO {
+	var query = "select user_id, min_pts, min_date from user_update_floors where user_id = ?"
+	rows, err := dao.db.Queryx(query, user_id)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in Select(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	do := &dataobject.UserUpdateFloorsDO{}
+	if rows.Next() {
+		err = rows.StructScan(do)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in Select(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+	} else {
+		return nil
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in Select(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return do
+}
@@ -0,0 +1,96 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/base"
+	"github.com/nebulaim/telegramd/server/sync/biz/core/update"
+)
+
+// 定期清理user_pts_updates、channel_pts_updates、auth_seq_updates、user_qts_updates
+const (
+	kDefaultCompactionInterval  = 10 * time.Minute
+	kDefaultCompactionRetention = 7 * 24 * time.Hour
+	kDefaultCompactionBatchSize = 1000
+)
+
+// 多个sync节点时只需要在一个节点上打开
+type compactionConfig struct {
+	Enable        bool
+	Interval      base.Duration
+	Retention     base.Duration // 保留时间
+	MaxPtsUpdates int32         // 每个user/channel最多保留最新的多少个pts, 0为不限制
+	BatchSize     int32         // 每批最多处理的user/channel数或扫描的pts update数
+}
+
+func (c compactionConfig) String() string {
+	return fmt.Sprintf("{enable: %v, interval: %v, retention: %v, maxPtsUpdates: %d, batchSize: %d}",
+		c.Enable,
+		time.Duration(c.Interval),
+		time.Duration(c.Retention),
+		c.MaxPtsUpdates,
+		c.BatchSize)
+}
+
+func (c compactionConfig) interval() time.Duration {
+	if c.Interval <= 0 {
+		return kDefaultCompactionInterval
+	}
+	return time.Duration(c.Interval)
+}
+
+func (c compactionConfig) retention() time.Duration {
+	if c.Retention <= 0 {
+		return kDefaultCompactionRetention
+	}
+	return time.Duration(c.Retention)
+}
+
+func (c compactionConfig) batchSize() int32 {
+	if c.BatchSize <= 0 {
+		return kDefaultCompactionBatchSize
+	}
+	return c.BatchSize
+}
+
+func compactUpdatesLoop(updateModel *update.UpdateModel, c compactionConfig, closeChan chan struct{}) {
+	ticker := time.NewTicker(c.interval())
+	defer ticker.Stop()
+
+	// 每次从上次扫描到的位置继续
+	cursor := &update.CompactionCursor{}
+
+	for {
+		select {
+		case <-ticker.C:
+			before := int32(time.Now().Add(-c.retention()).Unix())
+			n, err := updateModel.CompactUpdates(before, c.MaxPtsUpdates, c.batchSize(), cursor)
+			if err != nil {
+				glog.Error(err)
+			} else if n > 0 {
+				glog.Infof("syncServer - compact %d updates", n)
+			}
+		case <-closeChan:
+			return
+		}
+	}
+}
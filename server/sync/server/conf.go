@@ -23,8 +23,10 @@ import (
 	"github.com/BurntSushi/toml"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/mysql_client"
+	"github.com/nebulaim/telegramd/baselib/nsq_client"
 	"github.com/nebulaim/telegramd/baselib/redis_client"
 	"github.com/nebulaim/telegramd/proto/zproto"
+	"github.com/nebulaim/telegramd/server/sync/server/rpc"
 )
 
 var (
@@ -33,20 +35,30 @@ var (
 )
 
 type syncConfig struct {
-	ServerId      int32 // 服务器ID
-	Redis         []redis_client.RedisConfig
-	Mysql         []mysql_client.MySQLConfig
-	Server        *grpc_util.RPCServerConfig
-	SessionClient *zproto.ZProtoClientConfig
+	ServerId          int32 // 服务器ID
+	Redis             []redis_client.RedisConfig
+	Mysql             []mysql_client.MySQLConfig
+	Server            *grpc_util.RPCServerConfig
+	SessionClient     *zproto.ZProtoClientConfig
+	Difference        rpc.DifferenceConfig
+	ChannelDifference rpc.ChannelDifferenceConfig
+	Shard             rpc.ShardConfig
+	PushQueue         *nsq_client.ProducerConfig // 不为空时通过nsq推送给session服务器
+	Compaction        compactionConfig
 }
 
 func (c *syncConfig) String() string {
-	return fmt.Sprintf("{server_id: %d, redis: %v. mysql: %v, server: %v, sessionClient: %v}",
+	return fmt.Sprintf("{server_id: %d, redis: %v. mysql: %v, server: %v, sessionClient: %v, difference: %v, channelDifference: %v, shard: %v, pushQueue: %v, compaction: %v}",
 		c.ServerId,
 		c.Redis,
 		c.Mysql,
 		c.Server,
-		c.SessionClient)
+		c.SessionClient,
+		c.Difference,
+		c.ChannelDifference,
+		c.Shard,
+		c.PushQueue,
+		c.Compaction)
 }
 
 func init() {
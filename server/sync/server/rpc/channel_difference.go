@@ -0,0 +1,208 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/biz/core/update"
+)
+
+const (
+	kDefaultChannelDifferenceMaxLimit   = 100
+	kDefaultChannelDifferenceTooLongGap = 1000
+	kDefaultChannelDifferenceTimeout    = 30
+)
+
+type ChannelTooLongGap struct {
+	ChannelId  int32
+	TooLongGap int32
+}
+
+type ChannelDifferenceConfig struct {
+	MaxLimit   int32               // 每次最多返回的update数, 客户端传的limit不能超过该值
+	TooLongGap int32               // 客户端pts落后超过该值时返回channelDifferenceTooLong
+	Timeout    int32               // 建议客户端下次getChannelDifference的间隔(秒)
+	Channels   []ChannelTooLongGap // 按频道单独设置tooLongGap, 大频道可以设小一些
+}
+
+func (c ChannelDifferenceConfig) limit(limit int32) int32 {
+	maxLimit := c.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = kDefaultChannelDifferenceMaxLimit
+	}
+	if limit <= 0 || limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
+
+func (c ChannelDifferenceConfig) timeout() int32 {
+	if c.Timeout <= 0 {
+		return kDefaultChannelDifferenceTimeout
+	}
+	return c.Timeout
+}
+
+func (c ChannelDifferenceConfig) tooLongGap(channelId int32) int32 {
+	for _, ch := range c.Channels {
+		if ch.ChannelId == channelId && ch.TooLongGap > 0 {
+			return ch.TooLongGap
+		}
+	}
+	if c.TooLongGap <= 0 {
+		return kDefaultChannelDifferenceTooLongGap
+	}
+	return c.TooLongGap
+}
+
+// force: 客户端允许跳过部分update, 落后超过一页时直接返回tooLong, 不再逐页补发
+func (c ChannelDifferenceConfig) isTooLong(channelId, pts, limit, currentPts int32, force bool) bool {
+	gap := currentPts - pts
+	if pts <= 0 {
+		// 客户端没有本地状态
+		return gap > 0
+	}
+	if force && gap > limit {
+		return true
+	}
+	return gap > c.tooLongGap(channelId)
+}
+
+// channelMessagesFilter#cd77d957 flags:# exclude_new_messages:flags.1?true ranges:Vector<MessageRange> = ChannelMessagesFilter;
+type channelMessagesFilter struct {
+	excludeNewMessages bool
+	ranges             []*mtproto.MessageRange_Data
+}
+
+func makeChannelMessagesFilter(filter *mtproto.ChannelMessagesFilter) *channelMessagesFilter {
+	f := &channelMessagesFilter{}
+	if filter.GetConstructor() == mtproto.TLConstructor_CRC32_channelMessagesFilter {
+		f.excludeNewMessages = filter.GetData2().GetExcludeNewMessages()
+		for _, r := range filter.GetData2().GetRanges() {
+			f.ranges = append(f.ranges, r.GetData2())
+		}
+	}
+	return f
+}
+
+func (f *channelMessagesFilter) matchId(id int32) bool {
+	if len(f.ranges) == 0 {
+		return true
+	}
+	for _, r := range f.ranges {
+		if id >= r.GetMinId() && id <= r.GetMaxId() {
+			return true
+		}
+	}
+	return false
+}
+
+// 返回false时丢弃该update
+func (f *channelMessagesFilter) filterUpdate(u *mtproto.Update) bool {
+	switch u.GetConstructor() {
+	case mtproto.TLConstructor_CRC32_updateNewChannelMessage:
+		return !f.excludeNewMessages && f.matchId(u.GetData2().GetMessage_1().GetData2().GetId())
+	case mtproto.TLConstructor_CRC32_updateEditChannelMessage:
+		return f.matchId(u.GetData2().GetMessage_1().GetData2().GetId())
+	case mtproto.TLConstructor_CRC32_updateDeleteChannelMessages:
+		if len(f.ranges) == 0 {
+			return true
+		}
+		idList := make([]int32, 0, len(u.GetData2().GetMessages()))
+		for _, id := range u.GetData2().GetMessages() {
+			if f.matchId(id) {
+				idList = append(idList, id)
+			}
+		}
+		u.Data2.Messages = idList
+		return len(idList) > 0
+	}
+	return true
+}
+
+func makeChannelDifferenceEmpty(pts, timeout int32) *mtproto.Updates_ChannelDifference {
+	difference := &mtproto.TLUpdatesChannelDifferenceEmpty{Data2: &mtproto.Updates_ChannelDifference_Data{
+		Final:   true,
+		Pts:     pts,
+		Timeout: timeout,
+	}}
+	return difference.To_Updates_ChannelDifference()
+}
+
+// top_message、read_inbox_max_id、messages、chats和users由biz_server填充
+func makeChannelDifferenceTooLong(pts, timeout int32) *mtproto.Updates_ChannelDifference {
+	difference := &mtproto.TLUpdatesChannelDifferenceTooLong{Data2: &mtproto.Updates_ChannelDifference_Data{
+		Final:    true,
+		Pts:      pts,
+		Timeout:  timeout,
+		Messages: []*mtproto.Message{},
+		Chats:    []*mtproto.Chat{},
+		Users:    []*mtproto.User{},
+	}}
+	return difference.To_Updates_ChannelDifference()
+}
+
+// updates最多取limit+1个, 多出来的说明还有下一页(final为false)
+func makeChannelDifference(limit int, updates []*update.PtsUpdate, filter *channelMessagesFilter, currentPts, timeout int32) *mtproto.Updates_ChannelDifference {
+	final := len(updates) <= limit
+	if !final {
+		updates = updates[:limit]
+	}
+	if len(updates) == 0 {
+		return makeChannelDifferenceEmpty(currentPts, timeout)
+	}
+
+	var (
+		pts          = currentPts
+		newMessages  = make([]*mtproto.Message, 0, len(updates))
+		otherUpdates = make([]*mtproto.Update, 0, len(updates))
+	)
+
+	if !final {
+		// 客户端用返回的pts继续getChannelDifference
+		pts = updates[len(updates)-1].Pts
+	}
+
+	for _, u := range updates {
+		if !filter.filterUpdate(u.Update) {
+			continue
+		}
+		switch u.Update.GetConstructor() {
+		case mtproto.TLConstructor_CRC32_updateNewChannelMessage:
+			newMessages = append(newMessages, u.Update.GetData2().GetMessage_1())
+		default:
+			u.Update.Data2.PtsCount = 0
+			otherUpdates = append(otherUpdates, u.Update)
+		}
+	}
+
+	if final && len(newMessages) == 0 && len(otherUpdates) == 0 {
+		return makeChannelDifferenceEmpty(pts, timeout)
+	}
+
+	difference := &mtproto.TLUpdatesChannelDifference{Data2: &mtproto.Updates_ChannelDifference_Data{
+		Final:        final,
+		Pts:          pts,
+		Timeout:      timeout,
+		NewMessages:  newMessages,
+		OtherUpdates: otherUpdates,
+		Chats:        []*mtproto.Chat{},
+		Users:        []*mtproto.User{},
+	}}
+	return difference.To_Updates_ChannelDifference()
+}
@@ -0,0 +1,117 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/biz/core/update"
+)
+
+func newTestChannelUpdates(pts, n int32) []*update.PtsUpdate {
+	var updates []*update.PtsUpdate
+	for i := int32(1); i <= n; i++ {
+		var u *mtproto.Update
+		if i%2 == 1 {
+			message := mtproto.NewTLMessage()
+			message.SetId(pts + i)
+			u = (&mtproto.TLUpdateNewChannelMessage{Data2: &mtproto.Update_Data{
+				Message_1: message.To_Message(),
+				Pts:       pts + i,
+				PtsCount:  1,
+			}}).To_Update()
+		} else {
+			u = (&mtproto.TLUpdateDeleteChannelMessages{Data2: &mtproto.Update_Data{
+				Messages: []int32{pts + i - 1},
+				Pts:      pts + i,
+				PtsCount: 1,
+			}}).To_Update()
+		}
+		updates = append(updates, &update.PtsUpdate{Pts: pts + i, PtsCount: 1, Date: 1000 + i, Update: u})
+	}
+	return updates
+}
+
+func TestChannelDifferenceTooLong(t *testing.T) {
+	c := ChannelDifferenceConfig{
+		TooLongGap: 100,
+		Channels:   []ChannelTooLongGap{{ChannelId: 2, TooLongGap: 10}},
+	}
+	if c.isTooLong(1, 10, 20, 110, false) {
+		t.Fatal("gap 100 is not too long")
+	}
+	if !c.isTooLong(2, 10, 20, 21, false) {
+		t.Fatal("channel 2 gap 11 is too long")
+	}
+	if !c.isTooLong(1, 10, 20, 31, true) {
+		t.Fatal("force and gap > limit")
+	}
+	if c.limit(0) != kDefaultChannelDifferenceMaxLimit || c.limit(1000) != kDefaultChannelDifferenceMaxLimit || c.limit(10) != 10 {
+		t.Fatal("invalid limit")
+	}
+}
+
+func TestMakeChannelDifference(t *testing.T) {
+	filter := makeChannelMessagesFilter(mtproto.NewTLChannelMessagesFilterEmpty().To_ChannelMessagesFilter())
+
+	difference := makeChannelDifference(10, nil, filter, 20, 30)
+	if difference.GetConstructor() != mtproto.TLConstructor_CRC32_updates_channelDifferenceEmpty || difference.GetData2().GetPts() != 20 {
+		t.Fatalf("invalid empty difference: %v", difference)
+	}
+
+	difference = makeChannelDifference(10, newTestChannelUpdates(10, 4), filter, 14, 30)
+	data := difference.GetData2()
+	if difference.GetConstructor() != mtproto.TLConstructor_CRC32_updates_channelDifference || !data.GetFinal() || data.GetPts() != 14 {
+		t.Fatalf("invalid difference: %v", difference)
+	}
+	if len(data.GetNewMessages()) != 2 || len(data.GetOtherUpdates()) != 2 {
+		t.Fatalf("invalid difference: {new_messages: %d, other_updates: %d}", len(data.GetNewMessages()), len(data.GetOtherUpdates()))
+	}
+
+	// 还有下一页
+	difference = makeChannelDifference(3, newTestChannelUpdates(10, 4), filter, 14, 30)
+	data = difference.GetData2()
+	if data.GetFinal() || data.GetPts() != 13 || data.GetTimeout() != 30 {
+		t.Fatalf("invalid difference: %v", difference)
+	}
+}
+
+func TestChannelMessagesFilter(t *testing.T) {
+	r := mtproto.NewTLMessageRange()
+	r.SetMinId(11)
+	r.SetMaxId(12)
+	f := mtproto.NewTLChannelMessagesFilter()
+	f.SetRanges([]*mtproto.MessageRange{r.To_MessageRange()})
+	filter := makeChannelMessagesFilter(f.To_ChannelMessagesFilter())
+
+	// 11: new, 12: delete 11, 13: new, 14: delete 13
+	difference := makeChannelDifference(10, newTestChannelUpdates(10, 4), filter, 14, 30)
+	data := difference.GetData2()
+	if len(data.GetNewMessages()) != 1 || len(data.GetOtherUpdates()) != 1 {
+		t.Fatalf("invalid difference: {new_messages: %d, other_updates: %d}", len(data.GetNewMessages()), len(data.GetOtherUpdates()))
+	}
+
+	f.SetExcludeNewMessages(true)
+	filter = makeChannelMessagesFilter(f.To_ChannelMessagesFilter())
+	difference = makeChannelDifference(10, newTestChannelUpdates(10, 4), filter, 14, 30)
+	data = difference.GetData2()
+	if len(data.GetNewMessages()) != 0 || len(data.GetOtherUpdates()) != 1 {
+		t.Fatalf("invalid difference: {new_messages: %d, other_updates: %d}", len(data.GetNewMessages()), len(data.GetOtherUpdates()))
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/biz/core/update"
+)
+
+const (
+	kDefaultDifferenceSliceLimit = 500
+	kDefaultDifferenceTooLongGap = 5000
+)
+
+type DifferenceConfig struct {
+	SliceLimit int32 // 每次最多返回的update数, 超过时返回differenceSlice
+	TooLongGap int32 // 客户端pts落后超过该值时返回differenceTooLong
+}
+
+func (c DifferenceConfig) sliceLimit() int32 {
+	if c.SliceLimit <= 0 {
+		return kDefaultDifferenceSliceLimit
+	}
+	return c.SliceLimit
+}
+
+func (c DifferenceConfig) tooLongGap() int32 {
+	if c.TooLongGap <= 0 {
+		return kDefaultDifferenceTooLongGap
+	}
+	return c.TooLongGap
+}
+
+// pts_total_limit: 客户端要求落后超过该值时直接返回differenceTooLong
+func (c DifferenceConfig) isTooLong(pts, ptsTotalLimit, currentPts int32) bool {
+	gap := currentPts - pts
+	if ptsTotalLimit > 0 && gap > ptsTotalLimit {
+		return true
+	}
+	return gap > c.tooLongGap()
+}
+
+// 客户端getDifference只带date, 不带seq, 下次请求用date作为起点,
+// 同一秒的update不能拆到两次返回
+func trimSeqUpdates(seqUpdates []*update.SeqUpdate, limit int) ([]*update.SeqUpdate, bool) {
+	if len(seqUpdates) <= limit {
+		return seqUpdates, false
+	}
+
+	n := limit
+	for n > 0 && seqUpdates[n-1].Date == seqUpdates[limit].Date {
+		n--
+	}
+	if n == 0 {
+		// 第一秒的update就超过了limit, 这一秒整个返回, 调用方需要先取全这一秒(见isSecondOverflow)
+		for n < len(seqUpdates) && seqUpdates[n].Date == seqUpdates[0].Date {
+			n++
+		}
+	}
+	return seqUpdates[:n], true
+}
+
+// 第一秒的update超过limit, 按date分页取不全这一秒, 需要单独把这一秒的update全部取出来
+func isSecondOverflow(seqUpdates []*update.SeqUpdate, limit int) bool {
+	return len(seqUpdates) > limit && seqUpdates[0].Date == seqUpdates[limit].Date
+}
+
+type differenceState struct {
+	pts  int32
+	qts  int32
+	seq  int32
+	date int32
+}
+
+func (st *differenceState) toUpdatesState() *mtproto.Updates_State {
+	state := &mtproto.TLUpdatesState{Data2: &mtproto.Updates_State_Data{
+		Pts:         st.pts,
+		Qts:         st.qts,
+		Seq:         st.seq,
+		Date:        st.date,
+		UnreadCount: 0,
+	}}
+	return state.To_Updates_State()
+}
+
+// ptsUpdates和seqUpdates最多取limit+1个, 多出来的说明还有下一页
+// current为服务端当前状态, users和chats由biz_server填充
+func makeDifference(limit int, ptsUpdates []*update.PtsUpdate, seqUpdates []*update.SeqUpdate, current *differenceState) *mtproto.Updates_Difference {
+	ptsSlice := len(ptsUpdates) > limit
+	if ptsSlice {
+		ptsUpdates = ptsUpdates[:limit]
+	}
+	seqUpdates, seqSlice := trimSeqUpdates(seqUpdates, limit)
+
+	if len(ptsUpdates) == 0 && len(seqUpdates) == 0 {
+		difference := &mtproto.TLUpdatesDifferenceEmpty{Data2: &mtproto.Updates_Difference_Data{
+			Date: current.date,
+			Seq:  current.seq,
+		}}
+		return difference.To_Updates_Difference()
+	}
+
+	var (
+		newMessages  = make([]*mtproto.Message, 0, len(ptsUpdates))
+		otherUpdates = make([]*mtproto.Update, 0, len(ptsUpdates)+len(seqUpdates))
+		state        = *current
+	)
+
+	for _, u := range ptsUpdates {
+		switch u.Update.GetConstructor() {
+		case mtproto.TLConstructor_CRC32_updateNewMessage:
+			newMessages = append(newMessages, u.Update.To_UpdateNewMessage().GetMessage())
+		default:
+			// 客户端不再按pts_count检查
+			u.Update.Data2.PtsCount = 0
+			otherUpdates = append(otherUpdates, u.Update)
+		}
+		if u.Pts > state.pts {
+			state.pts = u.Pts
+		}
+	}
+	for _, u := range seqUpdates {
+		otherUpdates = append(otherUpdates, u.Update)
+	}
+
+	if !ptsSlice && !seqSlice {
+		difference := &mtproto.TLUpdatesDifference{Data2: &mtproto.Updates_Difference_Data{
+			NewMessages:          newMessages,
+			NewEncryptedMessages: []*mtproto.EncryptedMessage{},
+			OtherUpdates:         otherUpdates,
+			Users:                []*mtproto.User{},
+			Chats:                []*mtproto.Chat{},
+			State:                state.toUpdatesState(),
+		}}
+		return difference.To_Updates_Difference()
+	}
+
+	// 客户端用intermediate_state继续getDifference
+	if ptsSlice {
+		state.pts = ptsUpdates[len(ptsUpdates)-1].Pts
+	}
+	if seqSlice {
+		last := seqUpdates[len(seqUpdates)-1]
+		state.seq = last.Seq
+		state.date = last.Date
+	}
+	difference := &mtproto.TLUpdatesDifferenceSlice{Data2: &mtproto.Updates_Difference_Data{
+		NewMessages:          newMessages,
+		NewEncryptedMessages: []*mtproto.EncryptedMessage{},
+		OtherUpdates:         otherUpdates,
+		Users:                []*mtproto.User{},
+		Chats:                []*mtproto.Chat{},
+		IntermediateState:    state.toUpdatesState(),
+	}}
+	return difference.To_Updates_Difference()
+}
@@ -0,0 +1,136 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"github.com/nebulaim/telegramd/server/sync/biz/core/update"
+)
+
+func newTestPtsUpdates(pts, n int32) []*update.PtsUpdate {
+	var updates []*update.PtsUpdate
+	for i := int32(1); i <= n; i++ {
+		var u *mtproto.Update
+		if i%2 == 0 {
+			u = (&mtproto.TLUpdateNewMessage{Data2: &mtproto.Update_Data{
+				Message_1: mtproto.NewTLMessage().To_Message(),
+				Pts:       pts + i,
+				PtsCount:  1,
+			}}).To_Update()
+		} else {
+			u = (&mtproto.TLUpdateDeleteMessages{Data2: &mtproto.Update_Data{
+				Messages: []int32{i},
+				Pts:      pts + i,
+				PtsCount: 1,
+			}}).To_Update()
+		}
+		updates = append(updates, &update.PtsUpdate{Pts: pts + i, PtsCount: 1, Date: 1000 + i, Update: u})
+	}
+	return updates
+}
+
+func newTestSeqUpdates(dates ...int32) []*update.SeqUpdate {
+	var updates []*update.SeqUpdate
+	for i, date := range dates {
+		u := (&mtproto.TLUpdateUserName{Data2: &mtproto.Update_Data{UserId: int32(i + 1)}}).To_Update()
+		updates = append(updates, &update.SeqUpdate{Seq: int32(i + 1), Date: date, Update: u})
+	}
+	return updates
+}
+
+func TestMakeDifference(t *testing.T) {
+	current := &differenceState{pts: 14, qts: 0, seq: 3, date: 2000}
+
+	difference := makeDifference(10, nil, nil, current)
+	if difference.GetConstructor() != mtproto.TLConstructor_CRC32_updates_differenceEmpty || difference.GetData2().GetSeq() != 3 {
+		t.Fatalf("invalid empty difference: %v", difference)
+	}
+
+	difference = makeDifference(10, newTestPtsUpdates(10, 4), newTestSeqUpdates(1500, 1600, 1700), current)
+	if difference.GetConstructor() != mtproto.TLConstructor_CRC32_updates_difference {
+		t.Fatalf("invalid difference: %v", difference)
+	}
+	data := difference.GetData2()
+	if len(data.GetNewMessages()) != 2 || len(data.GetOtherUpdates()) != 5 {
+		t.Fatalf("invalid difference: {new_messages: %d, other_updates: %d}", len(data.GetNewMessages()), len(data.GetOtherUpdates()))
+	}
+	state := data.GetState().GetData2()
+	if state.GetPts() != 14 || state.GetSeq() != 3 || state.GetDate() != 2000 {
+		t.Fatalf("invalid state: %v", state)
+	}
+}
+
+func TestMakeDifferenceSlice(t *testing.T) {
+	current := &differenceState{pts: 30, qts: 0, seq: 5, date: 2000}
+
+	// pts分页
+	difference := makeDifference(10, newTestPtsUpdates(10, 11), nil, current)
+	if difference.GetConstructor() != mtproto.TLConstructor_CRC32_updates_differenceSlice {
+		t.Fatalf("invalid difference: %v", difference)
+	}
+	state := difference.GetData2().GetIntermediateState().GetData2()
+	if state.GetPts() != 20 || state.GetDate() != 2000 || state.GetSeq() != 5 {
+		t.Fatalf("invalid intermediate state: %v", state)
+	}
+
+	// 同一秒的seq update不能拆开
+	difference = makeDifference(3, nil, newTestSeqUpdates(1500, 1600, 1600, 1600), current)
+	if difference.GetConstructor() != mtproto.TLConstructor_CRC32_updates_differenceSlice {
+		t.Fatalf("invalid difference: %v", difference)
+	}
+	data := difference.GetData2()
+	state = data.GetIntermediateState().GetData2()
+	if len(data.GetOtherUpdates()) != 1 || state.GetDate() != 1500 || state.GetSeq() != 1 || state.GetPts() != 30 {
+		t.Fatalf("invalid intermediate state: {other_updates: %d, state: %v}", len(data.GetOtherUpdates()), state)
+	}
+}
+
+func TestMakeDifferenceSecondOverflow(t *testing.T) {
+	current := &differenceState{pts: 30, qts: 0, seq: 6, date: 2000}
+
+	// 1500这一秒有5个update, 超过limit, 必须整秒返回, 不能丢
+	seqUpdates := newTestSeqUpdates(1500, 1500, 1500, 1500, 1500, 1600)
+	if !isSecondOverflow(seqUpdates[:4], 3) || isSecondOverflow(newTestSeqUpdates(1500, 1600, 1600, 1600), 3) {
+		t.Fatal("invalid isSecondOverflow")
+	}
+
+	difference := makeDifference(3, nil, seqUpdates, current)
+	if difference.GetConstructor() != mtproto.TLConstructor_CRC32_updates_differenceSlice {
+		t.Fatalf("invalid difference: %v", difference)
+	}
+	data := difference.GetData2()
+	state := data.GetIntermediateState().GetData2()
+	if len(data.GetOtherUpdates()) != 5 || state.GetDate() != 1500 || state.GetSeq() != 5 {
+		t.Fatalf("invalid intermediate state: {other_updates: %d, state: %v}", len(data.GetOtherUpdates()), state)
+	}
+}
+
+func TestDifferenceTooLong(t *testing.T) {
+	c := DifferenceConfig{TooLongGap: 100}
+	if c.isTooLong(10, 0, 110) {
+		t.Fatal("gap 100 is not too long")
+	}
+	if !c.isTooLong(10, 0, 111) {
+		t.Fatal("gap 101 is too long")
+	}
+	if !c.isTooLong(10, 50, 61) {
+		t.Fatal("pts_total_limit exceeded")
+	}
+}
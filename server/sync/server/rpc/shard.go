@@ -0,0 +1,144 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nebulaim/telegramd/baselib/base"
+	"github.com/nebulaim/telegramd/baselib/grpc_util/load_balancer"
+)
+
+// sync节点按user_id分片:
+// biz_server的sync_client用consistent_hash把同一个用户的请求路由到同一个sync节点,
+// 节点增减时部分用户会迁到新节点, 旧节点队列里可能还有没推完的update,
+// 新节点在handoffDelay内暂缓推送迁入用户的update, 保证推给session的pts顺序
+const (
+	kDefaultHandoffDelay = 2 * time.Second
+)
+
+type ShardConfig struct {
+	HandoffDelay base.Duration
+}
+
+type ShardRing struct {
+	mu           sync.RWMutex
+	self         string
+	nodes        []string
+	ring         *load_balancer.KetamaRing
+	prevRing     *load_balancer.KetamaRing
+	changedAt    time.Time
+	handoffDelay time.Duration
+}
+
+// self为注册到etcd的地址
+func NewShardRing(self string, c ShardConfig) *ShardRing {
+	r := &ShardRing{
+		self:         self,
+		handoffDelay: time.Duration(c.HandoffDelay),
+	}
+	if r.handoffDelay <= 0 {
+		r.handoffDelay = kDefaultHandoffDelay
+	}
+	return r
+}
+
+func shardKey(userId int32) string {
+	return base.Int32ToString(userId)
+}
+
+// 节点列表有变化时返回true
+func (r *ShardRing) SetNodes(nodes []string) bool {
+	nodes = append([]string{}, nodes...)
+	sort.Strings(nodes)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if equalNodes(r.nodes, nodes) {
+		return false
+	}
+	if r.ring == nil {
+		// 刚启动: 加入前的节点列表里没有自己
+		others := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			if node != r.self {
+				others = append(others, node)
+			}
+		}
+		r.prevRing = load_balancer.NewKetamaRing(others...)
+	} else {
+		// 两次变化间隔小于handoffDelay时, 只处理最近一次迁入的用户
+		r.prevRing = r.ring
+	}
+	r.ring = load_balancer.NewKetamaRing(nodes...)
+	r.nodes = nodes
+	r.changedAt = time.Now()
+	return true
+}
+
+func (r *ShardRing) owner(ring *load_balancer.KetamaRing, userId int32) string {
+	if ring == nil {
+		return r.self
+	}
+	addr, ok := ring.Get(shardKey(userId))
+	if !ok {
+		// 还没有拿到节点列表时认为都归自己
+		return r.self
+	}
+	return addr
+}
+
+func (r *ShardRing) IsOwner(userId int32) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.owner(r.ring, userId) == r.self
+}
+
+// 刚迁入本节点的用户返回暂缓推送的截止时间, 否则返回零值
+func (r *ShardRing) handoffDeadline(userId int32, now time.Time) time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.prevRing == nil {
+		return time.Time{}
+	}
+	deadline := r.changedAt.Add(r.handoffDelay)
+	if !now.Before(deadline) {
+		return time.Time{}
+	}
+	if r.owner(r.ring, userId) != r.self || r.owner(r.prevRing, userId) == r.self {
+		return time.Time{}
+	}
+	return deadline
+}
+
+func equalNodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nebulaim/telegramd/baselib/base"
+)
+
+func TestShardRingHandoff(t *testing.T) {
+	const (
+		a = "127.0.0.1:10002"
+		b = "127.0.0.1:10012"
+	)
+
+	r := NewShardRing(b, ShardConfig{HandoffDelay: base.Duration(time.Second)})
+	if !r.IsOwner(1) {
+		t.Fatal("empty ring should own all users")
+	}
+
+	r.SetNodes([]string{a})
+	r.SetNodes([]string{a, b})
+	if r.SetNodes([]string{b, a}) {
+		t.Fatal("same nodes should not change the ring")
+	}
+
+	now := time.Now()
+	var moved, stayed int
+	for userId := int32(1); userId <= 1000; userId++ {
+		deadline := r.handoffDeadline(userId, now)
+		if r.IsOwner(userId) {
+			// 加入前所有用户都在a上
+			if deadline.IsZero() {
+				t.Fatalf("user %d moved to b without handoff delay", userId)
+			}
+			moved++
+		} else {
+			if !deadline.IsZero() {
+				t.Fatalf("user %d not owned by b but delayed", userId)
+			}
+			stayed++
+		}
+		if !r.handoffDeadline(userId, now.Add(2*time.Second)).IsZero() {
+			t.Fatalf("user %d still delayed after handoffDelay", userId)
+		}
+	}
+	if moved == 0 || stayed == 0 {
+		t.Fatalf("bad distribution: {moved: %d, stayed: %d}", moved, stayed)
+	}
+}
@@ -18,20 +18,40 @@
 package rpc
 
 import (
-    "github.com/golang/glog"
-    "github.com/nebulaim/telegramd/proto/mtproto"
-    "golang.org/x/net/context"
-    "fmt"
-    "github.com/nebulaim/telegramd/baselib/grpc_util"
-    "github.com/nebulaim/telegramd/baselib/logger"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/logger"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"golang.org/x/net/context"
 )
 
 // sync.getChannelDifference flags:# auth_key_id:long user_id:int force:flags.0?true channel:InputChannel filter:ChannelMessagesFilter pts:int limit:int = updates.ChannelDifference;
 func (s *SyncServiceImpl) SyncGetChannelDifference(ctx context.Context, request *mtproto.TLSyncGetChannelDifference) (*mtproto.Updates_ChannelDifference, error) {
-    md := grpc_util.RpcMetadataFromIncoming(ctx)
-    glog.Infof("SyncGetChannelDifference - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
+	glog.Infof("sync.getChannelDifference - request: %s", logger.JsonDebugData(request))
+
+	if request.GetChannel().GetConstructor() != mtproto.TLConstructor_CRC32_inputChannel {
+		err := fmt.Errorf("sync.getChannelDifference - invalid channel: %v", request.GetChannel())
+		glog.Error(err)
+		return nil, err
+	}
+
+	var (
+		channelId  = request.GetChannel().GetData2().GetChannelId()
+		currentPts = int32(s.CurrentChannelPtsId(channelId))
+		limit      = s.channelDifference.limit(request.GetLimit())
+		timeout    = s.channelDifference.timeout()
+		difference *mtproto.Updates_ChannelDifference
+	)
 
-    // TODO(@benqi): Impl SyncGetChannelDifference logic
+	// 小于等于minPts的update已被清理
+	if request.GetPts() < s.GetChannelPtsFloor(channelId) || s.channelDifference.isTooLong(channelId, request.GetPts(), limit, currentPts, request.GetForce()) {
+		difference = makeChannelDifferenceTooLong(currentPts, timeout)
+	} else {
+		updates := s.GetChannelPtsUpdateListByGtPts(channelId, request.GetPts(), limit+1)
+		difference = makeChannelDifference(int(limit), updates, makeChannelMessagesFilter(request.GetFilter()), currentPts, timeout)
+	}
 
-    return nil, fmt.Errorf("Not impl SyncGetChannelDifference")
+	glog.Infof("sync.getChannelDifference - reply: %s", logger.JsonDebugData(difference))
+	return difference, nil
 }
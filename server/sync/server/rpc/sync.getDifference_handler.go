@@ -18,77 +18,47 @@
 package rpc
 
 import (
-    "github.com/golang/glog"
-    "github.com/nebulaim/telegramd/proto/mtproto"
-    "golang.org/x/net/context"
-    "github.com/nebulaim/telegramd/baselib/logger"
-    "time"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/logger"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"golang.org/x/net/context"
+	"time"
 )
 
 // sync.getDifference flags:# auth_key_id:long user_id:int pts:int pts_total_limit:flags.0?int date:int qts:int = updates.Difference;
 func (s *SyncServiceImpl) SyncGetDifference(ctx context.Context, request *mtproto.TLSyncGetDifference) (*mtproto.Updates_Difference, error) {
-    glog.Infof("sync.getDifference - request: %s", logger.JsonDebugData(request))
+	glog.Infof("sync.getDifference - request: %s", logger.JsonDebugData(request))
 
-    var (
-        lastPts      = request.GetPts()
-        otherUpdates []*mtproto.Update
-        messages     []*mtproto.Message
-        userList     []*mtproto.User
-        chatList     []*mtproto.Chat
-        difference   *mtproto.Updates_Difference
-    )
+	var (
+		userId     = request.GetUserId()
+		difference *mtproto.Updates_Difference
+		current    = &differenceState{
+			pts:  int32(s.CurrentPtsId(userId)),
+			qts:  request.GetQts(),
+			seq:  int32(s.CurrentSeqId(userId)),
+			date: int32(time.Now().Unix()),
+		}
+	)
 
-    updateList := s.UpdateModel.GetUpdateListByGtPts(request.GetUserId(), request.GetPts())
-    if len(updateList) == 0 {
-        difference2 := &mtproto.TLUpdatesDifferenceEmpty{Data2: &mtproto.Updates_Difference_Data{
-            Date: int32(time.Now().Unix()),
-            Seq:  0,
-        }}
-        difference = difference2.To_Updates_Difference()
-    } else {
-        for _, update := range updateList {
-            switch update.GetConstructor() {
-            case mtproto.TLConstructor_CRC32_updateNewMessage:
-                newMessage := update.To_UpdateNewMessage()
-                messages = append(messages, newMessage.GetMessage())
-                // otherUpdates = append(otherUpdates, update)
+	// 早于minPts、minDate的update已被清理
+	minPts, minDate := s.GetUserUpdateFloor(userId)
+	if request.GetPts() < minPts || request.GetDate() < minDate || s.difference.isTooLong(request.GetPts(), request.GetPtsTotalLimit(), current.pts) {
+		// 客户端需要重新拉取对话列表
+		differenceTooLong := &mtproto.TLUpdatesDifferenceTooLong{Data2: &mtproto.Updates_Difference_Data{
+			Pts: current.pts,
+		}}
+		difference = differenceTooLong.To_Updates_Difference()
+	} else {
+		limit := s.difference.sliceLimit()
+		ptsUpdates := s.GetPtsUpdateListByGtPts(userId, request.GetPts(), limit+1)
+		seqUpdates := s.GetSeqUpdateListByGtDate(userId, request.GetDate(), limit+1)
+		if isSecondOverflow(seqUpdates, int(limit)) {
+			glog.Warningf("sync.getDifference - too many seq updates in one second: {user_id: %d, date: %d}", userId, seqUpdates[0].Date)
+			seqUpdates = s.GetSeqUpdateListByDate(userId, seqUpdates[0].Date)
+		}
+		difference = makeDifference(int(limit), ptsUpdates, seqUpdates, current)
+	}
 
-            case mtproto.TLConstructor_CRC32_updateReadHistoryOutbox:
-                readHistoryOutbox := update.To_UpdateReadHistoryOutbox()
-                readHistoryOutbox.SetPtsCount(0)
-                otherUpdates = append(otherUpdates, readHistoryOutbox.To_Update())
-            case mtproto.TLConstructor_CRC32_updateReadHistoryInbox:
-                readHistoryInbox := update.To_UpdateReadHistoryInbox()
-                readHistoryInbox.SetPtsCount(0)
-                otherUpdates = append(otherUpdates, readHistoryInbox.To_Update())
-            default:
-                continue
-            }
-            if update.Data2.GetPts() > lastPts {
-                lastPts = update.Data2.GetPts()
-            }
-        }
-        if lastPts <= request.GetPts() {
-            lastPts = 0
-        }
-        state := &mtproto.TLUpdatesState{Data2: &mtproto.Updates_State_Data{
-            Pts:         lastPts,
-            Date:        int32(time.Now().Unix()),
-            UnreadCount: 0,
-            // Seq:         int32(model.GetSequenceModel().CurrentSeqId(base2.Int32ToString(md.UserId))),
-            Seq: 0,
-        }}
-
-        difference2 := &mtproto.TLUpdatesDifference{Data2: &mtproto.Updates_Difference_Data{
-            NewMessages:  messages,
-            OtherUpdates: otherUpdates,
-            Users:        userList,
-            Chats:        chatList,
-            State:        state.To_Updates_State(),
-        }}
-        difference = difference2.To_Updates_Difference()
-    }
-
-    glog.Infof("sync.getDifference - reply: %s", logger.JsonDebugData(difference))
-    return difference, nil
+	glog.Infof("sync.getDifference - reply: %s", logger.JsonDebugData(difference))
+	return difference, nil
 }
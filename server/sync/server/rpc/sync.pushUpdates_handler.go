@@ -28,7 +28,7 @@ import (
 func (s *SyncServiceImpl) SyncPushUpdates(ctx context.Context, request *mtproto.TLSyncPushUpdates) (*mtproto.Bool, error) {
     glog.Infof("sync.pushUpdates#5c612649 - request: {%s}", logger.JsonDebugData(request))
 
-    err := s.processUpdatesRequest(request.GetUserId(), request.GetUpdates())
+    err := s.processUpdatesRequest(0, request.GetUserId(), request.GetUpdates())
     if err == nil {
         pushData := &mtproto.PushData{
             Constructor: mtproto.TLConstructor_CRC32_sync_pushUpdatesData,
@@ -28,7 +28,7 @@ import (
 func (s *SyncServiceImpl) SyncSyncUpdates(ctx context.Context, request *mtproto.TLSyncSyncUpdates) (*mtproto.Bool, error) {
     glog.Infof("sync.syncUpdates#3a077679 - request: {%s}", logger.JsonDebugData(request))
 
-    err := s.processUpdatesRequest(request.GetUserId(), request.GetUpdates())
+    err := s.processUpdatesRequest(request.GetAuthKeyId(), request.GetUserId(), request.GetUpdates())
     if err == nil {
 		pushData := &mtproto.PushData{
 			Constructor: mtproto.TLConstructor_CRC32_sync_pushUpdatesData,
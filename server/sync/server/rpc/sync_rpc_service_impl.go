@@ -26,6 +26,7 @@ import (
 	"sync"
 	"github.com/gogo/protobuf/proto"
 	"github.com/nebulaim/telegramd/service/status/client"
+	"time"
 )
 
 /*
@@ -67,16 +68,22 @@ type SyncServiceImpl struct {
 	pushCB    	PushDataCallback
 	status     	status_client.StatusClient
 	closeChan 	chan int
-	pushChan 	chan struct {int; *mtproto.PushData}
+	pushChan 	chan *pushItem
+	difference	DifferenceConfig
+	channelDifference	ChannelDifferenceConfig
+	shard		*ShardRing
 	*update.UpdateModel
 }
 
-func NewSyncService(pushCB PushDataCallback, status status_client.StatusClient, updateModel *update.UpdateModel) *SyncServiceImpl {
+func NewSyncService(pushCB PushDataCallback, status status_client.StatusClient, updateModel *update.UpdateModel, difference DifferenceConfig, channelDifference ChannelDifferenceConfig, shard *ShardRing) *SyncServiceImpl {
 	s := &SyncServiceImpl{
 		pushCB:      pushCB,
 		status:      status,
 		closeChan:   make(chan int),
-		pushChan:    make(chan struct {int; *mtproto.PushData}, 1024),
+		pushChan:    make(chan *pushItem, 1024),
+		difference:  difference,
+		channelDifference: channelDifference,
+		shard:       shard,
 		UpdateModel: updateModel,
 	}
 
@@ -85,17 +92,50 @@ func NewSyncService(pushCB PushDataCallback, status status_client.StatusClient,
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////////////////
+type pushItem struct {
+	userId   int32
+	serverId int
+	pushData *mtproto.PushData
+	deadline time.Time // 不为零时等到deadline再推送, 见ShardRing
+}
+
 func (s *SyncServiceImpl) pushUpdatesLoop() {
+	ticker := time.NewTicker(100 * time.Millisecond)
 	defer func() {
+		ticker.Stop()
 		close(s.pushChan)
 	}()
 
+	// 迁入用户暂缓推送的update, deadline不会变小, 按到达顺序推送
+	var delayed []*pushItem
+	flush := func(now time.Time) {
+		n := 0
+		for ; n < len(delayed) && !now.Before(delayed[n].deadline); n++ {
+			s.pushCB.SendToSessionServer(delayed[n].serverId, delayed[n].pushData)
+		}
+		delayed = delayed[n:]
+	}
+
 	for {
 		select {
-		case pair, ok := <-s.pushChan:
+		case item, ok := <-s.pushChan:
 			if ok {
-				s.pushCB.SendToSessionServer(pair.int, pair.PushData)
+				now := time.Now()
+				flush(now)
+				// 同一个用户还有暂缓的update时排在后面
+				for _, d := range delayed {
+					if d.userId == item.userId && d.deadline.After(item.deadline) {
+						item.deadline = d.deadline
+					}
+				}
+				if now.Before(item.deadline) {
+					delayed = append(delayed, item)
+				} else {
+					s.pushCB.SendToSessionServer(item.serverId, item.pushData)
+				}
 			}
+		case now := <-ticker.C:
+			flush(now)
 		case <-s.closeChan:
 			return
 		}
@@ -174,7 +214,35 @@ func updateShortChatToUpdateNewMessage(userId int32, shortMessage *mtproto.TLUpd
 	return updateNew.To_Update()
 }
 
-func (s *SyncServiceImpl) processUpdatesRequest(userId int32, ups *mtproto.Updates) error {
+// 需要在getDifference里补发的非pts update, 状态类(在线状态、正在输入等)的不保存
+func isSeqUpdate(update *mtproto.Update) bool {
+	switch update.GetConstructor() {
+	case mtproto.TLConstructor_CRC32_updateUserName,
+		mtproto.TLConstructor_CRC32_updateUserPhoto,
+		mtproto.TLConstructor_CRC32_updateUserPhone,
+		mtproto.TLConstructor_CRC32_updateUserBlocked,
+		mtproto.TLConstructor_CRC32_updateContactRegistered,
+		mtproto.TLConstructor_CRC32_updateContactLink,
+		mtproto.TLConstructor_CRC32_updateChatParticipants,
+		mtproto.TLConstructor_CRC32_updateChatParticipantAdd,
+		mtproto.TLConstructor_CRC32_updateChatParticipantDelete,
+		mtproto.TLConstructor_CRC32_updateChatAdmins,
+		mtproto.TLConstructor_CRC32_updateChatParticipantAdmin,
+		mtproto.TLConstructor_CRC32_updateNotifySettings,
+		mtproto.TLConstructor_CRC32_updatePrivacy,
+		mtproto.TLConstructor_CRC32_updateDraftMessage,
+		mtproto.TLConstructor_CRC32_updateDialogPinned,
+		mtproto.TLConstructor_CRC32_updatePinnedDialogs,
+		mtproto.TLConstructor_CRC32_updateDialogUnreadMark,
+		mtproto.TLConstructor_CRC32_updateStickerSets,
+		mtproto.TLConstructor_CRC32_updateSavedGifs,
+		mtproto.TLConstructor_CRC32_updateReadFeaturedStickers:
+		return true
+	}
+	return false
+}
+
+func (s *SyncServiceImpl) processUpdatesRequest(authKeyId int64, userId int32, ups *mtproto.Updates) error {
 	switch ups.GetConstructor() {
 	case mtproto.TLConstructor_CRC32_updateShortMessage:
 		shortMessage := ups.To_UpdateShortMessage()
@@ -185,6 +253,9 @@ func (s *SyncServiceImpl) processUpdatesRequest(userId int32, ups *mtproto.Updat
 	case mtproto.TLConstructor_CRC32_updateShort:
 		//short := updates.To_UpdateShort()
 		//short.SetDate(date)
+		if update2 := ups.To_UpdateShort().GetUpdate(); isSeqUpdate(update2) {
+			s.UpdateModel.AddToSeqQueue(authKeyId, userId, update2)
+		}
 	case mtproto.TLConstructor_CRC32_updates:
 		updates2 := ups.To_Updates()
 		// totalPtsCount := int32(0)
@@ -224,6 +295,11 @@ func (s *SyncServiceImpl) processUpdatesRequest(userId int32, ups *mtproto.Updat
 				//	update.Data2.PtsCount = ptsCount
 				//	s.UpdateModel.AddToChannelPtsQueue(channelMessage.GetData2().GetToId().GetData2().GetChannelId(), pts, ptsCount, update)
 				//}
+			default:
+				// 没有pts的update, getDifference时按date补发
+				if isSeqUpdate(update) {
+					s.UpdateModel.AddToSeqQueue(authKeyId, userId, update)
+				}
 			}
 		}
 
@@ -271,10 +347,19 @@ func (s *SyncServiceImpl) processChannelUpdatesRequest(channelId int32, ups *mtp
 }
 
 func (s *SyncServiceImpl) pushUpdatesToSession(syncType SyncType, userId int32, pushData *mtproto.PushData, hasServerId int32) {
+	var deadline time.Time
+	if syncType != syncTypeRpcResult && s.shard != nil {
+		if !s.shard.IsOwner(userId) {
+			// sync_client的节点列表还没更新, 照常处理
+			glog.Warningf("pushUpdatesToSession - user(%d) not owned by this node", userId)
+		}
+		deadline = s.shard.handoffDeadline(userId, time.Now())
+	}
+
 	if (syncType == syncTypeUserMe || syncType == syncTypeRpcResult) && hasServerId > 0 {
 		glog.Infof("pushUpdatesToSession - phshData: {server_id: %d, auth_key_id: %d}", hasServerId, pushData.Data2.GetAuthKeyId())
 		// s.s.sendToSessionServer(int(hasServerId), pushData)
-		s.pushChan <- struct {int; *mtproto.PushData}{int(hasServerId), pushData}
+		s.pushChan <- &pushItem{userId: userId, serverId: int(hasServerId), pushData: pushData, deadline: deadline}
 	} else {
 		statusList, _ := s.status.GetUserOnlineSessions(userId)
 		ss := make(map[int32][]*status.SessionEntry)
@@ -295,7 +380,7 @@ func (s *SyncServiceImpl) pushUpdatesToSession(syncType SyncType, userId int32,
 				pushData2, _ := proto.Clone(pushData).(*mtproto.PushData)
 				pushData2.Data2.AuthKeyId = ss4.AuthKeyId
 				glog.Infof("pushUpdatesToSession - pushData: {server_id: %d, auth_key_id: %d}", k, pushData2.Data2.GetAuthKeyId())
-				s.pushChan <- struct {int; *mtproto.PushData}{int(k), pushData2}
+				s.pushChan <- &pushItem{userId: userId, serverId: int(k), pushData: pushData2, deadline: deadline}
 			}
 		}
 	}
@@ -0,0 +1,77 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"sort"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
+	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery/etcd3"
+	"github.com/nebulaim/telegramd/server/sync/server/rpc"
+	"google.golang.org/grpc/naming"
+)
+
+// 监听etcd里注册的sync节点, 节点变化时更新ShardRing
+type shardWatcher struct {
+	w     naming.Watcher
+	shard *rpc.ShardRing
+	nodes map[string]bool
+}
+
+func newShardWatcher(discovery *service_discovery.ServiceDiscoveryServerConfig, shard *rpc.ShardRing) (*shardWatcher, error) {
+	r := etcd3.NewResolver("/nebulaim", discovery.ServiceName, clientv3.Config{Endpoints: discovery.EtcdAddrs})
+	w, err := r.Resolve("")
+	if err != nil {
+		return nil, err
+	}
+	return &shardWatcher{w: w, shard: shard, nodes: make(map[string]bool)}, nil
+}
+
+func (w *shardWatcher) watch() {
+	for {
+		updates, err := w.w.Next()
+		if err != nil {
+			glog.Info("shardWatcher - stop: ", err)
+			return
+		}
+
+		for _, u := range updates {
+			switch u.Op {
+			case naming.Add:
+				w.nodes[u.Addr] = true
+			case naming.Delete:
+				delete(w.nodes, u.Addr)
+			}
+		}
+
+		addrs := make([]string, 0, len(w.nodes))
+		for addr := range w.nodes {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		if w.shard.SetNodes(addrs) {
+			glog.Infof("shardWatcher - sync nodes changed: %v", addrs)
+		}
+	}
+}
+
+func (w *shardWatcher) stop() {
+	w.w.Close()
+}
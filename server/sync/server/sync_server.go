@@ -25,11 +25,13 @@ import (
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/mysql_client"
 	"github.com/nebulaim/telegramd/baselib/net2"
+	"github.com/nebulaim/telegramd/baselib/nsq_client"
 	"github.com/nebulaim/telegramd/baselib/redis_client"
 	"github.com/nebulaim/telegramd/biz/dal/dao"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/proto/zproto"
 	"github.com/nebulaim/telegramd/server/sync/biz/core/update"
+	"github.com/nebulaim/telegramd/server/sync/sync_client"
 	"github.com/nebulaim/telegramd/service/idgen/client"
 	"github.com/nebulaim/telegramd/service/status/client"
 	"google.golang.org/grpc"
@@ -53,13 +55,17 @@ type connContext struct {
 }
 
 type syncServer struct {
-	idgen idgen.UUIDGen
-	// update     *update.UpdateModel
+	idgen      idgen.UUIDGen
+	update     *update.UpdateModel
 	status     status_client.StatusClient
 	client     *zproto.ZProtoClient
 	server     *grpc_util.RPCServer
 	impl       *rpc.SyncServiceImpl
+	shard      *rpc.ShardRing
+	watcher    *shardWatcher
+	queue      nsq_client.Producer
 	sessionMap sync.Map
+	closeChan  chan struct{}
 }
 
 func NewSyncServer() *syncServer {
@@ -91,28 +97,56 @@ func (s *syncServer) Initialize() error {
 
 	s.status, _ = status_client.NewStatusClient("redis", "cache")
 
+	s.update = update.NewUpdateModel(Conf.ServerId, "immaster", "cache")
+	s.closeChan = make(chan struct{})
+
 	s.server = grpc_util.NewRpcServer(Conf.Server.Addr, &Conf.Server.RpcDiscovery)
+
+	// 按user_id分片, 节点地址为注册到etcd的rPCAddr
+	s.shard = rpc.NewShardRing(Conf.Server.RpcDiscovery.RPCAddr, Conf.Shard)
+	s.watcher, err = newShardWatcher(&Conf.Server.RpcDiscovery, s.shard)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
 	s.client = zproto.NewZProtoClient("zproto", Conf.SessionClient, s)
 
+	if Conf.PushQueue != nil {
+		s.queue, err = nsq_client.NewNsqProducer(Conf.PushQueue)
+		if err != nil {
+			glog.Fatal(err)
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (s *syncServer) RunLoop() {
+	go s.watcher.watch()
+	if Conf.Compaction.Enable {
+		go compactUpdatesLoop(s.update, Conf.Compaction, s.closeChan)
+	}
 	go s.server.Serve(func(s2 *grpc.Server) {
-		updateModel := update.NewUpdateModel(Conf.ServerId, "immaster", "cache")
-		s.impl = rpc.NewSyncService(s, s.status, updateModel)
+		s.impl = rpc.NewSyncService(s, s.status, s.update, Conf.Difference, Conf.ChannelDifference, s.shard)
 		mtproto.RegisterRPCSyncServer(s2, s.impl)
 	})
 	s.client.Serve()
 }
 
 func (s *syncServer) Destroy() {
+	close(s.closeChan)
 	if s.impl != nil {
 		s.impl.Destroy()
 	}
 
 	s.server.Stop()
 	s.client.Stop()
+	s.watcher.stop()
+	if s.queue != nil {
+		s.queue.Stop()
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -142,7 +176,7 @@ func protoToSyncData(m proto.Message) (*zproto.ZProtoSyncData, error) {
 func (s *syncServer) OnNewClient(client *net2.TcpClient) {
 	glog.Infof("OnNewConnection")
 	connectToSession := &mtproto.TLSyncConnectToSessionServer{Data2: &mtproto.ConnectToServer_Data{
-		SyncServerId: 1,
+		SyncServerId: Conf.ServerId,
 	}}
 
 	req, _ := protoToSyncData(connectToSession)
@@ -211,6 +245,16 @@ func (s *syncServer) OnClientTimer(client *net2.TcpClient) {
 }
 
 func (s *syncServer) SendToSessionServer(serverId int, m proto.Message) {
+	if s.queue != nil {
+		// session服务器不在线时消息留在队列里, 上线后再消费
+		message, _ := protoToSyncData(m)
+		err := s.queue.Publish(sync_client.SessionPushTopic(int32(serverId)), message.SyncRawData)
+		if err == nil {
+			return
+		}
+		glog.Errorf("publish to session server %d error: %v, push directly", serverId, err)
+	}
+
 	if c, ok := s.sessionMap.Load(int32(serverId)); ok {
 		client := c.(*net2.TcpClient)
 		if client != nil {
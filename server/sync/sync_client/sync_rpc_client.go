@@ -20,10 +20,11 @@ package sync_client
 import (
 	"context"
 	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/base"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
+	"github.com/nebulaim/telegramd/baselib/grpc_util/load_balancer"
 	"github.com/nebulaim/telegramd/baselib/grpc_util/service_discovery"
 	"github.com/nebulaim/telegramd/proto/mtproto"
-	"time"
 )
 
 type syncClient struct {
@@ -49,6 +50,16 @@ func InstallSyncClient(discovery *service_discovery.ServiceDiscoveryClientConfig
 	syncInstance.client = mtproto.NewRPCSyncClient(conn)
 }
 
+// 通过队列推送时每个session服务器一个topic
+func SessionPushTopic(serverId int32) string {
+	return "session_push_" + base.Int32ToString(serverId)
+}
+
+// sync节点按user_id分片, 同一个用户的请求用consistent_hash路由到同一个节点, 保证pts顺序
+func userContext(userId int32) context.Context {
+	return context.WithValue(context.Background(), load_balancer.DefaultKetamaKey, base.Int32ToString(userId))
+}
+
 // sync.syncUpdates#3a077679 flags:# layer:int user_id:int auth_key_id:long server_id:flags.1?int not_me:flags.0?true updates:Updates = Bool;
 func (c *syncClient) SyncUpdatesMe(userId int32, authKeyId int64, serverId int32, updates *mtproto.Updates) (bool, error) {
 	m := &mtproto.TLSyncSyncUpdates{
@@ -58,7 +69,7 @@ func (c *syncClient) SyncUpdatesMe(userId int32, authKeyId int64, serverId int32
 		Updates:   updates,
 	}
 
-	r, err := c.client.SyncSyncUpdates(context.Background(), m)
+	r, err := c.client.SyncSyncUpdates(userContext(userId), m)
 	return mtproto.FromBool(r), err
 }
 
@@ -70,7 +81,7 @@ func (c *syncClient) SyncUpdatesNotMe(userId int32, authKeyId int64, updates *mt
 		Updates:   updates,
 	}
 
-	r, err := c.client.SyncSyncUpdates(context.Background(), m)
+	r, err := c.client.SyncSyncUpdates(userContext(userId), m)
 	return mtproto.FromBool(r), err
 }
 
@@ -81,7 +92,7 @@ func (c *syncClient) PushUpdates(userId int32, updates *mtproto.Updates) (bool,
 		Updates: updates,
 	}
 
-	r, err := c.client.SyncPushUpdates(context.Background(), m)
+	r, err := c.client.SyncPushUpdates(userContext(userId), m)
 	return mtproto.FromBool(r), err
 }
 
@@ -94,7 +105,7 @@ func (c *syncClient) SyncChannelUpdatesMe(channelId int32, participantId int32,
 		Updates:   updates,
 	}
 
-	r, err := c.client.SyncSyncChannelUpdates(context.Background(), m)
+	r, err := c.client.SyncSyncChannelUpdates(userContext(participantId), m)
 	return mtproto.FromBool(r), err
 }
 
@@ -106,7 +117,7 @@ func (c *syncClient) SyncChannelUpdatesNotMe(channelId int32, participantId int3
 		Updates:   updates,
 	}
 
-	r, err := c.client.SyncSyncChannelUpdates(context.Background(), m)
+	r, err := c.client.SyncSyncChannelUpdates(userContext(participantId), m)
 	return mtproto.FromBool(r), err
 }
 
@@ -117,7 +128,7 @@ func (c *syncClient) PushChannelUpdates(channelId, userId int32, updates *mtprot
 		Updates:   updates,
 	}
 
-	r, err := c.client.SyncPushChannelUpdates(context.Background(), m)
+	r, err := c.client.SyncPushChannelUpdates(userContext(userId), m)
 	return mtproto.FromBool(r), err
 }
 
@@ -154,35 +165,38 @@ func (c *syncClient) SyncGetState(authKeyId int64, userId int32) (*mtproto.Updat
 		UserId:    userId,
 	}
 
-	state, err := c.client.SyncGetState(context.Background(), req)
+	state, err := c.client.SyncGetState(userContext(userId), req)
 	return state, err
 }
 
 // sync.getDifference flags:# auth_key_id:long user_id:int pts:int pts_total_limit:flags.0?int date:int qts:int = updates.Difference;
-func (c *syncClient) SyncGetDifference(authKeyId int64, userId, pts int32) (*mtproto.Updates_Difference, error) {
+func (c *syncClient) SyncGetDifference(authKeyId int64, userId, pts, ptsTotalLimit, date, qts int32) (*mtproto.Updates_Difference, error) {
 	req := &mtproto.TLSyncGetDifference{
-		AuthKeyId: authKeyId,
-		UserId:    userId,
-		Pts:       pts,
-		Date:      int32(time.Now().Unix()),
-		Qts:       0,
+		AuthKeyId:     authKeyId,
+		UserId:        userId,
+		Pts:           pts,
+		PtsTotalLimit: ptsTotalLimit,
+		Date:          date,
+		Qts:           qts,
 	}
 
-	difference, err := c.client.SyncGetDifference(context.Background(), req)
+	difference, err := c.client.SyncGetDifference(userContext(userId), req)
 	return difference, err
 }
 
 
-func (c *syncClient) SyncGetChannelDifference(authKeyId int64, userId, pts int32) (*mtproto.Updates_ChannelDifference, error) {
+func (c *syncClient) SyncGetChannelDifference(authKeyId int64, userId int32, force bool, channel *mtproto.InputChannel, filter *mtproto.ChannelMessagesFilter, pts, limit int32) (*mtproto.Updates_ChannelDifference, error) {
 	req := &mtproto.TLSyncGetChannelDifference{
 		AuthKeyId: authKeyId,
 		UserId:    userId,
+		Force:     force,
+		Channel:   channel,
+		Filter:    filter,
 		Pts:       pts,
-		// Date:      int32(time.Now().Unix()),
-		// Qts:       0,
+		Limit:     limit,
 	}
 
-	difference, err := c.client.SyncGetChannelDifference(context.Background(), req)
+	difference, err := c.client.SyncGetChannelDifference(userContext(userId), req)
 	return difference, err
 }
 
@@ -19,19 +19,31 @@ package main
 
 import (
 	"flag"
+	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/baselib/app"
 	_ "github.com/nebulaim/telegramd/proto/mtproto"
 	"github.com/nebulaim/telegramd/service/auth_session/service"
 )
 
+var rewrapAuthKeys bool
+
 func init() {
 	flag.Set("alsologtostderr", "true")
 	flag.Set("log_dir", "false")
+	flag.BoolVar(&rewrapAuthKeys, "rewrap_auth_keys", false, "rewrap auth_keys with the current master key and exit")
 }
 
 func main() {
 	flag.Parse()
 
+	if rewrapAuthKeys {
+		if err := service.RewrapAuthKeys(); err != nil {
+			glog.Fatal(err)
+		}
+		glog.Flush()
+		return
+	}
+
 	instance := service.NewAuthSessionServer()
 	app.DoMainAppInstance(instance)
 }
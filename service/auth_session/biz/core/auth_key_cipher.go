@@ -0,0 +1,92 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth_session
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/service/auth_session/biz/dal/dataobject"
+)
+
+// 重新加密时每批处理的记录数
+const rewrapAuthKeysBatchSize = 500
+
+// auth_key_id作为AES-GCM的additional data, 密文不能被替换到其它记录
+func authKeyAdditionalData(authKeyId int64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(authKeyId))
+	return b
+}
+
+// 使用当前版本的master key加密auth_key, 返回auth_keys.body和key_version
+func (m *AuthSessionModel) encodeAuthKey(authKeyId int64, authKey []byte) (string, int32, error) {
+	keyVersion, data, err := m.keyWrapper.Wrap(authKey, authKeyAdditionalData(authKeyId))
+	if err != nil {
+		return "", 0, err
+	}
+	return base64.RawStdEncoding.EncodeToString(data), keyVersion, nil
+}
+
+func (m *AuthSessionModel) decodeAuthKey(do *dataobject.AuthKeysDO) ([]byte, error) {
+	data, err := base64.RawStdEncoding.DecodeString(do.Body)
+	if err != nil {
+		return nil, err
+	}
+	return m.keyWrapper.Unwrap(do.KeyVersion, data, authKeyAdditionalData(do.AuthKeyId))
+}
+
+// master key轮换后, 将key_version不是当前版本的记录使用当前master key重新加密
+func (m *AuthSessionModel) RewrapAuthKeys() (n int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("rewrap auth_keys error: %v", r)
+		}
+	}()
+
+	var (
+		lastId     int32
+		keyVersion = m.keyWrapper.Version()
+	)
+
+	for {
+		doList := m.dao.AuthKeysDAO.SelectListByKeyVersion(lastId, keyVersion, rewrapAuthKeysBatchSize)
+		for i := 0; i < len(doList); i++ {
+			lastId = doList[i].Id
+
+			authKey, err := m.decodeAuthKey(&doList[i])
+			if err != nil {
+				return n, fmt.Errorf("decode auth_key %d error: %v", doList[i].AuthKeyId, err)
+			}
+			body, newKeyVersion, err := m.encodeAuthKey(doList[i].AuthKeyId, authKey)
+			if err != nil {
+				return n, fmt.Errorf("encode auth_key %d error: %v", doList[i].AuthKeyId, err)
+			}
+			// 以原key_version为条件, 记录已被修改时跳过
+			n += m.dao.AuthKeysDAO.UpdateBody(body, newKeyVersion, doList[i].Id, doList[i].KeyVersion)
+		}
+
+		if len(doList) < rewrapAuthKeysBatchSize {
+			break
+		}
+		glog.Infof("rewrapAuthKeys - rewrap %d auth_keys, last_id: %d", n, lastId)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,70 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth_session
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"github.com/nebulaim/telegramd/service/auth_session/biz/dal/dataobject"
+	"os"
+	"testing"
+)
+
+func TestEncodeAuthKey(t *testing.T) {
+	os.Setenv("TEST_AUTH_KEY_MASTER_1", base64.StdEncoding.EncodeToString(crypto.GenerateNonce(32)))
+	defer os.Unsetenv("TEST_AUTH_KEY_MASTER_1")
+
+	keyWrapper, err := crypto.NewKeyWrapper(&crypto.KeyWrapperConfig{
+		Version:    1,
+		MasterKeys: []crypto.MasterKeyConfig{{Version: 1, Env: "TEST_AUTH_KEY_MASTER_1"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &AuthSessionModel{keyWrapper: keyWrapper}
+
+	authKey := crypto.GenerateNonce(256)
+	body, keyVersion, err := m.encodeAuthKey(1001, authKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// auth_keys.body为varchar(512)
+	if keyVersion != 1 || len(body) > 512 || body == base64.RawStdEncoding.EncodeToString(authKey) {
+		t.Fatalf("invalid body: {key_version: %d, body: %s}", keyVersion, body)
+	}
+
+	authKey2, err := m.decodeAuthKey(&dataobject.AuthKeysDO{AuthKeyId: 1001, Body: body, KeyVersion: keyVersion})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(authKey, authKey2) {
+		t.Fatal("decode auth_key mismatch")
+	}
+
+	// 密文不能用于其它auth_key_id
+	if _, err = m.decodeAuthKey(&dataobject.AuthKeysDO{AuthKeyId: 1002, Body: body, KeyVersion: keyVersion}); err == nil {
+		t.Fatal("other auth_key_id must be rejected")
+	}
+
+	// 未加密的旧数据
+	authKey2, err = m.decodeAuthKey(&dataobject.AuthKeysDO{AuthKeyId: 1001, Body: base64.RawStdEncoding.EncodeToString(authKey)})
+	if err != nil || !bytes.Equal(authKey, authKey2) {
+		t.Fatal("decode plaintext auth_key error")
+	}
+}
@@ -23,7 +23,7 @@ import (
 	"github.com/golang/glog"
 	"fmt"
 	"github.com/nebulaim/telegramd/service/auth_session/biz/dal/dataobject"
-	"encoding/base64"
+	"github.com/nebulaim/telegramd/baselib/crypto"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"time"
 )
@@ -36,10 +36,11 @@ type authSessionDAO struct {
 }
 
 type AuthSessionModel struct {
-	dao authSessionDAO
+	dao        authSessionDAO
+	keyWrapper *crypto.KeyWrapper // auth_key加密存储
 }
 
-func NewAuthSessionModel(dbName, redisName string) *AuthSessionModel {
+func NewAuthSessionModel(dbName, redisName string, keyWrapper *crypto.KeyWrapper) *AuthSessionModel {
 	db := mysql_client.GetMysqlClient(dbName)
 	if db == nil {
 		glog.Fatal("not found db: ", dbName)
@@ -50,7 +51,7 @@ func NewAuthSessionModel(dbName, redisName string) *AuthSessionModel {
 		AuthOpLogsDAO: mysql_dao.NewAuthOpLogsDAO(db),
 		AuthsDAO:      mysql_dao.NewAuthsDAO(db),
 		AuthUsersDAO:  mysql_dao.NewAuthUsersDAO(db),
-	}}
+	}, keyWrapper: keyWrapper}
 	return m
 }
 
@@ -60,30 +61,48 @@ func (m *AuthSessionModel) QueryAuthKey(authKeyId int64) (*mtproto.AuthKeyInfo,
 		err := fmt.Errorf("not find key - keyId = %d", authKeyId)
 		return nil, err
 	}
-	authKey, err := base64.RawStdEncoding.DecodeString(do.Body)
+	authKey, err := m.decodeAuthKey(do)
 	if err != nil {
 		glog.Errorf("read keyData error - keyId = %d, %v", authKeyId, err)
 		return nil, err
 	}
+	// 过期的临时key
+	if do.AuthKeyType == mtproto.AUTH_KEY_TYPE_TEMP && do.ExpiresAt <= int32(time.Now().Unix()) {
+		err = fmt.Errorf("temp key expired - keyId = %d, expires_at = %d", authKeyId, do.ExpiresAt)
+		return nil, err
+	}
+
 	keyInfo := &mtproto.TLAuthKeyInfo{Data2: &mtproto.AuthKeyInfo_Data{
-		AuthKeyId: authKeyId,
-		AuthKey:   authKey,
+		AuthKeyId:     authKeyId,
+		AuthKey:       authKey,
+		AuthKeyType:   int32(do.AuthKeyType),
+		PermAuthKeyId: do.PermAuthKeyId,
+		ExpiresAt:     do.ExpiresAt,
 	}}
 
 	// TODO(@benqi): get salt
 	return keyInfo.To_AuthKeyInfo(), nil
 }
 
-func (m *AuthSessionModel) InsertAuthKey(authKeyId int64, authKey []byte, salt *mtproto.TLFutureSalt) (err error) {
+func (m *AuthSessionModel) InsertAuthKey(authKeyId int64, authKey []byte, authKeyType, expiresAt int32, salt *mtproto.TLFutureSalt) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("storage auth_key error: auth_key_id = %d", authKeyId)
 		}
 	}()
 
+	body, keyVersion, err := m.encodeAuthKey(authKeyId, authKey)
+	if err != nil {
+		glog.Errorf("encode keyData error - keyId = %d, %v", authKeyId, err)
+		return err
+	}
+
 	do := &dataobject.AuthKeysDO{
-		AuthKeyId: authKeyId,
-		Body:      base64.RawStdEncoding.EncodeToString(authKey),
+		AuthKeyId:   authKeyId,
+		Body:        body,
+		KeyVersion:  keyVersion,
+		AuthKeyType: int8(authKeyType),
+		ExpiresAt:   expiresAt,
 	}
 
 	do.Id = int32(m.dao.AuthKeysDAO.Insert(do))
@@ -0,0 +1,131 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth_session
+
+import (
+	"fmt"
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"time"
+)
+
+// 校验auth.bindTempAuthKey的encrypted_message
+// https://core.telegram.org/method/auth.bindTempAuthKey
+func checkBindTempAuthKey(request *mtproto.TLSessionBindTempAuthKey, permAuthKey []byte, now int32) error {
+	msgId, inner, err := mtproto.DecryptBindAuthKeyInner(permAuthKey, request.GetEncryptedMessage())
+	if err != nil {
+		return err
+	}
+
+	// binding message的msg_id必须和auth.bindTempAuthKey请求的msg_id相同
+	if msgId != request.GetMsgId() {
+		return fmt.Errorf("invalid msg_id: %d (need %d)", msgId, request.GetMsgId())
+	}
+	if inner.Nonce != request.GetNonce() {
+		return fmt.Errorf("invalid nonce: %d (need %d)", inner.Nonce, request.GetNonce())
+	}
+	if inner.TempAuthKeyId != request.GetTempAuthKeyId() {
+		return fmt.Errorf("invalid temp_auth_key_id: %d (need %d)", inner.TempAuthKeyId, request.GetTempAuthKeyId())
+	}
+	if inner.PermAuthKeyId != request.GetPermAuthKeyId() {
+		return fmt.Errorf("invalid perm_auth_key_id: %d (need %d)", inner.PermAuthKeyId, request.GetPermAuthKeyId())
+	}
+	if inner.TempSessionId != request.GetTempSessionId() {
+		return fmt.Errorf("invalid temp_session_id: %d (need %d)", inner.TempSessionId, request.GetTempSessionId())
+	}
+	if inner.ExpiresAt != request.GetExpiresAt() || inner.ExpiresAt <= now {
+		return fmt.Errorf("invalid expires_at: %d (need %d)", inner.ExpiresAt, request.GetExpiresAt())
+	}
+	return nil
+}
+
+// 将临时key绑定到永久key，之后临时key使用永久key的用户和授权信息
+func (m *AuthSessionModel) BindTempAuthKey(request *mtproto.TLSessionBindTempAuthKey) error {
+	now := int32(time.Now().Unix())
+
+	tempDO := m.dao.AuthKeysDAO.SelectByAuthKeyId(request.GetTempAuthKeyId())
+	if tempDO == nil || tempDO.AuthKeyType != mtproto.AUTH_KEY_TYPE_TEMP || tempDO.ExpiresAt <= now {
+		glog.Errorf("bindTempAuthKey - invalid temp key: %d", request.GetTempAuthKeyId())
+		return mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_TEMP_AUTH_KEY_EMPTY)
+	}
+	// 允许重复绑定到同一个永久key
+	if tempDO.PermAuthKeyId != 0 && tempDO.PermAuthKeyId != request.GetPermAuthKeyId() {
+		glog.Errorf("bindTempAuthKey - temp key %d already bound to %d", request.GetTempAuthKeyId(), tempDO.PermAuthKeyId)
+		return mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_TEMP_AUTH_KEY_ALREADY_BOUND)
+	}
+
+	permDO := m.dao.AuthKeysDAO.SelectByAuthKeyId(request.GetPermAuthKeyId())
+	if permDO == nil || permDO.AuthKeyType != mtproto.AUTH_KEY_TYPE_PERM {
+		glog.Errorf("bindTempAuthKey - invalid perm key: %d", request.GetPermAuthKeyId())
+		return mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_ENCRYPTED_MESSAGE_INVALID)
+	}
+	permAuthKey, err := m.decodeAuthKey(permDO)
+	if err != nil {
+		glog.Errorf("bindTempAuthKey - read keyData error - keyId = %d, %v", request.GetPermAuthKeyId(), err)
+		return mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_ENCRYPTED_MESSAGE_INVALID)
+	}
+
+	if err = checkBindTempAuthKey(request, permAuthKey, now); err != nil {
+		glog.Errorf("bindTempAuthKey - invalid encrypted_message: %v", err)
+		return mtproto.NewRpcError2(mtproto.TLRpcErrorCodes_ENCRYPTED_MESSAGE_INVALID)
+	}
+
+	// 不能超过握手时p_q_inner_data_temp.expires_in指定的时间
+	expiresAt := tempDO.ExpiresAt
+	if request.GetExpiresAt() < expiresAt {
+		expiresAt = request.GetExpiresAt()
+	}
+	m.dao.AuthKeysDAO.UpdatePermAuthKeyId(request.GetPermAuthKeyId(), expiresAt, request.GetTempAuthKeyId())
+	return nil
+}
+
+// 删除永久key绑定的临时key, exceptAuthKeys除外
+func (m *AuthSessionModel) DropTempAuthKeys(permAuthKeyId int64, exceptAuthKeys []int64) []int64 {
+	doList := m.dao.AuthKeysDAO.SelectTempAuthKeyIdList(permAuthKeyId)
+
+	idList := make([]int64, 0, len(doList))
+	for i := 0; i < len(doList); i++ {
+		except := false
+		for _, id := range exceptAuthKeys {
+			if id == doList[i].AuthKeyId {
+				except = true
+				break
+			}
+		}
+		if !except {
+			idList = append(idList, doList[i].AuthKeyId)
+		}
+	}
+
+	if len(idList) > 0 {
+		m.dao.AuthKeysDAO.DeleteByAuthKeyIdList(idList)
+	}
+	return idList
+}
+
+// 清理过期的临时key
+func (m *AuthSessionModel) PurgeExpiredTempAuthKeys() (n int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("purge expired temp keys error: %v", r)
+		}
+	}()
+
+	n = m.dao.AuthKeysDAO.DeleteExpired(int32(time.Now().Unix()))
+	return
+}
@@ -0,0 +1,84 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth_session
+
+import (
+	"encoding/binary"
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"github.com/nebulaim/telegramd/proto/mtproto"
+	"testing"
+)
+
+func makeTestBindTempAuthKey(permAuthKey []byte, now int32) *mtproto.TLSessionBindTempAuthKey {
+	request := &mtproto.TLSessionBindTempAuthKey{
+		TempAuthKeyId: 1001,
+		TempSessionId: 2002,
+		MsgId:         6500000000000000004,
+		PermAuthKeyId: int64(binary.LittleEndian.Uint64(crypto.Sha1Digest(permAuthKey)[12:])),
+		Nonce:         3003,
+		ExpiresAt:     now + 3600,
+	}
+	request.EncryptedMessage = mtproto.EncryptBindAuthKeyInner(permAuthKey, request.MsgId, &mtproto.TLBindAuthKeyInner{
+		Nonce:         request.Nonce,
+		TempAuthKeyId: request.TempAuthKeyId,
+		PermAuthKeyId: request.PermAuthKeyId,
+		TempSessionId: request.TempSessionId,
+		ExpiresAt:     request.ExpiresAt,
+	})
+	return request
+}
+
+func TestCheckBindTempAuthKey(t *testing.T) {
+	const now = 1500000000
+	permAuthKey := crypto.GenerateNonce(256)
+
+	if err := checkBindTempAuthKey(makeTestBindTempAuthKey(permAuthKey, now), permAuthKey, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// 请求字段和binding message不一致
+	tests := []struct {
+		name   string
+		modify func(r *mtproto.TLSessionBindTempAuthKey)
+	}{
+		{"msg_id", func(r *mtproto.TLSessionBindTempAuthKey) { r.MsgId += 4 }},
+		{"nonce", func(r *mtproto.TLSessionBindTempAuthKey) { r.Nonce++ }},
+		{"temp_auth_key_id", func(r *mtproto.TLSessionBindTempAuthKey) { r.TempAuthKeyId++ }},
+		{"perm_auth_key_id", func(r *mtproto.TLSessionBindTempAuthKey) { r.PermAuthKeyId++ }},
+		{"temp_session_id", func(r *mtproto.TLSessionBindTempAuthKey) { r.TempSessionId++ }},
+		{"expires_at", func(r *mtproto.TLSessionBindTempAuthKey) { r.ExpiresAt++ }},
+		{"encrypted_message", func(r *mtproto.TLSessionBindTempAuthKey) { r.EncryptedMessage[30] ^= 0x01 }},
+	}
+	for _, tt := range tests {
+		request := makeTestBindTempAuthKey(permAuthKey, now)
+		tt.modify(request)
+		if err := checkBindTempAuthKey(request, permAuthKey, now); err == nil {
+			t.Errorf("%s: must be rejected", tt.name)
+		}
+	}
+
+	// 已过期
+	if err := checkBindTempAuthKey(makeTestBindTempAuthKey(permAuthKey, now), permAuthKey, now+3600); err == nil {
+		t.Error("expired binding message must be rejected")
+	}
+
+	// 非绑定的永久key加密
+	if err := checkBindTempAuthKey(makeTestBindTempAuthKey(permAuthKey, now), crypto.GenerateNonce(256), now); err == nil {
+		t.Error("other perm key must be rejected")
+	}
+}
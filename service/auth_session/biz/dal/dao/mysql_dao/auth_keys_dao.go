@@ -33,10 +33,10 @@ func NewAuthKeysDAO(db *sqlx.DB) *AuthKeysDAO {
 	return &AuthKeysDAO{db}
 }
 
-// insert into auth_keys(auth_key_id, body) values (:auth_key_id, :body)
+// insert into auth_keys(auth_key_id, body, key_version, auth_key_type, expires_at) values (:auth_key_id, :body, :key_version, :auth_key_type, :expires_at)
 // TODO(@benqi): sqlmap
 func (dao *AuthKeysDAO) Insert(do *dataobject.AuthKeysDO) int64 {
-	var query = "insert into auth_keys(auth_key_id, body) values (:auth_key_id, :body)"
+	var query = "insert into auth_keys(auth_key_id, body, key_version, auth_key_type, expires_at) values (:auth_key_id, :body, :key_version, :auth_key_type, :expires_at)"
 	r, err := dao.db.NamedExec(query, do)
 	if err != nil {
 		errDesc := fmt.Sprintf("NamedExec in Insert(%v), error: %v", do, err)
@@ -53,10 +53,10 @@ func (dao *AuthKeysDAO) Insert(do *dataobject.AuthKeysDO) int64 {
 	return id
 }
 
-// select auth_key_id, body from auth_keys where auth_key_id = :auth_key_id
+// select auth_key_id, body, key_version, auth_key_type, perm_auth_key_id, expires_at from auth_keys where auth_key_id = :auth_key_id
 // TODO(@benqi): sqlmap
 func (dao *AuthKeysDAO) SelectByAuthKeyId(auth_key_id int64) *dataobject.AuthKeysDO {
-	var query = "select auth_key_id, body from auth_keys where auth_key_id = ?"
+	var query = "select auth_key_id, body, key_version, auth_key_type, perm_auth_key_id, expires_at from auth_keys where auth_key_id = ?"
 	rows, err := dao.db.Queryx(query, auth_key_id)
 
 	if err != nil {
@@ -88,3 +88,173 @@ func (dao *AuthKeysDAO) SelectByAuthKeyId(auth_key_id int64) *dataobject.AuthKey
 
 	return do
 }
+
+// update auth_keys set perm_auth_key_id = :perm_auth_key_id, expires_at = :expires_at where auth_key_id = :auth_key_id and auth_key_type = 1
+// TODO(@benqi): sqlmap
+func (dao *AuthKeysDAO) UpdatePermAuthKeyId(perm_auth_key_id int64, expires_at int32, auth_key_id int64) int64 {
+	var query = "update auth_keys set perm_auth_key_id = ?, expires_at = ? where auth_key_id = ? and auth_key_type = 1"
+	r, err := dao.db.Exec(query, perm_auth_key_id, expires_at, auth_key_id)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Exec in UpdatePermAuthKeyId(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	rows, err := r.RowsAffected()
+	if err != nil {
+		errDesc := fmt.Sprintf("RowsAffected in UpdatePermAuthKeyId(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return rows
+}
+
+// select auth_key_id from auth_keys where perm_auth_key_id = :perm_auth_key_id and auth_key_type = 1
+// TODO(@benqi): sqlmap
+func (dao *AuthKeysDAO) SelectTempAuthKeyIdList(perm_auth_key_id int64) []dataobject.AuthKeysDO {
+	var query = "select auth_key_id from auth_keys where perm_auth_key_id = ? and auth_key_type = 1"
+	rows, err := dao.db.Queryx(query, perm_auth_key_id)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectTempAuthKeyIdList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.AuthKeysDO
+	for rows.Next() {
+		v := dataobject.AuthKeysDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectTempAuthKeyIdList(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectTempAuthKeyIdList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// delete from auth_keys where auth_key_id in (:idList) and auth_key_type = 1
+// TODO(@benqi): sqlmap
+func (dao *AuthKeysDAO) DeleteByAuthKeyIdList(idList []int64) int64 {
+	var q = "delete from auth_keys where auth_key_id in (?) and auth_key_type = 1"
+	query, a, err := sqlx.In(q, idList)
+	if err != nil {
+		errDesc := fmt.Sprintf("In in DeleteByAuthKeyIdList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+	r, err := dao.db.Exec(query, a...)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Exec in DeleteByAuthKeyIdList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	rows, err := r.RowsAffected()
+	if err != nil {
+		errDesc := fmt.Sprintf("RowsAffected in DeleteByAuthKeyIdList(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return rows
+}
+
+// delete from auth_keys where auth_key_type = 1 and expires_at < :expires_at
+// TODO(@benqi): sqlmap
+func (dao *AuthKeysDAO) DeleteExpired(expires_at int32) int64 {
+	var query = "delete from auth_keys where auth_key_type = 1 and expires_at < ?"
+	r, err := dao.db.Exec(query, expires_at)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Exec in DeleteExpired(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	rows, err := r.RowsAffected()
+	if err != nil {
+		errDesc := fmt.Sprintf("RowsAffected in DeleteExpired(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return rows
+}
+
+// select id, auth_key_id, body, key_version from auth_keys where id > :id and key_version <> :key_version order by id asc limit :limit
+// TODO(@benqi): sqlmap
+func (dao *AuthKeysDAO) SelectListByKeyVersion(id int32, key_version int32, limit int32) []dataobject.AuthKeysDO {
+	var query = "select id, auth_key_id, body, key_version from auth_keys where id > ? and key_version <> ? order by id asc limit ?"
+	rows, err := dao.db.Queryx(query, id, key_version, limit)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Queryx in SelectListByKeyVersion(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	defer rows.Close()
+
+	var values []dataobject.AuthKeysDO
+	for rows.Next() {
+		v := dataobject.AuthKeysDO{}
+
+		// TODO(@benqi): 不使用反射
+		err := rows.StructScan(&v)
+		if err != nil {
+			errDesc := fmt.Sprintf("StructScan in SelectListByKeyVersion(_), error: %v", err)
+			glog.Error(errDesc)
+			panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+		}
+		values = append(values, v)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		errDesc := fmt.Sprintf("rows in SelectListByKeyVersion(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return values
+}
+
+// update auth_keys set body = :body, key_version = :key_version where id = :id and key_version = :old_key_version
+// TODO(@benqi): sqlmap
+func (dao *AuthKeysDAO) UpdateBody(body string, key_version int32, id int32, old_key_version int32) int64 {
+	var query = "update auth_keys set body = ?, key_version = ? where id = ? and key_version = ?"
+	r, err := dao.db.Exec(query, body, key_version, id, old_key_version)
+
+	if err != nil {
+		errDesc := fmt.Sprintf("Exec in UpdateBody(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	rows, err := r.RowsAffected()
+	if err != nil {
+		errDesc := fmt.Sprintf("RowsAffected in UpdateBody(_), error: %v", err)
+		glog.Error(errDesc)
+		panic(mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_DBERR), errDesc))
+	}
+
+	return rows
+}
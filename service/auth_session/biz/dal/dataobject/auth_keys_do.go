@@ -18,8 +18,12 @@
 package dataobject
 
 type AuthKeysDO struct {
-	Id        int32  `db:"id"`
-	AuthKeyId int64  `db:"auth_key_id"`
-	Body      string `db:"body"`
-	CreatedAt string `db:"created_at"`
+	Id            int32  `db:"id"`
+	AuthKeyId     int64  `db:"auth_key_id"`
+	Body          string `db:"body"`
+	KeyVersion    int32  `db:"key_version"`
+	AuthKeyType   int8   `db:"auth_key_type"`
+	PermAuthKeyId int64  `db:"perm_auth_key_id"`
+	ExpiresAt     int32  `db:"expires_at"`
+	CreatedAt     string `db:"created_at"`
 }
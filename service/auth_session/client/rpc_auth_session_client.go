@@ -23,6 +23,10 @@ import (
 	"github.com/golang/glog"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type authSessionClient struct {
@@ -76,3 +80,34 @@ func UnbindAuthKeyUser(authKeyId int64, userId int32) bool {
 	return true
 }
 
+// 失败时返回rpc_error(ENCRYPTED_MESSAGE_INVALID等)
+func BindTempAuthKey(request *mtproto.TLSessionBindTempAuthKey) error {
+	var trailer metadata.MD
+	_, err := authSessionInstance.client.SessionBindTempAuthKey(context.Background(), request, grpc.Trailer(&trailer))
+
+	if err != nil {
+		glog.Error(err)
+		if s, ok := status.FromError(err); ok && s.Code() == codes.Unknown {
+			return grpc_util.RpcErrorFromMD(trailer)
+		}
+		return mtproto.NewRpcError(int32(mtproto.TLRpcErrorCodes_INTERNAL), "INTERNAL_SERVER_ERROR")
+	}
+
+	return nil
+}
+
+func DropTempAuthKeys(permAuthKeyId int64, exceptAuthKeys []int64) bool {
+	request := &mtproto.TLSessionDropTempAuthKeys{
+		PermAuthKeyId:  permAuthKeyId,
+		ExceptAuthKeys: exceptAuthKeys,
+	}
+
+	_, err := authSessionInstance.client.SessionDropTempAuthKeys(context.Background(), request)
+
+	if err != nil {
+		glog.Error(err)
+		return false
+	}
+
+	return true
+}
@@ -19,16 +19,23 @@ package service
 
 import (
 	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/crypto"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/mysql_client"
 	"github.com/nebulaim/telegramd/baselib/redis_client"
 	"github.com/nebulaim/telegramd/proto/mtproto"
 	"google.golang.org/grpc"
 	"github.com/nebulaim/telegramd/service/auth_session/service/rpc"
+	"time"
 )
 
+// 过期临时key的清理间隔
+const purgeTempAuthKeysInterval = time.Minute
+
 type authSessionServer struct {
 	rpcServer *grpc_util.RPCServer
+	impl      *rpc.SessionServiceImpl
+	closeChan chan struct{}
 }
 
 func NewAuthSessionServer() *authSessionServer {
@@ -50,23 +57,52 @@ func (s *authSessionServer) Initialize() error {
 	redis_client.InstallRedisClientManager(Conf.Redis)
 	mysql_client.InstallMysqlClientManager(Conf.Mysql)
 
+	keyWrapper, err := crypto.NewKeyWrapper(Conf.AuthKeyEncryption)
+	if err != nil {
+		glog.Fatal(err)
+		return err
+	}
+
 	s.rpcServer = grpc_util.NewRpcServer(Conf.RpcServer.Addr, &Conf.RpcServer.RpcDiscovery)
+	s.impl = rpc.NewSessionServiceImpl("immaster", "cache", keyWrapper)
+	s.closeChan = make(chan struct{})
 	return nil
 }
 
 func (s *authSessionServer) RunLoop() {
 	glog.Infof("authSessionServer - runLoop...")
 
+	go s.purgeTempAuthKeysLoop()
+
 	// TODO(@benqi): check error
 	s.rpcServer.Serve(func(s2 *grpc.Server) {
-		impl := rpc.NewSessionServiceImpl("immaster", "cache")
 		// &rpc.SessionServiceImpl{}
-		mtproto.RegisterRPCSessionServer(s2, impl)
+		mtproto.RegisterRPCSessionServer(s2, s.impl)
 	})
 }
 
+func (s *authSessionServer) purgeTempAuthKeysLoop() {
+	ticker := time.NewTicker(purgeTempAuthKeysInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n, err := s.impl.PurgeExpiredTempAuthKeys()
+			if err != nil {
+				glog.Error(err)
+			} else if n > 0 {
+				glog.Infof("authSessionServer - purge %d expired temp keys", n)
+			}
+		case <-s.closeChan:
+			return
+		}
+	}
+}
+
 func (s *authSessionServer) Destroy() {
 	glog.Infof("authSessionServer - destroy...")
+	close(s.closeChan)
 	s.rpcServer.Stop()
 	//time.Sleep(1*time.Second)
 }
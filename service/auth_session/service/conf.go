@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/BurntSushi/toml"
+	"github.com/nebulaim/telegramd/baselib/crypto"
 	"github.com/nebulaim/telegramd/baselib/grpc_util"
 	"github.com/nebulaim/telegramd/baselib/mysql_client"
 	"github.com/nebulaim/telegramd/baselib/redis_client"
@@ -32,16 +33,22 @@ var (
 )
 
 type authSessionConfig struct {
-	Redis     []redis_client.RedisConfig
-	Mysql     []mysql_client.MySQLConfig
-	RpcServer *grpc_util.RPCServerConfig
+	Redis             []redis_client.RedisConfig
+	Mysql             []mysql_client.MySQLConfig
+	RpcServer         *grpc_util.RPCServerConfig
+	AuthKeyEncryption *crypto.KeyWrapperConfig // auth_keys加密存储, 为空时不加密
 }
 
 func (c *authSessionConfig) String() string {
-	return fmt.Sprintf("{redis: %v. mysql: %v, server: %v}",
+	var keyVersion int32
+	if c.AuthKeyEncryption != nil {
+		keyVersion = c.AuthKeyEncryption.Version
+	}
+	return fmt.Sprintf("{redis: %v. mysql: %v, server: %v, auth_key_version: %d}",
 		c.Redis,
 		c.Mysql,
-		c.RpcServer)
+		c.RpcServer,
+		keyVersion)
 }
 
 func init() {
@@ -0,0 +1,48 @@
+/*
+ *  Copyright (c) 2018, https://github.com/nebulaim
+ *  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/golang/glog"
+	"github.com/nebulaim/telegramd/baselib/crypto"
+	"github.com/nebulaim/telegramd/baselib/mysql_client"
+	"github.com/nebulaim/telegramd/service/auth_session/biz/core"
+)
+
+// 使用authKeyEncryption.version对应的master key重新加密auth_keys里的所有记录,
+// 轮换master key或第一次启用加密时执行: auth_session -conf=./auth_session.toml -rewrap_auth_keys
+func RewrapAuthKeys() error {
+	err := InitializeConfig()
+	if err != nil {
+		return err
+	}
+
+	keyWrapper, err := crypto.NewKeyWrapper(Conf.AuthKeyEncryption)
+	if err != nil {
+		return err
+	}
+
+	mysql_client.InstallMysqlClientManager(Conf.Mysql)
+	n, err := auth_session.NewAuthSessionModel("immaster", "cache", keyWrapper).RewrapAuthKeys()
+	if err != nil {
+		return err
+	}
+
+	glog.Infof("rewrapAuthKeys - rewrap %d auth_keys to key_version %d", n, keyWrapper.Version())
+	return nil
+}
@@ -31,7 +31,11 @@ func (s *SessionServiceImpl) SessionSetAuthKey(ctx context.Context, request *mtp
 	glog.Infof("session.setAuthKey - metadata: %s, request: %s", logger.JsonDebugData(md), logger.JsonDebugData(request))
 
 	keyInfo := request.GetAuthKey().To_AuthKeyInfo()
-	err := s.AuthSessionModel.InsertAuthKey(keyInfo.GetAuthKeyId(), keyInfo.GetAuthKey(), keyInfo.GetFutureSalt().To_FutureSalt())
+	err := s.AuthSessionModel.InsertAuthKey(keyInfo.GetAuthKeyId(),
+		keyInfo.GetAuthKey(),
+		keyInfo.GetAuthKeyType(),
+		keyInfo.GetExpiresAt(),
+		keyInfo.GetFutureSalt().To_FutureSalt())
 	if err != nil {
 		glog.Error(err)
 		return mtproto.ToBool(false), nil
@@ -18,6 +18,7 @@
 package rpc
 
 import (
+	"github.com/nebulaim/telegramd/baselib/crypto"
 	"github.com/nebulaim/telegramd/service/auth_session/biz/core"
 )
 
@@ -25,7 +26,7 @@ type SessionServiceImpl struct {
 	*auth_session.AuthSessionModel
 }
 
-func NewSessionServiceImpl(dbName, redisName string) *SessionServiceImpl {
-	s := &SessionServiceImpl{auth_session.NewAuthSessionModel(dbName, redisName)}
+func NewSessionServiceImpl(dbName, redisName string, keyWrapper *crypto.KeyWrapper) *SessionServiceImpl {
+	s := &SessionServiceImpl{auth_session.NewAuthSessionModel(dbName, redisName, keyWrapper)}
 	return s
 }